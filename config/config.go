@@ -9,6 +9,7 @@ import (
 	"log/slog"
 	"os"
 	"path/filepath"
+	"strings"
 	"time"
 
 	"github.com/dicedb/dice/internal/server/utils"
@@ -19,11 +20,16 @@ const (
 	DefaultConfigName = "dicedb.conf"
 	DefaultConfigDir  = "."
 
-	EvictSimpleFirst   = "simple-first"
-	EvictAllKeysRandom = "allkeys-random"
-	EvictAllKeysLRU    = "allkeys-lru"
-	EvictAllKeysLFU    = "allkeys-lfu"
-	EvictBatchKeysLRU  = "batch_keys_lru"
+	EvictSimpleFirst    = "simple-first"
+	EvictAllKeysRandom  = "allkeys-random"
+	EvictAllKeysLRU     = "allkeys-lru"
+	EvictAllKeysLFU     = "allkeys-lfu"
+	EvictBatchKeysLRU   = "batch_keys_lru"
+	EvictNoEviction     = "noeviction"
+	EvictVolatileLRU    = "volatile-lru"
+	EvictVolatileLFU    = "volatile-lfu"
+	EvictVolatileRandom = "volatile-random"
+	EvictVolatileTTL    = "volatile-ttl"
 
 	DefaultKeysLimit     int     = 200000000
 	DefaultEvictionRatio float64 = 0.1
@@ -49,6 +55,12 @@ websocket.enabled = false
 websocket.port = 8379
 websocket.max_write_response_retries = 3
 websocket.write_response_timeout = 10s
+websocket.ping_interval = 30s
+websocket.pong_timeout = 10s
+
+# Memcached Configuration
+memcached.enabled = false
+memcached.port = 11211
 
 # Performance Configuration
 performance.watch_chan_buf_size = 20000
@@ -60,6 +72,21 @@ performance.adhoc_req_chan_buf_size = 20
 performance.enable_profiling = false
 performance.enable_watch = false
 performance.num_shards = -1
+performance.request_timeout = 6s
+performance.command_timeouts = ""
+performance.slowlog_log_slower_than = 10000
+performance.slowlog_max_len = 128
+
+# Client Output Buffer Limits (bytes, 0 disables the limit)
+client_output_buffer_limit.normal_hard_limit = 0
+client_output_buffer_limit.normal_soft_limit = 0
+client_output_buffer_limit.normal_soft_seconds = 0
+client_output_buffer_limit.watch_hard_limit = 33554432
+client_output_buffer_limit.watch_soft_limit = 8388608
+client_output_buffer_limit.watch_soft_seconds = 60
+client_output_buffer_limit.pubsub_hard_limit = 33554432
+client_output_buffer_limit.pubsub_soft_limit = 8388608
+client_output_buffer_limit.pubsub_soft_seconds = 60
 
 # Memory Configuration
 memory.max_memory = 0
@@ -67,6 +94,8 @@ memory.eviction_policy = "allkeys-lfu"
 memory.eviction_ratio = 0.9
 memory.keys_limit = 200000000
 memory.lfu_log_factor = 10
+memory.databases = 16
+memory.spill_dir = ""
 
 # Persistence Configuration
 persistence.enabled = false
@@ -84,6 +113,7 @@ logging.log_dir = "/tmp/dicedb"
 # Authentication Configuration
 auth.username = "dice"
 auth.password = ""
+auth.deny_admin_commands = false
 
 # Network Configuration
 network.io_buffer_length = 512
@@ -108,6 +138,10 @@ RecoveryMode = "strict"`
 var (
 	CustomConfigFilePath = utils.EmptyStr
 	CustomConfigDirPath  = utils.EmptyStr
+
+	// ConfigFilePath is the path of the last configuration file loaded, reported by INFO, or empty if
+	// the defaults are used.
+	ConfigFilePath = utils.EmptyStr
 )
 
 type Config struct {
@@ -117,17 +151,22 @@ type Config struct {
 	RespServer  respServer  `config:"async_server"`
 	HTTP        http        `config:"http"`
 	WebSocket   websocket   `config:"websocket"`
+	Memcached   memcached   `config:"memcached"`
 	Performance performance `config:"performance"`
 	Memory      memory      `config:"memory"`
 	Persistence persistence `config:"persistence"`
 	Logging     logging     `config:"logging"`
 	Network     network     `config:"network"`
 	WAL         WALConfig   `config:"WAL"`
+
+	ClientOutputBufferLimit clientOutputBufferLimit `config:"client_output_buffer_limit"`
 }
 
 type auth struct {
 	UserName string `config:"username" default:"dice"`
 	Password string `config:"password"`
+	// DenyAdminCommands refuses the admin commands, e.g. ABORT and FLUSHDB, to the configured user
+	DenyAdminCommands bool `config:"deny_admin_commands" default:"false"`
 }
 
 type respServer struct {
@@ -143,11 +182,20 @@ type http struct {
 	Port    int  `config:"port" default:"8082" validate:"number,gte=0,lte=65535"`
 }
 
+type memcached struct {
+	Enabled bool `config:"enabled" default:"false"`
+	Port    int  `config:"port" default:"11211" validate:"number,gte=0,lte=65535"`
+}
+
 type websocket struct {
 	Enabled                 bool          `config:"enabled" default:"true"`
 	Port                    int           `config:"port" default:"8379" validate:"number,gte=0,lte=65535"`
 	MaxWriteResponseRetries int           `config:"max_write_response_retries" default:"3" validate:"min=0"`
 	WriteResponseTimeout    time.Duration `config:"write_response_timeout" default:"10s"`
+	// PingInterval is how often the server pings idle clients. A client that does not answer with a
+	// pong within PongTimeout is disconnected. A ping interval of 0 disables the heartbeats.
+	PingInterval time.Duration `config:"ping_interval" default:"30s"`
+	PongTimeout  time.Duration `config:"pong_timeout" default:"10s"`
 }
 
 type performance struct {
@@ -160,14 +208,47 @@ type performance struct {
 	EnableProfiling        bool          `config:"profiling" default:"false"`
 	EnableWatch            bool          `config:"enable_watch" default:"false"`
 	NumShards              int           `config:"num_shards" default:"-1" validate:"oneof=-1|min=1,lte=128"`
+	RequestTimeout         time.Duration `config:"request_timeout" default:"6s"`
+	// CommandTimeouts overrides the request timeout for individual commands, e.g. "KEYS=30s,FLUSHDB=1m".
+	// A timeout of 0 disables it for the command.
+	CommandTimeouts string `config:"command_timeouts"`
+	// CommandTimeoutsByName holds CommandTimeouts parsed once by the config load, keyed by command name.
+	// It is never modified once loaded, so that it is read without locking for every command.
+	CommandTimeoutsByName map[string]time.Duration `config:"-"`
+
+	// SlowlogLogSlowerThan is the execution time, in microseconds, from which a command is recorded
+	// by SLOWLOG. A negative value disables the slowlog, while 0 records every command.
+	SlowlogLogSlowerThan int64 `config:"slowlog_log_slower_than" default:"10000"`
+	// SlowlogMaxLen is the number of slow commands kept by each shard, the oldest being dropped first.
+	SlowlogMaxLen int `config:"slowlog_max_len" default:"128" validate:"min=0"`
+}
+
+// clientOutputBufferLimit holds the output buffer limits for each client class. A client is
+// disconnected as soon as its pending output reaches the hard limit, or when it stays above the
+// soft limit for longer than the configured number of seconds. A value of 0 disables the limit.
+type clientOutputBufferLimit struct {
+	NormalHardLimit   int `config:"normal_hard_limit" default:"0" validate:"min=0"`
+	NormalSoftLimit   int `config:"normal_soft_limit" default:"0" validate:"min=0"`
+	NormalSoftSeconds int `config:"normal_soft_seconds" default:"0" validate:"min=0"`
+	WatchHardLimit    int `config:"watch_hard_limit" default:"33554432" validate:"min=0"`
+	WatchSoftLimit    int `config:"watch_soft_limit" default:"8388608" validate:"min=0"`
+	WatchSoftSeconds  int `config:"watch_soft_seconds" default:"60" validate:"min=0"`
+	PubSubHardLimit   int `config:"pubsub_hard_limit" default:"33554432" validate:"min=0"`
+	PubSubSoftLimit   int `config:"pubsub_soft_limit" default:"8388608" validate:"min=0"`
+	PubSubSoftSeconds int `config:"pubsub_soft_seconds" default:"60" validate:"min=0"`
 }
 
 type memory struct {
 	MaxMemory      int64   `config:"max_memory" default:"0" validate:"min=0"`
-	EvictionPolicy string  `config:"eviction_policy" default:"allkeys-lfu" validate:"oneof=simple-first allkeys-random allkeys-lru allkeys-lfu"`
+	EvictionPolicy string  `config:"eviction_policy" default:"allkeys-lfu" validate:"oneof=simple-first allkeys-random allkeys-lru allkeys-lfu batch_keys_lru noeviction volatile-lru volatile-lfu volatile-random volatile-ttl"`
 	EvictionRatio  float64 `config:"eviction_ratio" default:"0.9" validate:"min=0,lte=1"`
 	KeysLimit      int     `config:"keys_limit" default:"200000000" validate:"min=10"`
 	LFULogFactor   int     `config:"lfu_log_factor" default:"10" validate:"min=0"`
+	// Databases is the number of logical databases, which the clients switch between with SELECT.
+	Databases int `config:"databases" default:"16" validate:"min=1,lte=1024"`
+	// SpillDir is the directory where the shards spill the keys they evict, instead of deleting
+	// them, or empty to delete them.
+	SpillDir string `config:"spill_dir" default:""`
 }
 
 type persistence struct {
@@ -217,6 +298,32 @@ type network struct {
 	IOBufferLength    int `config:"io_buffer_length" default:"512" validate:"min=0"`
 }
 
+// ParseCommandTimeouts parses the per-command timeouts from a comma-separated list of
+// COMMAND=duration pairs. Command names are upper-cased.
+func ParseCommandTimeouts(value string) (map[string]time.Duration, error) {
+	timeouts := make(map[string]time.Duration)
+	for _, entry := range strings.Split(value, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		name, duration, found := strings.Cut(entry, "=")
+		name = strings.ToUpper(strings.TrimSpace(name))
+		if !found || name == "" {
+			return nil, fmt.Errorf("invalid command timeout %q, expected COMMAND=duration", entry)
+		}
+
+		timeout, err := time.ParseDuration(strings.TrimSpace(duration))
+		if err != nil || timeout < 0 {
+			return nil, fmt.Errorf("invalid timeout for command %s: %q", name, duration)
+		}
+		timeouts[name] = timeout
+	}
+
+	return timeouts, nil
+}
+
 // DiceConfig is the global configuration object for dice
 var DiceConfig = &Config{}
 
@@ -273,7 +380,11 @@ func loadDiceConfig(configFilePath string) error {
 		return parser.ParseDefaults(DiceConfig)
 	}
 
-	return parser.Loadconfig(DiceConfig)
+	if err := parser.Loadconfig(DiceConfig); err != nil {
+		return err
+	}
+	ConfigFilePath = configFilePath
+	return nil
 }
 
 func MergeFlags(flags *Config) {
@@ -293,6 +404,10 @@ func MergeFlags(flags *Config) {
 			DiceConfig.WebSocket.Enabled = flags.WebSocket.Enabled
 		case "websocket-port":
 			DiceConfig.WebSocket.Port = flags.WebSocket.Port
+		case "enable-memcached":
+			DiceConfig.Memcached.Enabled = flags.Memcached.Enabled
+		case "memcached-port":
+			DiceConfig.Memcached.Port = flags.Memcached.Port
 		case "num-shards":
 			DiceConfig.Performance.NumShards = flags.Performance.NumShards
 		case "enable-watch":
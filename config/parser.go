@@ -80,6 +80,10 @@ func (p *ConfigParser) Loadconfig(cfg interface{}) error {
 		return fmt.Errorf("failed to validate config: %w", err)
 	}
 
+	// The per-command timeouts are looked up for every command, so they are parsed once here. They
+	// parse, having been validated.
+	DiceConfig.Performance.CommandTimeoutsByName, _ = ParseCommandTimeouts(DiceConfig.Performance.CommandTimeouts)
+
 	return nil
 }
 
@@ -16,6 +16,7 @@ func validateConfig(config *Config) error {
 	validate := validator.New()
 	validate.RegisterStructValidation(validateShardCount, Config{})
 	validate.RegisterStructValidation(validateWALConfig, Config{})
+	validate.RegisterStructValidation(validateRequestTimeouts, Config{})
 
 	if err := validate.Struct(config); err != nil {
 		validationErrors, ok := err.(validator.ValidationErrors)
@@ -50,6 +51,17 @@ func validateShardCount(sl validator.StructLevel) {
 	}
 }
 
+func validateRequestTimeouts(sl validator.StructLevel) {
+	config := sl.Current().Interface().(Config)
+	if config.Performance.RequestTimeout <= 0 {
+		sl.ReportError(config.Performance.RequestTimeout, "Performance.RequestTimeout", "RequestTimeout", "gt", "must be greater than 0")
+	}
+
+	if _, err := ParseCommandTimeouts(config.Performance.CommandTimeouts); err != nil {
+		sl.ReportError(config.Performance.CommandTimeouts, "Performance.CommandTimeouts", "CommandTimeouts", "invalidValue", err.Error())
+	}
+}
+
 func applyDefaultValuesFromTags(config *Config, fieldName string) error {
 	configType := reflect.TypeOf(config).Elem()
 	configValue := reflect.ValueOf(config).Elem()
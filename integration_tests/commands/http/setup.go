@@ -118,7 +118,7 @@ func RunHTTPServer(ctx context.Context, wg *sync.WaitGroup, opt TestServerOption
 
 	config.DiceConfig.HTTP.Port = opt.Port
 	// Initialize the HTTPServer
-	testServer := httpws.NewHTTPServer(shardManager, nil)
+	testServer := httpws.NewHTTPServer(shardManager, nil, globalErrChannel, nil)
 	// Inform the user that the server is starting
 	fmt.Println("Starting the test server on port", config.DiceConfig.HTTP.Port)
 	shardManagerCtx, cancelShardManager := context.WithCancel(ctx)
@@ -0,0 +1,121 @@
+// Copyright (c) 2022-present, DiceDB contributors
+// All rights reserved. Licensed under the BSD 3-Clause License. See LICENSE file in the project root for full license information.
+
+package resp
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHEXPIRE(t *testing.T) {
+	conn := getLocalConnection()
+	defer conn.Close()
+
+	testCases := []struct {
+		name   string
+		cmds   []string
+		expect []interface{}
+		delays []time.Duration
+	}{
+		{
+			name:   "HEXPIRE with wrong number of args",
+			cmds:   []string{"HEXPIRE key 10 FIELDS 1"},
+			expect: []interface{}{"ERR wrong number of arguments for 'hexpire' command"},
+			delays: []time.Duration{0},
+		},
+		{
+			name:   "HEXPIRE with non-existent key",
+			cmds:   []string{"HEXPIRE key_hExpire 10 FIELDS 1 field"},
+			expect: []interface{}{[]interface{}{int64(-2)}},
+			delays: []time.Duration{0},
+		},
+		{
+			name:   "HEXPIRE with non-hash",
+			cmds:   []string{"SET key_hExpire value", "HEXPIRE key_hExpire 10 FIELDS 1 field"},
+			expect: []interface{}{"OK", "WRONGTYPE Operation against a key holding the wrong kind of value"},
+			delays: []time.Duration{0, 0},
+		},
+		{
+			name: "HEXPIRE with conditions",
+			cmds: []string{
+				"HSET key_hExpire f1 v1 f2 v2",
+				"HEXPIRE key_hExpire 100 NX FIELDS 3 f1 f2 f3",
+				"HEXPIRE key_hExpire 200 NX FIELDS 1 f1",
+				"HEXPIRE key_hExpire 50 GT FIELDS 1 f1",
+				"HEXPIRE key_hExpire 50 LT FIELDS 1 f1",
+				"HEXPIRE key_hExpire 60 LT FIELDS 1 f1",
+			},
+			expect: []interface{}{
+				int64(2),
+				[]interface{}{int64(1), int64(1), int64(-2)},
+				[]interface{}{int64(0)},
+				[]interface{}{int64(0)},
+				[]interface{}{int64(1)},
+				[]interface{}{int64(0)},
+			},
+			delays: []time.Duration{0, 0, 0, 0, 0, 0},
+		},
+		{
+			name: "HPEXPIRE expires the fields and then the hash",
+			cmds: []string{
+				"HSET key_hExpire f1 v1 f2 v2",
+				"HPEXPIRE key_hExpire 1000 FIELDS 1 f1",
+				"HGETALL key_hExpire",
+				"HPEXPIRE key_hExpire 1000 FIELDS 1 f2",
+				"HLEN key_hExpire",
+				"EXISTS key_hExpire",
+			},
+			expect: []interface{}{
+				int64(2),
+				[]interface{}{int64(1)},
+				[]interface{}{"f2", "v2"},
+				[]interface{}{int64(1)},
+				int64(0),
+				int64(0),
+			},
+			delays: []time.Duration{0, 0, 1100 * time.Millisecond, 0, 1100 * time.Millisecond, 0},
+		},
+		{
+			name: "HPERSIST removes the expiry",
+			cmds: []string{
+				"HSET key_hExpire f1 v1 f2 v2",
+				"HEXPIRE key_hExpire 100 FIELDS 1 f1",
+				"HPERSIST key_hExpire FIELDS 3 f1 f2 f3",
+				"HTTL key_hExpire FIELDS 1 f1",
+			},
+			expect: []interface{}{
+				int64(2),
+				[]interface{}{int64(1)},
+				[]interface{}{int64(1), int64(-1), int64(-2)},
+				[]interface{}{int64(-1)},
+			},
+			delays: []time.Duration{0, 0, 0, 0},
+		},
+		{
+			name: "HEXPIRE with a past time deletes the fields",
+			cmds: []string{
+				"HSET key_hExpire f1 v1",
+				"HEXPIREAT key_hExpire 1 FIELDS 1 f1",
+				"EXISTS key_hExpire",
+			},
+			expect: []interface{}{int64(1), []interface{}{int64(2)}, int64(0)},
+			delays: []time.Duration{0, 0, 0},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			FireCommand(conn, "DEL key_hExpire")
+			for i, cmd := range tc.cmds {
+				if tc.delays[i] > 0 {
+					time.Sleep(tc.delays[i])
+				}
+				result := FireCommand(conn, cmd)
+				assert.Equal(t, tc.expect[i], result, "Value mismatch for cmd %s", cmd)
+			}
+		})
+	}
+}
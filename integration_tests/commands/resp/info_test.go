@@ -0,0 +1,95 @@
+// Copyright (c) 2022-present, DiceDB contributors
+// All rights reserved. Licensed under the BSD 3-Clause License. See LICENSE file in the project root for full license information.
+
+package resp
+
+import (
+	"net"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// infoField returns the value of a field of the reply of INFO.
+func infoField(t *testing.T, conn net.Conn, section, name string) string {
+	info, ok := FireCommand(conn, "INFO "+section).(string)
+	require.True(t, ok)
+	for _, line := range strings.Split(info, "\r\n") {
+		if value, found := strings.CutPrefix(line, name+":"); found {
+			return value
+		}
+	}
+	require.Failf(t, "missing INFO field", "%s in %q", name, info)
+	return ""
+}
+
+func TestINFO(t *testing.T) {
+	conn := getLocalConnection()
+	defer conn.Close()
+
+	t.Run("INFO default sections", func(t *testing.T) {
+		info, ok := FireCommand(conn, "INFO").(string)
+		require.True(t, ok)
+		assert.True(t, strings.HasPrefix(info, "# Server\r\n"))
+		for _, header := range []string{"Clients", "Memory", "Persistence", "Stats", "Replication", "CPU", "Keyspace"} {
+			assert.Contains(t, info, "\r\n# "+header+"\r\n")
+		}
+		assert.NotContains(t, info, "# Commandstats")
+
+		all, ok := FireCommand(conn, "INFO all").(string)
+		require.True(t, ok)
+		assert.Contains(t, all, "\r\n# Commandstats\r\n")
+	})
+
+	t.Run("INFO stats", func(t *testing.T) {
+		info, ok := FireCommand(conn, "INFO STATS").(string)
+		require.True(t, ok)
+		assert.True(t, strings.HasPrefix(info, "# Stats\r\n"))
+		for _, field := range []string{"total_connections_received:", "total_commands_processed:", "keyspace_hits:",
+			"keyspace_misses:", "expired_keys:", "expired_stale_perc:", "expire_cycle_cpu_milliseconds:", "evicted_keys:",
+			"shard0:keyspace_hits="} {
+			assert.Contains(t, info, field)
+		}
+	})
+
+	t.Run("INFO server and clients", func(t *testing.T) {
+		assert.NotEmpty(t, infoField(t, conn, "server", "dice_version"))
+		assert.NotEqual(t, "0", infoField(t, conn, "server", "process_id"))
+		connected, err := strconv.Atoi(infoField(t, conn, "clients", "connected_clients"))
+		require.NoError(t, err)
+		assert.GreaterOrEqual(t, connected, 1)
+		assert.Equal(t, "master", infoField(t, conn, "replication", "role"))
+	})
+
+	t.Run("INFO keyspace and commandstats", func(t *testing.T) {
+		FireCommand(conn, "FLUSHDB")
+		FireCommand(conn, "SET info_key1 value")
+		FireCommand(conn, "SET info_key2 value EX 100")
+		defer FireCommand(conn, "DEL info_key1 info_key2")
+
+		assert.Equal(t, "keys=2,expires=1", infoField(t, conn, "keyspace", "db0"))
+		assert.Regexp(t, `^calls=\d+,usec=\d+,usec_per_call=[\d.]+,failed_calls=\d+$`, infoField(t, conn, "commandstats", "cmdstat_set"))
+	})
+
+	t.Run("INFO keyspace hits and misses", func(t *testing.T) {
+		FireCommand(conn, "SET info_key value")
+		defer FireCommand(conn, "DEL info_key")
+
+		hits, err := strconv.Atoi(infoField(t, conn, "stats", "keyspace_hits"))
+		require.NoError(t, err)
+		misses, err := strconv.Atoi(infoField(t, conn, "stats", "keyspace_misses"))
+		require.NoError(t, err)
+
+		FireCommand(conn, "GET info_key")
+		FireCommand(conn, "GET info_missing")
+		assert.Equal(t, strconv.Itoa(hits+1), infoField(t, conn, "stats", "keyspace_hits"))
+		assert.Equal(t, strconv.Itoa(misses+1), infoField(t, conn, "stats", "keyspace_misses"))
+	})
+
+	t.Run("INFO unknown section", func(t *testing.T) {
+		assert.Equal(t, "", FireCommand(conn, "INFO unknown"))
+	})
+}
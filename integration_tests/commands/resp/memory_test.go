@@ -0,0 +1,59 @@
+// Copyright (c) 2022-present, DiceDB contributors
+// All rights reserved. Licensed under the BSD 3-Clause License. See LICENSE file in the project root for full license information.
+
+package resp
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMEMORY(t *testing.T) {
+	conn := getLocalConnection()
+	defer conn.Close()
+
+	keys := "memory_small memory_large memory_hash"
+	FireCommand(conn, "DEL "+keys)
+	defer FireCommand(conn, "DEL "+keys)
+
+	t.Run("MEMORY USAGE", func(t *testing.T) {
+		FireCommand(conn, "SET memory_small "+strings.Repeat("a", 10))
+		FireCommand(conn, "SET memory_large "+strings.Repeat("a", 110))
+		FireCommand(conn, "HSET memory_hash f1 v1 f2 v2")
+
+		small, ok := FireCommand(conn, "MEMORY USAGE memory_small").(int64)
+		require.True(t, ok)
+		large, ok := FireCommand(conn, "MEMORY USAGE memory_large SAMPLES 0").(int64)
+		require.True(t, ok)
+		// The keys have the same length, so they differ by the length of their values
+		assert.Equal(t, int64(100), large-small)
+
+		hash, ok := FireCommand(conn, "MEMORY USAGE memory_hash").(int64)
+		require.True(t, ok)
+		assert.Greater(t, hash, int64(0))
+
+		assert.Equal(t, "(nil)", FireCommand(conn, "MEMORY USAGE memory_missing"))
+	})
+
+	t.Run("MEMORY STATS", func(t *testing.T) {
+		stats, ok := FireCommand(conn, "MEMORY STATS").([]interface{})
+		require.True(t, ok)
+		require.Len(t, stats, 10)
+		assert.Equal(t, []interface{}{"keys.count", "dataset.bytes", "keys.bytes-per-key", "types", "shards"},
+			[]interface{}{stats[0], stats[2], stats[4], stats[6], stats[8]})
+		assert.GreaterOrEqual(t, stats[1], int64(3))
+		assert.Contains(t, stats[7], "hash")
+		assert.Contains(t, stats[7], "string")
+	})
+
+	t.Run("MEMORY errors", func(t *testing.T) {
+		assert.Equal(t, "ERR wrong number of arguments for 'memory' command", FireCommand(conn, "MEMORY"))
+		assert.Equal(t, "ERR wrong number of arguments for 'memory|usage' command", FireCommand(conn, "MEMORY USAGE"))
+		assert.Equal(t, "ERR syntax error", FireCommand(conn, "MEMORY USAGE memory_small COUNT 1"))
+		assert.Equal(t, "ERR value is not an integer or out of range", FireCommand(conn, "MEMORY USAGE memory_small SAMPLES -1"))
+		assert.Equal(t, "ERR unknown subcommand 'DOCTOR'. Try MEMORY HELP.", FireCommand(conn, "MEMORY DOCTOR"))
+	})
+}
@@ -31,6 +31,14 @@ func TestObjectCommand(t *testing.T) {
 			delay:      []time.Duration{0, 2 * time.Second, 3 * time.Second, 0, 0},
 			cleanup:    []string{"DEL foo"},
 		},
+		{
+			name:       "Object Tier",
+			commands:   []string{"SET foo bar", "OBJECT TIER foo", "OBJECT TIER missing"},
+			expected:   []interface{}{"OK", "memory", "(nil)"},
+			assertType: []string{"equal", "equal", "equal"},
+			delay:      []time.Duration{0, 0, 0},
+			cleanup:    []string{"DEL foo"},
+		},
 	}
 
 	for _, tc := range testCases {
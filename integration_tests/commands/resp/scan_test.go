@@ -0,0 +1,80 @@
+// Copyright (c) 2022-present, DiceDB contributors
+// All rights reserved. Licensed under the BSD 3-Clause License. See LICENSE file in the project root for full license information.
+
+package resp
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSCAN(t *testing.T) {
+	conn := getLocalConnection()
+	defer conn.Close()
+	FireCommand(conn, "FLUSHDB")
+	defer FireCommand(conn, "FLUSHDB")
+
+	expected := make([]interface{}, 0, 100)
+	for i := 0; i < 100; i++ {
+		key := fmt.Sprintf("scan_key:%d", i)
+		assert.Equal(t, "OK", FireCommand(conn, fmt.Sprintf("SET %s v", key)))
+		expected = append(expected, key)
+	}
+	assert.Equal(t, int64(1), FireCommand(conn, "SADD scan_set m"))
+
+	scan := func(args string) []interface{} {
+		var keys []interface{}
+		cursor := "0"
+		for page := 0; ; page++ {
+			require.Less(t, page, 1000, "the scan does not terminate")
+			result, ok := FireCommand(conn, fmt.Sprintf("SCAN %s %s", cursor, args)).([]interface{})
+			require.True(t, ok)
+			keys = append(keys, result[1].([]interface{})...)
+			if cursor = result[0].(string); cursor == "0" {
+				return keys
+			}
+		}
+	}
+
+	t.Run("SCAN returns every key", func(t *testing.T) {
+		assert.ElementsMatch(t, expected, scan("MATCH scan_key:* COUNT 7"))
+	})
+
+	t.Run("SCAN with TYPE argument", func(t *testing.T) {
+		assert.ElementsMatch(t, []interface{}{"scan_set"}, scan("TYPE set"))
+	})
+
+	t.Run("SCAN with invalid arguments", func(t *testing.T) {
+		assert.Equal(t, "ERR wrong number of arguments for 'scan' command", FireCommand(conn, "SCAN"))
+		assert.Equal(t, "ERR invalid cursor", FireCommand(conn, "SCAN abc"))
+		assert.Equal(t, "ERR value is not an integer or out of range", FireCommand(conn, "SCAN 0 COUNT 0"))
+		assert.Equal(t, "ERR syntax error", FireCommand(conn, "SCAN 0 MATCH"))
+	})
+}
+
+func TestSSCAN(t *testing.T) {
+	conn := getLocalConnection()
+	defer conn.Close()
+	FireCommand(conn, "FLUSHDB")
+
+	assert.Equal(t, int64(3), FireCommand(conn, "SADD key_sScan member2 member1 other"))
+	assert.Equal(t, []interface{}{"0", []interface{}{"member1", "member2", "other"}}, FireCommand(conn, "SSCAN key_sScan 0"))
+	assert.Equal(t, []interface{}{"1", []interface{}{"member1"}}, FireCommand(conn, "SSCAN key_sScan 0 MATCH member* COUNT 1"))
+	assert.Equal(t, []interface{}{"0", []interface{}{}}, FireCommand(conn, "SSCAN non_existent_key 0"))
+	assert.Equal(t, "ERR wrong number of arguments for 'sscan' command", FireCommand(conn, "SSCAN key_sScan"))
+}
+
+func TestZSCAN(t *testing.T) {
+	conn := getLocalConnection()
+	defer conn.Close()
+	FireCommand(conn, "FLUSHDB")
+
+	assert.Equal(t, int64(2), FireCommand(conn, "ZADD key_zScan 2 member1 1.5 member2"))
+	assert.Equal(t, []interface{}{"0", []interface{}{"member1", "2", "member2", "1.5"}}, FireCommand(conn, "ZSCAN key_zScan 0"))
+	assert.Equal(t, []interface{}{"1", []interface{}{"member1", "2"}}, FireCommand(conn, "ZSCAN key_zScan 0 COUNT 1"))
+	assert.Equal(t, "OK", FireCommand(conn, "SET string_key string_value"))
+	assert.Equal(t, "WRONGTYPE Operation against a key holding the wrong kind of value", FireCommand(conn, "ZSCAN string_key 0"))
+}
@@ -0,0 +1,39 @@
+// Copyright (c) 2022-present, DiceDB contributors
+// All rights reserved. Licensed under the BSD 3-Clause License. See LICENSE file in the project root for full license information.
+
+package resp
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSELECT(t *testing.T) {
+	conn := getLocalConnection()
+	defer conn.Close()
+	other := getLocalConnection()
+	defer other.Close()
+
+	FireCommand(conn, "FLUSHDB")
+	defer FireCommand(conn, "FLUSHDB")
+
+	assert.Equal(t, "OK", FireCommand(conn, "SET select_key v"))
+	assert.Equal(t, "OK", FireCommand(other, "SELECT 1"))
+	assert.Equal(t, "OK", FireCommand(other, "FLUSHDB"))
+	assert.Equal(t, "(nil)", FireCommand(other, "GET select_key"))
+	assert.Equal(t, int64(0), FireCommand(other, "DBSIZE"))
+
+	assert.Equal(t, int64(1), FireCommand(conn, "MOVE select_key 1"))
+	assert.Equal(t, "(nil)", FireCommand(conn, "GET select_key"))
+	assert.Equal(t, "v", FireCommand(other, "GET select_key"))
+	assert.Equal(t, []interface{}{"select_key"}, FireCommand(other, "KEYS *"))
+
+	assert.Equal(t, "OK", FireCommand(conn, "SWAPDB 0 1"))
+	assert.Equal(t, "v", FireCommand(conn, "GET select_key"))
+	assert.Equal(t, "(nil)", FireCommand(other, "GET select_key"))
+
+	assert.Equal(t, "ERR DB index is out of range", FireCommand(conn, "SELECT 16"))
+	assert.Equal(t, "ERR source and destination objects are the same", FireCommand(conn, "MOVE select_key 0"))
+	assert.Equal(t, "ERR wrong number of arguments for 'select' command", FireCommand(conn, "SELECT"))
+}
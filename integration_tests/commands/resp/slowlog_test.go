@@ -0,0 +1,72 @@
+// Copyright (c) 2022-present, DiceDB contributors
+// All rights reserved. Licensed under the BSD 3-Clause License. See LICENSE file in the project root for full license information.
+
+package resp
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/dicedb/dice/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSLOWLOG(t *testing.T) {
+	conn := getLocalConnection()
+	defer conn.Close()
+
+	threshold := config.DiceConfig.Performance.SlowlogLogSlowerThan
+	defer func() { config.DiceConfig.Performance.SlowlogLogSlowerThan = threshold }()
+
+	FireCommand(conn, "DEL slowlog_key")
+	defer FireCommand(conn, "DEL slowlog_key")
+
+	t.Run("SLOWLOG GET", func(t *testing.T) {
+		config.DiceConfig.Performance.SlowlogLogSlowerThan = 0
+		assert.Equal(t, "OK", FireCommand(conn, "SLOWLOG RESET"))
+		assert.Equal(t, "OK", FireCommand(conn, "CLIENT SETNAME slowlog-client"))
+		assert.Equal(t, "slowlog-client", FireCommand(conn, "CLIENT GETNAME"))
+
+		FireCommand(conn, "SET slowlog_key value")
+		FireCommand(conn, "GET slowlog_key")
+		config.DiceConfig.Performance.SlowlogLogSlowerThan = -1
+		assert.Equal(t, int64(2), FireCommand(conn, "SLOWLOG LEN"))
+
+		entries, ok := FireCommand(conn, "SLOWLOG GET 1").([]interface{})
+		require.True(t, ok)
+		require.Len(t, entries, 1)
+		entry, ok := entries[0].([]interface{})
+		require.True(t, ok)
+		require.Len(t, entry, 6)
+		assert.Greater(t, entry[1], int64(0))
+		assert.GreaterOrEqual(t, entry[2], int64(0))
+		assert.Equal(t, []interface{}{"GET", "slowlog_key"}, entry[3])
+		assert.True(t, strings.HasPrefix(entry[4].(string), "127.0.0.1:"), entry[4])
+		assert.Equal(t, "slowlog-client", entry[5])
+
+		// The entries are listed from the most recent one
+		entries, ok = FireCommand(conn, "SLOWLOG GET").([]interface{})
+		require.True(t, ok)
+		require.Len(t, entries, 2)
+		assert.Equal(t, []interface{}{"SET", "slowlog_key", "value"}, entries[1].([]interface{})[3])
+		assert.Greater(t, entries[0].([]interface{})[0], entries[1].([]interface{})[0])
+	})
+
+	t.Run("SLOWLOG threshold", func(t *testing.T) {
+		config.DiceConfig.Performance.SlowlogLogSlowerThan = 10000000
+		assert.Equal(t, "OK", FireCommand(conn, "SLOWLOG RESET"))
+		FireCommand(conn, "GET slowlog_key")
+		assert.Equal(t, int64(0), FireCommand(conn, "SLOWLOG LEN"))
+		assert.Equal(t, []interface{}{}, FireCommand(conn, "SLOWLOG GET"))
+	})
+
+	t.Run("SLOWLOG errors", func(t *testing.T) {
+		assert.Equal(t, "ERR wrong number of arguments for 'slowlog' command", FireCommand(conn, "SLOWLOG"))
+		assert.Equal(t, "ERR count should be greater than or equal to -1", FireCommand(conn, "SLOWLOG GET -2"))
+		assert.Equal(t, "ERR value is not an integer or out of range", FireCommand(conn, "SLOWLOG GET all"))
+		assert.Equal(t, "ERR unknown subcommand 'DOCTOR'. Try SLOWLOG HELP.", FireCommand(conn, "SLOWLOG DOCTOR"))
+		assert.Equal(t, "ERR Client names cannot contain spaces, newlines or special characters.",
+			FireCommand(conn, "CLIENT SETNAME \"slow client\""))
+	})
+}
@@ -0,0 +1,85 @@
+// Copyright (c) 2022-present, DiceDB contributors
+// All rights reserved. Licensed under the BSD 3-Clause License. See LICENSE file in the project root for full license information.
+
+package resp
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSORT(t *testing.T) {
+	conn := getLocalConnection()
+	defer conn.Close()
+
+	setup := []string{
+		"RPUSH sort_ids 3 1 2",
+		"SET sort_weight_1 30", "SET sort_weight_2 10", "SET sort_weight_3 20",
+		"HSET sort_user_1 name alice", "HSET sort_user_2 name carol", "HSET sort_user_3 name bob",
+	}
+	keys := "sort_ids sort_weight_1 sort_weight_2 sort_weight_3 sort_user_1 sort_user_2 sort_user_3 sort_dest"
+
+	testCases := []struct {
+		name     string
+		commands []string
+		expected []interface{}
+	}{
+		{
+			name:     "SORT numerically",
+			commands: []string{"SORT sort_ids", "SORT sort_ids DESC LIMIT 0 2"},
+			expected: []interface{}{
+				[]interface{}{"1", "2", "3"},
+				[]interface{}{"3", "2"},
+			},
+		},
+		{
+			name:     "SORT BY keys and hash fields",
+			commands: []string{"SORT sort_ids BY sort_weight_*", "SORT sort_ids BY sort_user_*->name ALPHA", "SORT sort_ids BY nosort"},
+			expected: []interface{}{
+				[]interface{}{"2", "3", "1"},
+				[]interface{}{"1", "3", "2"},
+				[]interface{}{"3", "1", "2"},
+			},
+		},
+		{
+			name:     "SORT with GET",
+			commands: []string{"SORT_RO sort_ids BY sort_weight_* GET # GET sort_user_*->name GET sort_missing_*"},
+			expected: []interface{}{
+				[]interface{}{"2", "carol", "(nil)", "3", "bob", "(nil)", "1", "alice", "(nil)"},
+			},
+		},
+		{
+			name:     "SORT with STORE",
+			commands: []string{"SORT sort_ids BY sort_weight_* DESC GET sort_user_*->name STORE sort_dest", "LRANGE sort_dest 0 -1"},
+			expected: []interface{}{
+				int64(3),
+				[]interface{}{"alice", "bob", "carol"},
+			},
+		},
+		{
+			name:     "SORT errors",
+			commands: []string{"SORT", "SORT_RO sort_ids STORE sort_dest", "SORT sort_weight_1", "SORT sort_ids BY sort_user_*->name"},
+			expected: []interface{}{
+				"ERR wrong number of arguments for 'sort' command",
+				"ERR syntax error",
+				"WRONGTYPE Operation against a key holding the wrong kind of value",
+				"ERR One or more scores can't be converted into double",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			FireCommand(conn, "DEL "+keys)
+			for _, cmd := range setup {
+				FireCommand(conn, cmd)
+			}
+			for i, cmd := range tc.commands {
+				result := FireCommand(conn, cmd)
+				assert.Equal(t, tc.expected[i], result, "Value mismatch for cmd %s", cmd)
+			}
+		})
+	}
+	FireCommand(conn, "DEL "+keys)
+}
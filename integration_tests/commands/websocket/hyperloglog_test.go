@@ -1,10 +1,6 @@
 // Copyright (c) 2022-present, DiceDB contributors
 // All rights reserved. Licensed under the BSD 3-Clause License. See LICENSE file in the project root for full license information.
 
-//go:build ignore
-// +build ignore
-
-// Ignored as multishard commands not supported by WS
 package websocket
 
 import (
@@ -117,7 +117,7 @@ func RunWebsocketServer(ctx context.Context, wg *sync.WaitGroup, opt TestServerO
 	globalErrChannel := make(chan error)
 	shardManager := shard.NewShardManager(1, nil, globalErrChannel)
 	config.DiceConfig.WebSocket.Port = opt.Port
-	testServer := httpws.NewWebSocketServer(shardManager, testPort1, nil)
+	testServer := httpws.NewWebSocketServer(shardManager, nil, testPort1, globalErrChannel, nil)
 	shardManagerCtx, cancelShardManager := context.WithCancel(ctx)
 
 	// run shard manager
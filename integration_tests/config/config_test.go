@@ -6,7 +6,9 @@ package config_test
 import (
 	"os"
 	"path/filepath"
+	"reflect"
 	"testing"
+	"time"
 
 	"github.com/dicedb/dice/config"
 )
@@ -100,3 +102,47 @@ func TestCreateConfigFile_ExistingDirectory(t *testing.T) {
 		t.Error("Config file was not created in existing directory")
 	}
 }
+
+// TestParseCommandTimeouts tests parsing of the per-command timeouts
+func TestParseCommandTimeouts(t *testing.T) {
+	timeouts, err := config.ParseCommandTimeouts("keys=30s, FLUSHDB=1m,SLEEP=0s,")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := map[string]time.Duration{
+		"KEYS":    30 * time.Second,
+		"FLUSHDB": time.Minute,
+		"SLEEP":   0,
+	}
+	if !reflect.DeepEqual(timeouts, expected) {
+		t.Errorf("Expected %v, got %v", expected, timeouts)
+	}
+
+	for _, value := range []string{"KEYS", "KEYS=abc", "=1s", "KEYS=-1s"} {
+		if _, err := config.ParseCommandTimeouts(value); err == nil {
+			t.Errorf("Expected error for %q", value)
+		}
+	}
+}
+
+// TestLoadCommandTimeouts tests that the per-command timeouts are parsed when the config is loaded
+func TestLoadCommandTimeouts(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), configFileName)
+	if err := os.WriteFile(configPath, []byte(`performance.command_timeouts = "keys=30s,SLEEP=0s"`), 0644); err != nil {
+		t.Fatalf("Failed to create test config file: %v", err)
+	}
+
+	parser := config.NewConfigParser()
+	if err := parser.ParseFromFile(configPath); err != nil {
+		t.Fatalf("Failed to parse test config file: %v", err)
+	}
+	if err := parser.Loadconfig(config.DiceConfig); err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+
+	expected := map[string]time.Duration{"KEYS": 30 * time.Second, "SLEEP": 0}
+	if !reflect.DeepEqual(config.DiceConfig.Performance.CommandTimeoutsByName, expected) {
+		t.Errorf("Expected %v, got %v", expected, config.DiceConfig.Performance.CommandTimeoutsByName)
+	}
+}
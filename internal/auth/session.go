@@ -6,6 +6,7 @@ package auth
 import (
 	"fmt"
 	"log/slog"
+	"strings"
 	"sync"
 	"time"
 
@@ -25,6 +26,10 @@ const (
 
 var (
 	UserStore = NewUsersStore()
+
+	// AdminCommands are the commands affecting the whole server rather than the keys of a client,
+	// which are refused to the users whose admin commands are denied.
+	AdminCommands = []string{"ABORT", "FLUSHDB", "SWAPDB"}
 )
 
 type (
@@ -33,6 +38,14 @@ type (
 		ID   uint64
 		User *User
 
+		// Database is the logical database selected by the client with SELECT.
+		Database int
+
+		// ClientAddr is the address the client connects from, and ClientName the name it set with
+		// CLIENT SETNAME, which SLOWLOG reports.
+		ClientAddr string
+		ClientName string
+
 		CreatedAt      time.Time
 		LastAccessedAt time.Time
 
@@ -48,6 +61,8 @@ type (
 		Username          string
 		Passwords         []string
 		IsPasswordEnabled bool
+
+		deniedCommands map[string]bool
 	}
 )
 
@@ -95,6 +110,61 @@ func (user *User) SetPassword(password string) (err error) {
 	return
 }
 
+// DenyCommands refuses the commands to the user.
+func (user *User) DenyCommands(cmds ...string) {
+	if user.deniedCommands == nil {
+		user.deniedCommands = make(map[string]bool, len(cmds))
+	}
+	for _, cmd := range cmds {
+		user.deniedCommands[strings.ToUpper(cmd)] = true
+	}
+}
+
+// DenyAdminCommands refuses the admin commands to the user.
+func (user *User) DenyAdminCommands() {
+	user.DenyCommands(AdminCommands...)
+}
+
+// Authorize returns an error if the command is refused to the user.
+func (user *User) Authorize(cmd string) error {
+	if user.deniedCommands[cmd] {
+		return fmt.Errorf("NOPERM User %s has no permissions to run the '%s' command", user.Username, strings.ToLower(cmd))
+	}
+	return nil
+}
+
+// InitDefaultUser adds the user configured with auth.username and auth.password to UserStore.
+func InitDefaultUser() error {
+	user, err := UserStore.Add(config.DiceConfig.Auth.UserName)
+	if err != nil {
+		return err
+	}
+	if err = user.SetPassword(config.DiceConfig.Auth.Password); err != nil {
+		return err
+	}
+	if config.DiceConfig.Auth.DenyAdminCommands {
+		user.DenyAdminCommands()
+	}
+	return nil
+}
+
+// Authenticate returns the user of UserStore matching the credentials.
+func Authenticate(username, password string) (*User, error) {
+	user, err := UserStore.Get(username)
+	if err != nil {
+		return nil, err
+	}
+	if username == config.DiceConfig.Auth.UserName && len(user.Passwords) == 0 {
+		return user, nil
+	}
+	for _, userPassword := range user.Passwords {
+		if bcrypt.CompareHashAndPassword([]byte(userPassword), []byte(password)) == nil {
+			return user, nil
+		}
+	}
+	return nil, fmt.Errorf("WRONGPASS invalid username-password pair or user is disabled")
+}
+
 func NewSession() (session *Session) {
 	session = &Session{
 		ID: uint64(utils.GetCurrentTime().UTC().Unix()),
@@ -109,7 +179,12 @@ func NewSession() (session *Session) {
 
 func (session *Session) IsActive() (isActive bool) {
 	if config.DiceConfig.Auth.Password == utils.EmptyStr && session.Status != SessionStatusActive {
-		session.Activate(session.User)
+		user := session.User
+		if user == nil {
+			// Without a password, clients act as the default user
+			user, _ = UserStore.Get(config.DiceConfig.Auth.UserName)
+		}
+		session.Activate(user)
 	}
 	isActive = session.Status == SessionStatusActive
 	if isActive {
@@ -126,25 +201,12 @@ func (session *Session) Activate(user *User) {
 }
 
 func (session *Session) Validate(username, password string) error {
-	var (
-		err  error
-		user *User
-	)
-	if user, err = UserStore.Get(username); err != nil {
+	user, err := Authenticate(username, password)
+	if err != nil {
 		return err
 	}
-	if username == config.DiceConfig.Auth.UserName && len(user.Passwords) == 0 {
-		session.Activate(user)
-		return nil
-	}
-	for _, userPassword := range user.Passwords {
-		if err = bcrypt.CompareHashAndPassword([]byte(userPassword), []byte(password)); err != nil {
-			continue
-		}
-		session.Activate(user)
-		return nil
-	}
-	return fmt.Errorf("WRONGPASS invalid username-password pair or user is disabled")
+	session.Activate(user)
+	return nil
 }
 
 func (session *Session) Expire() {
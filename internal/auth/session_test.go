@@ -152,3 +152,20 @@ func TestSessionExpire(t *testing.T) {
 		t.Errorf("Session.Expire() did not set status to Expired. Got %v, want %v", session.Status, SessionStatusExpired)
 	}
 }
+
+func TestUserDenyAdminCommands(t *testing.T) {
+	user := &User{Username: "reader"}
+	if err := user.Authorize("ABORT"); err != nil {
+		t.Errorf("User.Authorize() refused a command that is not denied: %v", err)
+	}
+
+	user.DenyAdminCommands()
+	for _, cmd := range AdminCommands {
+		if err := user.Authorize(cmd); err == nil {
+			t.Errorf("User.Authorize() did not refuse the admin command %s", cmd)
+		}
+	}
+	if err := user.Authorize("GET"); err != nil {
+		t.Errorf("User.Authorize() refused a command that is not denied: %v", err)
+	}
+}
@@ -36,6 +36,10 @@ func printConfiguration() {
 		slog.Info("running with", slog.Int("websocket-port", config.DiceConfig.WebSocket.Port))
 	}
 
+	if config.DiceConfig.Memcached.Enabled {
+		slog.Info("running with", slog.Int("memcached-port", config.DiceConfig.Memcached.Port))
+	}
+
 	// Add the number of CPU cores available on the machine
 	slog.Info("running with", slog.Int("cores", runtime.NumCPU()))
 
@@ -82,6 +86,9 @@ func Execute() {
 	flag.IntVar(&flagsConfig.WebSocket.Port, "websocket-port", 8379, "port for accepting requets over WebSocket")
 	flag.BoolVar(&flagsConfig.WebSocket.Enabled, "enable-websocket", false, "enable DiceDB to listen, accept, and process WebSocket")
 
+	flag.IntVar(&flagsConfig.Memcached.Port, "memcached-port", 11211, "port for accepting requests over the memcached text protocol")
+	flag.BoolVar(&flagsConfig.Memcached.Enabled, "enable-memcached", false, "enable DiceDB to listen, accept, and process the memcached text protocol")
+
 	flag.IntVar(&flagsConfig.Performance.NumShards, "num-shards", -1, "number shards to create. defaults to number of cores")
 
 	flag.BoolVar(&flagsConfig.Performance.EnableWatch, "enable-watch", false, "enable support for .WATCH commands and real-time reactivity")
@@ -122,6 +129,8 @@ func Execute() {
 		fmt.Println("  -enable-http           Enable DiceDB to listen, accept, and process HTTP (default: false)")
 		fmt.Println("  -websocket-port        Port for accepting requests over WebSocket (default: 8379)")
 		fmt.Println("  -enable-websocket      Enable DiceDB to listen, accept, and process WebSocket (default: false)")
+		fmt.Println("  -memcached-port        Port for accepting requests over the memcached text protocol (default: 11211)")
+		fmt.Println("  -enable-memcached      Enable DiceDB to listen, accept, and process the memcached text protocol (default: false)")
 		fmt.Println("  -num-shards            Number of shards to create. Defaults to number of cores (default: -1)")
 		fmt.Println("  -enable-watch          Enable support for .WATCH commands and real-time reactivity (default: false)")
 		fmt.Println("  -enable-profiling      Enable profiling and capture critical metrics and traces in .prof files (default: false)")
@@ -217,6 +226,7 @@ func Execute() {
 				if err := parser.Loadconfig(config.DiceConfig); err != nil {
 					log.Fatal(err)
 				}
+				config.ConfigFilePath = filePath
 
 				config.MergeFlags(&flagsConfig)
 				render()
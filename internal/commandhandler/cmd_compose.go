@@ -6,9 +6,12 @@ package commandhandler
 import (
 	"math"
 	"sort"
+	"strconv"
 
 	"github.com/dicedb/dice/internal/clientio"
+	"github.com/dicedb/dice/internal/eval"
 	"github.com/dicedb/dice/internal/ops"
+	dstore "github.com/dicedb/dice/internal/store"
 )
 
 // This file contains functions used by the CommandHandler to handle and process responses
@@ -240,6 +243,14 @@ func composeKeys(responses ...ops.StoreResponse) interface{} {
 	return results
 }
 
+func composeScan(responses ...ops.StoreResponse) interface{} {
+	if responses[0].EvalResponse.Error != nil {
+		return responses[0].EvalResponse.Error
+	}
+
+	return responses[0].EvalResponse.Result
+}
+
 func composeFlushDB(responses ...ops.StoreResponse) interface{} {
 	for idx := range responses {
 		if responses[idx].EvalResponse.Error != nil {
@@ -250,6 +261,133 @@ func composeFlushDB(responses ...ops.StoreResponse) interface{} {
 	return clientio.OK
 }
 
+// composeSwapDB returns "OK" once every shard has swapped the databases.
+func composeSwapDB(responses ...ops.StoreResponse) interface{} {
+	for idx := range responses {
+		if responses[idx].EvalResponse.Error != nil {
+			return responses[idx].EvalResponse.Error
+		}
+	}
+
+	return clientio.OK
+}
+
+// composeMemory merges the replies of the shards to MEMORY. USAGE replies with the size of the key
+// from the shard owning it, and STATS with the stats of the keys of all the shards, per type and
+// per shard.
+func composeMemory(responses ...ops.StoreResponse) interface{} {
+	sort.Slice(responses, func(i, j int) bool {
+		return responses[i].SeqID < responses[j].SeqID
+	})
+
+	var usage interface{} = clientio.NIL
+	var total *eval.MemoryShardStats
+	shards := make([]interface{}, 0, 2*len(responses))
+	for idx := range responses {
+		if responses[idx].EvalResponse.Error != nil {
+			return responses[idx].EvalResponse.Error
+		}
+
+		switch result := responses[idx].EvalResponse.Result.(type) {
+		case int64:
+			usage = result
+		case *eval.MemoryShardStats:
+			if total == nil {
+				total = &eval.MemoryShardStats{Types: make(map[string]eval.MemoryUsageStats)}
+			}
+			total.Keys += result.Keys
+			total.Bytes += result.Bytes
+			for name, stats := range result.Types {
+				typeStats := total.Types[name]
+				typeStats.Keys += stats.Keys
+				typeStats.Bytes += stats.Bytes
+				total.Types[name] = typeStats
+			}
+			shards = append(shards, strconv.Itoa(int(responses[idx].SeqID)), memoryUsageReply(result.MemoryUsageStats))
+		}
+	}
+
+	if total == nil {
+		return usage
+	}
+
+	names := make([]string, 0, len(total.Types))
+	for name := range total.Types {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	types := make([]interface{}, 0, 2*len(names))
+	for _, name := range names {
+		types = append(types, name, memoryUsageReply(total.Types[name]))
+	}
+
+	bytesPerKey := int64(0)
+	if total.Keys > 0 {
+		bytesPerKey = total.Bytes / total.Keys
+	}
+	return []interface{}{
+		"keys.count", total.Keys,
+		"dataset.bytes", total.Bytes,
+		"keys.bytes-per-key", bytesPerKey,
+		"types", types,
+		"shards", shards,
+	}
+}
+
+// memoryUsageReply returns the reply of MEMORY STATS for the usage of a group of keys.
+func memoryUsageReply(stats eval.MemoryUsageStats) []interface{} {
+	return []interface{}{"keys.count", stats.Keys, "dataset.bytes", stats.Bytes}
+}
+
+// composeSlowLog merges the replies of the shards to SLOWLOG. GET replies with the most recent
+// entries of all the shards, in the order they were recorded, and LEN with the number of entries of
+// all the shards.
+func composeSlowLog(responses ...ops.StoreResponse) interface{} {
+	var reply interface{} = clientio.OK
+	var length int64
+	var get *eval.SlowLogShardEntries
+	var entries []dstore.SlowLogEntry
+	for idx := range responses {
+		if responses[idx].EvalResponse.Error != nil {
+			return responses[idx].EvalResponse.Error
+		}
+
+		switch result := responses[idx].EvalResponse.Result.(type) {
+		case int64:
+			length += result
+			reply = length
+		case *eval.SlowLogShardEntries:
+			get = result
+			entries = append(entries, result.Entries...)
+		default:
+			reply = result
+		}
+	}
+
+	if get == nil {
+		return reply
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].ID > entries[j].ID
+	})
+	if get.Count >= 0 && len(entries) > get.Count {
+		entries = entries[:get.Count]
+	}
+	entriesReply := make([]interface{}, len(entries))
+	for i := range entries {
+		entriesReply[i] = []interface{}{
+			int64(entries[i].ID),
+			entries[i].Time.Unix(),
+			entries[i].Duration.Microseconds(),
+			entries[i].Args,
+			entries[i].ClientAddr,
+			entries[i].ClientName,
+		}
+	}
+	return entriesReply
+}
+
 // composePFMerge processes responses from multiple shards for an "PFMerge" operation.
 // It loops through the responses to check if any shard returned an error.
 // If an error is detected, it immediately returns that error. Otherwise, it returns "OK"
@@ -263,3 +401,13 @@ func composePFMerge(responses ...ops.StoreResponse) interface{} {
 
 	return clientio.OK
 }
+
+// composeSort returns the response of the single shard which sorted the elements of a SORT or
+// SORT_RO, which is either the sorted elements or the number of elements stored.
+func composeSort(responses ...ops.StoreResponse) interface{} {
+	if responses[0].EvalResponse.Error != nil {
+		return responses[0].EvalResponse.Error
+	}
+
+	return responses[0].EvalResponse.Result
+}
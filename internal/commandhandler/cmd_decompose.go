@@ -6,12 +6,15 @@ package commandhandler
 import (
 	"context"
 	"log/slog"
+	"strconv"
 
 	"github.com/dicedb/dice/internal/clientio"
 	"github.com/dicedb/dice/internal/cmd"
 	diceerrors "github.com/dicedb/dice/internal/errors"
+	"github.com/dicedb/dice/internal/eval"
 	"github.com/dicedb/dice/internal/object"
 	"github.com/dicedb/dice/internal/ops"
+	"github.com/dicedb/dice/internal/shard"
 	"github.com/dicedb/dice/internal/store"
 )
 
@@ -150,6 +153,94 @@ func (h *BaseCommandHandler) decomposePFMerge(ctx context.Context, cd *cmd.DiceD
 	return decomposedCmds, nil
 }
 
+// decomposeSort resolves the BY and GET patterns of the SORT and SORT_RO commands across shards. It
+// waits for the elements of the sorted key and the lookups of its patterns, reads the lookups with
+// one SORTLOOKUP command per shard owning some of them, and sends the command along with what it
+// reads to the shard owning the sorted key, or the STORE destination if any.
+func (h *BaseCommandHandler) decomposeSort(ctx context.Context, cd *cmd.DiceDBCmd) ([]*cmd.DiceDBCmd, error) {
+	var input *eval.SortInput
+	select {
+	case <-ctx.Done():
+		slog.Error("CommandHandler timed out waiting for response from shards", slog.String("id", h.id), slog.Any("error", ctx.Err()))
+		return nil, ctx.Err()
+	case preProcessedResp, ok := <-h.preprocessingChan:
+		if !ok {
+			return nil, diceerrors.ErrInternalServer
+		}
+		if preProcessedResp.EvalResponse.Error != nil {
+			return nil, preProcessedResp.EvalResponse.Error
+		}
+		if input, ok = preProcessedResp.EvalResponse.Result.(*eval.SortInput); !ok {
+			return nil, diceerrors.ErrInternalServer
+		}
+	}
+
+	lookupArgs := make(map[shard.ShardID][]string)
+	for _, lookup := range input.Lookups {
+		sid, _ := h.shardManager.GetShardInfo(lookup.Key)
+		lookupArgs[sid] = append(lookupArgs[sid], lookup.Key, lookup.Field)
+	}
+	for sid, args := range lookupArgs {
+		err := sendPreProcessingOp(ctx, h.shardManager.GetShard(sid).ReqChan, &ops.StoreOp{
+			SeqID:         0,
+			RequestID:     GenerateUniqueRequestID(),
+			Cmd:           &cmd.DiceDBCmd{Cmd: store.SortLookup, Args: args},
+			CmdHandlerID:  h.id,
+			ShardID:       sid,
+			Database:      h.Session.Database,
+			Client:        nil,
+			Ctx:           ctx,
+			PreProcessing: true,
+		})
+		if err != nil {
+			slog.Error("CommandHandler timed out sending a command to shards", slog.String("id", h.id), slog.Any("error", err))
+			return nil, err
+		}
+	}
+
+	input.Values = make(map[eval.SortLookup]string, len(input.Lookups))
+	for range lookupArgs {
+		select {
+		case <-ctx.Done():
+			slog.Error("CommandHandler timed out waiting for response from shards", slog.String("id", h.id), slog.Any("error", ctx.Err()))
+			return nil, ctx.Err()
+		case preProcessedResp, ok := <-h.preprocessingChan:
+			if !ok {
+				return nil, diceerrors.ErrInternalServer
+			}
+			if preProcessedResp.EvalResponse.Error != nil {
+				return nil, preProcessedResp.EvalResponse.Error
+			}
+			values, ok := preProcessedResp.EvalResponse.Result.(map[eval.SortLookup]string)
+			if !ok {
+				return nil, diceerrors.ErrInternalServer
+			}
+			for lookup, value := range values {
+				input.Values[lookup] = value
+			}
+		}
+	}
+
+	internalObjs := []*object.InternalObj{{Obj: &object.Obj{Value: input}}}
+	if input.StoreKey != "" {
+		return []*cmd.DiceDBCmd{
+			{
+				Cmd:          store.SortStore,
+				Args:         append([]string{input.StoreKey}, cd.Args...),
+				InternalObjs: internalObjs,
+			},
+		}, nil
+	}
+
+	return []*cmd.DiceDBCmd{
+		{
+			Cmd:          cd.Cmd,
+			Args:         cd.Args,
+			InternalObjs: internalObjs,
+		},
+	}, nil
+}
+
 // decomposeMSet decomposes the MSET (Multi-set) command into individual SET commands.
 // It expects an even number of arguments (key-value pairs). For each pair, it creates
 // a separate SET command to store the value at the given key.
@@ -295,6 +386,32 @@ func (h *BaseCommandHandler) decomposeKeys(_ context.Context, cd *cmd.DiceDBCmd)
 	return decomposedCmds, nil
 }
 
+// decomposeScan turns SCAN into the internal SINGLESCAN command, which is routed to the shard the
+// cursor points to, and is also given the number of shards to move on to the next one once done.
+func (h *BaseCommandHandler) decomposeScan(_ context.Context, cd *cmd.DiceDBCmd) ([]*cmd.DiceDBCmd, error) {
+	if len(cd.Args) < 1 {
+		return nil, diceerrors.ErrWrongArgumentCount("SCAN")
+	}
+
+	cursor, err := strconv.ParseUint(cd.Args[0], 10, 64)
+	if err != nil {
+		return nil, diceerrors.ErrInvalidCursor
+	}
+	if shardID, _ := store.DecodeScanCursor(cursor); int(shardID) >= int(h.shardManager.GetShardCount()) {
+		return nil, diceerrors.ErrInvalidCursor
+	}
+
+	args := make([]string, 0, len(cd.Args)+1)
+	args = append(args, cd.Args[0], strconv.Itoa(int(h.shardManager.GetShardCount())))
+	args = append(args, cd.Args[1:]...)
+	return []*cmd.DiceDBCmd{
+		{
+			Cmd:  store.SingleShardScan,
+			Args: args,
+		},
+	}, nil
+}
+
 func (h *BaseCommandHandler) decomposeFlushDB(_ context.Context, cd *cmd.DiceDBCmd) ([]*cmd.DiceDBCmd, error) {
 	if len(cd.Args) > 1 {
 		return nil, diceerrors.ErrWrongArgumentCount("FLUSHDB")
@@ -311,3 +428,68 @@ func (h *BaseCommandHandler) decomposeFlushDB(_ context.Context, cd *cmd.DiceDBC
 	}
 	return decomposedCmds, nil
 }
+
+// decomposeSwapDB validates the database indexes of SWAPDB, which every shard then swaps in its
+// store, since each shard holds its own part of every database.
+func (h *BaseCommandHandler) decomposeSwapDB(_ context.Context, cd *cmd.DiceDBCmd) ([]*cmd.DiceDBCmd, error) {
+	if len(cd.Args) != 2 {
+		return nil, diceerrors.ErrWrongArgumentCount("SWAPDB")
+	}
+
+	for _, arg := range cd.Args {
+		db, err := strconv.Atoi(arg)
+		if err != nil {
+			return nil, diceerrors.ErrIntegerOutOfRange
+		}
+		if db < 0 || db >= store.DatabaseCount() {
+			return nil, diceerrors.ErrDBIndexOutOfRange
+		}
+	}
+
+	decomposedCmds := make([]*cmd.DiceDBCmd, 0, h.shardManager.GetShardCount())
+	for i := uint8(0); i < uint8(h.shardManager.GetShardCount()); i++ {
+		decomposedCmds = append(decomposedCmds,
+			&cmd.DiceDBCmd{
+				Cmd:  store.SwapDB,
+				Args: cd.Args,
+			},
+		)
+	}
+	return decomposedCmds, nil
+}
+
+// decomposeInfo sends INFO to every shard, to merge their statistics.
+func (h *BaseCommandHandler) decomposeInfo(_ context.Context, cd *cmd.DiceDBCmd) ([]*cmd.DiceDBCmd, error) {
+	decomposedCmds := make([]*cmd.DiceDBCmd, 0, h.shardManager.GetShardCount())
+	for i := uint8(0); i < uint8(h.shardManager.GetShardCount()); i++ {
+		decomposedCmds = append(decomposedCmds, cd)
+	}
+	return decomposedCmds, nil
+}
+
+// decomposeMemory sends MEMORY to every shard: only the shard owning the key of USAGE finds it,
+// while the stats of the shards are merged for STATS.
+func (h *BaseCommandHandler) decomposeMemory(_ context.Context, cd *cmd.DiceDBCmd) ([]*cmd.DiceDBCmd, error) {
+	if len(cd.Args) < 1 {
+		return nil, diceerrors.ErrWrongArgumentCount("MEMORY")
+	}
+
+	decomposedCmds := make([]*cmd.DiceDBCmd, 0, h.shardManager.GetShardCount())
+	for i := uint8(0); i < uint8(h.shardManager.GetShardCount()); i++ {
+		decomposedCmds = append(decomposedCmds, cd)
+	}
+	return decomposedCmds, nil
+}
+
+// decomposeSlowLog sends SLOWLOG to every shard, to merge their slowlogs.
+func (h *BaseCommandHandler) decomposeSlowLog(_ context.Context, cd *cmd.DiceDBCmd) ([]*cmd.DiceDBCmd, error) {
+	if len(cd.Args) < 1 {
+		return nil, diceerrors.ErrWrongArgumentCount("SLOWLOG")
+	}
+
+	decomposedCmds := make([]*cmd.DiceDBCmd, 0, h.shardManager.GetShardCount())
+	for i := uint8(0); i < uint8(h.shardManager.GetShardCount()); i++ {
+		decomposedCmds = append(decomposedCmds, cd)
+	}
+	return decomposedCmds, nil
+}
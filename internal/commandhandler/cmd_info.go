@@ -0,0 +1,356 @@
+// Copyright (c) 2022-present, DiceDB contributors
+// All rights reserved. Licensed under the BSD 3-Clause License. See LICENSE file in the project root for full license information.
+
+package commandhandler
+
+import (
+	"fmt"
+	"os"
+	"runtime"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/dicedb/dice/config"
+	"github.com/dicedb/dice/internal/clientio"
+	"github.com/dicedb/dice/internal/eval"
+	"github.com/dicedb/dice/internal/iothread"
+	"github.com/dicedb/dice/internal/ops"
+	dstore "github.com/dicedb/dice/internal/store"
+	"github.com/dicedb/dice/internal/wal"
+)
+
+// infoSection is a section of INFO, written from the replies of the shards ordered by shard. The
+// extra sections are only written when requested by name, or with all or everything.
+type infoSection struct {
+	name  string
+	extra bool
+	write func(b *strings.Builder, shards []*eval.InfoShardStats)
+}
+
+// infoSections lists the sections of INFO, in the order they are written.
+var infoSections = []infoSection{
+	{name: "server", write: writeInfoServer},
+	{name: "clients", write: writeInfoClients},
+	{name: "memory", write: writeInfoMemory},
+	{name: "persistence", write: writeInfoPersistence},
+	{name: "stats", write: writeInfoStats},
+	{name: "replication", write: writeInfoReplication},
+	{name: "cpu", write: writeInfoCPU},
+	{name: "commandstats", extra: true, write: writeInfoCommandStats},
+	{name: "keyspace", write: writeInfoKeyspace},
+}
+
+// composeInfo merges the statistics of the shards into the sections of INFO requested, or the
+// default ones. The sections are returned as a bulk string, with one field per line.
+func composeInfo(responses ...ops.StoreResponse) interface{} {
+	sort.Slice(responses, func(i, j int) bool {
+		return responses[i].SeqID < responses[j].SeqID
+	})
+
+	shards := make([]*eval.InfoShardStats, 0, len(responses))
+	for idx := range responses {
+		if responses[idx].EvalResponse.Error != nil {
+			return responses[idx].EvalResponse.Error
+		}
+		if stats, ok := responses[idx].EvalResponse.Result.(*eval.InfoShardStats); ok {
+			shards = append(shards, stats)
+		}
+	}
+	if len(shards) == 0 {
+		return clientio.Encode("", false)
+	}
+
+	var b strings.Builder
+	for _, section := range infoSections {
+		if !infoSectionRequested(shards[0].Sections, section) {
+			continue
+		}
+		if b.Len() > 0 {
+			b.WriteString("\r\n")
+		}
+		section.write(&b, shards)
+	}
+	return clientio.Encode(b.String(), false)
+}
+
+// infoSectionRequested returns whether the section is part of the requested ones, all the sections
+// but the extra ones being requested by default. The unknown sections are ignored.
+func infoSectionRequested(requested []string, section infoSection) bool {
+	if len(requested) == 0 {
+		return !section.extra
+	}
+	for _, name := range requested {
+		switch name {
+		case "all", "everything":
+			return true
+		case "default":
+			if !section.extra {
+				return true
+			}
+		}
+	}
+	return slices.Contains(requested, section.name)
+}
+
+// writeInfoFields writes the fields of a section after its header, each name separated from its
+// value by a colon.
+func writeInfoFields(b *strings.Builder, header string, fields ...string) {
+	b.WriteString("# " + header + "\r\n")
+	for _, field := range fields {
+		b.WriteString(field + "\r\n")
+	}
+}
+
+// writeInfoServer writes the server section: the version of DiceDB and how it runs.
+func writeInfoServer(b *strings.Builder, shards []*eval.InfoShardStats) {
+	var uptime time.Duration
+	if info := GetServerInfo(); !info.StartTime.IsZero() {
+		uptime = time.Since(info.StartTime)
+	}
+
+	writeInfoFields(b, "Server",
+		"dice_version:"+config.DiceDBVersion,
+		"os:"+runtime.GOOS+" "+runtime.GOARCH,
+		"arch_bits:"+strconv.Itoa(strconv.IntSize),
+		"go_version:"+runtime.Version(),
+		"process_id:"+strconv.Itoa(os.Getpid()),
+		"tcp_port:"+strconv.Itoa(config.DiceConfig.RespServer.Port),
+		"uptime_in_seconds:"+strconv.FormatInt(int64(uptime/time.Second), 10),
+		"uptime_in_days:"+strconv.FormatInt(int64(uptime/(24*time.Hour)), 10),
+		"shards:"+strconv.Itoa(len(shards)),
+		"config_file:"+config.ConfigFilePath,
+	)
+}
+
+// writeInfoClients writes the clients section: the clients connected to the RESP server.
+func writeInfoClients(b *strings.Builder, _ []*eval.InfoShardStats) {
+	var connected uint32
+	maxClients := config.DiceConfig.Performance.MaxClients
+	if ioThreads := GetServerInfo().IOThreads; ioThreads != nil {
+		connected, maxClients = ioThreads.IOThreadCount(), ioThreads.MaxClients()
+	}
+
+	writeInfoFields(b, "Clients",
+		"connected_clients:"+strconv.FormatUint(uint64(connected), 10),
+		"maxclients:"+strconv.FormatUint(uint64(maxClients), 10),
+	)
+}
+
+// writeInfoMemory writes the memory section: the estimated memory used by the keys of all the
+// shards, and the memory the Go runtime got from the system.
+func writeInfoMemory(b *strings.Builder, shards []*eval.InfoShardStats) {
+	var used int64
+	for _, shard := range shards {
+		used += shard.UsedMemory
+	}
+	var mem runtime.MemStats
+	runtime.ReadMemStats(&mem)
+	maxMemory := config.DiceConfig.Memory.MaxMemory
+
+	writeInfoFields(b, "Memory",
+		"used_memory:"+strconv.FormatInt(used, 10),
+		"used_memory_human:"+humanBytes(used),
+		"used_memory_heap:"+strconv.FormatUint(mem.HeapAlloc, 10),
+		"used_memory_heap_human:"+humanBytes(int64(mem.HeapAlloc)),
+		"used_memory_sys:"+strconv.FormatUint(mem.Sys, 10),
+		"used_memory_sys_human:"+humanBytes(int64(mem.Sys)),
+		"maxmemory:"+strconv.FormatInt(maxMemory, 10),
+		"maxmemory_human:"+humanBytes(maxMemory),
+		"maxmemory_policy:"+config.DiceConfig.Memory.EvictionPolicy,
+	)
+}
+
+// writeInfoPersistence writes the persistence section: the state of the WAL.
+func writeInfoPersistence(b *strings.Builder, _ []*eval.InfoShardStats) {
+	var stats wal.Stats
+	if wl := GetServerInfo().WAL; wl != nil {
+		stats = wl.Stats()
+	}
+	lastSync := int64(-1)
+	if !stats.LastSync.IsZero() {
+		lastSync = stats.LastSync.Unix()
+	}
+	lastSyncStatus := "ok"
+	if stats.LastSyncFailed {
+		lastSyncStatus = "err"
+	}
+
+	writeInfoFields(b, "Persistence",
+		"wal_enabled:"+boolField(config.DiceConfig.Persistence.Enabled),
+		"wal_engine:"+config.DiceConfig.Persistence.WALEngine,
+		"wal_logged_commands:"+strconv.FormatUint(stats.LoggedCommands, 10),
+		"wal_logged_bytes:"+strconv.FormatUint(stats.LoggedBytes, 10),
+		"wal_syncs:"+strconv.FormatUint(stats.Syncs, 10),
+		"wal_sync_milliseconds:"+strconv.FormatInt(stats.SyncLatency.Sum.Milliseconds(), 10),
+		"wal_last_sync_time:"+strconv.FormatInt(lastSync, 10),
+		"wal_last_sync_status:"+lastSyncStatus,
+	)
+}
+
+// writeInfoStats writes the stats section: the connections and commands of the server, the clients
+// disconnected for exceeding their output buffer limit, the watch subscribers that fell behind, the
+// statistics of the stores of all the shards, then those of each shard.
+func writeInfoStats(b *strings.Builder, shards []*eval.InfoShardStats) {
+	var total dstore.Stats
+	for _, shard := range shards {
+		total.KeyspaceHits += shard.Stats.KeyspaceHits
+		total.KeyspaceMisses += shard.Stats.KeyspaceMisses
+		total.ExpiredKeys += shard.Stats.ExpiredKeys
+		total.ExpireCycles += shard.Stats.ExpireCycles
+		total.ExpireCycleTime += shard.Stats.ExpireCycleTime
+		total.LastExpireCycleTime = max(total.LastExpireCycleTime, shard.Stats.LastExpireCycleTime)
+		total.ExpiredStaleRatio += shard.Stats.ExpiredStaleRatio / float64(len(shards))
+		total.EvictedKeys += shard.Stats.EvictedKeys
+		total.Evictions += shard.Stats.Evictions
+		total.LastEvictionCount += shard.Stats.LastEvictionCount
+		total.SpilledKeys += shard.Stats.SpilledKeys
+		total.PromotedKeys += shard.Stats.PromotedKeys
+		total.DiskTierKeys += shard.Stats.DiskTierKeys
+		total.DiskTierBytes += shard.Stats.DiskTierBytes
+	}
+
+	var connections, commands uint64
+	if ioThreads := GetServerInfo().IOThreads; ioThreads != nil {
+		connections = ioThreads.TotalIOThreadCount()
+	}
+	for _, stats := range GetCommandStats() {
+		commands += stats.Calls
+	}
+
+	var slowConsumers uint64
+	if watches := GetServerInfo().Watches; watches != nil {
+		slowConsumers = watches.SlowConsumers()
+	}
+
+	fields := []string{
+		"total_connections_received:" + strconv.FormatUint(connections, 10),
+		"total_commands_processed:" + strconv.FormatUint(commands, 10),
+	}
+	for _, class := range iothread.ClientClasses {
+		fields = append(fields, "client_output_buffer_limit_disconnections_"+class.String()+":"+
+			strconv.FormatUint(iothread.OutputBufferDisconnections(class), 10))
+	}
+	fields = append(fields, "watch_slow_consumers:"+strconv.FormatUint(slowConsumers, 10))
+	writeInfoFields(b, "Stats", append(fields, infoStatsFields(total, ":")...)...)
+	for i, shard := range shards {
+		fmt.Fprintf(b, "shard%d:%s\r\n", i, strings.Join(infoStatsFields(shard.Stats, "="), ","))
+	}
+}
+
+// infoStatsFields returns the fields of the stats section, each name separated from its value by sep.
+func infoStatsFields(stats dstore.Stats, sep string) []string {
+	return []string{
+		"keyspace_hits" + sep + strconv.FormatUint(stats.KeyspaceHits, 10),
+		"keyspace_misses" + sep + strconv.FormatUint(stats.KeyspaceMisses, 10),
+		"expired_keys" + sep + strconv.FormatUint(stats.ExpiredKeys, 10),
+		"expired_stale_perc" + sep + strconv.FormatFloat(stats.ExpiredStaleRatio*100, 'f', 2, 64),
+		"expire_cycles" + sep + strconv.FormatUint(stats.ExpireCycles, 10),
+		"expire_cycle_cpu_milliseconds" + sep + strconv.FormatInt(stats.ExpireCycleTime.Milliseconds(), 10),
+		"expire_cycle_last_microseconds" + sep + strconv.FormatInt(stats.LastExpireCycleTime.Microseconds(), 10),
+		"evicted_keys" + sep + strconv.FormatUint(stats.EvictedKeys, 10),
+		"evictions" + sep + strconv.FormatUint(stats.Evictions, 10),
+		"last_eviction_keys" + sep + strconv.FormatInt(stats.LastEvictionCount, 10),
+		"spilled_keys" + sep + strconv.FormatUint(stats.SpilledKeys, 10),
+		"promoted_keys" + sep + strconv.FormatUint(stats.PromotedKeys, 10),
+		"disk_tier_keys" + sep + strconv.Itoa(stats.DiskTierKeys),
+		"disk_tier_bytes" + sep + strconv.FormatInt(stats.DiskTierBytes, 10),
+	}
+}
+
+// writeInfoReplication writes the replication section. DiceDB does not replicate, every server is
+// a master without replicas.
+func writeInfoReplication(b *strings.Builder, _ []*eval.InfoShardStats) {
+	writeInfoFields(b, "Replication",
+		"role:master",
+		"connected_slaves:0",
+	)
+}
+
+// writeInfoCPU writes the cpu section: the system and user CPU time consumed by the server and its
+// children, in seconds.
+func writeInfoCPU(b *strings.Builder, _ []*eval.InfoShardStats) {
+	var self, children syscall.Rusage
+	_ = syscall.Getrusage(syscall.RUSAGE_SELF, &self)
+	_ = syscall.Getrusage(syscall.RUSAGE_CHILDREN, &children)
+
+	writeInfoFields(b, "CPU",
+		"used_cpu_sys:"+cpuSeconds(self.Stime),
+		"used_cpu_user:"+cpuSeconds(self.Utime),
+		"used_cpu_sys_children:"+cpuSeconds(children.Stime),
+		"used_cpu_user_children:"+cpuSeconds(children.Utime),
+	)
+}
+
+// writeInfoCommandStats writes the commandstats section: the calls of each command called since the
+// server started, ordered by name.
+func writeInfoCommandStats(b *strings.Builder, _ []*eval.InfoShardStats) {
+	stats := GetCommandStats()
+	names := make([]string, 0, len(stats))
+	for name := range stats {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	fields := make([]string, 0, len(names))
+	for _, name := range names {
+		command := stats[name]
+		usec := command.Time.Microseconds()
+		fields = append(fields, fmt.Sprintf("cmdstat_%s:calls=%d,usec=%d,usec_per_call=%.2f,failed_calls=%d",
+			name, command.Calls, usec, float64(usec)/float64(command.Calls), command.FailedCalls))
+	}
+	writeInfoFields(b, "Commandstats", fields...)
+}
+
+// writeInfoKeyspace writes the keyspace section: the number of keys of each database holding keys,
+// summed over the shards, and how many of them have an expiry.
+func writeInfoKeyspace(b *strings.Builder, shards []*eval.InfoShardStats) {
+	var databases []dstore.DatabaseStats
+	for _, shard := range shards {
+		if databases == nil {
+			databases = make([]dstore.DatabaseStats, len(shard.Databases))
+		}
+		for db, stats := range shard.Databases {
+			databases[db].Keys += stats.Keys
+			databases[db].Expires += stats.Expires
+		}
+	}
+
+	fields := make([]string, 0, len(databases))
+	for db, stats := range databases {
+		if stats.Keys > 0 {
+			fields = append(fields, fmt.Sprintf("db%d:keys=%d,expires=%d", db, stats.Keys, stats.Expires))
+		}
+	}
+	writeInfoFields(b, "Keyspace", fields...)
+}
+
+// humanBytes formats a number of bytes with the largest binary unit it reaches, as in 1.50M.
+func humanBytes(n int64) string {
+	units := []string{"B", "K", "M", "G", "T", "P"}
+	value, unit := float64(n), 0
+	for value >= 1024 && unit < len(units)-1 {
+		value /= 1024
+		unit++
+	}
+	if unit == 0 {
+		return strconv.FormatInt(n, 10) + units[0]
+	}
+	return strconv.FormatFloat(value, 'f', 2, 64) + units[unit]
+}
+
+// cpuSeconds formats a CPU time in seconds, with the precision of microseconds.
+func cpuSeconds(tv syscall.Timeval) string {
+	return fmt.Sprintf("%d.%06d", tv.Sec, tv.Usec)
+}
+
+// boolField formats a boolean field as INFO does, with 1 or 0.
+func boolField(b bool) string {
+	if b {
+		return "1"
+	}
+	return "0"
+}
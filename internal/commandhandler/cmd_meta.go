@@ -7,6 +7,7 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
+	"time"
 
 	"github.com/dicedb/dice/internal/cmd"
 	diceerrors "github.com/dicedb/dice/internal/errors"
@@ -47,12 +48,14 @@ const (
 
 // Global commands
 const (
-	CmdPing  = "PING"
-	CmdAbort = "ABORT"
-	CmdAuth  = "AUTH"
-	CmdEcho  = "ECHO"
-	CmdHello = "HELLO"
-	CmdSleep = "SLEEP"
+	CmdPing   = "PING"
+	CmdAbort  = "ABORT"
+	CmdAuth   = "AUTH"
+	CmdEcho   = "ECHO"
+	CmdHello  = "HELLO"
+	CmdSleep  = "SLEEP"
+	CmdSelect = "SELECT"
+	CmdClient = "CLIENT"
 )
 
 // Single-shard commands.
@@ -70,6 +73,8 @@ const (
 	CmdPFAdd               = "PFADD"
 	CmdPFCount             = "PFCOUNT"
 	CmdPFMerge             = "PFMERGE"
+	CmdSort                = "SORT"
+	CmdSortRO              = "SORT_RO"
 	CmdTTL                 = "TTL"
 	CmdPTTL                = "PTTL"
 	CmdIncr                = "INCR"
@@ -86,6 +91,18 @@ const (
 	CmdHLen                = "HLEN"
 	CmdHStrLen             = "HSTRLEN"
 	CmdHScan               = "HSCAN"
+	CmdHExpire             = "HEXPIRE"
+	CmdHPExpire            = "HPEXPIRE"
+	CmdHExpireAt           = "HEXPIREAT"
+	CmdHPExpireAt          = "HPEXPIREAT"
+	CmdHTTL                = "HTTL"
+	CmdHPTTL               = "HPTTL"
+	CmdHExpireTime         = "HEXPIRETIME"
+	CmdHPExpireTime        = "HPEXPIRETIME"
+	CmdHPersist            = "HPERSIST"
+	CmdMove                = "MOVE"
+	CmdSScan               = "SSCAN"
+	CmdZScan               = "ZSCAN"
 	CmdBFAdd               = "BF.ADD"
 	CmdBFReserve           = "BF.RESERVE"
 	CmdBFInfo              = "BF.INFO"
@@ -118,7 +135,6 @@ const (
 	CmdGeoDist             = "GEODIST"
 	CmdGeoPos              = "GEOPOS"
 	CmdGeoHash             = "GEOHASH"
-	CmdClient              = "CLIENT"
 	CmdLatency             = "LATENCY"
 	CmdDel                 = "DEL"
 	CmdExists              = "EXISTS"
@@ -179,9 +195,14 @@ const (
 	CmdSDiff    = "SDIFF"
 	CmdJSONMget = "JSON.MGET"
 	CmdKeys     = "KEYS"
+	CmdScan     = "SCAN"
 	CmdTouch    = "TOUCH"
 	CmdDBSize   = "DBSIZE"
 	CmdFlushDB  = "FLUSHDB"
+	CmdSwapDB   = "SWAPDB"
+	CmdMemory   = "MEMORY"
+	CmdInfo     = "INFO"
+	CmdSlowLog  = "SLOWLOG"
 )
 
 // Multi-Step-Multi-Shard commands
@@ -222,10 +243,19 @@ type CmdMeta struct {
 
 	// preProcessResponse is a function that handles the preprocessing of a DiceDB command by
 	// preparing the necessary operations (e.g., fetching values from shards) before the command
-	// is executed. It takes the CommandHandler and the original DiceDB command as parameters and
-	// ensures that any required information is retrieved and processed in advance. Use this when set
-	// preProcessingReq = true.
-	preProcessResponse func(h *BaseCommandHandler, DiceDBCmd *cmd.DiceDBCmd) error
+	// is executed. It takes the context of the request, the CommandHandler and the original DiceDB
+	// command as parameters and ensures that any required information is retrieved and processed in
+	// advance. Use this when set preProcessingReq = true.
+	preProcessResponse func(ctx context.Context, h *BaseCommandHandler, DiceDBCmd *cmd.DiceDBCmd) error
+
+	// blocking indicates that the command may legitimately take longer than the request timeout.
+	// Blocking commands are exempt from the request timeout, unless blockingTimeout derives one
+	// from their own arguments.
+	blocking bool
+
+	// blockingTimeout returns how long a blocking command is expected to block, based on its
+	// arguments. It returns false when the arguments do not specify it, e.g. when blocking forever.
+	blockingTimeout func(args []string) (time.Duration, bool)
 }
 
 var CommandsMeta = map[string]CmdMeta{
@@ -368,6 +398,42 @@ var CommandsMeta = map[string]CmdMeta{
 	CmdHScan: {
 		CmdType: SingleShard,
 	},
+	CmdHExpire: {
+		CmdType: SingleShard,
+	},
+	CmdHPExpire: {
+		CmdType: SingleShard,
+	},
+	CmdHExpireAt: {
+		CmdType: SingleShard,
+	},
+	CmdHPExpireAt: {
+		CmdType: SingleShard,
+	},
+	CmdHTTL: {
+		CmdType: SingleShard,
+	},
+	CmdHPTTL: {
+		CmdType: SingleShard,
+	},
+	CmdHExpireTime: {
+		CmdType: SingleShard,
+	},
+	CmdHPExpireTime: {
+		CmdType: SingleShard,
+	},
+	CmdHPersist: {
+		CmdType: SingleShard,
+	},
+	CmdMove: {
+		CmdType: SingleShard,
+	},
+	CmdSScan: {
+		CmdType: SingleShard,
+	},
+	CmdZScan: {
+		CmdType: SingleShard,
+	},
 	CmdHIncrBy: {
 		CmdType: SingleShard,
 	},
@@ -527,9 +593,6 @@ var CommandsMeta = map[string]CmdMeta{
 	CmdGeoHash: {
 		CmdType: SingleShard,
 	},
-	CmdClient: {
-		CmdType: SingleShard,
-	},
 	CmdLatency: {
 		CmdType: SingleShard,
 	},
@@ -589,6 +652,22 @@ var CommandsMeta = map[string]CmdMeta{
 		composeResponse:    composePFMerge,
 	},
 
+	CmdSort: {
+		CmdType:            MultiShard,
+		preProcessing:      true,
+		preProcessResponse: preProcessSort,
+		decomposeCommand:   (*BaseCommandHandler).decomposeSort,
+		composeResponse:    composeSort,
+	},
+
+	CmdSortRO: {
+		CmdType:            MultiShard,
+		preProcessing:      true,
+		preProcessResponse: preProcessSort,
+		decomposeCommand:   (*BaseCommandHandler).decomposeSort,
+		composeResponse:    composeSort,
+	},
+
 	CmdMset: {
 		CmdType:          MultiShard,
 		decomposeCommand: (*BaseCommandHandler).decomposeMSet,
@@ -633,11 +712,36 @@ var CommandsMeta = map[string]CmdMeta{
 		decomposeCommand: (*BaseCommandHandler).decomposeKeys,
 		composeResponse:  composeKeys,
 	},
+	CmdScan: {
+		CmdType:          MultiShard,
+		decomposeCommand: (*BaseCommandHandler).decomposeScan,
+		composeResponse:  composeScan,
+	},
 	CmdFlushDB: {
 		CmdType:          AllShard,
 		decomposeCommand: (*BaseCommandHandler).decomposeFlushDB,
 		composeResponse:  composeFlushDB,
 	},
+	CmdSwapDB: {
+		CmdType:          AllShard,
+		decomposeCommand: (*BaseCommandHandler).decomposeSwapDB,
+		composeResponse:  composeSwapDB,
+	},
+	CmdMemory: {
+		CmdType:          AllShard,
+		decomposeCommand: (*BaseCommandHandler).decomposeMemory,
+		composeResponse:  composeMemory,
+	},
+	CmdInfo: {
+		CmdType:          AllShard,
+		decomposeCommand: (*BaseCommandHandler).decomposeInfo,
+		composeResponse:  composeInfo,
+	},
+	CmdSlowLog: {
+		CmdType:          AllShard,
+		decomposeCommand: (*BaseCommandHandler).decomposeSlowLog,
+		composeResponse:  composeSlowLog,
+	},
 
 	// Custom commands.
 	CmdAbort: {
@@ -652,6 +756,19 @@ var CommandsMeta = map[string]CmdMeta{
 	CmdPing: {
 		CmdType: Custom,
 	},
+	CmdSelect: {
+		CmdType: Custom,
+	},
+	CmdClient: {
+		CmdType: Custom,
+	},
+
+	// Blocking commands.
+	CmdSleep: {
+		CmdType:         SingleShard,
+		blocking:        true,
+		blockingTimeout: sleepTimeout,
+	},
 
 	// Watch commands
 	CmdGetWatch: {
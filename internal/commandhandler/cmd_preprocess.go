@@ -4,6 +4,8 @@
 package commandhandler
 
 import (
+	"context"
+
 	"github.com/dicedb/dice/internal/cmd"
 	diceerrors "github.com/dicedb/dice/internal/errors"
 	"github.com/dicedb/dice/internal/ops"
@@ -12,7 +14,7 @@ import (
 // preProcessRename prepares the RENAME command for preprocessing by sending a GET command
 // to retrieve the value of the original key. The retrieved value is used later in the
 // decomposeRename function to delete the old key and set the new key.
-func preProcessRename(h *BaseCommandHandler, diceDBCmd *cmd.DiceDBCmd) error {
+func preProcessRename(ctx context.Context, h *BaseCommandHandler, diceDBCmd *cmd.DiceDBCmd) error {
 	if len(diceDBCmd.Args) < 2 {
 		return diceerrors.ErrWrongArgumentCount("RENAME")
 	}
@@ -31,7 +33,9 @@ func preProcessRename(h *BaseCommandHandler, diceDBCmd *cmd.DiceDBCmd) error {
 		Cmd:           &preCmd,
 		CmdHandlerID:  h.id,
 		ShardID:       sid,
+		Database:      h.Session.Database,
 		Client:        nil,
+		Ctx:           ctx,
 		PreProcessing: true,
 	}
 
@@ -41,7 +45,7 @@ func preProcessRename(h *BaseCommandHandler, diceDBCmd *cmd.DiceDBCmd) error {
 // preProcessCopy prepares the COPY command for preprocessing by sending a GET command
 // to retrieve the value of the original key. The retrieved value is used later in the
 // decomposeCopy function to copy the value to the destination key.
-func customProcessCopy(h *BaseCommandHandler, diceDBCmd *cmd.DiceDBCmd) error {
+func customProcessCopy(ctx context.Context, h *BaseCommandHandler, diceDBCmd *cmd.DiceDBCmd) error {
 	if len(diceDBCmd.Args) < 2 {
 		return diceerrors.ErrWrongArgumentCount("COPY")
 	}
@@ -60,7 +64,9 @@ func customProcessCopy(h *BaseCommandHandler, diceDBCmd *cmd.DiceDBCmd) error {
 		Cmd:           &preCmd,
 		CmdHandlerID:  h.id,
 		ShardID:       sid,
+		Database:      h.Session.Database,
 		Client:        nil,
+		Ctx:           ctx,
 		PreProcessing: true,
 	}
 
@@ -70,7 +76,7 @@ func customProcessCopy(h *BaseCommandHandler, diceDBCmd *cmd.DiceDBCmd) error {
 // preProcessPFMerge prepares the PFMERGE command for preprocessing by sending GETOBJECT commands
 // to retrieve the value of all the keys to be merged with. The retrieved value is used later in the
 // decomposePFMERGE function to merge the hll keys to the new key.
-func preProcessPFMerge(h *BaseCommandHandler, diceDBCmd *cmd.DiceDBCmd) error {
+func preProcessPFMerge(ctx context.Context, h *BaseCommandHandler, diceDBCmd *cmd.DiceDBCmd) error {
 	if len(diceDBCmd.Args) < 1 {
 		return diceerrors.ErrWrongArgumentCount("PFMERGE")
 	}
@@ -90,10 +96,50 @@ func preProcessPFMerge(h *BaseCommandHandler, diceDBCmd *cmd.DiceDBCmd) error {
 			Cmd:           &preCmd,
 			CmdHandlerID:  h.id,
 			ShardID:       sid,
+			Database:      h.Session.Database,
 			Client:        nil,
+			Ctx:           ctx,
 			PreProcessing: true,
 		}
 	}
 
 	return nil
 }
+
+// preProcessSort prepares the SORT and SORT_RO commands for preprocessing by asking the shard owning
+// the sorted key for its elements, along with the keys and hash fields its BY and GET patterns read.
+// Their values are then read from the shards owning them in the decomposeSort function.
+func preProcessSort(ctx context.Context, h *BaseCommandHandler, diceDBCmd *cmd.DiceDBCmd) error {
+	if len(diceDBCmd.Args) < 1 {
+		return diceerrors.ErrWrongArgumentCount(diceDBCmd.Cmd)
+	}
+
+	sid, rc := h.shardManager.GetShardInfo(diceDBCmd.Args[0])
+	preCmd := cmd.DiceDBCmd{
+		Cmd:  diceDBCmd.Cmd,
+		Args: diceDBCmd.Args,
+	}
+
+	return sendPreProcessingOp(ctx, rc, &ops.StoreOp{
+		SeqID:         0,
+		RequestID:     GenerateUniqueRequestID(),
+		Cmd:           &preCmd,
+		CmdHandlerID:  h.id,
+		ShardID:       sid,
+		Database:      h.Session.Database,
+		Client:        nil,
+		Ctx:           ctx,
+		PreProcessing: true,
+	})
+}
+
+// sendPreProcessingOp sends a preprocessing operation to a shard, giving up once the context is
+// done, for the shard may be too busy to take it before the request times out.
+func sendPreProcessingOp(ctx context.Context, rc chan *ops.StoreOp, op *ops.StoreOp) error {
+	select {
+	case rc <- op:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
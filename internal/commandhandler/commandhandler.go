@@ -10,6 +10,7 @@ import (
 	"log/slog"
 	"net"
 	"strconv"
+	"strings"
 	"sync/atomic"
 	"syscall"
 	"time"
@@ -20,24 +21,44 @@ import (
 	"github.com/dicedb/dice/internal/clientio/requestparser"
 	"github.com/dicedb/dice/internal/cmd"
 	diceerrors "github.com/dicedb/dice/internal/errors"
+	"github.com/dicedb/dice/internal/iothread"
 	"github.com/dicedb/dice/internal/ops"
 	"github.com/dicedb/dice/internal/querymanager"
 	"github.com/dicedb/dice/internal/shard"
+	"github.com/dicedb/dice/internal/store"
 	"github.com/dicedb/dice/internal/wal"
 	"github.com/dicedb/dice/internal/watchmanager"
 	"github.com/google/uuid"
 )
 
-const defaultRequestTimeout = 6 * time.Second
-
 var requestCounter uint32
 
+// internalCommands are the commands the multi-shard commands are decomposed into, which only the
+// command handlers send to the shards.
+var internalCommands = map[string]bool{
+	store.SingleShardTouch: true,
+	store.SingleShardSize:  true,
+	store.SingleShardKeys:  true,
+	store.SingleShardScan:  true,
+	store.SortStore:        true,
+}
+
+// IsInternalCommand reports whether the command is only sent to the shards by the command handlers,
+// and must be refused to the clients.
+func IsInternalCommand(name string) bool {
+	return internalCommands[name]
+}
+
 type CommandHandler interface {
 	ID() string
 	Start(ctx context.Context) error
 	Stop() error
 }
 
+// watchUnsubscribeTimeout is how long a stopped command handler waits for the watch manager to take
+// the unsubscription of its client.
+const watchUnsubscribeTimeout = time.Second
+
 type BaseCommandHandler struct {
 	CommandHandler
 	id     string
@@ -53,7 +74,10 @@ type BaseCommandHandler struct {
 	ioThreadErrChan          chan error              // Channel to receive errors from io-thread
 	responseChan             chan *ops.StoreResponse // Channel to communicate with shard
 	preprocessingChan        chan *ops.StoreResponse // Channel to communicate with shard
+	pendingRequests          map[uint32]struct{}     // RequestIDs of the scattered operations awaiting a response
 	cmdWatchSubscriptionChan chan watchmanager.WatchSubscription
+	watching                 atomic.Bool                // Whether the client subscribed to a watch command, to unsubscribe it once stopped
+	setClientClass           func(iothread.ClientClass) // Notifies the io-thread when the client class changes
 }
 
 func NewCommandHandler(id string, responseChan, preprocessingChan chan *ops.StoreResponse,
@@ -82,6 +106,12 @@ func (h *BaseCommandHandler) ID() string {
 	return h.id
 }
 
+// SetClientClassNotifier registers the function used to report the client class to the
+// io-thread serving this command handler, so that the right output buffer limit is applied.
+func (h *BaseCommandHandler) SetClientClassNotifier(fn func(iothread.ClientClass)) {
+	h.setClientClass = fn
+}
+
 func (h *BaseCommandHandler) Start(ctx context.Context) error {
 	errChan := make(chan error, 1) // for adhoc request processing errors
 
@@ -92,17 +122,25 @@ func (h *BaseCommandHandler) Start(ctx context.Context) error {
 		case err := <-h.ioThreadErrChan:
 			return err
 		case cmdReq := <-h.adhocReqChan:
-			resp, err := h.handleCmdRequestWithTimeout(ctx, errChan, []*cmd.DiceDBCmd{cmdReq}, true, defaultRequestTimeout)
-			h.sendResponseToIOThread(resp, err)
+			resp, err := h.handleCmdRequestWithTimeout(ctx, errChan, []*cmd.DiceDBCmd{cmdReq}, true, RequestTimeout(cmdReq))
+			h.sendResponseToIOThread(ctx, resp, err)
 		case err := <-errChan:
 			return h.handleError(err)
 		case data := <-h.ioThreadReadChan:
 			resp, err := h.processCommand(ctx, &data, h.globalErrorChan)
-			h.sendResponseToIOThread(resp, err)
+			h.sendResponseToIOThread(ctx, resp, err)
 		}
 	}
 }
 
+// ExecuteCommand executes a command that was already parsed by its protocol server, such as
+// HTTP or WebSocket, and returns its result. The command goes through the same routing,
+// decomposition and preprocessing as the commands received from the io-thread. Commands of a
+// single handler must not be executed concurrently, as they share its response channels.
+func (h *BaseCommandHandler) ExecuteCommand(ctx context.Context, diceDBCmd *cmd.DiceDBCmd) (interface{}, error) {
+	return h.handleCmdRequestWithTimeout(ctx, h.globalErrorChan, []*cmd.DiceDBCmd{diceDBCmd}, false, RequestTimeout(diceDBCmd))
+}
+
 // processCommand processes commands recevied from io thread
 func (h *BaseCommandHandler) processCommand(ctx context.Context, data *[]byte, gec chan error) (interface{}, error) {
 	commands, err := h.parser.Parse(*data)
@@ -129,20 +167,33 @@ func (h *BaseCommandHandler) processCommand(ctx context.Context, data *[]byte, g
 		return nil, err
 	}
 
-	return h.handleCmdRequestWithTimeout(ctx, gec, commands, false, defaultRequestTimeout)
+	return h.handleCmdRequestWithTimeout(ctx, gec, commands, false, RequestTimeout(commands[0]))
 }
 
+// handleCmdRequestWithTimeout executes the commands, giving up after the timeout. A zero timeout
+// lets the commands run for as long as they need, which is the case for blocking commands.
+// Cancelling the execution context also cancels the shard operations that are still in flight.
 func (h *BaseCommandHandler) handleCmdRequestWithTimeout(ctx context.Context, gec chan error, commands []*cmd.DiceDBCmd, isWatchNotification bool, timeout time.Duration) (interface{}, error) {
-	execCtx, cancel := context.WithTimeout(ctx, timeout)
+	var execCtx context.Context
+	var cancel context.CancelFunc
+	if timeout > 0 {
+		execCtx, cancel = context.WithTimeout(ctx, timeout)
+	} else {
+		execCtx, cancel = context.WithCancel(ctx)
+	}
 	defer cancel()
-	return h.executeCommandHandler(execCtx, gec, commands, isWatchNotification)
+
+	name, start := commands[0].Cmd, time.Now()
+	resp, err := h.executeCommandHandler(execCtx, gec, commands, isWatchNotification)
+	recordCommand(name, time.Since(start), resp, err)
+	return resp, err
 }
 
 func (h *BaseCommandHandler) executeCommandHandler(execCtx context.Context, gec chan error, commands []*cmd.DiceDBCmd, isWatchNotification bool) (interface{}, error) {
 	// Retrieve metadata for the command to determine if multisharding is supported.
 	meta, ok := CommandsMeta[commands[0].Cmd]
 	if ok && meta.preProcessing {
-		if err := meta.preProcessResponse(h, commands[0]); err != nil {
+		if err := meta.preProcessResponse(execCtx, h, commands[0]); err != nil {
 			slog.Debug("error pre processing response", slog.String("id", h.id), slog.Any("error", err))
 			return nil, err
 		}
@@ -168,6 +219,11 @@ func (h *BaseCommandHandler) executeCommand(ctx context.Context, diceDBCmd *cmd.
 	cmdList := make([]*cmd.DiceDBCmd, 0)
 	var watchLabel string
 
+	// The commands the decomposed ones are made of are not for the clients to send.
+	if IsInternalCommand(diceDBCmd.Cmd) {
+		return nil, diceerrors.ErrFormatted("unknown command '%s', with args beginning with: %s", diceDBCmd.Cmd, strings.Join(diceDBCmd.Args, " "))
+	}
+
 	// Retrieve metadata for the command to determine if multisharding is supported.
 	meta, ok := CommandsMeta[diceDBCmd.Cmd]
 	if !ok {
@@ -280,6 +336,10 @@ func (h *BaseCommandHandler) handleCustomCommands(diceDBCmd *cmd.DiceDBCmd) (int
 		return RespHello(diceDBCmd.Args), nil
 	case CmdSleep:
 		return RespSleep(diceDBCmd.Args), nil
+	case CmdSelect:
+		return h.RespSelect(diceDBCmd.Args), nil
+	case CmdClient:
+		return h.RespClient(diceDBCmd.Args), nil
 	default:
 		return nil, diceerrors.ErrUnknownCmd(diceDBCmd.Cmd)
 	}
@@ -292,6 +352,12 @@ func (h *BaseCommandHandler) handleCommandWatch(cmdList []*cmd.DiceDBCmd) {
 		WatchCmd:     cmdList[len(cmdList)-1],
 		AdhocReqChan: h.adhocReqChan,
 	}
+	h.watching.Store(true)
+
+	// From now on the client receives pushed updates, so the watch output buffer limit applies.
+	if h.setClientClass != nil {
+		h.setClientClass(iothread.ClientClassWatch)
+	}
 }
 
 // handleCommandUnwatch sends an unwatch subscription request to the watch manager. It also sends a response to the client.
@@ -326,6 +392,7 @@ func (h *BaseCommandHandler) scatter(ctx context.Context, cmds []*cmd.DiceDBCmd,
 		return ctx.Err()
 	default:
 		// Proceed with the default case when the context is not canceled.
+		h.pendingRequests = make(map[uint32]struct{}, len(cmds))
 
 		if cmdType == AllShard {
 			// If the command type is for all shards, iterate over all available shards.
@@ -333,30 +400,54 @@ func (h *BaseCommandHandler) scatter(ctx context.Context, cmds []*cmd.DiceDBCmd,
 				// Get the shard ID (i) and its associated request channel.
 				shardID, responseChan := i, h.shardManager.GetShard(i).ReqChan
 
+				requestID := GenerateUniqueRequestID()
+				h.pendingRequests[requestID] = struct{}{}
+
 				// Send a StoreOp operation to the shard's request channel.
 				responseChan <- &ops.StoreOp{
-					SeqID:        i,                         // Sequence ID for this operation.
-					RequestID:    GenerateUniqueRequestID(), // Unique identifier for the request.
-					Cmd:          cmds[0],                   // Command to be executed, using the first command in cmds.
-					CmdHandlerID: h.id,                      // ID of the current command handler.
-					ShardID:      shardID,                   // ID of the shard handling this operation.
-					Client:       nil,                       // Client information (if applicable).
+					SeqID:        i,                  // Sequence ID for this operation.
+					RequestID:    requestID,          // Unique identifier for the request.
+					Cmd:          cmds[0],            // Command to be executed, using the first command in cmds.
+					CmdHandlerID: h.id,               // ID of the current command handler.
+					ShardID:      shardID,            // ID of the shard handling this operation.
+					Database:     h.Session.Database, // Database selected by the client.
+					Client:       nil,                // Client information (if applicable).
+					Ctx:          ctx,                // Cancels the operation once the request times out.
+					ClientAddr:   h.Session.ClientAddr,
+					ClientName:   h.Session.ClientName,
 				}
 			}
 		} else {
 			// If the command type is specific to certain commands, process them individually.
+			// Determine the appropriate shard for each command first, so that none is sent if one of
+			// them cannot be routed.
+			shardIDs := make([]shard.ShardID, len(cmds))
+			responseChans := make([]chan *ops.StoreOp, len(cmds))
+			for i := range cmds {
+				var err error
+				if shardIDs[i], responseChans[i], err = h.getShardForCommand(cmds[i]); err != nil {
+					return err
+				}
+			}
+
 			for i := uint8(0); i < uint8(len(cmds)); i++ {
-				// Determine the appropriate shard for the current command using a routing key.
-				shardID, responseChan := h.shardManager.GetShardInfo(getRoutingKeyFromCommand(cmds[i]))
+				shardID, responseChan := shardIDs[i], responseChans[i]
+
+				requestID := GenerateUniqueRequestID()
+				h.pendingRequests[requestID] = struct{}{}
 
 				// Send a StoreOp operation to the shard's request channel.
 				responseChan <- &ops.StoreOp{
-					SeqID:        i,                         // Sequence ID for this operation.
-					RequestID:    GenerateUniqueRequestID(), // Unique identifier for the request.
-					Cmd:          cmds[i],                   // Command to be executed, using the current command in cmds.
-					CmdHandlerID: h.id,                      // ID of the current command handler.
-					ShardID:      shardID,                   // ID of the shard handling this operation.
-					Client:       nil,                       // Client information (if applicable).
+					SeqID:        i,                  // Sequence ID for this operation.
+					RequestID:    requestID,          // Unique identifier for the request.
+					Cmd:          cmds[i],            // Command to be executed, using the current command in cmds.
+					CmdHandlerID: h.id,               // ID of the current command handler.
+					ShardID:      shardID,            // ID of the shard handling this operation.
+					Database:     h.Session.Database, // Database selected by the client.
+					Client:       nil,                // Client information (if applicable).
+					Ctx:          ctx,                // Cancels the operation once the request times out.
+					ClientAddr:   h.Session.ClientAddr,
+					ClientName:   h.Session.ClientName,
 				}
 			}
 		}
@@ -365,6 +456,28 @@ func (h *BaseCommandHandler) scatter(ctx context.Context, cmds []*cmd.DiceDBCmd,
 	return nil
 }
 
+// getShardForCommand returns the shard the command is sent to, which is the one owning its routing
+// key, except for SINGLESCAN, which is sent to the shard its cursor points to. It returns
+// ErrInvalidCursor if the cursor points to no shard.
+func (h *BaseCommandHandler) getShardForCommand(diceDBCmd *cmd.DiceDBCmd) (shard.ShardID, chan *ops.StoreOp, error) {
+	if diceDBCmd.Cmd == store.SingleShardScan {
+		if len(diceDBCmd.Args) < 1 {
+			return 0, nil, diceerrors.ErrWrongArgumentCount("SCAN")
+		}
+		cursor, err := strconv.ParseUint(diceDBCmd.Args[0], 10, 64)
+		if err != nil {
+			return 0, nil, diceerrors.ErrInvalidCursor
+		}
+		shardID, _ := store.DecodeScanCursor(cursor)
+		if int(shardID) >= int(h.shardManager.GetShardCount()) {
+			return 0, nil, diceerrors.ErrInvalidCursor
+		}
+		return shard.ShardID(shardID), h.shardManager.GetShard(shard.ShardID(shardID)).ReqChan, nil
+	}
+	shardID, responseChan := h.shardManager.GetShardInfo(getRoutingKeyFromCommand(diceDBCmd))
+	return shardID, responseChan, nil
+}
+
 // getRoutingKeyFromCommand determines the key used for shard routing
 func getRoutingKeyFromCommand(diceDBCmd *cmd.DiceDBCmd) string {
 	if len(diceDBCmd.Args) > 0 {
@@ -416,6 +529,12 @@ func (h *BaseCommandHandler) gatherResponses(ctx context.Context, numCmds int) (
 
 		case resp, ok := <-h.responseChan:
 			if ok {
+				if _, pending := h.pendingRequests[resp.RequestID]; !pending {
+					// Response to an operation of an earlier request, which stopped waiting for it
+					slog.Debug("Dropping response of a previous request", slog.String("id", h.id), slog.Any("requestID", resp.RequestID))
+					continue
+				}
+				delete(h.pendingRequests, resp.RequestID)
 				storeOp = append(storeOp, *resp)
 			}
 			numCmds--
@@ -492,16 +611,25 @@ func (h *BaseCommandHandler) handleError(err error) error {
 	return fmt.Errorf("error writing response: %v", err)
 }
 
-func (h *BaseCommandHandler) sendResponseToIOThread(resp interface{}, err error) {
+func (h *BaseCommandHandler) sendResponseToIOThread(ctx context.Context, resp interface{}, err error) {
 	if err != nil {
 		var customErr *diceerrors.PreProcessError
 		if errors.As(err, &customErr) {
-			h.ioThreadWriteChan <- customErr.Result
+			h.writeToIOThread(ctx, customErr.Result)
 		}
-		h.ioThreadWriteChan <- err
+		h.writeToIOThread(ctx, err)
 		return
 	}
-	h.ioThreadWriteChan <- resp
+	h.writeToIOThread(ctx, resp)
+}
+
+// writeToIOThread hands a response over to the io-thread. It gives up once the context is done,
+// which happens when the io-thread has already disconnected the client.
+func (h *BaseCommandHandler) writeToIOThread(ctx context.Context, resp interface{}) {
+	select {
+	case h.ioThreadWriteChan <- resp:
+	case <-ctx.Done():
+	}
 }
 
 func (h *BaseCommandHandler) isAuthenticated(diceDBCmd *cmd.DiceDBCmd) error {
@@ -509,12 +637,29 @@ func (h *BaseCommandHandler) isAuthenticated(diceDBCmd *cmd.DiceDBCmd) error {
 		return errors.New("NOAUTH Authentication required")
 	}
 
+	if user := h.Session.User; user != nil {
+		return user.Authorize(diceDBCmd.Cmd)
+	}
 	return nil
 }
 
 func (h *BaseCommandHandler) Stop() error {
 	slog.Info("Stopping command handler", slog.String("id", h.id))
 	h.Session.Expire()
+
+	// The watch manager would otherwise keep notifying the client that is gone. It is not waited for
+	// long, as it stops along with the server.
+	if h.watching.Swap(false) {
+		select {
+		case h.cmdWatchSubscriptionChan <- watchmanager.WatchSubscription{
+			Subscribe:    false,
+			AdhocReqChan: h.adhocReqChan,
+			All:          true,
+		}:
+		case <-time.After(watchUnsubscribeTimeout):
+			slog.Warn("Timed out unsubscribing the watch commands of the client", slog.String("id", h.id))
+		}
+	}
 	return nil
 }
 
@@ -549,3 +694,54 @@ func (h *BaseCommandHandler) RespAuth(args []string) interface{} {
 
 	return clientio.OK
 }
+
+// RespSelect switches the logical database of the client, which the following commands of the
+// client operate on.
+func (h *BaseCommandHandler) RespSelect(args []string) interface{} {
+	if len(args) != 1 {
+		return diceerrors.ErrWrongArgumentCount("SELECT")
+	}
+
+	db, err := strconv.Atoi(args[0])
+	if err != nil {
+		return diceerrors.ErrIntegerOutOfRange
+	}
+	if db < 0 || db >= store.DatabaseCount() {
+		return diceerrors.ErrDBIndexOutOfRange
+	}
+
+	h.Session.Database = db
+	return clientio.OK
+}
+
+// RespClient sets and returns the name of the client with CLIENT SETNAME and CLIENT GETNAME, which
+// SLOWLOG reports. The other subcommands of CLIENT are not supported.
+func (h *BaseCommandHandler) RespClient(args []string) interface{} {
+	if len(args) < 1 {
+		return diceerrors.ErrWrongArgumentCount("CLIENT")
+	}
+
+	switch strings.ToUpper(args[0]) {
+	case "SETNAME":
+		if len(args) != 2 {
+			return diceerrors.ErrWrongArgumentCount("CLIENT|SETNAME")
+		}
+		for _, c := range args[1] {
+			if c < '!' || c > '~' {
+				return diceerrors.ErrGeneral("Client names cannot contain spaces, newlines or special characters.")
+			}
+		}
+		h.Session.ClientName = args[1]
+		return clientio.OK
+	case "GETNAME":
+		if len(args) != 1 {
+			return diceerrors.ErrWrongArgumentCount("CLIENT|GETNAME")
+		}
+		if h.Session.ClientName == "" {
+			return clientio.NIL
+		}
+		return h.Session.ClientName
+	default:
+		return diceerrors.ErrGeneral(fmt.Sprintf("unknown subcommand '%s'", args[0]))
+	}
+}
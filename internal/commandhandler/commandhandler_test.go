@@ -0,0 +1,344 @@
+// Copyright (c) 2022-present, DiceDB contributors
+// All rights reserved. Licensed under the BSD 3-Clause License. See LICENSE file in the project root for full license information.
+
+package commandhandler
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/dicedb/dice/config"
+	"github.com/dicedb/dice/internal/clientio"
+	"github.com/dicedb/dice/internal/cmd"
+	diceerrors "github.com/dicedb/dice/internal/errors"
+	"github.com/dicedb/dice/internal/eval"
+	"github.com/dicedb/dice/internal/ops"
+	"github.com/dicedb/dice/internal/shard"
+	"github.com/dicedb/dice/internal/store"
+	"github.com/dicedb/dice/internal/watchmanager"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGatherResponsesDropsResponsesOfPreviousRequests(t *testing.T) {
+	responseChan := make(chan *ops.StoreResponse, 3)
+	h := NewCommandHandler("test", responseChan, nil, nil, nil, shard.NewShardManager(1, nil, nil),
+		nil, nil, nil, nil, nil)
+	h.pendingRequests = map[uint32]struct{}{1: {}, 2: {}}
+
+	responseChan <- &ops.StoreResponse{RequestID: 1, EvalResponse: &eval.EvalResponse{Result: "a"}}
+	responseChan <- &ops.StoreResponse{RequestID: 99, EvalResponse: &eval.EvalResponse{Result: "stale"}}
+	responseChan <- &ops.StoreResponse{RequestID: 2, EvalResponse: &eval.EvalResponse{Result: "b"}}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	responses, err := h.gatherResponses(ctx, 2)
+	require.NoError(t, err)
+	require.Len(t, responses, 2)
+	assert.Equal(t, "a", responses[0].EvalResponse.Result)
+	assert.Equal(t, "b", responses[1].EvalResponse.Result)
+	assert.Empty(t, h.pendingRequests)
+}
+
+func TestStopUnsubscribesWatchingClient(t *testing.T) {
+	subscriptionChan := make(chan watchmanager.WatchSubscription, 2)
+	h := NewCommandHandler("test", make(chan *ops.StoreResponse), nil, subscriptionChan, nil, shard.NewShardManager(1, nil, nil),
+		nil, nil, nil, nil, nil)
+
+	// a client that never watched anything has nothing to unsubscribe
+	require.NoError(t, h.Stop())
+	assert.Empty(t, subscriptionChan)
+
+	h.handleCommandWatch([]*cmd.DiceDBCmd{{Cmd: "GET.WATCH", Args: []string{"k"}}})
+	<-subscriptionChan
+	require.NoError(t, h.Stop())
+	require.Len(t, subscriptionChan, 1)
+	assert.Equal(t, watchmanager.WatchSubscription{AdhocReqChan: h.adhocReqChan, All: true}, <-subscriptionChan)
+}
+
+func TestScanAcrossShards(t *testing.T) {
+	require.NoError(t, config.NewConfigParser().ParseDefaults(config.DiceConfig))
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	shardManager := shard.NewShardManager(4, nil, make(chan error, 1))
+	go shardManager.Run(ctx)
+
+	responseChan := make(chan *ops.StoreResponse)
+	shardManager.RegisterCommandHandler("test", responseChan, nil)
+	h := NewCommandHandler("test", responseChan, nil, nil, nil, shardManager,
+		make(chan error, 1), nil, nil, nil, nil)
+
+	expected := make([]string, 0, 200)
+	for i := 0; i < 200; i++ {
+		key := fmt.Sprintf("key:%d", i)
+		_, err := h.ExecuteCommand(ctx, &cmd.DiceDBCmd{Cmd: CmdSet, Args: []string{key, "v"}})
+		require.NoError(t, err)
+		expected = append(expected, key)
+	}
+
+	var keys []string
+	cursor := "0"
+	for page := 0; ; page++ {
+		require.Less(t, page, 1000, "the scan does not terminate")
+		resp, err := h.ExecuteCommand(ctx, &cmd.DiceDBCmd{Cmd: CmdScan, Args: []string{cursor, "COUNT", "7"}})
+		require.NoError(t, err)
+		result := resp.([]interface{})
+		keys = append(keys, result[1].([]string)...)
+		if cursor = result[0].(string); cursor == "0" {
+			break
+		}
+	}
+	assert.ElementsMatch(t, expected, keys)
+
+	// A cursor pointing to a shard that does not exist is refused
+	_, err := h.ExecuteCommand(ctx, &cmd.DiceDBCmd{Cmd: CmdScan,
+		Args: []string{strconv.FormatUint(store.EncodeScanCursor(4, 0), 10)}})
+	assert.ErrorIs(t, err, diceerrors.ErrInvalidCursor)
+
+	// The clients cannot send the command scanning a single shard
+	for _, args := range [][]string{nil, {"18446744073709551615", "4"}, {"0", "4"}} {
+		_, err = h.ExecuteCommand(ctx, &cmd.DiceDBCmd{Cmd: store.SingleShardScan, Args: args})
+		assert.ErrorContains(t, err, "unknown command 'SINGLESCAN'")
+	}
+
+	// Nor is it routed anywhere if its cursor points to no shard
+	for _, args := range [][]string{nil, {"18446744073709551615", "4"}} {
+		err = h.scatter(ctx, []*cmd.DiceDBCmd{{Cmd: store.SingleShardScan, Args: args}}, SingleShard)
+		assert.Error(t, err)
+	}
+	assert.Empty(t, h.pendingRequests)
+}
+
+func TestRespClient(t *testing.T) {
+	h := NewCommandHandler("test", make(chan *ops.StoreResponse), nil, nil, nil, shard.NewShardManager(1, nil, nil),
+		nil, nil, nil, nil, nil)
+
+	assert.Equal(t, clientio.NIL, h.RespClient([]string{"GETNAME"}))
+	assert.Equal(t, clientio.OK, h.RespClient([]string{"setname", "client"}))
+	assert.Equal(t, "client", h.RespClient([]string{"GETNAME"}))
+	assert.Equal(t, diceerrors.ErrGeneral("unknown subcommand 'KILL'"), h.RespClient([]string{"KILL", "127.0.0.1:7379"}))
+	assert.Equal(t, diceerrors.ErrWrongArgumentCount("CLIENT"), h.RespClient(nil))
+}
+
+func TestDatabasesAreScopedToTheSelectedOne(t *testing.T) {
+	require.NoError(t, config.NewConfigParser().ParseDefaults(config.DiceConfig))
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	shardManager := shard.NewShardManager(2, nil, make(chan error, 1))
+	go shardManager.Run(ctx)
+
+	newHandler := func(id string) *BaseCommandHandler {
+		responseChan := make(chan *ops.StoreResponse)
+		shardManager.RegisterCommandHandler(id, responseChan, nil)
+		return NewCommandHandler(id, responseChan, nil, nil, nil, shardManager,
+			make(chan error, 1), nil, nil, nil, nil)
+	}
+	exec := func(h *BaseCommandHandler, command string, args ...string) interface{} {
+		resp, err := h.ExecuteCommand(ctx, &cmd.DiceDBCmd{Cmd: command, Args: args})
+		if err != nil {
+			return err
+		}
+		return resp
+	}
+
+	h0, h1 := newHandler("db0"), newHandler("db1")
+	assert.Equal(t, clientio.OK, exec(h1, CmdSelect, "1"))
+	assert.Equal(t, diceerrors.ErrDBIndexOutOfRange, exec(h1, CmdSelect, "16"))
+	assert.Equal(t, diceerrors.ErrIntegerOutOfRange, exec(h1, CmdSelect, "one"))
+
+	for _, key := range []string{"a", "b", "c"} {
+		exec(h0, CmdSet, key, "v")
+	}
+	exec(h1, CmdSet, "d", "v")
+
+	assert.Equal(t, uint64(3), exec(h0, CmdDBSize))
+	assert.Equal(t, uint64(1), exec(h1, CmdDBSize))
+	assert.Equal(t, []string{"d"}, exec(h1, CmdKeys, "*"))
+
+	// MOVE refuses to overwrite a key and to move a key to its own database
+	assert.Equal(t, clientio.IntegerOne, exec(h0, CmdMove, "a", "1"))
+	exec(h0, CmdSet, "d", "v")
+	assert.Equal(t, clientio.IntegerZero, exec(h0, CmdMove, "d", "1"))
+	assert.Equal(t, diceerrors.ErrSameObject, exec(h0, CmdMove, "b", "0"))
+	assert.Equal(t, "v", exec(h1, CmdGet, "a"))
+
+	assert.Equal(t, clientio.OK, exec(h0, CmdSwapDB, "0", "1"))
+	assert.ElementsMatch(t, []string{"a", "d"}, exec(h0, CmdKeys, "*"))
+	assert.ElementsMatch(t, []string{"b", "c", "d"}, exec(h1, CmdKeys, "*"))
+	assert.Equal(t, diceerrors.ErrDBIndexOutOfRange, exec(h0, CmdSwapDB, "0", "16"))
+
+	// FLUSHDB leaves the other databases untouched
+	assert.Equal(t, clientio.OK, exec(h1, CmdFlushDB))
+	assert.Equal(t, uint64(0), exec(h1, CmdDBSize))
+	assert.Equal(t, uint64(2), exec(h0, CmdDBSize))
+}
+
+func TestSortAcrossShards(t *testing.T) {
+	require.NoError(t, config.NewConfigParser().ParseDefaults(config.DiceConfig))
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	shardManager := shard.NewShardManager(4, nil, make(chan error, 1))
+	go shardManager.Run(ctx)
+
+	responseChan, preprocessingChan := make(chan *ops.StoreResponse), make(chan *ops.StoreResponse)
+	shardManager.RegisterCommandHandler("sort", responseChan, preprocessingChan)
+	h := NewCommandHandler("sort", responseChan, preprocessingChan, nil, nil, shardManager,
+		make(chan error, 1), nil, nil, nil, nil)
+	exec := func(command string, args ...string) interface{} {
+		resp, err := h.ExecuteCommand(ctx, &cmd.DiceDBCmd{Cmd: command, Args: args})
+		if err != nil {
+			return err
+		}
+		return resp
+	}
+
+	// The weights and the objects are spread across the shards
+	exec(CmdRPush, "ids", "3", "1", "2")
+	for id, weight := range map[string]string{"1": "30", "2": "10", "3": "20"} {
+		exec(CmdSet, "weight_"+id, weight)
+		exec(CmdHSet, "user_"+id, "name", "user"+id)
+	}
+
+	assert.Equal(t, []interface{}{"1", "2", "3"}, exec(CmdSort, "ids"))
+	assert.Equal(t, []interface{}{"2", "3", "1"}, exec(CmdSort, "ids", "BY", "weight_*"))
+	assert.Equal(t, []interface{}{"1", "user1", "3", "user3"},
+		exec(CmdSortRO, "ids", "BY", "weight_*", "DESC", "LIMIT", "0", "2", "GET", "#", "GET", "user_*->name"))
+	assert.Equal(t, []interface{}{"3", "1", "2"}, exec(CmdSort, "ids", "BY", "nosort"))
+
+	// The result is stored on the shard owning the destination
+	assert.Equal(t, int64(3), exec(CmdSort, "ids", "BY", "user_*->name", "ALPHA", "GET", "weight_*", "STORE", "sorted"))
+	assert.Equal(t, []string{"30", "10", "20"}, exec(CmdLrange, "sorted", "0", "-1"))
+
+	assert.Equal(t, diceerrors.ErrSyntax, exec(CmdSortRO, "ids", "STORE", "sorted"))
+	assert.Equal(t, diceerrors.ErrWrongTypeOperation, exec(CmdSort, "weight_1"))
+	assert.Equal(t, diceerrors.ErrSortScoreNotDouble, exec(CmdSort, "ids", "BY", "user_*->name"))
+}
+
+func TestMemoryAcrossShards(t *testing.T) {
+	require.NoError(t, config.NewConfigParser().ParseDefaults(config.DiceConfig))
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	shardManager := shard.NewShardManager(4, nil, make(chan error, 1))
+	go shardManager.Run(ctx)
+
+	responseChan, preprocessingChan := make(chan *ops.StoreResponse), make(chan *ops.StoreResponse)
+	shardManager.RegisterCommandHandler("memory", responseChan, preprocessingChan)
+	h := NewCommandHandler("memory", responseChan, preprocessingChan, nil, nil, shardManager,
+		make(chan error, 1), nil, nil, nil, nil)
+	exec := func(command string, args ...string) interface{} {
+		resp, err := h.ExecuteCommand(ctx, &cmd.DiceDBCmd{Cmd: command, Args: args})
+		if err != nil {
+			return err
+		}
+		return resp
+	}
+
+	// The keys are spread across the shards
+	keys := []string{"k1", "k2", "k3", "k4", "k5", "k6", "k7", "k8"}
+	usage := int64(0)
+	for _, key := range keys {
+		exec(CmdSet, key, "value")
+		size, ok := exec(CmdMemory, "USAGE", key).(int64)
+		require.True(t, ok, key)
+		usage += size
+	}
+	exec(CmdHSet, "hash", "field", "value")
+	hashUsage := exec(CmdMemory, "usage", "hash").(int64)
+
+	assert.Equal(t, clientio.NIL, exec(CmdMemory, "USAGE", "missing"))
+	assert.Equal(t, diceerrors.ErrSyntax, exec(CmdMemory, "USAGE", "k1", "COUNT", "1"))
+
+	stats := exec(CmdMemory, "STATS").([]interface{})
+	require.Len(t, stats, 10)
+	assert.Equal(t, []interface{}{"keys.count", int64(9), "dataset.bytes", usage + hashUsage}, stats[:4])
+	assert.Equal(t, []interface{}{
+		"hash", []interface{}{"keys.count", int64(1), "dataset.bytes", hashUsage},
+		"string", []interface{}{"keys.count", int64(8), "dataset.bytes", usage},
+	}, stats[7])
+	shards := stats[9].([]interface{})
+	require.Len(t, shards, 8)
+	assert.Equal(t, []interface{}{"0", "1", "2", "3"}, []interface{}{shards[0], shards[2], shards[4], shards[6]})
+}
+
+func TestComposeInfo(t *testing.T) {
+	responses := []ops.StoreResponse{
+		{SeqID: 1, EvalResponse: &eval.EvalResponse{Result: &eval.InfoShardStats{
+			Stats:      store.Stats{KeyspaceHits: 2, ExpiredKeys: 1, ExpiredStaleRatio: 0.5, EvictedKeys: 3, Evictions: 1},
+			UsedMemory: 1024,
+			Databases:  []store.DatabaseStats{{Keys: 1}, {}, {Keys: 2, Expires: 1}},
+		}}},
+		{SeqID: 0, EvalResponse: &eval.EvalResponse{Result: &eval.InfoShardStats{
+			Stats:      store.Stats{KeyspaceHits: 1, KeyspaceMisses: 4, ExpireCycles: 2, ExpireCycleTime: 3 * time.Millisecond},
+			UsedMemory: 512,
+			Databases:  []store.DatabaseStats{{Keys: 3, Expires: 2}, {}, {}},
+		}}},
+	}
+
+	info := string(composeInfo(responses...).([]byte))
+	for _, header := range []string{"Server", "Clients", "Memory", "Persistence", "Stats", "Replication", "CPU", "Keyspace"} {
+		assert.Contains(t, info, "# "+header+"\r\n")
+	}
+	assert.NotContains(t, info, "# Commandstats", "The command stats are not part of the default sections")
+	assert.Contains(t, info, "\r\nused_memory:1536\r\nused_memory_human:1.50K\r\n")
+	assert.Contains(t, info, "# Keyspace\r\ndb0:keys=4,expires=2\r\ndb2:keys=2,expires=1\r\n")
+	assert.Contains(t, info, "\r\nkeyspace_hits:3\r\nkeyspace_misses:4\r\nexpired_keys:1\r\nexpired_stale_perc:25.00\r\n")
+	assert.Contains(t, info, "\r\nexpire_cycles:2\r\nexpire_cycle_cpu_milliseconds:3\r\n")
+	assert.Contains(t, info, "\r\nevicted_keys:3\r\nevictions:1\r\n")
+	assert.Contains(t, info, "\r\nshard0:keyspace_hits=1,keyspace_misses=4,expired_keys=0,expired_stale_perc=0.00,expire_cycles=2,")
+	assert.Contains(t, info, "\r\nshard1:keyspace_hits=2,keyspace_misses=0,expired_keys=1,expired_stale_perc=50.00,expire_cycles=0,")
+
+	// The requested sections are written in the order of INFO
+	responses[0].EvalResponse.Result.(*eval.InfoShardStats).Sections = []string{"keyspace", "commandstats"}
+	info = string(composeInfo(responses...).([]byte))
+	assert.Regexp(t, "^\\$\\d+\r\n# Commandstats\r\n(cmdstat_.*\r\n)*\r\n# Keyspace\r\n", info)
+
+	// The unknown sections are ignored
+	responses[0].EvalResponse.Result.(*eval.InfoShardStats).Sections = []string{"unknown"}
+	assert.Equal(t, clientio.Encode("", false), composeInfo(responses...))
+}
+
+func TestRecordCommand(t *testing.T) {
+	before := GetCommandStats()["echo"]
+
+	recordCommand("ECHO", 2*time.Millisecond, "hello", nil)
+	recordCommand("echo", time.Millisecond, diceerrors.ErrWrongArgumentCount("ECHO"), nil)
+	recordCommand("NOTACOMMAND", time.Millisecond, nil, nil)
+
+	stats := GetCommandStats()
+	assert.Equal(t, before.Calls+2, stats["echo"].Calls)
+	assert.Equal(t, before.FailedCalls+1, stats["echo"].FailedCalls)
+	assert.Equal(t, before.Time+3*time.Millisecond, stats["echo"].Time)
+	assert.NotContains(t, stats, "notacommand", "The unknown commands are not counted")
+}
+
+func TestComposeSlowLog(t *testing.T) {
+	at := time.Unix(1700000000, 0)
+	responses := []ops.StoreResponse{
+		{SeqID: 0, EvalResponse: &eval.EvalResponse{Result: &eval.SlowLogShardEntries{Count: 2, Entries: []store.SlowLogEntry{
+			{ID: 3, Time: at, Duration: 15 * time.Millisecond, Args: []string{"KEYS", "*"}, ClientAddr: "127.0.0.1:5000"},
+			{ID: 1, Time: at, Duration: 12 * time.Millisecond, Args: []string{"GET", "a"}},
+		}}}},
+		{SeqID: 1, EvalResponse: &eval.EvalResponse{Result: &eval.SlowLogShardEntries{Count: 2, Entries: []store.SlowLogEntry{
+			{ID: 2, Time: at, Duration: 20 * time.Millisecond, Args: []string{"GET", "b"}, ClientName: "worker"},
+		}}}},
+	}
+
+	// The entries of the shards are merged from the most recent one, up to the count
+	assert.Equal(t, []interface{}{
+		[]interface{}{int64(3), int64(1700000000), int64(15000), []string{"KEYS", "*"}, "127.0.0.1:5000", ""},
+		[]interface{}{int64(2), int64(1700000000), int64(20000), []string{"GET", "b"}, "", "worker"},
+	}, composeSlowLog(responses...))
+
+	assert.Equal(t, int64(5), composeSlowLog(
+		ops.StoreResponse{EvalResponse: &eval.EvalResponse{Result: int64(2)}},
+		ops.StoreResponse{EvalResponse: &eval.EvalResponse{Result: int64(3)}},
+	))
+}
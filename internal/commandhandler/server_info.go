@@ -0,0 +1,102 @@
+// Copyright (c) 2022-present, DiceDB contributors
+// All rights reserved. Licensed under the BSD 3-Clause License. See LICENSE file in the project root for full license information.
+
+package commandhandler
+
+import (
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/dicedb/dice/internal/eval"
+	"github.com/dicedb/dice/internal/iothread"
+	"github.com/dicedb/dice/internal/observability"
+	"github.com/dicedb/dice/internal/wal"
+	"github.com/dicedb/dice/internal/watchmanager"
+)
+
+// ServerInfo holds the state of the server that INFO and the metrics report, besides the
+// statistics of the shards.
+type ServerInfo struct {
+	StartTime time.Time
+	IOThreads *iothread.Manager
+	WAL       wal.AbstractWAL
+	Watches   *watchmanager.Manager
+}
+
+// serverInfo is set by the server once it runs, see SetServerInfo.
+var serverInfo atomic.Pointer[ServerInfo]
+
+// SetServerInfo sets the state of the server reported by INFO.
+func SetServerInfo(info *ServerInfo) {
+	serverInfo.Store(info)
+}
+
+// GetServerInfo returns the state of the server, which is empty until the server runs.
+func GetServerInfo() *ServerInfo {
+	if info := serverInfo.Load(); info != nil {
+		return info
+	}
+	return &ServerInfo{}
+}
+
+// CommandStats holds the calls of a command since the server started, reported by INFO and the
+// metrics. Time is the time they took in total, and Latency the time each of them took.
+type CommandStats struct {
+	Calls       uint64
+	FailedCalls uint64
+	Time        time.Duration
+	Latency     observability.HistogramSnapshot
+}
+
+// commandCounters counts the calls of a command, which the command handlers update concurrently.
+type commandCounters struct {
+	calls       atomic.Uint64
+	failedCalls atomic.Uint64
+	latency     observability.Histogram
+}
+
+// commandStats holds the *commandCounters of the commands called, by lower-case name.
+var commandStats sync.Map
+
+// recordCommand counts a call of the command, which took the given time and failed if it returned
+// an error. The unknown commands are not counted, so that the clients cannot grow the stats.
+func recordCommand(name string, duration time.Duration, resp interface{}, err error) {
+	name = strings.ToUpper(name)
+	if _, ok := eval.DiceCmds[name]; !ok {
+		if _, ok := CommandsMeta[name]; !ok {
+			return
+		}
+	}
+
+	name = strings.ToLower(name)
+	v, ok := commandStats.Load(name)
+	if !ok {
+		v, _ = commandStats.LoadOrStore(name, &commandCounters{})
+	}
+	counters := v.(*commandCounters)
+	counters.calls.Add(1)
+	counters.latency.Observe(duration)
+	if _, isErr := resp.(error); isErr || err != nil {
+		counters.failedCalls.Add(1)
+	}
+}
+
+// GetCommandStats returns the calls of the commands called since the server started, by lower-case
+// name.
+func GetCommandStats() map[string]CommandStats {
+	stats := make(map[string]CommandStats)
+	commandStats.Range(func(name, v any) bool {
+		counters := v.(*commandCounters)
+		latency := counters.latency.Snapshot()
+		stats[name.(string)] = CommandStats{
+			Calls:       counters.calls.Load(),
+			FailedCalls: counters.failedCalls.Load(),
+			Time:        latency.Sum,
+			Latency:     latency,
+		}
+		return true
+	})
+	return stats
+}
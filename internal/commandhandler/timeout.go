@@ -0,0 +1,86 @@
+// Copyright (c) 2022-present, DiceDB contributors
+// All rights reserved. Licensed under the BSD 3-Clause License. See LICENSE file in the project root for full license information.
+
+package commandhandler
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/dicedb/dice/config"
+	"github.com/dicedb/dice/internal/cmd"
+)
+
+// defaultRequestTimeout is used when no request timeout is configured.
+const defaultRequestTimeout = 6 * time.Second
+
+// RequestTimeout returns how long a command may take to complete. A zero duration means that the
+// command is not subject to a timeout.
+//
+// A timeout configured for the command takes precedence. Blocking commands are otherwise exempt
+// from the request timeout, unless they carry their own timeout argument, in which case the
+// request timeout is added on top of it.
+func RequestTimeout(diceDBCmd *cmd.DiceDBCmd) time.Duration {
+	if timeout, ok := config.DiceConfig.Performance.CommandTimeoutsByName[diceDBCmd.Cmd]; ok {
+		return timeout
+	}
+
+	timeout := DefaultRequestTimeout()
+
+	meta, ok := CommandsMeta[diceDBCmd.Cmd]
+	if !ok || !meta.blocking {
+		return timeout
+	}
+
+	if meta.blockingTimeout == nil {
+		return 0
+	}
+
+	blockFor, ok := meta.blockingTimeout(diceDBCmd.Args)
+	if !ok {
+		return 0
+	}
+
+	return blockFor + timeout
+}
+
+// DefaultRequestTimeout returns the request timeout of the commands without a timeout of their own.
+func DefaultRequestTimeout() time.Duration {
+	if timeout := config.DiceConfig.Performance.RequestTimeout; timeout > 0 {
+		return timeout
+	}
+	return defaultRequestTimeout
+}
+
+// RequestContext returns the context of a request executing the given commands, done once the
+// longest of their timeouts elapses. It has no deadline if one of them is not subject to a timeout.
+func RequestContext(ctx context.Context, commands ...*cmd.DiceDBCmd) (context.Context, context.CancelFunc) {
+	var timeout time.Duration
+	for _, diceDBCmd := range commands {
+		commandTimeout := RequestTimeout(diceDBCmd)
+		if commandTimeout == 0 {
+			return context.WithCancel(ctx)
+		}
+		timeout = max(timeout, commandTimeout)
+	}
+
+	if timeout == 0 {
+		timeout = DefaultRequestTimeout()
+	}
+	return context.WithTimeout(ctx, timeout)
+}
+
+// sleepTimeout returns how long SLEEP blocks for, as given by its argument in seconds.
+func sleepTimeout(args []string) (time.Duration, bool) {
+	if len(args) != 1 {
+		return 0, false
+	}
+
+	seconds, err := strconv.ParseInt(args[0], 10, 64)
+	if err != nil || seconds < 0 {
+		return 0, false
+	}
+
+	return time.Duration(seconds) * time.Second, true
+}
@@ -0,0 +1,73 @@
+// Copyright (c) 2022-present, DiceDB contributors
+// All rights reserved. Licensed under the BSD 3-Clause License. See LICENSE file in the project root for full license information.
+
+package commandhandler
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/dicedb/dice/config"
+	"github.com/dicedb/dice/internal/cmd"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequestTimeout(t *testing.T) {
+	performance := config.DiceConfig.Performance
+	defer func() { config.DiceConfig.Performance = performance }()
+
+	config.DiceConfig.Performance.RequestTimeout = 2 * time.Second
+	config.DiceConfig.Performance.CommandTimeoutsByName = map[string]time.Duration{"KEYS": 30 * time.Second, "GET": 0}
+
+	tests := []struct {
+		name     string
+		cmd      *cmd.DiceDBCmd
+		expected time.Duration
+	}{
+		{"global timeout", &cmd.DiceDBCmd{Cmd: CmdSet, Args: []string{"k", "v"}}, 2 * time.Second},
+		{"per-command timeout", &cmd.DiceDBCmd{Cmd: CmdKeys, Args: []string{"*"}}, 30 * time.Second},
+		{"per-command timeout disabled", &cmd.DiceDBCmd{Cmd: CmdGet, Args: []string{"k"}}, 0},
+		{"blocking command with own timeout", &cmd.DiceDBCmd{Cmd: CmdSleep, Args: []string{"10"}}, 12 * time.Second},
+		{"blocking command with invalid timeout", &cmd.DiceDBCmd{Cmd: CmdSleep, Args: []string{"abc"}}, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, RequestTimeout(tt.cmd))
+		})
+	}
+}
+
+func TestRequestTimeoutDefault(t *testing.T) {
+	performance := config.DiceConfig.Performance
+	defer func() { config.DiceConfig.Performance = performance }()
+
+	config.DiceConfig.Performance.RequestTimeout = 0
+	config.DiceConfig.Performance.CommandTimeoutsByName = nil
+
+	assert.Equal(t, defaultRequestTimeout, RequestTimeout(&cmd.DiceDBCmd{Cmd: CmdGet, Args: []string{"k"}}))
+}
+
+func TestRequestContext(t *testing.T) {
+	performance := config.DiceConfig.Performance
+	defer func() { config.DiceConfig.Performance = performance }()
+
+	config.DiceConfig.Performance.RequestTimeout = 2 * time.Second
+	config.DiceConfig.Performance.CommandTimeoutsByName = map[string]time.Duration{"KEYS": 30 * time.Second}
+
+	// the request is given the longest of the timeouts of its commands
+	ctx, cancel := RequestContext(context.Background(),
+		&cmd.DiceDBCmd{Cmd: CmdGet, Args: []string{"k"}}, &cmd.DiceDBCmd{Cmd: CmdKeys, Args: []string{"*"}})
+	deadline, ok := ctx.Deadline()
+	cancel()
+	assert.True(t, ok)
+	assert.WithinDuration(t, time.Now().Add(30*time.Second), deadline, time.Second)
+
+	// a blocking command without a timeout argument lifts the deadline of the request
+	ctx, cancel = RequestContext(context.Background(),
+		&cmd.DiceDBCmd{Cmd: CmdGet, Args: []string{"k"}}, &cmd.DiceDBCmd{Cmd: CmdSleep, Args: []string{"abc"}})
+	_, ok = ctx.Deadline()
+	cancel()
+	assert.False(t, ok)
+}
@@ -30,6 +30,14 @@ var (
 	ErrInvalidExpireTimeValue     = errors.New("ERR invalid expire time")                                                // Indicates that the provided expiration time is invalid.
 	ErrHashValueNotInteger        = errors.New("ERR hash value is not an integer")                                       // Signifies that a hash value is expected to be an integer.
 	ErrInternalServer             = errors.New("ERR Internal server error, unable to process command")                   // Represents a generic internal server error.
+	ErrInvalidCursor              = errors.New("ERR invalid cursor")                                                     // Indicates that a SCAN cursor is malformed or points to no shard.
+	ErrDBIndexOutOfRange          = errors.New("ERR DB index is out of range")                                           // Indicates that a logical database does not exist.
+	ErrSameObject                 = errors.New("ERR source and destination objects are the same")                        // Signals that a key is moved onto itself.
+	ErrFieldsArgMissing           = errors.New("ERR Mandatory argument FIELDS is missing or not at the right position")  // Indicates that the FIELDS argument of a hash field command is missing.
+	ErrNumFieldsInvalid           = errors.New("ERR Parameter `numFields` should be greater than 0")                     // Indicates that the number of fields of a hash field command is not positive.
+	ErrNumFieldsMismatch          = errors.New("ERR The `numfields` parameter must match the number of arguments")       // Signals that the number of fields does not match the fields given.
+	ErrSortScoreNotDouble         = errors.New("ERR One or more scores can't be converted into double")                  // Indicates that SORT could not read an element or its weight as a number.
+	ErrOOM                        = errors.New("OOM command not allowed when used memory > 'maxmemory'.")                // Signals that a write is rejected since the shard is out of memory.
 	ErrAuth                       = errors.New("AUTH <password> called without any password configured for the default user. Are you sure your configuration is correct?")
 	ErrAborted                    = errors.New("server received ABORT command")
 	ErrEmptyCommand               = errors.New("empty command")
@@ -7,6 +7,7 @@ import (
 	"errors"
 	"fmt"
 	"strconv"
+	"unsafe"
 
 	"github.com/dicedb/dice/internal/object"
 
@@ -193,6 +194,11 @@ func (b *ByteArray) ResizeIfNecessary() *ByteArray {
 	return b
 }
 
+// Size returns the memory used by the byte array, in bytes. See object.Sizer.
+func (b *ByteArray) Size(_ int) int64 {
+	return int64(unsafe.Sizeof(*b)) + int64(cap(b.data))
+}
+
 // DeepCopy creates a deep copy of the ByteArray
 func (b *ByteArray) DeepCopy() *ByteArray {
 	if b == nil {
@@ -95,6 +95,22 @@ func (b *byteList) delete(bn *byteListNode) {
 	b.size -= byteListNodeSize
 }
 
+// Size estimates the memory used by the list, in bytes, from the buffers of the given number of
+// its nodes, or of all of them when it is not positive.
+func (b *byteList) Size(samples int) int64 {
+	size := int64(unsafe.Sizeof(*b)) + b.size
+
+	var sampled, buffers int64
+	for node := b.head; node != nil && (samples <= 0 || sampled < int64(samples)); node = node.next {
+		buffers += int64(cap(node.buf))
+		sampled++
+	}
+	if sampled > 0 {
+		size += buffers * (b.size / byteListNodeSize) / sampled
+	}
+	return size
+}
+
 // DeepCopy creates a deep copy of the byteList.
 func (b *byteList) DeepCopy() *byteList {
 	if b == nil {
@@ -42,6 +42,10 @@ type DiceCmdMeta struct {
 	// Only commands that really requires full object definition to pass across multiple shards
 	// should implement this function. e.g. COPY, RENAME etc
 	StoreObjectEval func(*cmd.DiceDBCmd, *dstore.Store) *EvalResponse
+
+	// DenyOOM marks the commands that may grow the memory used by the keys. They are rejected once
+	// the shard uses more than its share of memory.max_memory, and eviction cannot free it up.
+	DenyOOM bool
 }
 
 type KeySpecs struct {
@@ -120,6 +124,7 @@ var (
 		StoreObjectEval: evalCOPYObject,
 		IsMigrated:      true,
 		Arity:           -2,
+		DenyOOM:         true,
 	}
 	pfMergeCmdMeta = DiceCmdMeta{
 		Name: "PFMERGE",
@@ -129,6 +134,40 @@ var (
 		Arity:           -2,
 		KeySpecs:        KeySpecs{BeginIndex: 1},
 		StoreObjectEval: evalPFMERGE,
+		DenyOOM:         true,
+	}
+	sortCmdMeta = DiceCmdMeta{
+		Name: "SORT",
+		Info: `SORT key [BY pattern] [LIMIT offset count] [GET pattern [GET pattern ...]] [ASC | DESC] [ALPHA]
+		[STORE destination]
+		Sorts the elements of the list, set or sorted set stored at key, numerically unless ALPHA is given.
+		BY weights the elements by the keys or hash fields the pattern reads, where '*' stands for the
+		element and '->' introduces a hash field. A pattern without '*', such as nosort, skips sorting.
+		GET returns the values read by the patterns instead of the elements, '#' reading the element itself.
+		Returns the sorted elements, or their number when they are stored as a list at destination.`,
+		NewEval:         evalSORT,
+		StoreObjectEval: evalSortObject,
+		IsMigrated:      true,
+		Arity:           -2,
+		KeySpecs:        KeySpecs{BeginIndex: 1},
+	}
+	sortROCmdMeta = DiceCmdMeta{
+		Name: "SORT_RO",
+		Info: `SORT_RO key [BY pattern] [LIMIT offset count] [GET pattern [GET pattern ...]] [ASC | DESC] [ALPHA]
+		Read-only variant of SORT, which does not take STORE.`,
+		NewEval:         evalSORTRO,
+		StoreObjectEval: evalSortObject,
+		IsMigrated:      true,
+		Arity:           -2,
+		KeySpecs:        KeySpecs{BeginIndex: 1},
+	}
+	sortStoreCmdMeta = DiceCmdMeta{
+		Name:            "SORTSTORE",
+		Info:            `SORTSTORE runs a SORT with STORE on the shard owning its destination.`,
+		StoreObjectEval: evalSORTSTORE,
+		IsMigrated:      true,
+		Arity:           -3,
+		DenyOOM:         true,
 	}
 )
 
@@ -151,6 +190,7 @@ var (
 		KeySpecs:   KeySpecs{BeginIndex: 1},
 		IsMigrated: true,
 		NewEval:    evalSET,
+		DenyOOM:    true,
 	}
 	getCmdMeta = DiceCmdMeta{
 		Name: "GET",
@@ -168,8 +208,10 @@ var (
 		Name:       "GETSET",
 		Info:       `GETSET returns the previous string value of a key after setting it to a new value.`,
 		Arity:      2,
+		KeySpecs:   KeySpecs{BeginIndex: 1},
 		IsMigrated: true,
 		NewEval:    evalGETSET,
+		DenyOOM:    true,
 	}
 
 	getDelCmdMeta = DiceCmdMeta{
@@ -193,6 +235,7 @@ var (
 		Arity:      -3,
 		KeySpecs:   KeySpecs{BeginIndex: 1},
 		IsMigrated: true,
+		DenyOOM:    true,
 	}
 	jsongetCmdMeta = DiceCmdMeta{
 		Name: "JSON.GET",
@@ -261,8 +304,10 @@ var (
         Returns an array of integer replies for each path, the array's new size,
         or nil, if the matching JSON value is not an array.`,
 		Arity:      -3,
+		KeySpecs:   KeySpecs{BeginIndex: 1},
 		IsMigrated: true,
 		NewEval:    evalJSONARRAPPEND,
+		DenyOOM:    true,
 	}
 	jsonforgetCmdMeta = DiceCmdMeta{
 		Name: "JSON.FORGET",
@@ -294,6 +339,7 @@ var (
 		Arity:      3,
 		KeySpecs:   KeySpecs{BeginIndex: 1},
 		IsMigrated: true,
+		DenyOOM:    true,
 	}
 	jsonobjlenCmdMeta = DiceCmdMeta{
 		Name: "JSON.OBJLEN",
@@ -336,6 +382,7 @@ var (
 		It supports negative index and is out of bound safe.
 		`,
 		Arity:      -2,
+		KeySpecs:   KeySpecs{BeginIndex: 1},
 		IsMigrated: true,
 		NewEval:    evalJSONARRPOP,
 	}
@@ -350,6 +397,7 @@ var (
 		Arity:      -3,
 		KeySpecs:   KeySpecs{BeginIndex: 1},
 		IsMigrated: true,
+		DenyOOM:    true,
 	}
 	jsonarrinsertCmdMeta = DiceCmdMeta{
 		Name: "JSON.ARRINSERT",
@@ -362,6 +410,7 @@ var (
 		IsMigrated: true,
 		Arity:      -5,
 		KeySpecs:   KeySpecs{BeginIndex: 1},
+		DenyOOM:    true,
 	}
 	jsonrespCmdMeta = DiceCmdMeta{
 		Name: "JSON.RESP",
@@ -382,6 +431,7 @@ var (
 		NewEval:    evalJSONARRTRIM,
 		IsMigrated: true,
 		Arity:      -5,
+		KeySpecs:   KeySpecs{BeginIndex: 1},
 	}
 	ttlCmdMeta = DiceCmdMeta{
 		Name: "TTL",
@@ -452,6 +502,7 @@ var (
 		IsMigrated: true,
 		Arity:      2,
 		KeySpecs:   KeySpecs{BeginIndex: 1, Step: 1},
+		DenyOOM:    true,
 	}
 	incrByFloatCmdMeta = DiceCmdMeta{
 		Name: "INCRBYFLOAT",
@@ -463,8 +514,10 @@ var (
 		if not INCRBYFLOAT returns an  error response.
 		INCRBYFLOAT returns the incremented value for the key after applying the specified increment if there are no errors.`,
 		Arity:      2,
+		KeySpecs:   KeySpecs{BeginIndex: 1},
 		NewEval:    evalINCRBYFLOAT,
 		IsMigrated: true,
+		DenyOOM:    true,
 	}
 	clientCmdMeta = DiceCmdMeta{
 		Name:       "CLIENT",
@@ -488,6 +541,7 @@ var (
 		NewEval:    evalBFRESERVE,
 		Arity:      -2,
 		KeySpecs:   KeySpecs{BeginIndex: 1, Step: 1},
+		DenyOOM:    true,
 	}
 	bfaddCmdMeta = DiceCmdMeta{
 		Name: "BF.ADD",
@@ -498,6 +552,7 @@ var (
 		NewEval:    evalBFADD,
 		Arity:      3,
 		KeySpecs:   KeySpecs{BeginIndex: 1, Step: 1},
+		DenyOOM:    true,
 	}
 	bfexistsCmdMeta = DiceCmdMeta{
 		Name:       "BF.EXISTS",
@@ -519,6 +574,8 @@ var (
 		Info:       "SETBIT sets or clears the bit at offset in the string value stored at key",
 		IsMigrated: true,
 		NewEval:    evalSETBIT,
+		DenyOOM:    true,
+		KeySpecs:   KeySpecs{BeginIndex: 1},
 	}
 	getBitCmdMeta = DiceCmdMeta{
 		Name:       "GETBIT",
@@ -539,6 +596,7 @@ var (
 		Info:       "PERSIST removes the expiration from a key",
 		IsMigrated: true,
 		NewEval:    evalPERSIST,
+		KeySpecs:   KeySpecs{BeginIndex: 1},
 	}
 
 	commandCmdMeta = DiceCmdMeta{
@@ -617,6 +675,16 @@ var (
 		IsMigrated: true,
 	}
 
+	// Internal command used to scan the shard a SCAN cursor points to (works internally with the SCAN command)
+	singleScanCmdMeta = DiceCmdMeta{
+		Name: "SINGLESCAN",
+		Info: `SCAN command is used to incrementally iterate over the keys in the database.
+		The cursor encodes the shard being scanned and the position in that shard.`,
+		NewEval:    evalSCAN,
+		Arity:      -3,
+		IsMigrated: true,
+	}
+
 	decrCmdMeta = DiceCmdMeta{
 		Name: "DECR",
 		Info: `DECR decrements the value of the specified key in args by 1,
@@ -631,6 +699,7 @@ var (
 		IsMigrated: true,
 		Arity:      2,
 		KeySpecs:   KeySpecs{BeginIndex: 1, Step: 1},
+		DenyOOM:    true,
 	}
 	decrByCmdMeta = DiceCmdMeta{
 		Name: "DECRBY",
@@ -646,6 +715,7 @@ var (
 		IsMigrated: true,
 		Arity:      3,
 		KeySpecs:   KeySpecs{BeginIndex: 1, Step: 1},
+		DenyOOM:    true,
 	}
 	existsCmdMeta = DiceCmdMeta{
 		Name: "EXISTS",
@@ -688,6 +758,7 @@ var (
 		Arity:      -4,
 		KeySpecs:   KeySpecs{BeginIndex: 1},
 		IsMigrated: true,
+		DenyOOM:    true,
 	}
 	hmsetCmdMeta = DiceCmdMeta{
 		Name: "HMSET",
@@ -701,6 +772,7 @@ var (
 		Arity:      -4,
 		KeySpecs:   KeySpecs{BeginIndex: 1},
 		IsMigrated: true,
+		DenyOOM:    true,
 	}
 	hkeysCmdMeta = DiceCmdMeta{
 		Name:       "HKEYS",
@@ -719,6 +791,7 @@ var (
 		Arity:      4,
 		KeySpecs:   KeySpecs{BeginIndex: 1},
 		IsMigrated: true,
+		DenyOOM:    true,
 	}
 	hgetCmdMeta = DiceCmdMeta{
 		Name:       "HGET",
@@ -762,6 +835,7 @@ var (
 		KeySpecs:   KeySpecs{BeginIndex: 1},
 		IsMigrated: true,
 		NewEval:    evalHINCRBY,
+		DenyOOM:    true,
 	}
 	hstrLenCmdMeta = DiceCmdMeta{
 		Name:       "HSTRLEN",
@@ -796,6 +870,107 @@ var (
 		Arity:      -3,
 		KeySpecs:   KeySpecs{BeginIndex: 1},
 	}
+	hexpireCmdMeta = DiceCmdMeta{
+		Name: "HEXPIRE",
+		Info: `HEXPIRE sets a time to live, in seconds, on one or more fields of the hash stored at key.
+		NX, XX, GT and LT set the expiry only if the field has no expiry, has one, or if the new expiry
+		is greater or less than the current one.
+		Returns, for each field, -2 if it does not exist, 0 if the condition is not met, 1 if the expiry
+		is set, and 2 if the field is deleted since the time is already past.`,
+		NewEval:    evalHEXPIRE,
+		IsMigrated: true,
+		Arity:      -6,
+		KeySpecs:   KeySpecs{BeginIndex: 1},
+	}
+	hpexpireCmdMeta = DiceCmdMeta{
+		Name:       "HPEXPIRE",
+		Info:       `HPEXPIRE works like HEXPIRE, with the time to live in milliseconds.`,
+		NewEval:    evalHPEXPIRE,
+		IsMigrated: true,
+		Arity:      -6,
+		KeySpecs:   KeySpecs{BeginIndex: 1},
+	}
+	hexpireatCmdMeta = DiceCmdMeta{
+		Name:       "HEXPIREAT",
+		Info:       `HEXPIREAT works like HEXPIRE, with the expiry as an absolute Unix time in seconds.`,
+		NewEval:    evalHEXPIREAT,
+		IsMigrated: true,
+		Arity:      -6,
+		KeySpecs:   KeySpecs{BeginIndex: 1},
+	}
+	hpexpireatCmdMeta = DiceCmdMeta{
+		Name:       "HPEXPIREAT",
+		Info:       `HPEXPIREAT works like HEXPIRE, with the expiry as an absolute Unix time in milliseconds.`,
+		NewEval:    evalHPEXPIREAT,
+		IsMigrated: true,
+		Arity:      -6,
+		KeySpecs:   KeySpecs{BeginIndex: 1},
+	}
+	httlCmdMeta = DiceCmdMeta{
+		Name: "HTTL",
+		Info: `HTTL returns the remaining time to live, in seconds, of one or more fields of the hash stored at key.
+		Returns, for each field, -2 if it does not exist and -1 if it has no expiry.`,
+		NewEval:    evalHTTL,
+		IsMigrated: true,
+		Arity:      -5,
+		KeySpecs:   KeySpecs{BeginIndex: 1},
+	}
+	hpttlCmdMeta = DiceCmdMeta{
+		Name:       "HPTTL",
+		Info:       `HPTTL works like HTTL, with the time to live in milliseconds.`,
+		NewEval:    evalHPTTL,
+		IsMigrated: true,
+		Arity:      -5,
+		KeySpecs:   KeySpecs{BeginIndex: 1},
+	}
+	hexpiretimeCmdMeta = DiceCmdMeta{
+		Name: "HEXPIRETIME",
+		Info: `HEXPIRETIME returns the absolute Unix time, in seconds, at which one or more fields of the hash
+		stored at key expire.
+		Returns, for each field, -2 if it does not exist and -1 if it has no expiry.`,
+		NewEval:    evalHEXPIRETIME,
+		IsMigrated: true,
+		Arity:      -5,
+		KeySpecs:   KeySpecs{BeginIndex: 1},
+	}
+	hpexpiretimeCmdMeta = DiceCmdMeta{
+		Name:       "HPEXPIRETIME",
+		Info:       `HPEXPIRETIME works like HEXPIRETIME, with the Unix time in milliseconds.`,
+		NewEval:    evalHPEXPIRETIME,
+		IsMigrated: true,
+		Arity:      -5,
+		KeySpecs:   KeySpecs{BeginIndex: 1},
+	}
+	hpersistCmdMeta = DiceCmdMeta{
+		Name: "HPERSIST",
+		Info: `HPERSIST removes the expiry of one or more fields of the hash stored at key.
+		Returns, for each field, -2 if it does not exist, -1 if it has no expiry, and 1 if the expiry
+		is removed.`,
+		NewEval:    evalHPERSIST,
+		IsMigrated: true,
+		Arity:      -5,
+		KeySpecs:   KeySpecs{BeginIndex: 1},
+	}
+	sscanCmdMeta = DiceCmdMeta{
+		Name: "SSCAN",
+		Info: `SSCAN is used to iterate over the members of a set.
+		It returns a cursor and a list of members.
+		The command returns a cursor value of 0 when all the members are iterated.`,
+		NewEval:    evalSSCAN,
+		IsMigrated: true,
+		Arity:      -3,
+		KeySpecs:   KeySpecs{BeginIndex: 1},
+	}
+	zscanCmdMeta = DiceCmdMeta{
+		Name: "ZSCAN",
+		Info: `ZSCAN is used to iterate over the members and scores of a sorted set.
+		It returns a cursor and a list of member-score pairs.
+		The command returns a cursor value of 0 when all the members are iterated.`,
+		NewEval:    evalZSCAN,
+		IsMigrated: true,
+		Arity:      -3,
+		KeySpecs:   KeySpecs{BeginIndex: 1},
+	}
 	hexistsCmdMeta = DiceCmdMeta{
 		Name:       "HEXISTS",
 		Info:       `Returns if field is an existing field in the hash stored at key.`,
@@ -815,6 +990,40 @@ var (
 		IsMigrated: true,
 	}
 
+	memoryCmdMeta = DiceCmdMeta{
+		Name: "MEMORY",
+		Info: `MEMORY USAGE key [SAMPLES count] | MEMORY STATS
+		USAGE returns the number of bytes used by key and its value, estimating the size of an aggregate
+		value from count of its elements, 5 by default and all of them with 0.
+		STATS returns the number of keys and the bytes they use, in total, per type and per shard.`,
+		NewEval:    evalMEMORY,
+		Arity:      -2,
+		KeySpecs:   KeySpecs{BeginIndex: 2},
+		IsMigrated: true,
+	}
+
+	infoCmdMeta = DiceCmdMeta{
+		Name: "INFO",
+		Info: `INFO [section [section ...]]
+		Returns the statistics of the server, in the given sections or in the default ones. The
+		stats section holds the keyspace hits and misses, the expired and evicted keys, and the
+		expire cycles, in total and per shard.`,
+		NewEval:    evalINFO,
+		Arity:      -1,
+		IsMigrated: true,
+	}
+
+	slowlogCmdMeta = DiceCmdMeta{
+		Name: "SLOWLOG",
+		Info: `SLOWLOG GET [count] | SLOWLOG LEN | SLOWLOG RESET
+		GET returns the last count commands which took at least performance.slowlog_log_slower_than
+		microseconds to execute, the most recent first, 10 by default and all of them with -1.
+		LEN returns the number of commands recorded and RESET deletes them.`,
+		NewEval:    evalSLOWLOG,
+		Arity:      -2,
+		IsMigrated: true,
+	}
+
 	// Internal command used to spawn request across all shards (works internally with Touch command)
 	singleTouchCmdMeta = DiceCmdMeta{
 		Name: "SINGLETOUCH",
@@ -834,6 +1043,8 @@ var (
 		NewEval:    evalLPUSH,
 		IsMigrated: true,
 		Arity:      -3,
+		KeySpecs:   KeySpecs{BeginIndex: 1},
+		DenyOOM:    true,
 	}
 	rpushCmdMeta = DiceCmdMeta{
 		Name:       "RPUSH",
@@ -841,6 +1052,8 @@ var (
 		NewEval:    evalRPUSH,
 		IsMigrated: true,
 		Arity:      -3,
+		KeySpecs:   KeySpecs{BeginIndex: 1},
+		DenyOOM:    true,
 	}
 	lpopCmdMeta = DiceCmdMeta{
 		Name:       "LPOP",
@@ -848,6 +1061,7 @@ var (
 		NewEval:    evalLPOP,
 		IsMigrated: true,
 		Arity:      2,
+		KeySpecs:   KeySpecs{BeginIndex: 1},
 	}
 	rpopCmdMeta = DiceCmdMeta{
 		Name:       "RPOP",
@@ -855,6 +1069,7 @@ var (
 		NewEval:    evalRPOP,
 		IsMigrated: true,
 		Arity:      2,
+		KeySpecs:   KeySpecs{BeginIndex: 1},
 	}
 	llenCmdMeta = DiceCmdMeta{
 		Name: "LLEN",
@@ -882,6 +1097,24 @@ var (
 		IsMigrated: true,
 		Arity:      -1,
 	}
+	moveCmdMeta = DiceCmdMeta{
+		Name: "MOVE",
+		Info: `MOVE key db
+		Moves key from the selected database to the given database, along with its expiry.
+		Returns 1 if the key was moved, and 0 if it does not exist or already exists in the destination.`,
+		NewEval:    evalMOVE,
+		IsMigrated: true,
+		Arity:      3,
+		KeySpecs:   KeySpecs{BeginIndex: 1},
+	}
+	swapdbCmdMeta = DiceCmdMeta{
+		Name: "SWAPDB",
+		Info: `SWAPDB index1 index2
+		Swaps the keys of two databases, so that the clients connected to one of them see the keys of the other.`,
+		NewEval:    evalSWAPDB,
+		IsMigrated: true,
+		Arity:      3,
+	}
 	bitposCmdMeta = DiceCmdMeta{
 		Name: "BITPOS",
 		Info: `BITPOS returns the position of the first bit set to 1 or 0 in a string
@@ -922,6 +1155,7 @@ var (
 		Arity:      -3,
 		KeySpecs:   KeySpecs{BeginIndex: 1},
 		IsMigrated: true,
+		DenyOOM:    true,
 	}
 	smembersCmdMeta = DiceCmdMeta{
 		Name: "SMEMBERS",
@@ -961,6 +1195,7 @@ var (
 		IsMigrated: true,
 		Arity:      -2,
 		KeySpecs:   KeySpecs{BeginIndex: 1},
+		DenyOOM:    true,
 	}
 	pfCountCmdMeta = DiceCmdMeta{
 		Name: "PFCOUNT",
@@ -995,6 +1230,7 @@ var (
 		Arity:      3,
 		KeySpecs:   KeySpecs{BeginIndex: 1},
 		IsMigrated: true,
+		DenyOOM:    true,
 	}
 	dumpkeyCMmdMeta = DiceCmdMeta{
 		Name: "DUMP",
@@ -1013,6 +1249,7 @@ var (
 		IsMigrated: true,
 		Arity:      2,
 		KeySpecs:   KeySpecs{BeginIndex: 1},
+		DenyOOM:    true,
 	}
 	typeCmdMeta = DiceCmdMeta{
 		Name:       "TYPE",
@@ -1037,6 +1274,7 @@ var (
 		IsMigrated: true,
 		Arity:      2,
 		KeySpecs:   KeySpecs{BeginIndex: 1, Step: 1},
+		DenyOOM:    true,
 	}
 	getRangeCmdMeta = DiceCmdMeta{
 		Name:       "GETRANGE",
@@ -1058,6 +1296,7 @@ var (
 		KeySpecs:   KeySpecs{BeginIndex: 1},
 		IsMigrated: true,
 		NewEval:    evalSETEX,
+		DenyOOM:    true,
 	}
 	hrandfieldCmdMeta = DiceCmdMeta{
 		Name:       "HRANDFIELD",
@@ -1073,6 +1312,8 @@ var (
 		IsMigrated: true,
 		NewEval:    evalAPPEND,
 		Arity:      2,
+		KeySpecs:   KeySpecs{BeginIndex: 1},
+		DenyOOM:    true,
 	}
 	zaddCmdMeta = DiceCmdMeta{
 		Name: "ZADD",
@@ -1084,6 +1325,7 @@ var (
 		KeySpecs:   KeySpecs{BeginIndex: 1},
 		IsMigrated: true,
 		NewEval:    evalZADD,
+		DenyOOM:    true,
 	}
 	zcountCmdMeta = DiceCmdMeta{
 		Name: "ZCOUNT",
@@ -1185,6 +1427,7 @@ var (
 		KeySpecs:   KeySpecs{BeginIndex: 1},
 		IsMigrated: true,
 		NewEval:    evalBITFIELD,
+		DenyOOM:    true,
 	}
 	bitfieldroCmdMeta = DiceCmdMeta{
 		Name: "BITFIELD_RO",
@@ -1207,6 +1450,7 @@ var (
 		KeySpecs:   KeySpecs{BeginIndex: 1},
 		IsMigrated: true,
 		NewEval:    evalHINCRBYFLOAT,
+		DenyOOM:    true,
 	}
 	geoAddCmdMeta = DiceCmdMeta{
 		Name:       "GEOADD",
@@ -1215,6 +1459,7 @@ var (
 		IsMigrated: true,
 		NewEval:    evalGEOADD,
 		KeySpecs:   KeySpecs{BeginIndex: 1},
+		DenyOOM:    true,
 	}
 	geoDistCmdMeta = DiceCmdMeta{
 		Name:       "GEODIST",
@@ -1250,6 +1495,7 @@ var (
 		IsMigrated: true,
 		Arity:      3,
 		KeySpecs:   KeySpecs{BeginIndex: 1},
+		DenyOOM:    true,
 	}
 	cmsInitByDimCmdMeta = DiceCmdMeta{
 		Name:       "CMS.INITBYDIM",
@@ -1258,6 +1504,7 @@ var (
 		IsMigrated: true,
 		NewEval:    evalCMSINITBYDIM,
 		KeySpecs:   KeySpecs{BeginIndex: 1},
+		DenyOOM:    true,
 	}
 	cmsInitByProbCmdMeta = DiceCmdMeta{
 		Name:       "CMS.INITBYPROB",
@@ -1266,6 +1513,7 @@ var (
 		IsMigrated: true,
 		NewEval:    evalCMSINITBYPROB,
 		KeySpecs:   KeySpecs{BeginIndex: 1},
+		DenyOOM:    true,
 	}
 	cmsInfoCmdMeta = DiceCmdMeta{
 		Name:       "CMS.INFO",
@@ -1290,6 +1538,7 @@ var (
 		IsMigrated: true,
 		NewEval:    evalCMSIncrBy,
 		KeySpecs:   KeySpecs{BeginIndex: 1},
+		DenyOOM:    true,
 	}
 	cmsMergeCmdMeta = DiceCmdMeta{
 		Name: "CMS.MERGE",
@@ -1300,6 +1549,7 @@ var (
 		IsMigrated: true,
 		NewEval:    evalCMSMerge,
 		KeySpecs:   KeySpecs{BeginIndex: 1},
+		DenyOOM:    true,
 	}
 	linsertCmdMeta = DiceCmdMeta{
 		Name: "LINSERT",
@@ -1319,6 +1569,7 @@ var (
 		IsMigrated: true,
 		Arity:      5,
 		KeySpecs:   KeySpecs{BeginIndex: 1},
+		DenyOOM:    true,
 	}
 	lrangeCmdMeta = DiceCmdMeta{
 		Name: "LRANGE",
@@ -1348,6 +1599,9 @@ func init() {
 	PreProcessing["COPY"] = evalGetObject
 	PreProcessing["RENAME"] = evalGET
 	PreProcessing["GETOBJECT"] = evalGetObject
+	PreProcessing["SORT"] = evalSortPreProcess("SORT")
+	PreProcessing["SORT_RO"] = evalSortPreProcess("SORT_RO")
+	PreProcessing["SORTLOOKUP"] = evalSortLookup
 
 	DiceCmds["ABORT"] = abortCmdMeta
 	DiceCmds["APPEND"] = appendCmdMeta
@@ -1393,6 +1647,9 @@ func init() {
 	DiceCmds["HDEL"] = hdelCmdMeta
 	DiceCmds["HELLO"] = helloCmdMeta
 	DiceCmds["HEXISTS"] = hexistsCmdMeta
+	DiceCmds["HEXPIRE"] = hexpireCmdMeta
+	DiceCmds["HEXPIREAT"] = hexpireatCmdMeta
+	DiceCmds["HEXPIRETIME"] = hexpiretimeCmdMeta
 	DiceCmds["HGET"] = hgetCmdMeta
 	DiceCmds["HGETALL"] = hgetAllCmdMeta
 	DiceCmds["HINCRBY"] = hincrbyCmdMeta
@@ -1401,11 +1658,17 @@ func init() {
 	DiceCmds["HLEN"] = hlenCmdMeta
 	DiceCmds["HMGET"] = hmgetCmdMeta
 	DiceCmds["HMSET"] = hmsetCmdMeta
+	DiceCmds["HPERSIST"] = hpersistCmdMeta
+	DiceCmds["HPEXPIRE"] = hpexpireCmdMeta
+	DiceCmds["HPEXPIREAT"] = hpexpireatCmdMeta
+	DiceCmds["HPEXPIRETIME"] = hpexpiretimeCmdMeta
+	DiceCmds["HPTTL"] = hpttlCmdMeta
 	DiceCmds["HRANDFIELD"] = hrandfieldCmdMeta
 	DiceCmds["HSCAN"] = hscanCmdMeta
 	DiceCmds["HSET"] = hsetCmdMeta
 	DiceCmds["HSETNX"] = hsetnxCmdMeta
 	DiceCmds["HSTRLEN"] = hstrLenCmdMeta
+	DiceCmds["HTTL"] = httlCmdMeta
 	DiceCmds["HVALS"] = hValsCmdMeta
 	DiceCmds["INCR"] = incrCmdMeta
 	DiceCmds["INCRBYFLOAT"] = incrByFloatCmdMeta
@@ -1434,6 +1697,10 @@ func init() {
 	DiceCmds["LLEN"] = llenCmdMeta
 	DiceCmds["LPOP"] = lpopCmdMeta
 	DiceCmds["LPUSH"] = lpushCmdMeta
+	DiceCmds["MEMORY"] = memoryCmdMeta
+	DiceCmds["INFO"] = infoCmdMeta
+	DiceCmds["SLOWLOG"] = slowlogCmdMeta
+	DiceCmds["MOVE"] = moveCmdMeta
 	DiceCmds["OBJECT"] = objectCmdMeta
 	DiceCmds["PERSIST"] = persistCmdMeta
 	DiceCmds["PFADD"] = pfAddCmdMeta
@@ -1451,7 +1718,11 @@ func init() {
 	DiceCmds["SETEX"] = setexCmdMeta
 	DiceCmds["SLEEP"] = sleepCmdMeta
 	DiceCmds["SMEMBERS"] = smembersCmdMeta
+	DiceCmds["SORT"] = sortCmdMeta
+	DiceCmds["SORT_RO"] = sortROCmdMeta
 	DiceCmds["SREM"] = sremCmdMeta
+	DiceCmds["SSCAN"] = sscanCmdMeta
+	DiceCmds["SWAPDB"] = swapdbCmdMeta
 	DiceCmds["TTL"] = ttlCmdMeta
 	DiceCmds["TYPE"] = typeCmdMeta
 	DiceCmds["ZADD"] = zaddCmdMeta
@@ -1462,6 +1733,7 @@ func init() {
 	DiceCmds["ZRANK"] = zrankCmdMeta
 	DiceCmds["ZCARD"] = zcardCmdMeta
 	DiceCmds["ZREM"] = zremCmdMeta
+	DiceCmds["ZSCAN"] = zscanCmdMeta
 	DiceCmds["JSON.STRAPPEND"] = jsonstrappendCmdMeta
 	DiceCmds["CMS.INITBYDIM"] = cmsInitByDimCmdMeta
 	DiceCmds["CMS.INITBYPROB"] = cmsInitByProbCmdMeta
@@ -1476,6 +1748,8 @@ func init() {
 	DiceCmds["SINGLETOUCH"] = singleTouchCmdMeta
 	DiceCmds["SINGLEDBSIZE"] = singleDBSizeCmdMeta
 	DiceCmds["SINGLEKEYS"] = singleKeysCmdMeta
+	DiceCmds["SINGLESCAN"] = singleScanCmdMeta
+	DiceCmds["SORTSTORE"] = sortStoreCmdMeta
 }
 
 // Function to convert DiceCmdMeta to []interface{}
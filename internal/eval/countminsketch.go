@@ -14,6 +14,7 @@ import (
 	"slices"
 	"strconv"
 	"strings"
+	"unsafe"
 
 	"github.com/dicedb/dice/internal/clientio"
 	diceerrors "github.com/dicedb/dice/internal/errors"
@@ -172,6 +173,12 @@ func (c *CountMinSketch) estimateCount(key string) uint64 {
 	return count
 }
 
+// Size returns the memory used by the Count Min Sketch, in bytes. See object.Sizer.
+func (c *CountMinSketch) Size(_ int) int64 {
+	row := int64(unsafe.Sizeof([]uint64{})) + int64(c.opts.width)*int64(unsafe.Sizeof(uint64(0)))
+	return int64(unsafe.Sizeof(*c)+unsafe.Sizeof(*c.opts)) + int64(c.opts.depth)*row
+}
+
 // returns a deep copy of the Count Min Sketch
 func (c *CountMinSketch) DeepCopy() *CountMinSketch {
 	if c == nil {
@@ -9,6 +9,7 @@ import (
 	"errors"
 	"fmt"
 	"strconv"
+	"unsafe"
 
 	"github.com/dicedb/dice/internal/dencoding"
 )
@@ -43,6 +44,11 @@ func (q *DequeBasic) GetLength() int64 {
 	return q.Length
 }
 
+// Size returns the memory used by the deque, in bytes. See object.Sizer.
+func (q *DequeBasic) Size(_ int) int64 {
+	return int64(unsafe.Sizeof(*q)) + int64(cap(q.buf))
+}
+
 // LPush pushes `x` into the left side of the Deque.
 func (q *DequeBasic) LPush(x string) {
 	// enc + data + backlen
@@ -249,6 +255,12 @@ func (q *Deque) GetLength() int64 {
 	return q.Length
 }
 
+// Size estimates the memory used by the deque, in bytes, sampling the nodes of its list. See
+// object.Sizer.
+func (q *Deque) Size(samples int) int64 {
+	return int64(unsafe.Sizeof(*q)) + q.list.Size(samples)
+}
+
 func (q *Deque) LPush(x string) {
 	// enc + data + backlen
 	entrySize := int(GetEncodeDeqEntrySize(x))
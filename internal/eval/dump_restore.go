@@ -12,24 +12,41 @@ import (
 
 	"github.com/dicedb/dice/internal/eval/sortedset"
 	"github.com/dicedb/dice/internal/object"
+	dstore "github.com/dicedb/dice/internal/store"
 )
 
-func rdbDeserialize(data []byte) (*object.Obj, error) {
+// DumpCodec serializes the objects spilled to the disk tier of the stores in the format of DUMP.
+type DumpCodec struct{}
+
+var _ dstore.ObjectCodec = DumpCodec{}
+
+func (DumpCodec) Serialize(obj *object.Obj, fieldExpiries map[string]uint64) ([]byte, error) {
+	return rdbSerialize(obj, fieldExpiries)
+}
+
+func (DumpCodec) Deserialize(data []byte) (*object.Obj, map[string]uint64, error) {
+	return rdbDeserialize(data)
+}
+
+// rdbDeserialize returns the object serialized by rdbSerialize, along with the deadlines of its
+// fields if it is a hash.
+func rdbDeserialize(data []byte) (*object.Obj, map[string]uint64, error) {
 	if len(data) < 3 {
-		return nil, errors.New("insufficient data for deserialization")
+		return nil, nil, errors.New("insufficient data for deserialization")
 	}
 	var value interface{}
+	var fieldExpiries map[string]uint64
 	var err error
 	var valueRaw interface{}
 
 	buf := bytes.NewReader(data)
 	_, err = buf.ReadByte()
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 	_oType, err := buf.ReadByte()
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	objType := object.ObjectType(_oType)
@@ -43,19 +60,19 @@ func rdbDeserialize(data []byte) (*object.Obj, error) {
 	case object.ObjTypeJSON: // JSON type
 		valueRaw, err = readString(buf)
 		if err := json.Unmarshal([]byte(valueRaw.(string)), &value); err != nil {
-			return nil, err
+			return nil, nil, err
 		}
 	case object.ObjTypeByteArray: // Byte array type
 		valueRaw, err = readInt(buf)
 		if err != nil {
-			return nil, err
+			return nil, nil, err
 		}
 		byteArray := &ByteArray{
 			Length: valueRaw.(int64),
 			data:   make([]byte, valueRaw.(int64)),
 		}
 		if _, err := buf.Read(byteArray.data); err != nil {
-			return nil, err
+			return nil, nil, err
 		}
 		value = byteArray
 	case object.ObjTypeDequeue: // Byte list type (Deque)
@@ -66,13 +83,15 @@ func rdbDeserialize(data []byte) (*object.Obj, error) {
 		value, err = sortedset.DeserializeSortedSet(buf)
 	case object.ObjTypeCountMinSketch:
 		value, err = DeserializeCMS(buf)
+	case object.ObjTypeHashMap:
+		value, fieldExpiries, err = readHashMap(buf)
 	default:
-		return nil, errors.New("unsupported object type")
+		return nil, nil, errors.New("unsupported object type")
 	}
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
-	return &object.Obj{Type: objType, Value: value}, nil
+	return &object.Obj{Type: objType, Value: value}, fieldExpiries, nil
 }
 
 func readString(buf *bytes.Reader) (interface{}, error) {
@@ -114,7 +133,37 @@ func readSet(buf *bytes.Reader) (interface{}, error) {
 	return setItems, nil
 }
 
-func rdbSerialize(obj *object.Obj) ([]byte, error) {
+// readHashMap reads the fields of a hash written by writeHashMap, along with their deadlines.
+func readHashMap(buf *bytes.Reader) (HashMap, map[string]uint64, error) {
+	var fieldCount uint64
+	if err := binary.Read(buf, binary.BigEndian, &fieldCount); err != nil {
+		return nil, nil, err
+	}
+	hashMap := make(HashMap, fieldCount)
+	fieldExpiries := make(map[string]uint64)
+	for i := uint64(0); i < fieldCount; i++ {
+		field, err := readString(buf)
+		if err != nil {
+			return nil, nil, err
+		}
+		value, err := readString(buf)
+		if err != nil {
+			return nil, nil, err
+		}
+		deadline, err := readInt(buf)
+		if err != nil {
+			return nil, nil, err
+		}
+		hashMap[field.(string)] = value.(string)
+		if deadline.(int64) > 0 {
+			fieldExpiries[field.(string)] = uint64(deadline.(int64))
+		}
+	}
+	return hashMap, fieldExpiries, nil
+}
+
+// rdbSerialize serializes the object, along with the deadlines of its fields if it is a hash.
+func rdbSerialize(obj *object.Obj, fieldExpiries map[string]uint64) ([]byte, error) {
 	var buf bytes.Buffer
 	buf.WriteByte(0x09)
 	buf.WriteByte(byte(obj.Type))
@@ -189,6 +238,14 @@ func rdbSerialize(obj *object.Obj) ([]byte, error) {
 		if err := cms.serialize(&buf); err != nil {
 			return nil, err
 		}
+	case object.ObjTypeHashMap:
+		hashMap, ok := obj.Value.(HashMap)
+		if !ok {
+			return nil, errors.New("invalid hash value")
+		}
+		if err := writeHashMap(&buf, hashMap, fieldExpiries); err != nil {
+			return nil, err
+		}
 	default:
 		return nil, errors.New("unsupported object type")
 	}
@@ -224,6 +281,25 @@ func writeSet(buf *bytes.Buffer, setItems map[string]struct{}) error {
 	}
 	return nil
 }
+
+// writeHashMap writes the fields of a hash, each followed by its value and its deadline in Unix
+// milliseconds, which is 0 if the field does not expire.
+func writeHashMap(buf *bytes.Buffer, hashMap HashMap, fieldExpiries map[string]uint64) error {
+	if err := binary.Write(buf, binary.BigEndian, uint64(len(hashMap))); err != nil {
+		return err
+	}
+	for field, value := range hashMap {
+		if err := writeString(buf, field); err != nil {
+			return err
+		}
+		if err := writeString(buf, value); err != nil {
+			return err
+		}
+		writeInt(buf, int64(fieldExpiries[field]))
+	}
+	return nil
+}
+
 func appendChecksum(data []byte) []byte {
 	checksum := crc64.Checksum(data, crc64.MakeTable(crc64.ECMA))
 	checksumBuf := make([]byte, 8)
@@ -27,6 +27,7 @@ import (
 	dstore "github.com/dicedb/dice/internal/store"
 	"github.com/ohler55/ojg/jp"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 type evalTestCase struct {
@@ -96,6 +97,16 @@ func TestEval(t *testing.T) {
 	testEvalHEXISTS(t, store)
 	testEvalHDEL(t, store)
 	testEvalHSCAN(t, store)
+	testEvalHEXPIRE(t, store)
+	testEvalHTTL(t, store)
+	testEvalHPERSIST(t, store)
+	testEvalHashFieldExpiryDumpRestore(t, store)
+	testEvalSORT(t, store)
+	testEvalSORTRO(t, store)
+	testEvalMEMORY(t, store)
+	testEvalSSCAN(t, store)
+	testEvalZSCAN(t, store)
+	testEvalSCAN(t, store)
 	testEvalJSONSTRLEN(t, store)
 	testEvalJSONOBJLEN(t, store)
 	testEvalHLEN(t, store)
@@ -3729,6 +3740,493 @@ func testEvalHDEL(t *testing.T, store *dstore.Store) {
 	runMigratedEvalTests(t, tests, evalHDEL, store)
 }
 
+func testEvalHEXPIRE(t *testing.T, store *dstore.Store) {
+	tests := map[string]evalTestCase{
+		"HEXPIRE with wrong number of args": {
+			input:          []string{"hash_key", "10", "FIELDS", "1"},
+			migratedOutput: EvalResponse{Result: nil, Error: diceerrors.ErrWrongArgumentCount("HEXPIRE")},
+		},
+		"HEXPIRE with invalid time": {
+			input:          []string{"hash_key", "ten", "FIELDS", "1", "field"},
+			migratedOutput: EvalResponse{Result: nil, Error: diceerrors.ErrIntegerOutOfRange},
+		},
+		"HEXPIRE with negative time": {
+			input:          []string{"hash_key", "-1", "FIELDS", "1", "field"},
+			migratedOutput: EvalResponse{Result: nil, Error: diceerrors.ErrInvalidExpireTime("HEXPIRE")},
+		},
+		"HEXPIRE without FIELDS": {
+			input:          []string{"hash_key", "10", "NX", "1", "field"},
+			migratedOutput: EvalResponse{Result: nil, Error: diceerrors.ErrFieldsArgMissing},
+		},
+		"HEXPIRE with mismatching numfields": {
+			input:          []string{"hash_key", "10", "FIELDS", "2", "field"},
+			migratedOutput: EvalResponse{Result: nil, Error: diceerrors.ErrNumFieldsMismatch},
+		},
+		"HEXPIRE with zero numfields": {
+			input:          []string{"hash_key", "10", "FIELDS", "0", "field"},
+			migratedOutput: EvalResponse{Result: nil, Error: diceerrors.ErrNumFieldsInvalid},
+		},
+		"HEXPIRE with key does not exist": {
+			input:          []string{"hash_key", "10", "FIELDS", "2", "field1", "field2"},
+			migratedOutput: EvalResponse{Result: []int64{-2, -2}, Error: nil},
+		},
+		"HEXPIRE with key exists but not a hash": {
+			setup: func() {
+				evalSET([]string{"string_key", "string_value"}, store)
+			},
+			input:          []string{"string_key", "10", "FIELDS", "1", "field"},
+			migratedOutput: EvalResponse{Result: nil, Error: diceerrors.ErrWrongTypeOperation},
+		},
+		"HEXPIRE with existing and missing fields": {
+			setup: func() {
+				evalHSET([]string{"hash_key", "field1", "value1"}, store)
+			},
+			input:          []string{"hash_key", "10", "FIELDS", "2", "field1", "nonexistent"},
+			migratedOutput: EvalResponse{Result: []int64{1, -2}, Error: nil},
+		},
+		"HEXPIRE with NX on a field having an expiry": {
+			setup: func() {
+				evalHSET([]string{"hash_key", "field1", "value1", "field2", "value2"}, store)
+				evalHEXPIRE([]string{"hash_key", "10", "FIELDS", "1", "field1"}, store)
+			},
+			input:          []string{"hash_key", "20", "NX", "FIELDS", "2", "field1", "field2"},
+			migratedOutput: EvalResponse{Result: []int64{0, 1}, Error: nil},
+		},
+		"HEXPIRE with GT and LT": {
+			setup: func() {
+				evalHSET([]string{"hash_key", "field1", "value1", "field2", "value2"}, store)
+				evalHEXPIRE([]string{"hash_key", "10", "FIELDS", "2", "field1", "field2"}, store)
+				evalHEXPIRE([]string{"hash_key", "20", "GT", "FIELDS", "1", "field1"}, store)
+			},
+			input:          []string{"hash_key", "15", "LT", "FIELDS", "2", "field1", "field2"},
+			migratedOutput: EvalResponse{Result: []int64{1, 0}, Error: nil},
+		},
+		"HEXPIRE with NX and XX": {
+			setup: func() {
+				evalHSET([]string{"hash_key", "field1", "value1"}, store)
+			},
+			input:          []string{"hash_key", "10", "NX", "XX", "FIELDS", "1", "field1"},
+			migratedOutput: EvalResponse{Result: nil, Error: diceerrors.ErrGeneral("NX and XX, GT or LT options at the same time are not compatible")},
+		},
+		"HEXPIRE with zero time deletes the fields": {
+			setup: func() {
+				evalHSET([]string{"hash_key", "field1", "value1", "field2", "value2"}, store)
+			},
+			input: []string{"hash_key", "0", "FIELDS", "1", "field1"},
+			newValidator: func(output interface{}) {
+				assert.Equal(t, []int64{2}, output)
+				assert.Equal(t, 1, evalHLEN([]string{"hash_key"}, store).Result)
+			},
+		},
+		"HEXPIRE with zero time on the last fields deletes the hash": {
+			setup: func() {
+				evalHSET([]string{"hash_key", "field1", "value1"}, store)
+			},
+			input: []string{"hash_key", "0", "FIELDS", "1", "field1"},
+			newValidator: func(output interface{}) {
+				assert.Equal(t, []int64{2}, output)
+				assert.Nil(t, store.Get("hash_key"))
+			},
+		},
+	}
+	runMigratedEvalTests(t, tests, evalHEXPIRE, store)
+}
+
+func testEvalHTTL(t *testing.T, store *dstore.Store) {
+	tests := map[string]evalTestCase{
+		"HTTL with wrong number of args": {
+			input:          []string{"hash_key", "FIELDS", "1"},
+			migratedOutput: EvalResponse{Result: nil, Error: diceerrors.ErrWrongArgumentCount("HTTL")},
+		},
+		"HTTL with key does not exist": {
+			input:          []string{"hash_key", "FIELDS", "1", "field"},
+			migratedOutput: EvalResponse{Result: []int64{-2}, Error: nil},
+		},
+		"HTTL with fields with and without expiry": {
+			setup: func() {
+				evalHSET([]string{"hash_key", "field1", "value1", "field2", "value2"}, store)
+				evalHEXPIRE([]string{"hash_key", "100", "FIELDS", "1", "field1"}, store)
+			},
+			input: []string{"hash_key", "FIELDS", "3", "field1", "field2", "nonexistent"},
+			newValidator: func(output interface{}) {
+				results := output.([]int64)
+				assert.InDelta(t, 100, results[0], 1)
+				assert.Equal(t, []int64{-1, -2}, results[1:])
+			},
+		},
+		"HTTL after HPEXPIREAT": {
+			setup: func() {
+				evalHSET([]string{"hash_key", "field1", "value1"}, store)
+				deadline := time.Now().Add(time.Hour).UnixMilli()
+				evalHPEXPIREAT([]string{"hash_key", strconv.FormatInt(deadline, 10), "FIELDS", "1", "field1"}, store)
+			},
+			input: []string{"hash_key", "FIELDS", "1", "field1"},
+			newValidator: func(output interface{}) {
+				assert.InDelta(t, 3600, output.([]int64)[0], 1)
+			},
+		},
+		"HTTL of an expired field": {
+			setup: func() {
+				evalHSET([]string{"hash_key", "field1", "value1", "field2", "value2"}, store)
+				obj := store.Get("hash_key")
+				store.SetFieldExpiry("hash_key", obj, "field1", uint64(time.Now().UnixMilli()-1))
+			},
+			input: []string{"hash_key", "FIELDS", "1", "field1"},
+			newValidator: func(output interface{}) {
+				assert.Equal(t, []int64{-2}, output)
+				assert.Equal(t, 1, evalHLEN([]string{"hash_key"}, store).Result)
+			},
+		},
+	}
+	runMigratedEvalTests(t, tests, evalHTTL, store)
+}
+
+func testEvalHPERSIST(t *testing.T, store *dstore.Store) {
+	tests := map[string]evalTestCase{
+		"HPERSIST with wrong number of args": {
+			input:          []string{"hash_key", "FIELDS", "1"},
+			migratedOutput: EvalResponse{Result: nil, Error: diceerrors.ErrWrongArgumentCount("HPERSIST")},
+		},
+		"HPERSIST with key exists but not a hash": {
+			setup: func() {
+				evalSET([]string{"string_key", "string_value"}, store)
+			},
+			input:          []string{"string_key", "FIELDS", "1", "field"},
+			migratedOutput: EvalResponse{Result: nil, Error: diceerrors.ErrWrongTypeOperation},
+		},
+		"HPERSIST with fields with and without expiry": {
+			setup: func() {
+				evalHSET([]string{"hash_key", "field1", "value1", "field2", "value2"}, store)
+				evalHEXPIRE([]string{"hash_key", "100", "FIELDS", "1", "field1"}, store)
+			},
+			input: []string{"hash_key", "FIELDS", "3", "field1", "field2", "nonexistent"},
+			newValidator: func(output interface{}) {
+				assert.Equal(t, []int64{1, -1, -2}, output)
+				assert.Equal(t, []int64{-1}, evalHTTL([]string{"hash_key", "FIELDS", "1", "field1"}, store).Result)
+			},
+		},
+		"HSET on a field clears its expiry": {
+			setup: func() {
+				evalHSET([]string{"hash_key", "field1", "value1"}, store)
+				evalHEXPIRE([]string{"hash_key", "100", "FIELDS", "1", "field1"}, store)
+				evalHSET([]string{"hash_key", "field1", "value2"}, store)
+			},
+			input:          []string{"hash_key", "FIELDS", "1", "field1"},
+			migratedOutput: EvalResponse{Result: []int64{-1}, Error: nil},
+		},
+	}
+	runMigratedEvalTests(t, tests, evalHPERSIST, store)
+}
+
+func testEvalHashFieldExpiryDumpRestore(t *testing.T, store *dstore.Store) {
+	t.Run("DUMP and RESTORE of a hash with expiring fields", func(t *testing.T) {
+		store = setupTest(store)
+		evalHSET([]string{"hash_key", "field1", "value1", "field2", "value2"}, store)
+		evalHEXPIRE([]string{"hash_key", "100", "FIELDS", "1", "field1"}, store)
+
+		dump := evalDUMP([]string{"hash_key"}, store)
+		require.NoError(t, dump.Error)
+		restored := evalRestore([]string{"restored_key", "0", dump.Result.(string)}, store)
+		require.NoError(t, restored.Error)
+
+		assert.Equal(t, "value1", evalHGET([]string{"restored_key", "field1"}, store).Result)
+		assert.Equal(t, "value2", evalHGET([]string{"restored_key", "field2"}, store).Result)
+		results := evalHTTL([]string{"restored_key", "FIELDS", "2", "field1", "field2"}, store).Result.([]int64)
+		assert.InDelta(t, 100, results[0], 1)
+		assert.Equal(t, int64(-1), results[1])
+	})
+}
+
+func testEvalSORT(t *testing.T, store *dstore.Store) {
+	setupSortKeys := func() {
+		evalRPUSH([]string{"ids", "3", "1", "2"}, store)
+		for id, weight := range map[string]string{"1": "30", "2": "10", "3": "20"} {
+			evalSET([]string{"weight_" + id, weight}, store)
+			evalHSET([]string{"user_" + id, "name", "user" + id}, store)
+		}
+	}
+
+	tests := map[string]evalTestCase{
+		"SORT with wrong number of args": {
+			input:          []string{},
+			migratedOutput: EvalResponse{Result: nil, Error: diceerrors.ErrWrongArgumentCount("SORT")},
+		},
+		"SORT with unknown option": {
+			input:          []string{"ids", "UP"},
+			migratedOutput: EvalResponse{Result: nil, Error: diceerrors.ErrSyntax},
+		},
+		"SORT with incomplete LIMIT": {
+			input:          []string{"ids", "LIMIT", "0"},
+			migratedOutput: EvalResponse{Result: nil, Error: diceerrors.ErrSyntax},
+		},
+		"SORT with invalid LIMIT": {
+			input:          []string{"ids", "LIMIT", "zero", "1"},
+			migratedOutput: EvalResponse{Result: nil, Error: diceerrors.ErrIntegerOutOfRange},
+		},
+		"SORT of a non existing key": {
+			input:          []string{"ids"},
+			migratedOutput: EvalResponse{Result: []interface{}{}, Error: nil},
+		},
+		"SORT of a string": {
+			setup: func() {
+				evalSET([]string{"string_key", "value"}, store)
+			},
+			input:          []string{"string_key"},
+			migratedOutput: EvalResponse{Result: nil, Error: diceerrors.ErrWrongTypeOperation},
+		},
+		"SORT of a list": {
+			setup:          setupSortKeys,
+			input:          []string{"ids"},
+			migratedOutput: EvalResponse{Result: []interface{}{"1", "2", "3"}, Error: nil},
+		},
+		"SORT DESC with LIMIT": {
+			setup:          setupSortKeys,
+			input:          []string{"ids", "DESC", "LIMIT", "1", "5"},
+			migratedOutput: EvalResponse{Result: []interface{}{"2", "1"}, Error: nil},
+		},
+		"SORT with LIMIT past the elements": {
+			setup:          setupSortKeys,
+			input:          []string{"ids", "LIMIT", "5", "1"},
+			migratedOutput: EvalResponse{Result: []interface{}{}, Error: nil},
+		},
+		"SORT of non numeric elements": {
+			setup: func() {
+				evalSADD([]string{"names", "b", "a"}, store)
+			},
+			input:          []string{"names"},
+			migratedOutput: EvalResponse{Result: nil, Error: diceerrors.ErrSortScoreNotDouble},
+		},
+		"SORT ALPHA of a set": {
+			setup: func() {
+				evalSADD([]string{"names", "b", "c", "a"}, store)
+			},
+			input:          []string{"names", "ALPHA"},
+			migratedOutput: EvalResponse{Result: []interface{}{"a", "b", "c"}, Error: nil},
+		},
+		"SORT of a sorted set without sorting": {
+			setup: func() {
+				evalZADD([]string{"ranks", "1", "c", "2", "a", "3", "b"}, store)
+			},
+			input:          []string{"ranks", "BY", "nosort", "DESC"},
+			migratedOutput: EvalResponse{Result: []interface{}{"b", "a", "c"}, Error: nil},
+		},
+		"SORT BY keys": {
+			setup:          setupSortKeys,
+			input:          []string{"ids", "BY", "weight_*"},
+			migratedOutput: EvalResponse{Result: []interface{}{"2", "3", "1"}, Error: nil},
+		},
+		"SORT BY missing keys": {
+			setup: func() {
+				setupSortKeys()
+				evalDEL([]string{"weight_1"}, store)
+			},
+			input:          []string{"ids", "BY", "weight_*"},
+			migratedOutput: EvalResponse{Result: []interface{}{"1", "2", "3"}, Error: nil},
+		},
+		"SORT BY hash fields with GET": {
+			setup: func() {
+				setupSortKeys()
+				evalDEL([]string{"weight_2"}, store)
+			},
+			input: []string{"ids", "BY", "user_*->name", "ALPHA", "DESC", "GET", "#", "GET", "weight_*", "GET", "user_*->name", "GET", "fixed"},
+			migratedOutput: EvalResponse{Result: []interface{}{
+				"3", "20", "user3", nil,
+				"2", nil, "user2", nil,
+				"1", "30", "user1", nil,
+			}, Error: nil},
+		},
+		"SORT with STORE": {
+			setup: func() {
+				setupSortKeys()
+				evalSET([]string{"sorted", "value", "EX", "100"}, store)
+			},
+			input: []string{"ids", "BY", "weight_*", "GET", "user_*->name", "GET", "missing_*", "STORE", "sorted"},
+			newValidator: func(output interface{}) {
+				assert.Equal(t, int64(6), output)
+				assert.Equal(t, []string{"user2", "", "user3", "", "user1", ""}, evalLRANGE([]string{"sorted", "0", "-1"}, store).Result)
+				assert.Equal(t, clientio.IntegerNegativeOne, evalTTL([]string{"sorted"}, store).Result)
+			},
+		},
+		"SORT with STORE of no elements": {
+			setup: func() {
+				evalSET([]string{"sorted", "value"}, store)
+			},
+			input: []string{"ids", "STORE", "sorted"},
+			newValidator: func(output interface{}) {
+				assert.Equal(t, clientio.IntegerZero, output)
+				assert.Nil(t, store.Get("sorted"))
+			},
+		},
+	}
+	runMigratedEvalTests(t, tests, evalSORT, store)
+}
+
+func testEvalSORTRO(t *testing.T, store *dstore.Store) {
+	tests := map[string]evalTestCase{
+		"SORT_RO with STORE": {
+			input:          []string{"ids", "STORE", "sorted"},
+			migratedOutput: EvalResponse{Result: nil, Error: diceerrors.ErrSyntax},
+		},
+		"SORT_RO of a list": {
+			setup: func() {
+				evalRPUSH([]string{"ids", "2", "10", "1"}, store)
+			},
+			input:          []string{"ids", "DESC"},
+			migratedOutput: EvalResponse{Result: []interface{}{"10", "2", "1"}, Error: nil},
+		},
+	}
+	runMigratedEvalTests(t, tests, evalSORTRO, store)
+}
+
+func TestSortLookup(t *testing.T) {
+	tests := []struct {
+		pattern string
+		lookup  SortLookup
+		ok      bool
+	}{
+		{pattern: "weight_*", lookup: SortLookup{Key: "weight_e"}, ok: true},
+		{pattern: "*_weight->field", lookup: SortLookup{Key: "e_weight", Field: "field"}, ok: true},
+		{pattern: "a->b_*", lookup: SortLookup{Key: "a->b_e"}, ok: true},
+		{pattern: "weight_*->", lookup: SortLookup{Key: "weight_e->"}, ok: true},
+		{pattern: "nosort", ok: false},
+	}
+	for _, tc := range tests {
+		lookup, ok := sortLookup(tc.pattern, "e")
+		assert.Equal(t, tc.ok, ok, tc.pattern)
+		assert.Equal(t, tc.lookup, lookup, tc.pattern)
+	}
+}
+
+func testEvalMEMORY(t *testing.T, store *dstore.Store) {
+	tests := map[string]evalTestCase{
+		"MEMORY with wrong number of args": {
+			input:          []string{},
+			migratedOutput: EvalResponse{Result: nil, Error: diceerrors.ErrWrongArgumentCount("MEMORY")},
+		},
+		"MEMORY with unknown subcommand": {
+			input:          []string{"DOCTOR"},
+			migratedOutput: EvalResponse{Result: nil, Error: diceerrors.ErrGeneral("unknown subcommand 'DOCTOR'. Try MEMORY HELP.")},
+		},
+		"MEMORY USAGE with wrong number of args": {
+			input:          []string{"USAGE", "key", "SAMPLES"},
+			migratedOutput: EvalResponse{Result: nil, Error: diceerrors.ErrWrongArgumentCount("MEMORY|USAGE")},
+		},
+		"MEMORY USAGE with invalid samples": {
+			input:          []string{"USAGE", "key", "SAMPLES", "-1"},
+			migratedOutput: EvalResponse{Result: nil, Error: diceerrors.ErrIntegerOutOfRange},
+		},
+		"MEMORY USAGE of a missing key": {
+			input:          []string{"USAGE", "key"},
+			migratedOutput: EvalResponse{Result: clientio.NIL, Error: nil},
+		},
+	}
+	runMigratedEvalTests(t, tests, evalMEMORY, store)
+
+	usage := func(args ...string) int64 {
+		result := evalMEMORY(append([]string{"USAGE"}, args...), store)
+		require.NoError(t, result.Error)
+		return result.Result.(int64)
+	}
+
+	t.Run("MEMORY USAGE grows with the value", func(t *testing.T) {
+		store = setupTest(store)
+		evalSET([]string{"short", strings.Repeat("a", 10)}, store)
+		evalSET([]string{"long_", strings.Repeat("a", 100)}, store)
+		assert.Equal(t, int64(90), usage("long_")-usage("short"))
+
+		evalHSET([]string{"small", "f1", "v1"}, store)
+		evalHSET([]string{"large", "f1", "v1", "f2", "v2", "f3", "v3"}, store)
+		assert.Greater(t, usage("large"), usage("small"))
+
+		evalRPUSH([]string{"list", "a"}, store)
+		evalRPUSH([]string{"tsil", strings.Repeat("a", 1000)}, store)
+		assert.Greater(t, usage("tsil"), usage("list"))
+	})
+
+	t.Run("MEMORY USAGE samples the aggregate values", func(t *testing.T) {
+		store = setupTest(store)
+		evalSADD([]string{"uniform", "a1", "a2", "a3", "a4"}, store)
+		assert.Equal(t, usage("uniform", "SAMPLES", "0"), usage("uniform", "SAMPLES", "1"))
+
+		evalZADD([]string{"zset", "1", "a", "2", "bbbbbbbbbbbbbbbbbbbbbbbbb"}, store)
+		assert.Equal(t, usage("zset"), usage("zset", "SAMPLES", "0"))
+	})
+
+	t.Run("MEMORY STATS sums the keys per type", func(t *testing.T) {
+		store = setupTest(store)
+		evalSET([]string{"string1", "value"}, store)
+		evalSET([]string{"string2", "value"}, store)
+		evalHSET([]string{"hash", "field", "value"}, store)
+		evalPFADD([]string{"hll", "a"}, store)
+
+		result := evalMEMORY([]string{"STATS"}, store)
+		require.NoError(t, result.Error)
+		stats := result.Result.(*MemoryShardStats)
+		assert.Equal(t, int64(4), stats.Keys)
+		assert.Equal(t, usage("string1")+usage("string2")+usage("hash")+usage("hll"), stats.Bytes)
+		assert.Equal(t, MemoryUsageStats{Keys: 2, Bytes: usage("string1") + usage("string2")}, stats.Types["string"])
+		assert.Equal(t, MemoryUsageStats{Keys: 1, Bytes: usage("hll")}, stats.Types["hll"])
+		assert.Equal(t, int64(1), stats.Types["hash"].Keys)
+	})
+}
+
+func TestEvalOutOfMemory(t *testing.T) {
+	store := dstore.NewStore(nil, dstore.NewNoEviction())
+	exec := func(args ...string) *EvalResponse {
+		return NewEval(&cmd.DiceDBCmd{Cmd: args[0], Args: args[1:]}, nil, store, false, false, false, false).ExecuteCommand()
+	}
+
+	require.NoError(t, exec("SET", "key", "value").Error)
+	store.SetMaxMemory(store.UsedMemory())
+
+	// The members added after the set is put in the store are accounted for
+	require.NoError(t, exec("SADD", "set", "a", "b").Error)
+	set := store.GetNoTouch("set")
+	assert.Equal(t, object.KeySize("key", store.GetNoTouch("key"), object.DefaultSizeSamples)+
+		object.KeySize("set", set, object.DefaultSizeSamples), store.UsedMemory())
+
+	// Without eviction, the writes are rejected while the reads and deletes go on
+	assert.Equal(t, diceerrors.ErrOOM, exec("SET", "other", "value").Error)
+	assert.Equal(t, diceerrors.ErrOOM, exec("SADD", "set", "c").Error)
+	assert.NoError(t, exec("GET", "key").Error)
+	assert.NoError(t, exec("DEL", "set").Error)
+	assert.NoError(t, exec("SET", "key", "value").Error)
+}
+
+func TestEvalShrinkingCommandsUpdateMemory(t *testing.T) {
+	store := dstore.NewStore(nil, nil)
+	exec := func(args ...string) *EvalResponse {
+		return NewEval(&cmd.DiceDBCmd{Cmd: args[0], Args: args[1:]}, nil, store, false, false, false, false).ExecuteCommand()
+	}
+	size := func(k string) int64 {
+		return object.KeySize(k, store.GetNoTouch(k), object.DefaultSizeSamples)
+	}
+
+	setArgs := []string{"SADD", "set"}
+	listArgs := []string{"LPUSH", "list"}
+	zsetArgs := []string{"ZADD", "zset"}
+	for i := 0; i < 1000; i++ {
+		member := strconv.Itoa(i)
+		setArgs = append(setArgs, member)
+		listArgs = append(listArgs, member)
+		zsetArgs = append(zsetArgs, member, member)
+	}
+	require.NoError(t, exec(setArgs...).Error)
+	require.NoError(t, exec(listArgs...).Error)
+	require.NoError(t, exec(zsetArgs...).Error)
+	grown := store.UsedMemory()
+
+	// The objects shrunk in place are accounted for at their new size
+	require.NoError(t, exec(append([]string{"SREM", "set"}, setArgs[3:]...)...).Error)
+	for i := 0; i < 999; i++ {
+		require.NoError(t, exec("LPOP", "list").Error)
+	}
+	require.NoError(t, exec(append([]string{"ZREM", "zset"}, setArgs[3:]...)...).Error)
+
+	assert.Less(t, store.UsedMemory(), grown)
+	assert.Equal(t, size("set")+size("list")+size("zset"), store.UsedMemory())
+}
+
 func testEvalHSCAN(t *testing.T, store *dstore.Store) {
 	tests := map[string]evalTestCase{
 		"HSCAN with wrong number of args": {
@@ -3814,6 +4312,273 @@ func testEvalHSCAN(t *testing.T, store *dstore.Store) {
 	runMigratedEvalTests(t, tests, evalHSCAN, store)
 }
 
+func testEvalSSCAN(t *testing.T, store *dstore.Store) {
+	tests := map[string]evalTestCase{
+		"SSCAN with wrong number of args": {
+			input:          []string{"key"},
+			migratedOutput: EvalResponse{Result: nil, Error: diceerrors.ErrWrongArgumentCount("SSCAN")},
+		},
+		"SSCAN with key does not exist": {
+			input:          []string{"NONEXISTENT_KEY", "0"},
+			migratedOutput: EvalResponse{Result: []interface{}{"0", []string{}}, Error: nil},
+		},
+		"SSCAN with key exists but not a set": {
+			setup: func() {
+				evalSET([]string{"string_key", "string_value"}, store)
+			},
+			input:          []string{"string_key", "0"},
+			migratedOutput: EvalResponse{Result: nil, Error: diceerrors.ErrWrongTypeOperation},
+		},
+		"SSCAN with valid key and cursor": {
+			setup: func() {
+				evalSADD([]string{"set_key", "member2", "member1", "member3"}, store)
+			},
+			input:          []string{"set_key", "0"},
+			migratedOutput: EvalResponse{Result: []interface{}{"0", []string{"member1", "member2", "member3"}}, Error: nil},
+		},
+		"SSCAN with MATCH and COUNT arguments": {
+			setup: func() {
+				evalSADD([]string{"set_key", "member1", "member2", "member3", "other"}, store)
+			},
+			input:          []string{"set_key", "1", "MATCH", "member*", "COUNT", "1"},
+			migratedOutput: EvalResponse{Result: []interface{}{"2", []string{"member2"}}, Error: nil},
+		},
+		"SSCAN with invalid cursor": {
+			setup: func() {
+				evalSADD([]string{"set_key", "member1"}, store)
+			},
+			input:          []string{"set_key", "-1"},
+			migratedOutput: EvalResponse{Result: nil, Error: diceerrors.ErrIntegerOutOfRange},
+		},
+		"SSCAN with missing option value": {
+			setup: func() {
+				evalSADD([]string{"set_key", "member1"}, store)
+			},
+			input:          []string{"set_key", "0", "COUNT"},
+			migratedOutput: EvalResponse{Result: nil, Error: diceerrors.ErrSyntax},
+		},
+	}
+
+	runMigratedEvalTests(t, tests, evalSSCAN, store)
+}
+
+func testEvalZSCAN(t *testing.T, store *dstore.Store) {
+	tests := map[string]evalTestCase{
+		"ZSCAN with wrong number of args": {
+			input:          []string{"key"},
+			migratedOutput: EvalResponse{Result: nil, Error: diceerrors.ErrWrongArgumentCount("ZSCAN")},
+		},
+		"ZSCAN with key does not exist": {
+			input:          []string{"NONEXISTENT_KEY", "0"},
+			migratedOutput: EvalResponse{Result: []interface{}{"0", []string{}}, Error: nil},
+		},
+		"ZSCAN with key exists but not a sorted set": {
+			setup: func() {
+				evalSADD([]string{"set_key", "member1"}, store)
+			},
+			input:          []string{"set_key", "0"},
+			migratedOutput: EvalResponse{Result: nil, Error: diceerrors.ErrWrongTypeOperation},
+		},
+		"ZSCAN with valid key and cursor": {
+			setup: func() {
+				evalZADD([]string{"zset_key", "2", "member1", "1.5", "member2"}, store)
+			},
+			input:          []string{"zset_key", "0"},
+			migratedOutput: EvalResponse{Result: []interface{}{"0", []string{"member1", "2", "member2", "1.5"}}, Error: nil},
+		},
+		"ZSCAN with COUNT argument": {
+			setup: func() {
+				evalZADD([]string{"zset_key", "1", "member1", "2", "member2", "3", "member3"}, store)
+			},
+			input:          []string{"zset_key", "0", "COUNT", "2"},
+			migratedOutput: EvalResponse{Result: []interface{}{"2", []string{"member1", "1", "member2", "2"}}, Error: nil},
+		},
+		"ZSCAN with TYPE argument": {
+			setup: func() {
+				evalZADD([]string{"zset_key", "1", "member1"}, store)
+			},
+			input:          []string{"zset_key", "0", "TYPE", "zset"},
+			migratedOutput: EvalResponse{Result: nil, Error: diceerrors.ErrSyntax},
+		},
+	}
+
+	runMigratedEvalTests(t, tests, evalZSCAN, store)
+}
+
+func testEvalSCAN(t *testing.T, store *dstore.Store) {
+	// assertScan asserts the cursor and the keys, which are replied in no particular order.
+	assertScan := func(cursor string, keys ...string) func(output interface{}) {
+		return func(output interface{}) {
+			result := output.([]interface{})
+			assert.Equal(t, cursor, result[0])
+			assert.ElementsMatch(t, keys, result[1])
+		}
+	}
+
+	tests := map[string]evalTestCase{
+		"SCAN with wrong number of args": {
+			input:          []string{"0"},
+			migratedOutput: EvalResponse{Result: nil, Error: diceerrors.ErrWrongArgumentCount("SCAN")},
+		},
+		"SCAN with invalid cursor": {
+			input:          []string{"invalid", "1"},
+			migratedOutput: EvalResponse{Result: nil, Error: diceerrors.ErrInvalidCursor},
+		},
+		"SCAN of empty store": {
+			input:          []string{"0", "1"},
+			migratedOutput: EvalResponse{Result: []interface{}{"0", []string{}}, Error: nil},
+		},
+		"SCAN of the last shard": {
+			setup: func() {
+				evalSET([]string{"k1", "v"}, store)
+				evalSET([]string{"k2", "v"}, store)
+			},
+			input:        []string{strconv.FormatUint(dstore.EncodeScanCursor(1, 0), 10), "2"},
+			newValidator: assertScan("0", "k1", "k2"),
+		},
+		"SCAN moves on to the next shard": {
+			setup: func() {
+				evalSET([]string{"k1", "v"}, store)
+			},
+			input:        []string{"0", "2"},
+			newValidator: assertScan(strconv.FormatUint(dstore.EncodeScanCursor(1, 0), 10), "k1"),
+		},
+		"SCAN with MATCH and TYPE arguments": {
+			setup: func() {
+				evalSET([]string{"user:1", "v"}, store)
+				evalSADD([]string{"user:2", "m"}, store)
+				evalSADD([]string{"order:1", "m"}, store)
+			},
+			input:        []string{"0", "1", "MATCH", "user:*", "TYPE", "SET"},
+			newValidator: assertScan("0", "user:2"),
+		},
+		"SCAN with COUNT argument": {
+			setup: func() {
+				for i := 0; i < 100; i++ {
+					evalSET([]string{fmt.Sprintf("k%d", i), "v"}, store)
+				}
+			},
+			input: []string{"0", "1", "COUNT", "10"},
+			newValidator: func(output interface{}) {
+				result := output.([]interface{})
+				assert.NotEqual(t, "0", result[0])
+				assert.Len(t, result[1], 10)
+			},
+		},
+		"SCAN with invalid COUNT value": {
+			input:          []string{"0", "1", "COUNT", "0"},
+			migratedOutput: EvalResponse{Result: nil, Error: diceerrors.ErrIntegerOutOfRange},
+		},
+	}
+
+	runMigratedEvalTests(t, tests, evalSCAN, store)
+}
+
+func TestEvalDatabases(t *testing.T) {
+	previous := config.DiceConfig.Memory.Databases
+	config.DiceConfig.Memory.Databases = 4
+	defer func() { config.DiceConfig.Memory.Databases = previous }()
+	store := dstore.NewStore(nil, nil)
+
+	testEvalMOVE(t, store)
+	testEvalSWAPDB(t, store)
+}
+
+// resetDatabases flushes all the databases of the store and selects the first one.
+func resetDatabases(store *dstore.Store) {
+	for db := 0; db < store.NumDatabases(); db++ {
+		store.SelectDB(db)
+		store.ResetStore()
+	}
+	store.SelectDB(0)
+}
+
+func testEvalMOVE(t *testing.T, store *dstore.Store) {
+	tests := map[string]evalTestCase{
+		"MOVE with wrong number of args": {
+			input:          []string{"k"},
+			migratedOutput: EvalResponse{Result: nil, Error: diceerrors.ErrWrongArgumentCount("MOVE")},
+		},
+		"MOVE with invalid db": {
+			input:          []string{"k", "one"},
+			migratedOutput: EvalResponse{Result: nil, Error: diceerrors.ErrIntegerOutOfRange},
+		},
+		"MOVE with out of range db": {
+			input:          []string{"k", "4"},
+			migratedOutput: EvalResponse{Result: nil, Error: diceerrors.ErrDBIndexOutOfRange},
+		},
+		"MOVE to the selected db": {
+			input:          []string{"k", "0"},
+			migratedOutput: EvalResponse{Result: nil, Error: diceerrors.ErrSameObject},
+		},
+		"MOVE of a non existing key": {
+			input:          []string{"k", "1"},
+			migratedOutput: EvalResponse{Result: clientio.IntegerZero, Error: nil},
+		},
+		"MOVE of an existing key": {
+			setup: func() {
+				evalSET([]string{"k", "v", "EX", "100"}, store)
+			},
+			input: []string{"k", "1"},
+			newValidator: func(output interface{}) {
+				assert.Equal(t, clientio.IntegerOne, output)
+				assert.Nil(t, store.Get("k"))
+				store.SelectDB(1)
+				defer store.SelectDB(0)
+				assert.Equal(t, "v", evalGET([]string{"k"}, store).Result)
+				assert.Greater(t, evalTTL([]string{"k"}, store).Result, uint64(0))
+			},
+		},
+		"MOVE over a key of the destination db": {
+			setup: func() {
+				evalSET([]string{"k", "v"}, store)
+				store.SelectDB(1)
+				evalSET([]string{"k", "other"}, store)
+				store.SelectDB(0)
+			},
+			input:          []string{"k", "1"},
+			migratedOutput: EvalResponse{Result: clientio.IntegerZero, Error: nil},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			resetDatabases(store)
+			if tc.setup != nil {
+				tc.setup()
+			}
+
+			output := evalMOVE(tc.input, store)
+			if tc.newValidator != nil {
+				tc.newValidator(output.Result)
+				return
+			}
+			if tc.migratedOutput.Error != nil {
+				assert.EqualError(t, output.Error, tc.migratedOutput.Error.Error())
+				return
+			}
+			assert.Equal(t, tc.migratedOutput.Result, output.Result)
+		})
+	}
+}
+
+func testEvalSWAPDB(t *testing.T, store *dstore.Store) {
+	resetDatabases(store)
+	evalSET([]string{"a", "v"}, store)
+	store.SelectDB(2)
+	evalSET([]string{"b", "v"}, store)
+
+	assert.EqualError(t, evalSWAPDB([]string{"0"}, store).Error, diceerrors.ErrWrongArgumentCount("SWAPDB").Error())
+	assert.EqualError(t, evalSWAPDB([]string{"0", "x"}, store).Error, diceerrors.ErrIntegerOutOfRange.Error())
+	assert.EqualError(t, evalSWAPDB([]string{"0", "-1"}, store).Error, diceerrors.ErrDBIndexOutOfRange.Error())
+
+	assert.Equal(t, clientio.OK, evalSWAPDB([]string{"0", "2"}, store).Result)
+	assert.Equal(t, "v", evalGET([]string{"a"}, store).Result)
+	assert.Equal(t, clientio.NIL, evalGET([]string{"b"}, store).Result)
+	store.SelectDB(0)
+	assert.Equal(t, "v", evalGET([]string{"b"}, store).Result)
+}
+
 func testEvalJSONSTRLEN(t *testing.T, store *dstore.Store) {
 	tests := map[string]evalTestCase{
 		"jsonstrlen nil value": {
@@ -5431,6 +6196,17 @@ func testEvalTYPE(t *testing.T, store *dstore.Store) {
 				Error:  nil,
 			},
 		},
+		"TYPE key exists and is of type JSON": {
+			name: "TYPE key exists and is of type JSON",
+			setup: func() {
+				evalJSONSET([]string{"json_key", "$", `{"a": 1}`}, store)
+			},
+			input: []string{"json_key"},
+			migratedOutput: EvalResponse{
+				Result: "ReJSON-RL",
+				Error:  nil,
+			},
+		},
 	}
 	runMigratedEvalTests(t, tests, evalTYPE, store)
 }
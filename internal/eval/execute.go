@@ -20,16 +20,18 @@ type Eval struct {
 	store                 *dstore.Store
 	isHTTPOperation       bool
 	isWebSocketOperation  bool
+	isMemcachedOperation  bool
 	isPreprocessOperation bool
 }
 
-func NewEval(c *cmd.DiceDBCmd, client *comm.Client, store *dstore.Store, httpOp, websocketOp, preProcessing bool) *Eval {
+func NewEval(c *cmd.DiceDBCmd, client *comm.Client, store *dstore.Store, httpOp, websocketOp, memcachedOp, preProcessing bool) *Eval {
 	return &Eval{
 		cmd:                   c,
 		client:                client,
 		store:                 store,
 		isHTTPOperation:       httpOp,
 		isWebSocketOperation:  websocketOp,
+		isMemcachedOperation:  memcachedOp,
 		isPreprocessOperation: preProcessing,
 	}
 }
@@ -43,10 +45,21 @@ func (e *Eval) PreProcessCommand() *EvalResponse {
 
 func (e *Eval) ExecuteCommand() *EvalResponse {
 	diceCmd, ok := DiceCmds[e.cmd.Cmd]
+	if e.isMemcachedOperation && !ok {
+		diceCmd, ok = MemcachedCmds[e.cmd.Cmd]
+	}
 	if !ok {
 		return &EvalResponse{Result: diceerrors.NewErrWithFormattedMessage("unknown command '%s', with args beginning with: %s", e.cmd.Cmd, strings.Join(e.cmd.Args, " ")), Error: nil}
 	}
 
+	// Free up memory for the commands which may grow it, or reject them if that is not possible
+	if diceCmd.DenyOOM && !e.store.FreeMemory() {
+		return &EvalResponse{Result: nil, Error: diceerrors.ErrOOM}
+	}
+
+	// Commands may grow or shrink the objects of their keys in place, without putting them back
+	defer e.updateMemory(diceCmd.KeySpecs)
+
 	// Temporary logic till we move all commands to new eval logic.
 	// MigratedDiceCmds map contains refactored eval commands
 	// For any command we will first check in the existing map
@@ -81,3 +94,21 @@ func (e *Eval) ExecuteCommand() *EvalResponse {
 		return &EvalResponse{Result: diceCmd.Eval(e.cmd.Args, e.store), Error: nil}
 	}
 }
+
+// updateMemory estimates again the size of the objects of the keys of the command, as given by
+// specs.
+func (e *Eval) updateMemory(specs KeySpecs) {
+	if specs.BeginIndex == 0 {
+		return
+	}
+
+	// The indexes of the specs count the command name, which is not in the arguments
+	step := max(specs.Step, 1)
+	last := specs.BeginIndex
+	if specs.LastKey != 0 {
+		last = len(e.cmd.Args) + 1 + specs.LastKey
+	}
+	for i := specs.BeginIndex; i <= last && i <= len(e.cmd.Args); i += step {
+		e.store.UpdateMemory(e.cmd.Args[i-1])
+	}
+}
@@ -0,0 +1,275 @@
+// Copyright (c) 2022-present, DiceDB contributors
+// All rights reserved. Licensed under the BSD 3-Clause License. See LICENSE file in the project root for full license information.
+
+package eval
+
+import (
+	"math"
+	"strconv"
+	"strings"
+
+	diceerrors "github.com/dicedb/dice/internal/errors"
+	"github.com/dicedb/dice/internal/object"
+	"github.com/dicedb/dice/internal/server/utils"
+	dstore "github.com/dicedb/dice/internal/store"
+)
+
+const FieldsConst = "FIELDS"
+
+// The replies of the hash field expiry commands, for each field.
+const (
+	fieldNotFound        int64 = -2
+	fieldWithoutExpiry   int64 = -1
+	fieldConditionNotMet int64 = 0
+	fieldExpiryUpdated   int64 = 1
+	fieldDeleted         int64 = 2
+)
+
+// parseHashFields parses the FIELDS numfields field [field ...] arguments of the hash field
+// expiry commands.
+func parseHashFields(args []string) ([]string, error) {
+	if len(args) < 2 || !strings.EqualFold(args[0], FieldsConst) {
+		return nil, diceerrors.ErrFieldsArgMissing
+	}
+
+	numFields, err := strconv.Atoi(args[1])
+	if err != nil {
+		return nil, diceerrors.ErrIntegerOutOfRange
+	}
+	if numFields <= 0 {
+		return nil, diceerrors.ErrNumFieldsInvalid
+	}
+	if numFields != len(args)-2 {
+		return nil, diceerrors.ErrNumFieldsMismatch
+	}
+
+	return args[2:], nil
+}
+
+// relativeDeadline returns the deadline, in Unix milliseconds, of a time to live in the given unit,
+// and false if it is negative or overflows.
+func relativeDeadline(unitMs int64) func(t int64) (int64, bool) {
+	return func(t int64) (int64, bool) {
+		now := utils.GetCurrentTime().UnixMilli()
+		if t < 0 || t > (math.MaxInt64-now)/unitMs {
+			return 0, false
+		}
+		return now + t*unitMs, true
+	}
+}
+
+// absoluteDeadline returns the deadline, in Unix milliseconds, of a Unix time in the given unit,
+// and false if it is negative or overflows.
+func absoluteDeadline(unitMs int64) func(t int64) (int64, bool) {
+	return func(t int64) (int64, bool) {
+		if t < 0 || t > math.MaxInt64/unitMs {
+			return 0, false
+		}
+		return t * unitMs, true
+	}
+}
+
+// evalHashFieldExpire implements HEXPIRE, HPEXPIRE, HEXPIREAT and HPEXPIREAT, with the arguments
+// key time [NX | XX | GT | LT] FIELDS numfields field [field ...], where deadline converts the
+// time to a deadline in Unix milliseconds.
+//
+// It replies, for each field, -2 if the field does not exist, 0 if the condition is not met, 1 if
+// the expiry is set, and 2 if the field is deleted since the deadline is already past.
+func evalHashFieldExpire(cmd string, args []string, store *dstore.Store, deadline func(t int64) (int64, bool)) *EvalResponse {
+	if len(args) < 5 {
+		return makeEvalError(diceerrors.ErrWrongArgumentCount(cmd))
+	}
+
+	key := args[0]
+	t, err := strconv.ParseInt(args[1], 10, 64)
+	if err != nil {
+		return makeEvalError(diceerrors.ErrIntegerOutOfRange)
+	}
+	expiresAt, ok := deadline(t)
+	if !ok {
+		return makeEvalError(diceerrors.ErrInvalidExpireTime(cmd))
+	}
+
+	// The conditions are the arguments up to FIELDS
+	i := 2
+	for i < len(args) && !strings.EqualFold(args[i], FieldsConst) {
+		i++
+	}
+	conditions := args[2:i]
+	fields, err := parseHashFields(args[i:])
+	if err != nil {
+		return makeEvalError(err)
+	}
+
+	results := make([]int64, len(fields))
+	obj := store.Get(key)
+	if obj == nil {
+		for idx := range results {
+			results[idx] = fieldNotFound
+		}
+		return makeEvalResult(results)
+	}
+
+	if err := object.AssertType(obj.Type, object.ObjTypeHashMap); err != nil {
+		return makeEvalError(diceerrors.ErrWrongTypeOperation)
+	}
+
+	hashMap := obj.Value.(HashMap)
+	now := utils.GetCurrentTime().UnixMilli()
+	deleted := false
+	for idx, field := range fields {
+		if _, ok := hashMap[field]; !ok {
+			results[idx] = fieldNotFound
+			continue
+		}
+
+		shouldSetExpiry, err := dstore.EvaluateFieldExpiry(conditions, expiresAt, obj, field, store)
+		switch {
+		case err != nil:
+			return makeEvalError(err)
+		case !shouldSetExpiry:
+			results[idx] = fieldConditionNotMet
+		case expiresAt <= now:
+			delete(hashMap, field)
+			store.DelFieldExpiry(obj, field)
+			deleted = true
+			results[idx] = fieldDeleted
+		default:
+			store.SetFieldExpiry(key, obj, field, uint64(expiresAt))
+			results[idx] = fieldExpiryUpdated
+		}
+	}
+
+	if len(hashMap) == 0 {
+		store.Del(key)
+	} else if deleted {
+		store.Put(key, obj)
+	}
+
+	return makeEvalResult(results)
+}
+
+// evalHashFieldTTL implements the commands reading the expiry of hash fields, with the arguments
+// key FIELDS numfields field [field ...]. It replies, for each field, -2 if the field does not
+// exist, -1 if it does not expire, and the reply of the command for its deadline otherwise.
+func evalHashFieldTTL(cmd string, args []string, store *dstore.Store, reply func(obj *object.Obj, field string, deadline uint64) int64) *EvalResponse {
+	if len(args) < 4 {
+		return makeEvalError(diceerrors.ErrWrongArgumentCount(cmd))
+	}
+
+	fields, err := parseHashFields(args[1:])
+	if err != nil {
+		return makeEvalError(err)
+	}
+
+	results := make([]int64, len(fields))
+	obj := store.Get(args[0])
+	if obj == nil {
+		for idx := range results {
+			results[idx] = fieldNotFound
+		}
+		return makeEvalResult(results)
+	}
+
+	if err := object.AssertType(obj.Type, object.ObjTypeHashMap); err != nil {
+		return makeEvalError(diceerrors.ErrWrongTypeOperation)
+	}
+
+	hashMap := obj.Value.(HashMap)
+	for idx, field := range fields {
+		if _, ok := hashMap[field]; !ok {
+			results[idx] = fieldNotFound
+			continue
+		}
+
+		deadline, ok := store.GetFieldExpiry(obj, field)
+		if !ok {
+			results[idx] = fieldWithoutExpiry
+			continue
+		}
+		results[idx] = reply(obj, field, deadline)
+	}
+
+	return makeEvalResult(results)
+}
+
+// evalHEXPIRE sets the time to live, in seconds, of the fields of the hash stored at key.
+//
+// Usage: HEXPIRE key seconds [NX | XX | GT | LT] FIELDS numfields field [field ...]
+func evalHEXPIRE(args []string, store *dstore.Store) *EvalResponse {
+	return evalHashFieldExpire("HEXPIRE", args, store, relativeDeadline(1000))
+}
+
+// evalHPEXPIRE sets the time to live, in milliseconds, of the fields of the hash stored at key.
+//
+// Usage: HPEXPIRE key milliseconds [NX | XX | GT | LT] FIELDS numfields field [field ...]
+func evalHPEXPIRE(args []string, store *dstore.Store) *EvalResponse {
+	return evalHashFieldExpire("HPEXPIRE", args, store, relativeDeadline(1))
+}
+
+// evalHEXPIREAT sets the Unix time, in seconds, at which the fields of the hash stored at key
+// expire.
+//
+// Usage: HEXPIREAT key unix-time-seconds [NX | XX | GT | LT] FIELDS numfields field [field ...]
+func evalHEXPIREAT(args []string, store *dstore.Store) *EvalResponse {
+	return evalHashFieldExpire("HEXPIREAT", args, store, absoluteDeadline(1000))
+}
+
+// evalHPEXPIREAT sets the Unix time, in milliseconds, at which the fields of the hash stored at key
+// expire.
+//
+// Usage: HPEXPIREAT key unix-time-milliseconds [NX | XX | GT | LT] FIELDS numfields field [field ...]
+func evalHPEXPIREAT(args []string, store *dstore.Store) *EvalResponse {
+	return evalHashFieldExpire("HPEXPIREAT", args, store, absoluteDeadline(1))
+}
+
+// evalHTTL returns the remaining time to live, in seconds, of the fields of the hash stored at key.
+//
+// Usage: HTTL key FIELDS numfields field [field ...]
+func evalHTTL(args []string, store *dstore.Store) *EvalResponse {
+	return evalHashFieldTTL("HTTL", args, store, func(_ *object.Obj, _ string, deadline uint64) int64 {
+		return (int64(deadline) - utils.GetCurrentTime().UnixMilli()) / 1000
+	})
+}
+
+// evalHPTTL returns the remaining time to live, in milliseconds, of the fields of the hash stored
+// at key.
+//
+// Usage: HPTTL key FIELDS numfields field [field ...]
+func evalHPTTL(args []string, store *dstore.Store) *EvalResponse {
+	return evalHashFieldTTL("HPTTL", args, store, func(_ *object.Obj, _ string, deadline uint64) int64 {
+		return int64(deadline) - utils.GetCurrentTime().UnixMilli()
+	})
+}
+
+// evalHEXPIRETIME returns the Unix time, in seconds, at which the fields of the hash stored at key
+// expire.
+//
+// Usage: HEXPIRETIME key FIELDS numfields field [field ...]
+func evalHEXPIRETIME(args []string, store *dstore.Store) *EvalResponse {
+	return evalHashFieldTTL("HEXPIRETIME", args, store, func(_ *object.Obj, _ string, deadline uint64) int64 {
+		return int64(deadline / 1000)
+	})
+}
+
+// evalHPEXPIRETIME returns the Unix time, in milliseconds, at which the fields of the hash stored
+// at key expire.
+//
+// Usage: HPEXPIRETIME key FIELDS numfields field [field ...]
+func evalHPEXPIRETIME(args []string, store *dstore.Store) *EvalResponse {
+	return evalHashFieldTTL("HPEXPIRETIME", args, store, func(_ *object.Obj, _ string, deadline uint64) int64 {
+		return int64(deadline)
+	})
+}
+
+// evalHPERSIST removes the expiry of the fields of the hash stored at key. It replies, for each
+// field, -2 if the field does not exist, -1 if it does not expire, and 1 once its expiry is
+// removed.
+//
+// Usage: HPERSIST key FIELDS numfields field [field ...]
+func evalHPERSIST(args []string, store *dstore.Store) *EvalResponse {
+	return evalHashFieldTTL("HPERSIST", args, store, func(obj *object.Obj, field string, _ uint64) int64 {
+		store.DelFieldExpiry(obj, field)
+		return fieldExpiryUpdated
+	})
+}
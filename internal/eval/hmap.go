@@ -12,6 +12,7 @@ import (
 
 	"github.com/dicedb/dice/internal/clientio"
 	diceerrors "github.com/dicedb/dice/internal/errors"
+	"github.com/dicedb/dice/internal/object"
 	dstore "github.com/dicedb/dice/internal/store"
 )
 
@@ -37,6 +38,34 @@ func (h HashMap) Set(k, v string) (*string, bool) {
 	return nil, false
 }
 
+// DelField deletes the field, and returns false if it does not exist.
+func (h HashMap) DelField(k string) bool {
+	if _, ok := h[k]; !ok {
+		return false
+	}
+	delete(h, k)
+	return true
+}
+
+// Len returns the number of fields of the hash.
+func (h HashMap) Len() int {
+	return len(h)
+}
+
+// ForEachField calls f with every field of the hash and its value.
+func (h HashMap) ForEachField(f func(field, value string)) {
+	for k, v := range h {
+		f(k, v)
+	}
+}
+
+// Size estimates the memory used by the hash, in bytes. See object.Sizer.
+func (h HashMap) Size(samples int) int64 {
+	return object.MapSize(h, samples, func(k, v string) int64 {
+		return object.StringSize(k) + object.StringSize(v)
+	})
+}
+
 func hashMapBuilder(keyValuePairs []string, currentHashMap HashMap) (HashMap, int64, error) {
 	var hmap HashMap
 	var numKeysNewlySet int64
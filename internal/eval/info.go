@@ -0,0 +1,43 @@
+// Copyright (c) 2022-present, DiceDB contributors
+// All rights reserved. Licensed under the BSD 3-Clause License. See LICENSE file in the project root for full license information.
+
+package eval
+
+import (
+	"strings"
+
+	dstore "github.com/dicedb/dice/internal/store"
+)
+
+// InfoShardStats is the reply of INFO from a shard, which the command handler merges with the
+// replies of the other shards into the requested sections.
+type InfoShardStats struct {
+	// Sections holds the names of the requested sections, in lower case, or none for the default
+	// ones.
+	Sections []string
+
+	Stats dstore.Stats
+
+	// UsedMemory is the estimated number of bytes used by the keys of the shard, and Databases the
+	// number of keys of each database.
+	UsedMemory int64
+	Databases  []dstore.DatabaseStats
+}
+
+// evalINFO returns the statistics of the shard for INFO.
+//
+// Usage: INFO [section [section ...]]
+func evalINFO(args []string, store *dstore.Store) *EvalResponse {
+	sections := make([]string, 0, len(args))
+	for _, arg := range args {
+		sections = append(sections, strings.ToLower(arg))
+	}
+
+	return makeEvalResult(&InfoShardStats{
+		Sections: sections,
+		Stats:    store.Stats(),
+
+		UsedMemory: store.UsedMemory(),
+		Databases:  store.DatabaseStats(),
+	})
+}
@@ -0,0 +1,305 @@
+// Copyright (c) 2022-present, DiceDB contributors
+// All rights reserved. Licensed under the BSD 3-Clause License. See LICENSE file in the project root for full license information.
+
+package eval
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/dicedb/dice/internal/clientio"
+	diceerrors "github.com/dicedb/dice/internal/errors"
+	"github.com/dicedb/dice/internal/object"
+	dstore "github.com/dicedb/dice/internal/store"
+)
+
+// Internal commands backing the memcached listener. Memcached items are plain DiceDB
+// strings, so the same keys are visible through both protocols. These commands implement
+// the memcached semantics that need to read and write a key atomically within its shard.
+// They are registered in MemcachedCmds rather than DiceCmds, so that only the operations
+// of the memcached listener can run them.
+//
+// Expiry arguments are durations in milliseconds: -1 means no expiry and 0 means that the
+// item expires immediately. The client flags of the items are kept in object.Obj.ClientFlags.
+const (
+	MCGet   = "MC.GET"
+	MCStore = "MC.STORE"
+	MCTouch = "MC.TOUCH"
+	MCIncr  = "MC.INCR"
+	MCDecr  = "MC.DECR"
+)
+
+// Storage modes of MC.STORE, named after the memcached commands.
+const (
+	MCModeSet     = "set"
+	MCModeAdd     = "add"
+	MCModeReplace = "replace"
+	MCModeAppend  = "append"
+	MCModePrepend = "prepend"
+	MCModeCas     = "cas"
+)
+
+// Replies of the memcached storage commands.
+const (
+	MCStored    = "STORED"
+	MCNotStored = "NOT_STORED"
+	MCExists    = "EXISTS"
+	MCNotFound  = "NOT_FOUND"
+	MCTouched   = "TOUCHED"
+)
+
+var (
+	mcGetCmdMeta = DiceCmdMeta{
+		Name: MCGet,
+		Info: `MC.GET key [expiry]
+		Internal command used by the memcached listener. Returns the value of the key along with
+		its CAS unique and client flags, or nil if the key does not exist. When expiry is given, the expiry of the
+		key is updated as well.`,
+		NewEval:    evalMCGET,
+		IsMigrated: true,
+		Arity:      -2,
+		KeySpecs:   KeySpecs{BeginIndex: 1},
+	}
+	mcStoreCmdMeta = DiceCmdMeta{
+		Name: MCStore,
+		Info: `MC.STORE key mode value flags expiry [cas]
+		Internal command used by the memcached listener. Stores the value according to the
+		memcached storage mode (set, add, replace, append, prepend or cas), along with its
+		client flags, which append and prepend ignore.`,
+		NewEval:    evalMCSTORE,
+		IsMigrated: true,
+		DenyOOM:    true,
+		Arity:      -6,
+		KeySpecs:   KeySpecs{BeginIndex: 1},
+	}
+	mcTouchCmdMeta = DiceCmdMeta{
+		Name: MCTouch,
+		Info: `MC.TOUCH key expiry
+		Internal command used by the memcached listener. Updates the expiry of the key.`,
+		NewEval:    evalMCTOUCH,
+		IsMigrated: true,
+		Arity:      3,
+		KeySpecs:   KeySpecs{BeginIndex: 1},
+	}
+	mcIncrCmdMeta = DiceCmdMeta{
+		Name: MCIncr,
+		Info: `MC.INCR key delta
+		Internal command used by the memcached listener. Increments the unsigned 64-bit value of
+		the key, wrapping around on overflow.`,
+		NewEval:    evalMCINCR,
+		IsMigrated: true,
+		DenyOOM:    true,
+		Arity:      3,
+		KeySpecs:   KeySpecs{BeginIndex: 1},
+	}
+	mcDecrCmdMeta = DiceCmdMeta{
+		Name: MCDecr,
+		Info: `MC.DECR key delta
+		Internal command used by the memcached listener. Decrements the unsigned 64-bit value of
+		the key, stopping at 0.`,
+		NewEval:    evalMCDECR,
+		IsMigrated: true,
+		DenyOOM:    true,
+		Arity:      3,
+		KeySpecs:   KeySpecs{BeginIndex: 1},
+	}
+)
+
+// MemcachedCmds holds the internal commands of the memcached listener, by name.
+var MemcachedCmds = map[string]DiceCmdMeta{
+	MCGet:   mcGetCmdMeta,
+	MCStore: mcStoreCmdMeta,
+	MCTouch: mcTouchCmdMeta,
+	MCIncr:  mcIncrCmdMeta,
+	MCDecr:  mcDecrCmdMeta,
+}
+
+// evalMCGET returns the value, the CAS unique and the client flags of the key, updating its expiry
+// when one is given.
+func evalMCGET(args []string, store *dstore.Store) *EvalResponse {
+	if len(args) != 1 && len(args) != 2 {
+		return makeEvalError(diceerrors.ErrWrongArgumentCount(MCGet))
+	}
+
+	key := args[0]
+	obj := store.Get(key)
+	if obj == nil {
+		return makeEvalResult(clientio.NIL)
+	}
+
+	value, err := convertValueToString(obj, obj.Type)
+	if err != nil {
+		return makeEvalError(err)
+	}
+
+	if len(args) == 2 {
+		expiryMs, err := strconv.ParseInt(args[1], 10, 64)
+		if err != nil {
+			return makeEvalError(diceerrors.ErrIntegerOutOfRange)
+		}
+		if !mcSetExpiry(store, key, obj, expiryMs) {
+			return makeEvalResult(clientio.NIL)
+		}
+	}
+
+	return makeEvalResult([]interface{}{value, obj.Version, obj.ClientFlags})
+}
+
+// evalMCSTORE stores the value of the key following the semantics of the memcached storage commands.
+func evalMCSTORE(args []string, store *dstore.Store) *EvalResponse {
+	if len(args) != 5 && len(args) != 6 {
+		return makeEvalError(diceerrors.ErrWrongArgumentCount(MCStore))
+	}
+
+	key, mode, value := args[0], strings.ToLower(args[1]), args[2]
+	flags, err := strconv.ParseUint(args[3], 10, 32)
+	if err != nil {
+		return makeEvalError(diceerrors.ErrIntegerOutOfRange)
+	}
+	expiryMs, err := strconv.ParseInt(args[4], 10, 64)
+	if err != nil {
+		return makeEvalError(diceerrors.ErrIntegerOutOfRange)
+	}
+
+	obj := store.Get(key)
+
+	switch mode {
+	case MCModeSet:
+	case MCModeAdd:
+		if obj != nil {
+			return makeEvalResult(MCNotStored)
+		}
+	case MCModeReplace:
+		if obj == nil {
+			return makeEvalResult(MCNotStored)
+		}
+	case MCModeAppend, MCModePrepend:
+		if obj == nil {
+			return makeEvalResult(MCNotStored)
+		}
+		current, err := convertValueToString(obj, obj.Type)
+		if err != nil {
+			return makeEvalResult(MCNotStored)
+		}
+		if mode == MCModeAppend {
+			value = current + value
+		} else {
+			value = value + current
+		}
+
+		// append and prepend leave the expiry and the flags of the item untouched
+		storedValue, oType := getRawStringOrInt(value)
+		newObj := store.NewObj(storedValue, -1, oType)
+		newObj.ClientFlags = obj.ClientFlags
+		store.Put(key, newObj, dstore.WithKeepTTL(true))
+		return makeEvalResult(MCStored)
+	case MCModeCas:
+		if len(args) != 6 {
+			return makeEvalError(diceerrors.ErrWrongArgumentCount(MCStore))
+		}
+		casUnique, err := strconv.ParseUint(args[5], 10, 64)
+		if err != nil {
+			return makeEvalError(diceerrors.ErrIntegerOutOfRange)
+		}
+		if obj == nil {
+			return makeEvalResult(MCNotFound)
+		}
+		if obj.Version != casUnique {
+			return makeEvalResult(MCExists)
+		}
+	default:
+		return makeEvalError(diceerrors.ErrSyntax)
+	}
+
+	storedValue, oType := getRawStringOrInt(value)
+	newObj := store.NewObj(storedValue, expiryMs, oType)
+	newObj.ClientFlags = uint32(flags)
+	store.Put(key, newObj)
+	return makeEvalResult(MCStored)
+}
+
+// evalMCTOUCH updates the expiry of the key.
+func evalMCTOUCH(args []string, store *dstore.Store) *EvalResponse {
+	if len(args) != 2 {
+		return makeEvalError(diceerrors.ErrWrongArgumentCount(MCTouch))
+	}
+
+	expiryMs, err := strconv.ParseInt(args[1], 10, 64)
+	if err != nil {
+		return makeEvalError(diceerrors.ErrIntegerOutOfRange)
+	}
+
+	obj := store.Get(args[0])
+	if obj == nil || !mcSetExpiry(store, args[0], obj, expiryMs) {
+		return makeEvalResult(MCNotFound)
+	}
+
+	return makeEvalResult(MCTouched)
+}
+
+func evalMCINCR(args []string, store *dstore.Store) *EvalResponse {
+	return mcIncrDecr(MCIncr, args, store)
+}
+
+func evalMCDECR(args []string, store *dstore.Store) *EvalResponse {
+	return mcIncrDecr(MCDecr, args, store)
+}
+
+// mcIncrDecr changes the value of the key by delta, treating it as an unsigned 64-bit integer.
+// Increments wrap around on overflow while decrements stop at 0, as in memcached.
+func mcIncrDecr(command string, args []string, store *dstore.Store) *EvalResponse {
+	if len(args) != 2 {
+		return makeEvalError(diceerrors.ErrWrongArgumentCount(command))
+	}
+
+	delta, err := strconv.ParseUint(args[1], 10, 64)
+	if err != nil {
+		return makeEvalError(diceerrors.ErrGeneral("invalid numeric delta argument"))
+	}
+
+	key := args[0]
+	obj := store.Get(key)
+	if obj == nil {
+		return makeEvalResult(MCNotFound)
+	}
+
+	current, err := convertValueToString(obj, obj.Type)
+	if err != nil {
+		return makeEvalError(err)
+	}
+
+	value, err := strconv.ParseUint(current, 10, 64)
+	if err != nil {
+		return makeEvalError(diceerrors.ErrGeneral("cannot increment or decrement non-numeric value"))
+	}
+
+	switch {
+	case command == MCIncr:
+		value += delta
+	case delta > value:
+		value = 0
+	default:
+		value -= delta
+	}
+
+	storedValue, oType := getRawStringOrInt(strconv.FormatUint(value, 10))
+	newObj := store.NewObj(storedValue, -1, oType)
+	newObj.ClientFlags = obj.ClientFlags
+	store.Put(key, newObj, dstore.WithKeepTTL(true))
+	return makeEvalResult(value)
+}
+
+// mcSetExpiry applies a memcached expiry to an existing key. It returns false if the key got
+// deleted because the expiry is in the past.
+func mcSetExpiry(store *dstore.Store, key string, obj *object.Obj, expiryMs int64) bool {
+	switch {
+	case expiryMs < 0:
+		dstore.DelExpiry(obj, store)
+	case expiryMs == 0:
+		store.Del(key)
+		return false
+	default:
+		store.SetExpiry(obj, expiryMs)
+	}
+	return true
+}
@@ -0,0 +1,149 @@
+// Copyright (c) 2022-present, DiceDB contributors
+// All rights reserved. Licensed under the BSD 3-Clause License. See LICENSE file in the project root for full license information.
+
+package eval
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/dicedb/dice/internal/clientio"
+	"github.com/dicedb/dice/internal/cmd"
+	diceerrors "github.com/dicedb/dice/internal/errors"
+	dstore "github.com/dicedb/dice/internal/store"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemcachedStore(t *testing.T) {
+	store := dstore.NewStore(nil, nil)
+
+	assert.Equal(t, clientio.NIL, evalMCGET([]string{"k"}, store).Result)
+	assert.Equal(t, MCNotStored, evalMCSTORE([]string{"k", MCModeReplace, "v", "0", "-1"}, store).Result)
+	assert.Equal(t, MCNotStored, evalMCSTORE([]string{"k", MCModeAppend, "v", "0", "-1"}, store).Result)
+	assert.Equal(t, MCStored, evalMCSTORE([]string{"k", MCModeAdd, "b", "0", "-1"}, store).Result)
+	assert.Equal(t, MCNotStored, evalMCSTORE([]string{"k", MCModeAdd, "x", "0", "-1"}, store).Result)
+	assert.Equal(t, MCStored, evalMCSTORE([]string{"k", MCModeAppend, "c", "0", "-1"}, store).Result)
+	assert.Equal(t, MCStored, evalMCSTORE([]string{"k", MCModePrepend, "a", "0", "-1"}, store).Result)
+
+	resp := evalMCGET([]string{"k"}, store)
+	assert.Nil(t, resp.Error)
+	assert.Equal(t, "abc", resp.Result.([]interface{})[0])
+	assert.Equal(t, store.Get("k").Version, resp.Result.([]interface{})[1])
+}
+
+func TestMemcachedClientFlags(t *testing.T) {
+	store := dstore.NewStore(nil, nil)
+	flags := func() uint32 {
+		return evalMCGET([]string{"k"}, store).Result.([]interface{})[2].(uint32)
+	}
+
+	assert.Equal(t, MCStored, evalMCSTORE([]string{"k", MCModeSet, "1", "4294967295", "-1"}, store).Result)
+	assert.Equal(t, uint32(4294967295), flags())
+
+	// append, prepend, incr and decr keep the flags of the item
+	evalMCSTORE([]string{"k", MCModeSet, "1", "42", "-1"}, store)
+	evalMCSTORE([]string{"k", MCModeAppend, "0", "7", "-1"}, store)
+	evalMCSTORE([]string{"k", MCModePrepend, "1", "7", "-1"}, store)
+	assert.Equal(t, uint32(42), flags())
+	assert.Equal(t, uint64(111), evalMCINCR([]string{"k", "1"}, store).Result)
+	assert.Equal(t, uint64(110), evalMCDECR([]string{"k", "1"}, store).Result)
+	assert.Equal(t, uint32(42), flags())
+
+	// the other storage commands replace them
+	evalMCSTORE([]string{"k", MCModeReplace, "v", "7", "-1"}, store)
+	assert.Equal(t, uint32(7), flags())
+
+	assert.Error(t, evalMCSTORE([]string{"k", MCModeSet, "v", "4294967296", "-1"}, store).Error)
+}
+
+func TestMemcachedCas(t *testing.T) {
+	store := dstore.NewStore(nil, nil)
+
+	assert.Equal(t, MCNotFound, evalMCSTORE([]string{"k", MCModeCas, "v", "0", "-1", "1"}, store).Result)
+	assert.Equal(t, MCStored, evalMCSTORE([]string{"k", MCModeSet, "v1", "0", "-1"}, store).Result)
+
+	casUnique := func() string {
+		return strconv.FormatUint(evalMCGET([]string{"k"}, store).Result.([]interface{})[1].(uint64), 10)
+	}
+	unique := casUnique()
+	assert.Equal(t, MCExists, evalMCSTORE([]string{"k", MCModeCas, "v2", "0", "-1", unique + "1"}, store).Result)
+	assert.Equal(t, MCStored, evalMCSTORE([]string{"k", MCModeCas, "v2", "0", "-1", unique}, store).Result)
+	assert.Equal(t, "v2", evalMCGET([]string{"k"}, store).Result.([]interface{})[0])
+
+	// a value changed and changed back since it was read is not taken for the value read
+	unique = casUnique()
+	evalMCSTORE([]string{"k", MCModeSet, "v3", "0", "-1"}, store)
+	evalMCSTORE([]string{"k", MCModeSet, "v2", "0", "-1"}, store)
+	assert.Equal(t, MCExists, evalMCSTORE([]string{"k", MCModeCas, "v4", "0", "-1", unique}, store).Result)
+
+	// as is a value incremented in place
+	evalMCSTORE([]string{"k", MCModeSet, "1", "0", "-1"}, store)
+	unique = casUnique()
+	assert.Nil(t, evalINCR([]string{"k"}, store).Error)
+	assert.Equal(t, MCExists, evalMCSTORE([]string{"k", MCModeCas, "v4", "0", "-1", unique}, store).Result)
+}
+
+func TestMemcachedIncrDecr(t *testing.T) {
+	store := dstore.NewStore(nil, nil)
+
+	assert.Equal(t, MCNotFound, evalMCINCR([]string{"n", "1"}, store).Result)
+	evalMCSTORE([]string{"n", MCModeSet, "10", "0", "-1"}, store)
+	assert.Equal(t, uint64(15), evalMCINCR([]string{"n", "5"}, store).Result)
+	assert.Equal(t, uint64(0), evalMCDECR([]string{"n", "100"}, store).Result)
+
+	evalMCSTORE([]string{"n", MCModeSet, "18446744073709551615", "0", "-1"}, store)
+	assert.Equal(t, uint64(1), evalMCINCR([]string{"n", "2"}, store).Result)
+
+	evalMCSTORE([]string{"s", MCModeSet, "abc", "0", "-1"}, store)
+	assert.Error(t, evalMCINCR([]string{"s", "1"}, store).Error)
+}
+
+func TestMemcachedTouch(t *testing.T) {
+	store := dstore.NewStore(nil, nil)
+
+	assert.Equal(t, MCNotFound, evalMCTOUCH([]string{"k", "1000"}, store).Result)
+	evalMCSTORE([]string{"k", MCModeSet, "v", "0", "-1"}, store)
+	assert.Equal(t, MCTouched, evalMCTOUCH([]string{"k", "100000"}, store).Result)
+
+	exp, ok := dstore.GetExpiry(store.Get("k"), store)
+	assert.True(t, ok)
+	assert.Positive(t, exp)
+
+	assert.Equal(t, MCNotFound, evalMCTOUCH([]string{"k", "0"}, store).Result)
+	assert.Equal(t, clientio.NIL, evalMCGET([]string{"k"}, store).Result)
+}
+
+func TestMemcachedCommandsAreInternal(t *testing.T) {
+	store := dstore.NewStore(nil, nil)
+	diceDBCmd := &cmd.DiceDBCmd{Cmd: MCStore, Args: []string{"k", MCModeSet, "v", "0", "-1"}}
+
+	assert.NotContains(t, DiceCmds, MCStore)
+	resp := NewEval(diceDBCmd, nil, store, false, false, false, false).ExecuteCommand()
+	assert.Equal(t, "-ERR unknown command 'MC.STORE', with args beginning with: k set v 0 -1\r\n", string(resp.Result.([]byte)))
+
+	resp = NewEval(diceDBCmd, nil, store, false, false, true, false).ExecuteCommand()
+	assert.Equal(t, MCStored, resp.Result)
+
+	// the memcached listener runs the public commands as well
+	resp = NewEval(&cmd.DiceDBCmd{Cmd: "GET", Args: []string{"k"}}, nil, store, false, false, true, false).ExecuteCommand()
+	assert.Equal(t, "v", resp.Result)
+}
+
+func TestMemcachedCommandsDenyOOM(t *testing.T) {
+	store := dstore.NewStore(nil, dstore.NewNoEviction())
+	evalMCSTORE([]string{"n", MCModeSet, "1", "0", "-1"}, store)
+	store.SetMaxMemory(store.UsedMemory() - 1)
+
+	for _, diceDBCmd := range []*cmd.DiceDBCmd{
+		{Cmd: MCStore, Args: []string{"k", MCModeSet, "v", "0", "-1"}},
+		{Cmd: MCIncr, Args: []string{"n", "1"}},
+		{Cmd: MCDecr, Args: []string{"n", "1"}},
+	} {
+		resp := NewEval(diceDBCmd, nil, store, false, false, true, false).ExecuteCommand()
+		assert.ErrorIs(t, resp.Error, diceerrors.ErrOOM, diceDBCmd.Cmd)
+	}
+
+	// reading keys is still allowed
+	resp := NewEval(&cmd.DiceDBCmd{Cmd: MCGet, Args: []string{"n"}}, nil, store, false, false, true, false).ExecuteCommand()
+	assert.Nil(t, resp.Error)
+}
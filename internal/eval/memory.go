@@ -0,0 +1,117 @@
+// Copyright (c) 2022-present, DiceDB contributors
+// All rights reserved. Licensed under the BSD 3-Clause License. See LICENSE file in the project root for full license information.
+
+package eval
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/dicedb/dice/internal/clientio"
+	diceerrors "github.com/dicedb/dice/internal/errors"
+	"github.com/dicedb/dice/internal/object"
+	dstore "github.com/dicedb/dice/internal/store"
+)
+
+const (
+	MemoryUsage   = "USAGE"
+	MemoryStats   = "STATS"
+	MemorySamples = "SAMPLES"
+)
+
+// MemoryUsageStats holds the number of keys and the bytes they use.
+type MemoryUsageStats struct {
+	Keys  int64
+	Bytes int64
+}
+
+// MemoryShardStats is the reply of MEMORY STATS from a shard, which the command handler merges
+// with the replies of the other shards. Types holds the usage of the keys of each type.
+type MemoryShardStats struct {
+	MemoryUsageStats
+	Types map[string]MemoryUsageStats
+}
+
+// memoryTypeName returns the name under which MEMORY STATS reports the keys of the type of obj.
+func memoryTypeName(obj *object.Obj) string {
+	switch obj.Type {
+	case object.ObjTypeBF:
+		return "bloom"
+	case object.ObjTypeCountMinSketch:
+		return "cms"
+	case object.ObjTypeHLL:
+		return "hll"
+	default:
+		return typeName(obj)
+	}
+}
+
+// evalMEMORY implements the MEMORY subcommands on the keys of a shard.
+//
+// Usage: MEMORY USAGE key [SAMPLES count] | MEMORY STATS
+func evalMEMORY(args []string, store *dstore.Store) *EvalResponse {
+	if len(args) < 1 {
+		return makeEvalError(diceerrors.ErrWrongArgumentCount("MEMORY"))
+	}
+
+	switch subcommand := strings.ToUpper(args[0]); subcommand {
+	case MemoryUsage:
+		return evalMemoryUsage(args[1:], store)
+	case MemoryStats:
+		if len(args) != 1 {
+			return makeEvalError(diceerrors.ErrWrongArgumentCount("MEMORY|STATS"))
+		}
+		return makeEvalResult(memoryShardStats(store))
+	default:
+		return makeEvalError(diceerrors.ErrGeneral(fmt.Sprintf("unknown subcommand '%s'. Try MEMORY HELP.", subcommand)))
+	}
+}
+
+// evalMemoryUsage returns the number of bytes used by key and its value, or nil if the key does
+// not exist. SAMPLES sets how many elements of an aggregate value are sampled to estimate its size,
+// 0 sampling all of them.
+func evalMemoryUsage(args []string, store *dstore.Store) *EvalResponse {
+	if len(args) != 1 && len(args) != 3 {
+		return makeEvalError(diceerrors.ErrWrongArgumentCount("MEMORY|USAGE"))
+	}
+
+	samples := object.DefaultSizeSamples
+	if len(args) == 3 {
+		if !strings.EqualFold(args[1], MemorySamples) {
+			return makeEvalError(diceerrors.ErrSyntax)
+		}
+		n, err := strconv.Atoi(args[2])
+		if err != nil || n < 0 {
+			return makeEvalError(diceerrors.ErrIntegerOutOfRange)
+		}
+		samples = n
+	}
+
+	key := args[0]
+	obj := store.GetNoTouch(key)
+	if obj == nil {
+		return makeEvalResult(clientio.NIL)
+	}
+
+	return makeEvalResult(object.KeySize(key, obj, samples))
+}
+
+// memoryShardStats sums the memory used by the keys of the selected database of the shard, in
+// total and per type.
+func memoryShardStats(store *dstore.Store) *MemoryShardStats {
+	stats := &MemoryShardStats{Types: make(map[string]MemoryUsageStats)}
+	store.GetStore().All(func(key string, obj *object.Obj) bool {
+		bytes := object.KeySize(key, obj, object.DefaultSizeSamples)
+		stats.Keys++
+		stats.Bytes += bytes
+
+		name := memoryTypeName(obj)
+		typeStats := stats.Types[name]
+		typeStats.Keys++
+		typeStats.Bytes += bytes
+		stats.Types[name] = typeStats
+		return true
+	})
+	return stats
+}
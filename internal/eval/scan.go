@@ -0,0 +1,231 @@
+// Copyright (c) 2022-present, DiceDB contributors
+// All rights reserved. Licensed under the BSD 3-Clause License. See LICENSE file in the project root for full license information.
+
+package eval
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	diceerrors "github.com/dicedb/dice/internal/errors"
+	"github.com/dicedb/dice/internal/eval/sortedset"
+	"github.com/dicedb/dice/internal/object"
+	dstore "github.com/dicedb/dice/internal/store"
+	"github.com/gobwas/glob"
+)
+
+const (
+	MatchConst = "MATCH"
+	TypeConst  = "TYPE"
+
+	defaultScanCount = 10
+)
+
+// scanOptions holds the options of the SCAN family of commands.
+type scanOptions struct {
+	pattern glob.Glob
+	count   int
+	objType string
+}
+
+// parseScanOptions parses the [MATCH pattern] [COUNT count] options of the SCAN family of commands,
+// along with [TYPE type] if withType is set.
+func parseScanOptions(args []string, withType bool) (*scanOptions, error) {
+	opts := &scanOptions{count: defaultScanCount}
+	pattern := "*"
+	for i := 0; i < len(args); i += 2 {
+		if i+1 >= len(args) {
+			return nil, diceerrors.ErrSyntax
+		}
+		switch strings.ToUpper(args[i]) {
+		case MatchConst:
+			pattern = args[i+1]
+		case CountConst:
+			count, err := strconv.Atoi(args[i+1])
+			if err != nil || count < 1 {
+				return nil, diceerrors.ErrIntegerOutOfRange
+			}
+			opts.count = count
+		case TypeConst:
+			if !withType {
+				return nil, diceerrors.ErrSyntax
+			}
+			opts.objType = args[i+1]
+		default:
+			return nil, diceerrors.ErrSyntax
+		}
+	}
+
+	g, err := glob.Compile(pattern)
+	if err != nil {
+		return nil, diceerrors.ErrGeneral(fmt.Sprintf("Invalid glob pattern: %s", err))
+	}
+	opts.pattern = g
+	return opts, nil
+}
+
+// scanMembers returns the cursor of the next call along with the page of the members of a
+// collection starting at the cursor. The cursor is the offset of a member in the sorted members,
+// and the next cursor is "0" once all the members are scanned. Each member that matches the
+// pattern is replied as the elements returned by reply.
+//
+// Note that sorting the members makes each call O(N), in contrast to Redis, which scans the
+// buckets of the hash table.
+func scanMembers(members []string, cursor int, opts *scanOptions, reply func(member string) []string) []interface{} {
+	sort.Strings(members)
+
+	matched := 0
+	results := make([]string, 0, opts.count*2)
+	newCursor := 0
+
+	// Scan the members and add them to the results if they match the pattern
+	for i := cursor; i < len(members); i++ {
+		if opts.pattern.Match(members[i]) {
+			results = append(results, reply(members[i])...)
+			matched++
+			if matched >= opts.count {
+				newCursor = i + 1
+				break
+			}
+		}
+	}
+
+	// If we've scanned all members, reset cursor to 0
+	if newCursor >= len(members) {
+		newCursor = 0
+	}
+
+	return []interface{}{strconv.Itoa(newCursor), results}
+}
+
+// scanCollection implements HSCAN, SSCAN and ZSCAN, with the arguments key cursor [MATCH pattern]
+// [COUNT count], on the collections of the given type.
+func scanCollection(cmd string, args []string, store *dstore.Store, objType object.ObjectType,
+	scan func(obj *object.Obj, cursor int, opts *scanOptions) []interface{}) *EvalResponse {
+	if len(args) < 2 {
+		return makeEvalError(diceerrors.ErrWrongArgumentCount(cmd))
+	}
+
+	cursor, err := strconv.Atoi(args[1])
+	if err != nil || cursor < 0 {
+		return makeEvalError(diceerrors.ErrIntegerOutOfRange)
+	}
+
+	obj := store.Get(args[0])
+	if obj == nil {
+		return makeEvalResult([]interface{}{"0", []string{}})
+	}
+
+	if err := object.AssertType(obj.Type, objType); err != nil {
+		return makeEvalError(diceerrors.ErrWrongTypeOperation)
+	}
+
+	opts, err := parseScanOptions(args[2:], false)
+	if err != nil {
+		return makeEvalError(err)
+	}
+
+	return makeEvalResult(scan(obj, cursor, opts))
+}
+
+// evalHSCAN return a two element multi-bulk reply, where the first element is a string representing the cursor,
+// and the second element is a multi-bulk with an array of elements.
+//
+// The array of elements contain two elements, a field and a value, for every returned element of the Hash.
+//
+// If key doesn't exist, it returns an array containing 0 and empty array.
+//
+// Usage: HSCAN key cursor [MATCH pattern] [COUNT count]
+func evalHSCAN(args []string, store *dstore.Store) *EvalResponse {
+	return scanCollection("HSCAN", args, store, object.ObjTypeHashMap, func(obj *object.Obj, cursor int, opts *scanOptions) []interface{} {
+		hashMap := obj.Value.(HashMap)
+		fields := make([]string, 0, len(hashMap))
+		for k := range hashMap {
+			fields = append(fields, k)
+		}
+		return scanMembers(fields, cursor, opts, func(field string) []string {
+			return []string{field, hashMap[field]}
+		})
+	})
+}
+
+// evalSSCAN iterates over the members of the set at key.
+func evalSSCAN(args []string, store *dstore.Store) *EvalResponse {
+	return scanCollection("SSCAN", args, store, object.ObjTypeSet, func(obj *object.Obj, cursor int, opts *scanOptions) []interface{} {
+		set := obj.Value.(map[string]struct{})
+		members := make([]string, 0, len(set))
+		for k := range set {
+			members = append(members, k)
+		}
+		return scanMembers(members, cursor, opts, func(member string) []string {
+			return []string{member}
+		})
+	})
+}
+
+// evalZSCAN iterates over the members of the sorted set at key, replying each matching member
+// followed by its score.
+func evalZSCAN(args []string, store *dstore.Store) *EvalResponse {
+	return scanCollection("ZSCAN", args, store, object.ObjTypeSortedSet, func(obj *object.Obj, cursor int, opts *scanOptions) []interface{} {
+		sortedSet := obj.Value.(*sortedset.Set)
+		return scanMembers(sortedSet.Members(), cursor, opts, func(member string) []string {
+			score, _ := sortedSet.Get(member)
+			return []string{member, strings.ToLower(strconv.FormatFloat(score, 'g', -1, 64))}
+		})
+	})
+}
+
+// evalSCAN scans the keys of the shard the cursor points to, and is spawned by the SCAN command as
+// the internal SINGLESCAN command, with the arguments cursor shard-count [MATCH pattern]
+// [COUNT count] [TYPE type]. It replies with the cursor to continue the scan with, which points
+// to the next shard once this one is scanned, and is "0" after the last shard.
+//
+// COUNT is the number of keys looked at, before filtering them with MATCH and TYPE, so that a
+// call is bounded even when few keys match.
+func evalSCAN(args []string, store *dstore.Store) *EvalResponse {
+	if len(args) < 2 {
+		return makeEvalError(diceerrors.ErrWrongArgumentCount("SCAN"))
+	}
+
+	cursor, err := strconv.ParseUint(args[0], 10, 64)
+	if err != nil {
+		return makeEvalError(diceerrors.ErrInvalidCursor)
+	}
+	shardCount, err := strconv.Atoi(args[1])
+	if err != nil {
+		return makeEvalError(diceerrors.ErrInternalServer)
+	}
+	opts, err := parseScanOptions(args[2:], true)
+	if err != nil {
+		return makeEvalError(err)
+	}
+
+	shardID, position := dstore.DecodeScanCursor(cursor)
+	keys, next := store.Scan(position, opts.count)
+
+	results := make([]string, 0, len(keys))
+	for _, key := range keys {
+		if !opts.pattern.Match(key) {
+			continue
+		}
+		if opts.objType != "" {
+			if obj := store.GetNoTouch(key); obj == nil || !strings.EqualFold(typeName(obj), opts.objType) {
+				continue
+			}
+		}
+		results = append(results, key)
+	}
+
+	switch {
+	case next != 0:
+		cursor = dstore.EncodeScanCursor(shardID, next)
+	case int(shardID)+1 < shardCount:
+		cursor = dstore.EncodeScanCursor(shardID+1, 0)
+	default:
+		cursor = 0
+	}
+
+	return makeEvalResult([]interface{}{strconv.FormatUint(cursor, 10), results})
+}
@@ -0,0 +1,73 @@
+// Copyright (c) 2022-present, DiceDB contributors
+// All rights reserved. Licensed under the BSD 3-Clause License. See LICENSE file in the project root for full license information.
+
+package eval
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/dicedb/dice/internal/clientio"
+	diceerrors "github.com/dicedb/dice/internal/errors"
+	dstore "github.com/dicedb/dice/internal/store"
+)
+
+const (
+	SlowLogCmd   = "SLOWLOG"
+	SlowLogGet   = "GET"
+	SlowLogLen   = "LEN"
+	SlowLogReset = "RESET"
+
+	// slowLogDefaultCount is the number of entries returned by SLOWLOG GET without a count.
+	slowLogDefaultCount = 10
+)
+
+// SlowLogShardEntries is the reply of SLOWLOG GET from a shard, which the command handler merges
+// with the replies of the other shards. Count is the number of entries requested, or -1 for all
+// of them.
+type SlowLogShardEntries struct {
+	Count   int
+	Entries []dstore.SlowLogEntry
+}
+
+// evalSLOWLOG implements the SLOWLOG subcommands on the slowlog of a shard.
+//
+// Usage: SLOWLOG GET [count] | SLOWLOG LEN | SLOWLOG RESET
+func evalSLOWLOG(args []string, store *dstore.Store) *EvalResponse {
+	if len(args) < 1 {
+		return makeEvalError(diceerrors.ErrWrongArgumentCount("SLOWLOG"))
+	}
+
+	switch subcommand := strings.ToUpper(args[0]); subcommand {
+	case SlowLogGet:
+		if len(args) > 2 {
+			return makeEvalError(diceerrors.ErrWrongArgumentCount("SLOWLOG|GET"))
+		}
+		count := slowLogDefaultCount
+		if len(args) == 2 {
+			n, err := strconv.Atoi(args[1])
+			if err != nil {
+				return makeEvalError(diceerrors.ErrIntegerOutOfRange)
+			}
+			if n < -1 {
+				return makeEvalError(diceerrors.ErrGeneral("count should be greater than or equal to -1"))
+			}
+			count = n
+		}
+		return makeEvalResult(&SlowLogShardEntries{Count: count, Entries: store.SlowLog().Entries(count)})
+	case SlowLogLen:
+		if len(args) != 1 {
+			return makeEvalError(diceerrors.ErrWrongArgumentCount("SLOWLOG|LEN"))
+		}
+		return makeEvalResult(int64(store.SlowLog().Len()))
+	case SlowLogReset:
+		if len(args) != 1 {
+			return makeEvalError(diceerrors.ErrWrongArgumentCount("SLOWLOG|RESET"))
+		}
+		store.SlowLog().Reset()
+		return makeEvalResult(clientio.OK)
+	default:
+		return makeEvalError(diceerrors.ErrGeneral(fmt.Sprintf("unknown subcommand '%s'. Try SLOWLOG HELP.", subcommand)))
+	}
+}
@@ -0,0 +1,465 @@
+// Copyright (c) 2022-present, DiceDB contributors
+// All rights reserved. Licensed under the BSD 3-Clause License. See LICENSE file in the project root for full license information.
+
+package eval
+
+import (
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/dicedb/dice/internal/clientio"
+	"github.com/dicedb/dice/internal/cmd"
+	diceerrors "github.com/dicedb/dice/internal/errors"
+	"github.com/dicedb/dice/internal/eval/sortedset"
+	"github.com/dicedb/dice/internal/object"
+	dstore "github.com/dicedb/dice/internal/store"
+)
+
+const (
+	SortAsc   = "ASC"
+	SortDesc  = "DESC"
+	SortAlpha = "ALPHA"
+	SortLimit = "LIMIT"
+	SortBy    = "BY"
+	SortStore = "STORE"
+
+	// sortElementPattern is the GET pattern reading the element itself.
+	sortElementPattern = "#"
+)
+
+// SortLookup is a key, or a field of the hash stored at a key, read by a BY or GET pattern of SORT.
+// Field is empty when the pattern reads the key itself.
+type SortLookup struct {
+	Key   string
+	Field string
+}
+
+// SortInput holds what SORT reads when the keys of its patterns may be owned by other shards. The
+// command handler gets the elements, the lookups of the patterns and the STORE destination from the
+// shard owning the sorted key, and fills Values with the lookups that exist from the shards owning
+// them.
+type SortInput struct {
+	Elements []string
+	Lookups  []SortLookup
+	StoreKey string
+	Values   map[SortLookup]string
+}
+
+// sortOptions holds the options of SORT and SORT_RO.
+type sortOptions struct {
+	by       string
+	dontSort bool
+	offset   int64
+	count    int64
+	gets     []string
+	desc     bool
+	alpha    bool
+	storeKey string
+}
+
+// parseSortOptions parses the options following the key of SORT or SORT_RO, which are
+// [BY pattern] [LIMIT offset count] [GET pattern ...] [ASC | DESC] [ALPHA] [STORE destination].
+func parseSortOptions(cmdName string, args []string) (*sortOptions, error) {
+	opts := &sortOptions{count: -1}
+	for i := 1; i < len(args); i++ {
+		remaining := len(args) - i - 1
+		switch strings.ToUpper(args[i]) {
+		case SortAsc:
+			opts.desc = false
+		case SortDesc:
+			opts.desc = true
+		case SortAlpha:
+			opts.alpha = true
+		case SortLimit:
+			if remaining < 2 {
+				return nil, diceerrors.ErrSyntax
+			}
+			offset, err := strconv.ParseInt(args[i+1], 10, 64)
+			if err != nil {
+				return nil, diceerrors.ErrIntegerOutOfRange
+			}
+			count, err := strconv.ParseInt(args[i+2], 10, 64)
+			if err != nil {
+				return nil, diceerrors.ErrIntegerOutOfRange
+			}
+			opts.offset, opts.count = offset, count
+			i += 2
+		case SortBy:
+			if remaining < 1 {
+				return nil, diceerrors.ErrSyntax
+			}
+			// A pattern without '*', such as nosort, reads the same key for every element, which
+			// leaves them in the order of the collection
+			opts.by = args[i+1]
+			opts.dontSort = !strings.Contains(opts.by, "*")
+			i++
+		case GET:
+			if remaining < 1 {
+				return nil, diceerrors.ErrSyntax
+			}
+			opts.gets = append(opts.gets, args[i+1])
+			i++
+		case SortStore:
+			if remaining < 1 || cmdName != "SORT" {
+				return nil, diceerrors.ErrSyntax
+			}
+			opts.storeKey = args[i+1]
+			i++
+		default:
+			return nil, diceerrors.ErrSyntax
+		}
+	}
+	return opts, nil
+}
+
+// sortElements returns the elements of the list, set or sorted set, in the order SORT returns them
+// when it does not sort them: the order of the list, the lexicographical order of the set so that
+// the result is deterministic, and the order of the scores of the sorted set, reversed by DESC.
+func sortElements(obj *object.Obj, opts *sortOptions) ([]string, error) {
+	if obj == nil {
+		return []string{}, nil
+	}
+
+	switch obj.Type {
+	case object.ObjTypeDequeue:
+		return obj.Value.(DequeI).LRange(0, -1)
+	case object.ObjTypeSet:
+		set := obj.Value.(map[string]struct{})
+		elements := make([]string, 0, len(set))
+		for element := range set {
+			elements = append(elements, element)
+		}
+		if opts.dontSort {
+			sort.Strings(elements)
+		}
+		return elements, nil
+	case object.ObjTypeSortedSet:
+		ss := obj.Value.(*sortedset.Set)
+		return ss.GetRange(0, -1, false, opts.dontSort && opts.desc), nil
+	default:
+		return nil, diceerrors.ErrWrongTypeOperation
+	}
+}
+
+// sortLookup returns what the pattern reads for the element, which is the key built by replacing
+// the first '*' of the pattern with the element, up to the '->' introducing a hash field if any. It
+// returns false if the pattern has no '*', in which case it reads nothing.
+func sortLookup(pattern, element string) (SortLookup, bool) {
+	star := strings.IndexByte(pattern, '*')
+	if star < 0 {
+		return SortLookup{}, false
+	}
+
+	keyPattern, field := pattern, ""
+	if arrow := strings.Index(pattern[star+1:], "->"); arrow >= 0 && star+arrow+3 < len(pattern) {
+		keyPattern, field = pattern[:star+1+arrow], pattern[star+arrow+3:]
+	}
+	return SortLookup{Key: keyPattern[:star] + element + keyPattern[star+1:], Field: field}, true
+}
+
+// sortLookups returns the distinct lookups of the BY and GET patterns for the elements.
+func sortLookups(opts *sortOptions, elements []string) []SortLookup {
+	patterns := make([]string, 0, len(opts.gets)+1)
+	if opts.by != "" && !opts.dontSort {
+		patterns = append(patterns, opts.by)
+	}
+	for _, pattern := range opts.gets {
+		if pattern != sortElementPattern {
+			patterns = append(patterns, pattern)
+		}
+	}
+
+	seen := make(map[SortLookup]struct{})
+	lookups := make([]SortLookup, 0)
+	for _, pattern := range patterns {
+		for _, element := range elements {
+			lookup, ok := sortLookup(pattern, element)
+			if !ok {
+				break
+			}
+			if _, ok := seen[lookup]; !ok {
+				seen[lookup] = struct{}{}
+				lookups = append(lookups, lookup)
+			}
+		}
+	}
+	return lookups
+}
+
+// lookupSortValue returns the value of the lookup in the store, and false if the key does not exist
+// or does not hold a string, or a hash having the field.
+func lookupSortValue(store *dstore.Store, lookup SortLookup) (string, bool) {
+	obj := store.Get(lookup.Key)
+	if obj == nil {
+		return "", false
+	}
+
+	if lookup.Field != "" {
+		if obj.Type != object.ObjTypeHashMap {
+			return "", false
+		}
+		value, ok := obj.Value.(HashMap)[lookup.Field]
+		return value, ok
+	}
+
+	if obj.Type != object.ObjTypeString && obj.Type != object.ObjTypeInt {
+		return "", false
+	}
+	switch value := obj.Value.(type) {
+	case string:
+		return value, true
+	case int64:
+		return strconv.FormatInt(value, 10), true
+	default:
+		return "", false
+	}
+}
+
+// sortItem is an element being sorted, along with its weight, which is the element itself or the
+// value of the BY pattern.
+type sortItem struct {
+	element  string
+	score    float64
+	value    string
+	hasValue bool
+}
+
+// compareSortItems compares the items in ascending order. Items of equal weight are compared
+// lexicographically, so that the result is deterministic.
+func compareSortItems(a, b *sortItem, alpha bool) int {
+	if !alpha {
+		switch {
+		case a.score < b.score:
+			return -1
+		case a.score > b.score:
+			return 1
+		}
+	} else if a.hasValue != b.hasValue {
+		// Missing weights come first
+		if !a.hasValue {
+			return -1
+		}
+		return 1
+	} else if cmp := strings.Compare(a.value, b.value); cmp != 0 {
+		return cmp
+	}
+	return strings.Compare(a.element, b.element)
+}
+
+// runSort sorts the elements and returns the reply of SORT, reading the values of the patterns with
+// lookup. The reply holds nil for the GET patterns reading nothing.
+func runSort(opts *sortOptions, elements []string, lookup func(SortLookup) (string, bool)) ([]interface{}, error) {
+	if !opts.dontSort {
+		items := make([]*sortItem, len(elements))
+		for i, element := range elements {
+			item := &sortItem{element: element}
+			value, ok := element, true
+			if opts.by != "" {
+				l, _ := sortLookup(opts.by, element)
+				value, ok = lookup(l)
+			}
+
+			if opts.alpha {
+				item.value, item.hasValue = value, ok
+			} else if ok {
+				score, err := strconv.ParseFloat(value, 64)
+				if err != nil || math.IsNaN(score) {
+					return nil, diceerrors.ErrSortScoreNotDouble
+				}
+				item.score = score
+			}
+			items[i] = item
+		}
+
+		sort.Slice(items, func(i, j int) bool {
+			cmp := compareSortItems(items[i], items[j], opts.alpha)
+			if opts.desc {
+				return cmp > 0
+			}
+			return cmp < 0
+		})
+		for i, item := range items {
+			elements[i] = item.element
+		}
+	}
+
+	start, end := int64(0), int64(len(elements))
+	if opts.offset > 0 {
+		start = min(opts.offset, end)
+	}
+	if opts.count >= 0 {
+		end = min(start+opts.count, end)
+	}
+	elements = elements[start:end]
+
+	if len(opts.gets) == 0 {
+		result := make([]interface{}, len(elements))
+		for i, element := range elements {
+			result[i] = element
+		}
+		return result, nil
+	}
+
+	result := make([]interface{}, 0, len(elements)*len(opts.gets))
+	for _, element := range elements {
+		for _, pattern := range opts.gets {
+			if pattern == sortElementPattern {
+				result = append(result, element)
+				continue
+			}
+			l, ok := sortLookup(pattern, element)
+			if !ok {
+				result = append(result, nil)
+				continue
+			}
+			if value, ok := lookup(l); ok {
+				result = append(result, value)
+			} else {
+				result = append(result, nil)
+			}
+		}
+	}
+	return result, nil
+}
+
+// sortReply returns the sorted values, or stores them as a list at the STORE destination, the
+// values reading nothing being stored as empty strings, and returns their number. The destination
+// is deleted if there are no values.
+func sortReply(opts *sortOptions, values []interface{}, store *dstore.Store) *EvalResponse {
+	if opts.storeKey == "" {
+		return makeEvalResult(values)
+	}
+
+	if len(values) == 0 {
+		store.Del(opts.storeKey)
+		return makeEvalResult(clientio.IntegerZero)
+	}
+
+	deque := NewDeque()
+	for _, value := range values {
+		element, _ := value.(string)
+		deque.RPush(element)
+	}
+	store.Put(opts.storeKey, store.NewObj(deque, -1, object.ObjTypeDequeue))
+	return makeEvalResult(int64(len(values)))
+}
+
+// evalSort implements SORT and SORT_RO on a single store, which holds the keys of the patterns.
+func evalSort(cmdName string, args []string, store *dstore.Store) *EvalResponse {
+	if len(args) < 1 {
+		return makeEvalError(diceerrors.ErrWrongArgumentCount(cmdName))
+	}
+
+	opts, err := parseSortOptions(cmdName, args)
+	if err != nil {
+		return makeEvalError(err)
+	}
+	elements, err := sortElements(store.Get(args[0]), opts)
+	if err != nil {
+		return makeEvalError(err)
+	}
+
+	values, err := runSort(opts, elements, func(lookup SortLookup) (string, bool) {
+		return lookupSortValue(store, lookup)
+	})
+	if err != nil {
+		return makeEvalError(err)
+	}
+	return sortReply(opts, values, store)
+}
+
+// evalSORT sorts the elements of the list, set or sorted set stored at key, numerically unless
+// ALPHA is given. The elements are weighted by the values of the BY pattern, and replaced by the
+// values of the GET patterns, in which '*' stands for the element and '->' reads a hash field.
+//
+// Usage: SORT key [BY pattern] [LIMIT offset count] [GET pattern [GET pattern ...]] [ASC | DESC]
+// [ALPHA] [STORE destination]
+func evalSORT(args []string, store *dstore.Store) *EvalResponse {
+	return evalSort("SORT", args, store)
+}
+
+// evalSORTRO is the read-only variant of SORT, which does not take STORE.
+//
+// Usage: SORT_RO key [BY pattern] [LIMIT offset count] [GET pattern [GET pattern ...]] [ASC | DESC]
+// [ALPHA]
+func evalSORTRO(args []string, store *dstore.Store) *EvalResponse {
+	return evalSort("SORT_RO", args, store)
+}
+
+// evalSortPreProcess returns the SortInput of SORT or SORT_RO, holding the elements of the key, the
+// lookups of the patterns and the STORE destination, without the values of the lookups.
+func evalSortPreProcess(cmdName string) func(args []string, store *dstore.Store) *EvalResponse {
+	return func(args []string, store *dstore.Store) *EvalResponse {
+		if len(args) < 1 {
+			return makeEvalError(diceerrors.ErrWrongArgumentCount(cmdName))
+		}
+
+		opts, err := parseSortOptions(cmdName, args)
+		if err != nil {
+			return makeEvalError(err)
+		}
+		elements, err := sortElements(store.Get(args[0]), opts)
+		if err != nil {
+			return makeEvalError(err)
+		}
+
+		return makeEvalResult(&SortInput{
+			Elements: elements,
+			Lookups:  sortLookups(opts, elements),
+			StoreKey: opts.storeKey,
+		})
+	}
+}
+
+// evalSortLookup returns the values of the lookups given as key and field pairs, an empty field
+// reading the key itself. The lookups which do not exist are left out.
+func evalSortLookup(args []string, store *dstore.Store) *EvalResponse {
+	if len(args)%2 != 0 {
+		return makeEvalError(diceerrors.ErrInternalServer)
+	}
+
+	values := make(map[SortLookup]string, len(args)/2)
+	for i := 0; i < len(args); i += 2 {
+		lookup := SortLookup{Key: args[i], Field: args[i+1]}
+		if value, ok := lookupSortValue(store, lookup); ok {
+			values[lookup] = value
+		}
+	}
+	return makeEvalResult(values)
+}
+
+// evalSortObject implements SORT and SORT_RO with the SortInput gathered by the command handler, on
+// the store owning the STORE destination if any.
+func evalSortObject(cd *cmd.DiceDBCmd, store *dstore.Store) *EvalResponse {
+	if len(cd.InternalObjs) < 1 {
+		return makeEvalError(diceerrors.ErrInternalServer)
+	}
+	opts, err := parseSortOptions(cd.Cmd, cd.Args)
+	if err != nil {
+		return makeEvalError(err)
+	}
+
+	input, ok := cd.InternalObjs[0].Obj.Value.(*SortInput)
+	if !ok {
+		return makeEvalError(diceerrors.ErrInternalServer)
+	}
+
+	values, err := runSort(opts, input.Elements, func(lookup SortLookup) (string, bool) {
+		value, ok := input.Values[lookup]
+		return value, ok
+	})
+	if err != nil {
+		return makeEvalError(err)
+	}
+	return sortReply(opts, values, store)
+}
+
+// evalSORTSTORE runs a SORT with STORE on the shard owning its destination, given first.
+func evalSORTSTORE(cd *cmd.DiceDBCmd, store *dstore.Store) *EvalResponse {
+	if len(cd.Args) < 2 {
+		return makeEvalError(diceerrors.ErrInternalServer)
+	}
+	return evalSortObject(&cmd.DiceDBCmd{Cmd: "SORT", Args: cd.Args[1:], InternalObjs: cd.InternalObjs}, store)
+}
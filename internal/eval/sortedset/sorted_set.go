@@ -8,6 +8,7 @@ import (
 	"encoding/binary"
 	"strconv"
 	"strings"
+	"unsafe"
 
 	diceerrors "github.com/dicedb/dice/internal/errors"
 	"github.com/dicedb/dice/internal/object"
@@ -208,11 +209,29 @@ func (ss *Set) Get(member string) (float64, bool) {
 	return score, exists
 }
 
+// Members returns the members of the sorted set, in no particular order.
+func (ss *Set) Members() []string {
+	members := make([]string, 0, len(ss.memberMap))
+	for member := range ss.memberMap {
+		members = append(members, member)
+	}
+	return members
+}
+
 func (ss *Set) Len() int {
 	cardinality := len(ss.memberMap)
 	return cardinality
 }
 
+// Size estimates the memory used by the sorted set, in bytes. Each member is held by an Item of
+// the btree, and by the map from the members to their scores. See object.Sizer.
+func (ss *Set) Size(samples int) int64 {
+	itemSize := int64(unsafe.Sizeof(Item{}) + unsafe.Sizeof(btree.Item(nil)))
+	return int64(unsafe.Sizeof(*ss)) + object.MapSize(ss.memberMap, samples, func(member string, score float64) int64 {
+		return object.StringSize(member) + int64(unsafe.Sizeof(score)) + itemSize
+	})
+}
+
 // This func is used to remove the maximum element from the sortedset.
 // It takes count as an argument which tells the number of elements to be removed from the sortedset.
 func (ss *Set) PopMax(count int) []string {
@@ -11,7 +11,6 @@ import (
 	"math/bits"
 	"reflect"
 	"regexp"
-	"sort"
 	"strconv"
 	"strings"
 	"unicode"
@@ -27,7 +26,6 @@ import (
 	"github.com/dicedb/dice/internal/object"
 	"github.com/dicedb/dice/internal/server/utils"
 	dstore "github.com/dicedb/dice/internal/store"
-	"github.com/gobwas/glob"
 	"github.com/ohler55/ojg/jp"
 	"github.com/rs/xid"
 )
@@ -2135,7 +2133,9 @@ func evalHINCRBY(args []string, store *dstore.Store) *EvalResponse {
 		}
 	}
 
-	obj = store.NewObj(hashmap, -1, object.ObjTypeHashMap)
+	if obj == nil {
+		obj = store.NewObj(hashmap, -1, object.ObjTypeHashMap)
+	}
 	store.Put(key, obj)
 
 	return &EvalResponse{
@@ -2194,7 +2194,9 @@ func evalHINCRBYFLOAT(args []string, store *dstore.Store) *EvalResponse {
 		}
 	}
 
-	obj = store.NewObj(hashmap, -1, object.ObjTypeHashMap)
+	if obj == nil {
+		obj = store.NewObj(hashmap, -1, object.ObjTypeHashMap)
+	}
 	store.Put(key, obj)
 
 	return &EvalResponse{
@@ -2395,6 +2397,7 @@ func incrDecrCmd(args []string, incr int64, store *dstore.Store) *EvalResponse {
 
 	i += incr
 	obj.Value = i
+	dstore.BumpVersion(obj)
 	return &EvalResponse{
 		Result: i,
 		Error:  nil,
@@ -2473,6 +2476,7 @@ func incrByFloatCmd(args []string, incr float64, store *dstore.Store) *EvalRespo
 
 	obj.Value = strValue
 	obj.Type = oType
+	dstore.BumpVersion(obj)
 
 	return &EvalResponse{
 		Result: strValue,
@@ -2571,7 +2575,7 @@ func evalDUMP(args []string, store *dstore.Store) *EvalResponse {
 		return makeEvalResult(clientio.NIL)
 	}
 
-	serializedValue, err := rdbSerialize(obj)
+	serializedValue, err := rdbSerialize(obj, store.GetFieldExpiries(obj))
 	if err != nil {
 		fmt.Println("error", err)
 		return makeEvalError(diceerrors.ErrGeneral("serialization failed"))
@@ -2595,7 +2599,7 @@ func evalRestore(args []string, store *dstore.Store) *EvalResponse {
 	if err != nil {
 		return makeEvalError(diceerrors.ErrGeneral("failed to decode base64 value"))
 	}
-	obj, err := rdbDeserialize(serializedData)
+	obj, fieldExpiries, err := rdbDeserialize(serializedData)
 	if err != nil {
 		return makeEvalError(diceerrors.ErrGeneral("deserialization failed"))
 	}
@@ -2605,9 +2609,13 @@ func evalRestore(args []string, store *dstore.Store) *EvalResponse {
 
 	if ttl > 0 {
 		store.Put(key, newobj, dstore.WithKeepTTL(keepttl))
+		obj = newobj
 	} else {
 		store.Put(key, obj)
 	}
+	for field, deadline := range fieldExpiries {
+		store.SetFieldExpiry(key, obj, field, deadline)
+	}
 
 	return makeEvalResult(clientio.OK)
 }
@@ -2700,114 +2708,6 @@ func evalHSTRLEN(args []string, store *dstore.Store) *EvalResponse {
 	}
 }
 
-// evalHSCAN return a two element multi-bulk reply, where the first element is a string representing the cursor,
-// and the second element is a multi-bulk with an array of elements.
-//
-// The array of elements contain two elements, a field and a value, for every returned element of the Hash.
-//
-// If key doesn't exist, it returns an array containing 0 and empty array.
-//
-// Usage: HSCAN key cursor [MATCH pattern] [COUNT count]
-func evalHSCAN(args []string, store *dstore.Store) *EvalResponse {
-	if len(args) < 2 {
-		return &EvalResponse{
-			Result: nil,
-			Error:  diceerrors.ErrWrongArgumentCount("HSCAN"),
-		}
-	}
-
-	key := args[0]
-	cursor, err := strconv.ParseInt(args[1], 10, 64)
-	if err != nil {
-		return &EvalResponse{
-			Result: nil,
-			Error:  diceerrors.ErrIntegerOutOfRange,
-		}
-	}
-
-	obj := store.Get(key)
-	if obj == nil {
-		return &EvalResponse{
-			Result: []interface{}{"0", []string{}},
-			Error:  nil,
-		}
-	}
-
-	if err := object.AssertType(obj.Type, object.ObjTypeHashMap); err != nil {
-		return &EvalResponse{
-			Result: nil,
-			Error:  diceerrors.ErrWrongTypeOperation,
-		}
-	}
-
-	hashMap := obj.Value.(HashMap)
-	pattern := "*"
-	count := 10
-
-	// Parse optional arguments
-	for i := 2; i < len(args); i += 2 {
-		switch strings.ToUpper(args[i]) {
-		case "MATCH":
-			if i+1 < len(args) {
-				pattern = args[i+1]
-			}
-		case CountConst:
-			if i+1 < len(args) {
-				parsedCount, err := strconv.Atoi(args[i+1])
-				if err != nil || parsedCount < 1 {
-					return &EvalResponse{
-						Result: nil,
-						Error:  diceerrors.ErrIntegerOutOfRange,
-					}
-				}
-				count = parsedCount
-			}
-		}
-	}
-
-	// Note that this implementation has a time complexity of O(N), where N is the number of keys in 'hashMap'.
-	// This is in contrast to Redis, which implements HSCAN in O(1) time complexity by maintaining a cursor.
-	keys := make([]string, 0, len(hashMap))
-	for k := range hashMap {
-		keys = append(keys, k)
-	}
-	sort.Strings(keys)
-
-	matched := 0
-	results := make([]string, 0, count*2)
-	newCursor := 0
-
-	g, err := glob.Compile(pattern)
-	if err != nil {
-		return &EvalResponse{
-			Result: nil,
-			Error:  diceerrors.ErrGeneral(fmt.Sprintf("Invalid glob pattern: %s", err)),
-		}
-	}
-
-	// Scan the keys and add them to the results if they match the pattern
-	for i := int(cursor); i < len(keys); i++ {
-		if g.Match(keys[i]) {
-			results = append(results, keys[i], hashMap[keys[i]])
-			matched++
-			if matched >= count {
-				newCursor = i + 1
-				break
-			}
-		}
-	}
-
-	// If we've scanned all keys, reset cursor to 0
-	if newCursor >= len(keys) {
-		newCursor = 0
-	}
-
-	return &EvalResponse{
-		Result: []interface{}{strconv.Itoa(newCursor), results},
-		Error:  nil,
-	}
-}
-
 // evalBF.RESERVE evaluates the BF.RESERVE command responsible for initializing a
 // new bloom filter and allocation it's relevant parameters based on given inputs.
 // If no params are provided, it uses defaults.
@@ -4064,7 +3964,7 @@ func evalJSONDebugMemory(args []string, store *dstore.Store) *EvalResponse {
 		jsonData := obj.Value
 
 		// memory used by json data
-		size := calculateSizeInBytes(jsonData)
+		size := object.JSONSize(jsonData)
 		if size == -1 {
 			return &EvalResponse{
 				Result: nil,
@@ -4072,7 +3972,7 @@ func evalJSONDebugMemory(args []string, store *dstore.Store) *EvalResponse {
 			}
 		}
 		// add memory used by storage object
-		size += int(unsafe.Sizeof(obj)) + calculateSizeInBytes(obj.LastAccessedAt) + calculateSizeInBytes(obj.Type)
+		size += int(unsafe.Sizeof(obj)) + object.JSONSize(obj.LastAccessedAt) + object.JSONSize(obj.Type)
 
 		return &EvalResponse{
 			Result: size,
@@ -4141,7 +4041,7 @@ func evalJSONDebugMemory(args []string, store *dstore.Store) *EvalResponse {
 	// get memory used by each path
 	sizeList := make([]interface{}, 0, len(results))
 	for _, result := range results {
-		size := calculateSizeInBytes(result)
+		size := object.JSONSize(result)
 		sizeList = append(sizeList, size)
 	}
 
@@ -4151,36 +4051,6 @@ func evalJSONDebugMemory(args []string, store *dstore.Store) *EvalResponse {
 	}
 }
 
-func calculateSizeInBytes(value interface{}) int {
-	switch convertedValue := value.(type) {
-	case string:
-		return int(unsafe.Sizeof(value)) + len(convertedValue)
-
-	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64, bool, nil:
-		return int(unsafe.Sizeof(value))
-
-	// object
-	case map[string]interface{}:
-		size := int(unsafe.Sizeof(value))
-		for k, v := range convertedValue {
-			size += int(unsafe.Sizeof(k)) + len(k) + calculateSizeInBytes(v)
-		}
-		return size
-
-	// array
-	case []interface{}:
-		size := int(unsafe.Sizeof(value))
-		for _, elem := range convertedValue {
-			size += calculateSizeInBytes(elem)
-		}
-		return size
-
-	// unknown type
-	default:
-		return -1
-	}
-}
-
 // GETEX key [EX seconds | PX milliseconds | EXAT unix-time-seconds |
 // PXAT unix-time-milliseconds | PERSIST]
 // Get the value of key and optionally set its expiration.
@@ -4468,7 +4338,14 @@ func insertInHashMap(args []string, store *dstore.Store) (int64, error) {
 		return 0, err
 	}
 
-	obj = store.NewObj(hashMap, -1, object.ObjTypeHashMap)
+	if obj == nil {
+		obj = store.NewObj(hashMap, -1, object.ObjTypeHashMap)
+	} else {
+		// Setting a field clears its expiry
+		for i := 0; i < len(keyValuePairs); i += 2 {
+			store.DelFieldExpiry(obj, keyValuePairs[i])
+		}
+	}
 	store.Put(key, obj)
 
 	return numKeys, nil
@@ -4719,6 +4596,7 @@ func evalHDEL(args []string, store *dstore.Store) *EvalResponse {
 	for _, field := range fields {
 		if _, ok := hashMap[field]; ok {
 			delete(hashMap, field)
+			store.DelFieldExpiry(obj, field)
 			count++
 		}
 	}
@@ -6487,25 +6365,29 @@ func evalTYPE(args []string, store *dstore.Store) *EvalResponse {
 		}
 	}
 
-	var typeStr string
-	switch oType := obj.Type; oType {
+	return &EvalResponse{
+		Result: typeName(obj),
+		Error:  nil,
+	}
+}
+
+// typeName returns the name of the type of the object, as replied by TYPE.
+func typeName(obj *object.Obj) string {
+	switch obj.Type {
 	case object.ObjTypeString, object.ObjTypeInt, object.ObjTypeByteArray:
-		typeStr = "string"
+		return "string"
 	case object.ObjTypeDequeue:
-		typeStr = "list"
+		return "list"
 	case object.ObjTypeSet:
-		typeStr = "set"
+		return "set"
 	case object.ObjTypeHashMap:
-		typeStr = "hash"
+		return "hash"
 	case object.ObjTypeSortedSet:
-		typeStr = "zset"
+		return "zset"
+	case object.ObjTypeJSON:
+		return "ReJSON-RL"
 	default:
-		typeStr = "non-supported type"
-	}
-
-	return &EvalResponse{
-		Result: typeStr,
-		Error:  nil,
+		return "non-supported type"
 	}
 }
 
@@ -6685,6 +6567,58 @@ func evalFLUSHDB(args []string, store *dstore.Store) *EvalResponse {
 	return makeEvalResult(clientio.OK)
 }
 
+// evalMOVE moves key from the selected database to the given one, along with its expiry.
+// Returns 1 if the key was moved, and 0 if it does not exist or the destination database
+// already holds it.
+//
+// Usage: MOVE key db
+func evalMOVE(args []string, store *dstore.Store) *EvalResponse {
+	if len(args) != 2 {
+		return makeEvalError(diceerrors.ErrWrongArgumentCount("MOVE"))
+	}
+
+	db, err := strconv.Atoi(args[1])
+	if err != nil {
+		return makeEvalError(diceerrors.ErrIntegerOutOfRange)
+	}
+	if db < 0 || db >= store.NumDatabases() {
+		return makeEvalError(diceerrors.ErrDBIndexOutOfRange)
+	}
+	if db == store.SelectedDB() {
+		return makeEvalError(diceerrors.ErrSameObject)
+	}
+
+	if !store.Move(args[0], db) {
+		return makeEvalResult(clientio.IntegerZero)
+	}
+	return makeEvalResult(clientio.IntegerOne)
+}
+
+// evalSWAPDB swaps the keys of the two given databases, so that the clients connected to one of
+// them immediately see the keys of the other one.
+//
+// Usage: SWAPDB index1 index2
+func evalSWAPDB(args []string, store *dstore.Store) *EvalResponse {
+	if len(args) != 2 {
+		return makeEvalError(diceerrors.ErrWrongArgumentCount("SWAPDB"))
+	}
+
+	dbs := [2]int{}
+	for i, arg := range args {
+		db, err := strconv.Atoi(arg)
+		if err != nil {
+			return makeEvalError(diceerrors.ErrIntegerOutOfRange)
+		}
+		if db < 0 || db >= store.NumDatabases() {
+			return makeEvalError(diceerrors.ErrDBIndexOutOfRange)
+		}
+		dbs[i] = db
+	}
+
+	store.SwapDB(dbs[0], dbs[1])
+	return makeEvalResult(clientio.OK)
+}
+
 func evalObjectIdleTime(key string, store *dstore.Store) *EvalResponse {
 	obj := store.GetNoTouch(key)
 	if obj == nil {
@@ -6694,6 +6628,17 @@ func evalObjectIdleTime(key string, store *dstore.Store) *EvalResponse {
 	return makeEvalResult(int64(dstore.GetIdleTime(obj.LastAccessedAt)))
 }
 
+// evalObjectTier returns the tier holding the key, memory or disk, without promoting it to memory
+// if it was spilled to disk, see dstore.DiskTier.
+func evalObjectTier(key string, store *dstore.Store) *EvalResponse {
+	tier := store.Tier(key)
+	if tier == "" {
+		return makeEvalResult(clientio.NIL)
+	}
+
+	return makeEvalResult(tier)
+}
+
 func evalOBJECT(args []string, store *dstore.Store) *EvalResponse {
 	if len(args) < 2 {
 		return makeEvalError(diceerrors.ErrWrongArgumentCount("OBJECT"))
@@ -6705,6 +6650,8 @@ func evalOBJECT(args []string, store *dstore.Store) *EvalResponse {
 	switch subcommand {
 	case "IDLETIME":
 		return evalObjectIdleTime(key, store)
+	case "TIER":
+		return evalObjectTier(key, store)
 	default:
 		return makeEvalError(diceerrors.ErrSyntax)
 	}
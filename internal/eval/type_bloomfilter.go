@@ -12,6 +12,7 @@ import (
 	"math/rand"
 	"strconv"
 	"strings"
+	"unsafe"
 
 	"github.com/dicedb/dice/internal/object"
 
@@ -233,6 +234,14 @@ func (b *Bloom) exists(value string) (clientio.RespType, error) {
 	return clientio.IntegerOne, nil
 }
 
+// Size returns the memory used by the bloom filter, in bytes. See object.Sizer.
+func (b *Bloom) Size(_ int) int64 {
+	size := int64(unsafe.Sizeof(*b)+unsafe.Sizeof(*b.opts)) + int64(cap(b.bitset))
+	size += int64(len(b.opts.hashFns)) * int64(unsafe.Sizeof(hash.Hash64(nil)))
+	size += int64(cap(b.opts.indexes)+cap(b.opts.hashFnsSeeds)) * int64(unsafe.Sizeof(uint64(0)))
+	return size
+}
+
 // DeepCopy creates a deep copy of the Bloom struct
 func (b *Bloom) DeepCopy() *Bloom {
 	if b == nil {
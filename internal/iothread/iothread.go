@@ -6,11 +6,19 @@ package iothread
 import (
 	"context"
 	"log/slog"
+	"sync/atomic"
+	"time"
 
 	"github.com/dicedb/dice/internal/auth"
+	"github.com/dicedb/dice/internal/clientio"
 	"github.com/dicedb/dice/internal/clientio/iohandler"
+	"github.com/dicedb/dice/internal/clientio/iohandler/netconn"
 )
 
+// OutputBufferCheckInterval is how often the soft output buffer limit is re-evaluated
+// while no new responses are queued.
+const OutputBufferCheckInterval = time.Second
+
 // IOThread interface
 type IOThread interface {
 	ID() string
@@ -26,6 +34,8 @@ type BaseIOThread struct {
 	ioThreadReadChan  chan []byte      // Channel to send data to the command handler
 	ioThreadWriteChan chan interface{} // Channel to receive data from the command handler
 	ioThreadErrChan   chan error       // Channel to receive errors from the ioHandler
+	outputBuffer      *OutputBuffer    // Responses pending to be written to the client
+	clientClass       atomic.Uint32    // ClientClass of the client, decides the output buffer limit
 }
 
 func NewIOThread(id string, ioHandler iohandler.IOHandler,
@@ -37,6 +47,7 @@ func NewIOThread(id string, ioHandler iohandler.IOHandler,
 		ioThreadReadChan:  ioThreadReadChan,
 		ioThreadWriteChan: ioThreadWriteChan,
 		ioThreadErrChan:   ioThreadErrChan,
+		outputBuffer:      NewOutputBuffer(),
 	}
 }
 
@@ -44,6 +55,17 @@ func (t *BaseIOThread) ID() string {
 	return t.id
 }
 
+// ClientClass returns the class of the client served by this io-thread.
+func (t *BaseIOThread) ClientClass() ClientClass {
+	return ClientClass(t.clientClass.Load())
+}
+
+// SetClientClass changes the class of the client served by this io-thread, e.g. once it
+// subscribes to a watch command. The new output buffer limit applies from the next response.
+func (t *BaseIOThread) SetClientClass(class ClientClass) {
+	t.clientClass.Store(uint32(class))
+}
+
 func (t *BaseIOThread) Start(ctx context.Context) error {
 	// local channels to communicate between Start and startInputReader goroutine
 	incomingDataChan := make(chan []byte) // data channel
@@ -56,12 +78,22 @@ func (t *BaseIOThread) Start(ctx context.Context) error {
 	// remains non-blocking and responsive to other events, such as adhoc requests or context cancellations.
 	go t.startInputReader(runCtx, incomingDataChan, readErrChan)
 
+	// Responses are written by a dedicated goroutine so that a slow client only grows its
+	// output buffer instead of blocking the command handler.
+	go t.startOutputWriter(runCtx)
+
+	limitTicker := time.NewTicker(OutputBufferCheckInterval)
+	defer limitTicker.Stop()
+
 	for {
 		select {
 		case <-ctx.Done():
 			if err := t.Stop(); err != nil {
 				slog.Warn("Error stopping io-thread:", slog.String("id", t.id), slog.Any("error", err))
 			}
+			if err := t.ioHandler.Close(); err != nil {
+				slog.Debug("Error closing connection", slog.String("id", t.id), slog.Any("error", err))
+			}
 			return ctx.Err()
 		case data := <-incomingDataChan:
 			t.ioThreadReadChan <- data
@@ -70,14 +102,70 @@ func (t *BaseIOThread) Start(ctx context.Context) error {
 			t.ioThreadErrChan <- err
 			return err
 		case resp := <-t.ioThreadWriteChan:
-			err := t.ioHandler.Write(ctx, resp)
-			if err != nil {
+			t.outputBuffer.Push(encodeResponse(resp))
+			if err := t.enforceOutputBufferLimit(); err != nil {
+				return err
+			}
+		case <-limitTicker.C:
+			if err := t.enforceOutputBufferLimit(); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// enforceOutputBufferLimit disconnects the client if its pending output exceeds the limit of its class.
+func (t *BaseIOThread) enforceOutputBufferLimit() error {
+	class := t.ClientClass()
+	if !t.outputBuffer.Exceeds(OutputBufferLimitFor(class), time.Now()) {
+		return nil
+	}
+
+	slog.Warn("Disconnecting client for exceeding its output buffer limit",
+		slog.String("id", t.id),
+		slog.String("class", class.String()),
+		slog.Int("pending_bytes", t.outputBuffer.Size()))
+	RecordOutputBufferDisconnection(class)
+
+	if err := t.ioHandler.Close(); err != nil {
+		slog.Debug("Error closing connection", slog.String("id", t.id), slog.Any("error", err))
+	}
+
+	t.ioThreadErrChan <- ErrOutputBufferLimitReached
+	return ErrOutputBufferLimitReached
+}
+
+// startOutputWriter writes the responses queued in the output buffer to the client, in order.
+func (t *BaseIOThread) startOutputWriter(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.outputBuffer.Notify():
+		}
+
+		for {
+			data, ok := t.outputBuffer.Pop()
+			if !ok {
+				break
+			}
+
+			if err := t.ioHandler.Write(ctx, data); err != nil {
 				slog.Debug("Error sending response to client", slog.String("id", t.id), slog.Any("error", err))
 			}
 		}
 	}
 }
 
+// encodeResponse converts a response from the command handler into its RESP encoding, which
+// is what gets accounted against the output buffer limits.
+func encodeResponse(resp interface{}) []byte {
+	if encoded := netconn.HandlePredefinedResponse(resp); encoded != nil {
+		return encoded
+	}
+	return clientio.Encode(resp, true)
+}
+
 // startInputReader continuously reads input data from the ioHandler and sends it to the incomingDataChan.
 func (t *BaseIOThread) startInputReader(ctx context.Context, incomingDataChan chan []byte, readErrChan chan error) {
 	defer close(incomingDataChan)
@@ -12,6 +12,7 @@ import (
 type Manager struct {
 	connectedClients sync.Map
 	numIOThreads     atomic.Uint32
+	totalIOThreads   atomic.Uint64 // Number of io-threads registered since the manager was created
 	maxClients       uint32
 	mu               sync.Mutex
 }
@@ -38,6 +39,7 @@ func (m *Manager) RegisterIOThread(ioThread IOThread) error {
 	m.connectedClients.Store(ioThread.ID(), ioThread)
 
 	m.numIOThreads.Add(1)
+	m.totalIOThreads.Add(1)
 	return nil
 }
 
@@ -45,6 +47,17 @@ func (m *Manager) IOThreadCount() uint32 {
 	return m.numIOThreads.Load()
 }
 
+// TotalIOThreadCount returns the number of io-threads registered since the manager was created,
+// one per client connection.
+func (m *Manager) TotalIOThreadCount() uint64 {
+	return m.totalIOThreads.Load()
+}
+
+// MaxClients returns the number of io-threads that may be registered at once.
+func (m *Manager) MaxClients() uint32 {
+	return m.maxClients
+}
+
 func (m *Manager) GetIOThread(id string) (IOThread, bool) {
 	client, ok := m.connectedClients.Load(id)
 	if !ok {
@@ -0,0 +1,174 @@
+// Copyright (c) 2022-present, DiceDB contributors
+// All rights reserved. Licensed under the BSD 3-Clause License. See LICENSE file in the project root for full license information.
+
+package iothread
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/dicedb/dice/config"
+)
+
+// ClientClass identifies the kind of traffic a client receives. Each class has its own
+// output buffer limits, as clients that receive pushed updates can fall behind much more
+// easily than plain request/response clients.
+type ClientClass uint8
+
+const (
+	ClientClassNormal ClientClass = iota // request/response clients
+	ClientClassWatch                     // clients with at least one .WATCH subscription
+	ClientClassPubSub                    // clients subscribed to pushed streams such as Q.WATCH
+	numClientClasses
+)
+
+// ClientClasses lists the client classes, in order.
+var ClientClasses = []ClientClass{ClientClassNormal, ClientClassWatch, ClientClassPubSub}
+
+var ErrOutputBufferLimitReached = errors.New("client output buffer limit reached")
+
+// outputBufferDisconnections counts the clients disconnected for exceeding their output buffer limit, per class.
+var outputBufferDisconnections [numClientClasses]atomic.Uint64
+
+func (c ClientClass) String() string {
+	switch c {
+	case ClientClassWatch:
+		return "watch"
+	case ClientClassPubSub:
+		return "pubsub"
+	default:
+		return "normal"
+	}
+}
+
+// OutputBufferLimit is the hard and soft limit applied to the pending output of a client.
+// A zero value for a limit disables it.
+type OutputBufferLimit struct {
+	HardLimit    int
+	SoftLimit    int
+	SoftDuration time.Duration
+}
+
+// OutputBufferLimitFor returns the configured output buffer limit for the given client class.
+func OutputBufferLimitFor(class ClientClass) OutputBufferLimit {
+	limits := config.DiceConfig.ClientOutputBufferLimit
+	switch class {
+	case ClientClassWatch:
+		return OutputBufferLimit{
+			HardLimit:    limits.WatchHardLimit,
+			SoftLimit:    limits.WatchSoftLimit,
+			SoftDuration: time.Duration(limits.WatchSoftSeconds) * time.Second,
+		}
+	case ClientClassPubSub:
+		return OutputBufferLimit{
+			HardLimit:    limits.PubSubHardLimit,
+			SoftLimit:    limits.PubSubSoftLimit,
+			SoftDuration: time.Duration(limits.PubSubSoftSeconds) * time.Second,
+		}
+	default:
+		return OutputBufferLimit{
+			HardLimit:    limits.NormalHardLimit,
+			SoftLimit:    limits.NormalSoftLimit,
+			SoftDuration: time.Duration(limits.NormalSoftSeconds) * time.Second,
+		}
+	}
+}
+
+// OutputBufferDisconnections returns the number of clients of the given class that were
+// disconnected for exceeding their output buffer limit.
+func OutputBufferDisconnections(class ClientClass) uint64 {
+	if class >= numClientClasses {
+		return 0
+	}
+	return outputBufferDisconnections[class].Load()
+}
+
+// RecordOutputBufferDisconnection counts a client of the given class as disconnected for
+// being a slow consumer. It is used by components that keep their own write backlog.
+func RecordOutputBufferDisconnection(class ClientClass) {
+	if class < numClientClasses {
+		outputBufferDisconnections[class].Add(1)
+	}
+}
+
+// OutputBuffer queues encoded responses that are yet to be written to a client, so that
+// a slow client does not block the goroutine producing its responses.
+type OutputBuffer struct {
+	mu            sync.Mutex
+	pending       [][]byte
+	size          int
+	softLimitFrom time.Time // when the buffer went above the soft limit, zero if it is below
+	notify        chan struct{}
+}
+
+func NewOutputBuffer() *OutputBuffer {
+	return &OutputBuffer{
+		notify: make(chan struct{}, 1),
+	}
+}
+
+// Push appends data to the buffer and wakes up the writer.
+func (b *OutputBuffer) Push(data []byte) {
+	b.mu.Lock()
+	b.pending = append(b.pending, data)
+	b.size += len(data)
+	b.mu.Unlock()
+
+	select {
+	case b.notify <- struct{}{}:
+	default:
+	}
+}
+
+// Pop removes and returns the oldest pending response, if any.
+func (b *OutputBuffer) Pop() ([]byte, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if len(b.pending) == 0 {
+		return nil, false
+	}
+
+	data := b.pending[0]
+	b.pending[0] = nil
+	b.pending = b.pending[1:]
+	b.size -= len(data)
+	return data, true
+}
+
+// Size returns the number of bytes pending to be written.
+func (b *OutputBuffer) Size() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.size
+}
+
+// Exceeds reports whether the pending output violates the given limit at time now. The hard
+// limit is violated as soon as it is reached, while the soft limit has to be exceeded
+// continuously for longer than its duration.
+func (b *OutputBuffer) Exceeds(limit OutputBufferLimit, now time.Time) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if limit.HardLimit > 0 && b.size >= limit.HardLimit {
+		return true
+	}
+
+	if limit.SoftLimit <= 0 || b.size < limit.SoftLimit {
+		b.softLimitFrom = time.Time{}
+		return false
+	}
+
+	if b.softLimitFrom.IsZero() {
+		b.softLimitFrom = now
+	}
+
+	return now.Sub(b.softLimitFrom) > limit.SoftDuration
+}
+
+// Notify returns the channel signalled whenever new data is pushed to the buffer.
+func (b *OutputBuffer) Notify() <-chan struct{} {
+	return b.notify
+}
@@ -0,0 +1,73 @@
+// Copyright (c) 2022-present, DiceDB contributors
+// All rights reserved. Licensed under the BSD 3-Clause License. See LICENSE file in the project root for full license information.
+
+package iothread
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOutputBufferPushPop(t *testing.T) {
+	b := NewOutputBuffer()
+	b.Push([]byte("+OK\r\n"))
+	b.Push([]byte(":1\r\n"))
+	assert.Equal(t, 9, b.Size())
+
+	data, ok := b.Pop()
+	assert.True(t, ok)
+	assert.Equal(t, "+OK\r\n", string(data))
+	assert.Equal(t, 4, b.Size())
+
+	data, ok = b.Pop()
+	assert.True(t, ok)
+	assert.Equal(t, ":1\r\n", string(data))
+
+	_, ok = b.Pop()
+	assert.False(t, ok)
+	assert.Equal(t, 0, b.Size())
+}
+
+func TestOutputBufferHardLimit(t *testing.T) {
+	b := NewOutputBuffer()
+	limit := OutputBufferLimit{HardLimit: 10}
+	now := time.Now()
+
+	b.Push(make([]byte, 9))
+	assert.False(t, b.Exceeds(limit, now))
+
+	b.Push(make([]byte, 1))
+	assert.True(t, b.Exceeds(limit, now))
+}
+
+func TestOutputBufferSoftLimit(t *testing.T) {
+	b := NewOutputBuffer()
+	limit := OutputBufferLimit{SoftLimit: 10, SoftDuration: time.Minute}
+	now := time.Now()
+
+	b.Push(make([]byte, 20))
+	assert.False(t, b.Exceeds(limit, now))
+	assert.False(t, b.Exceeds(limit, now.Add(30*time.Second)))
+	assert.True(t, b.Exceeds(limit, now.Add(61*time.Second)))
+
+	// draining below the soft limit resets the timer
+	b.Pop()
+	assert.False(t, b.Exceeds(limit, now.Add(62*time.Second)))
+	b.Push(make([]byte, 20))
+	assert.False(t, b.Exceeds(limit, now.Add(63*time.Second)))
+	assert.True(t, b.Exceeds(limit, now.Add(124*time.Second)))
+}
+
+func TestOutputBufferNoLimit(t *testing.T) {
+	b := NewOutputBuffer()
+	b.Push(make([]byte, 1<<20))
+	assert.False(t, b.Exceeds(OutputBufferLimit{}, time.Now()))
+}
+
+func TestRecordOutputBufferDisconnection(t *testing.T) {
+	before := OutputBufferDisconnections(ClientClassWatch)
+	RecordOutputBufferDisconnection(ClientClassWatch)
+	assert.Equal(t, before+1, OutputBufferDisconnections(ClientClassWatch))
+}
@@ -23,6 +23,15 @@ package object
 //     and to simplify management by not combining
 //     `Type` and `LastAccessedAt` into a single integer.
 //
+//   - LFUCounter: A uint8 field that counts the accesses to the object, logarithmically, for
+//     the LFU eviction policy. It fits in the padding between `Type` and `LastAccessedAt`.
+//
+//   - ClientFlags: A uint32 field holding the opaque flags a memcached client stored along with
+//     the object, which the memcached protocol returns with it.
+//
+//   - Version: A uint64 field that changes every time the object is stored or modified in place,
+//     so that clients can tell whether the object changed since they read it.
+//
 //   - Value: An `interface{}` type that holds the actual data of the object. This could
 //     represent any type of data, allowing flexibility to store different kinds of
 //     objects (e.g., strings, numbers, complex data structures like lists or maps).
@@ -30,10 +39,20 @@ type Obj struct {
 	// Type holds the type of the object (e.g., string, int, complex structure)
 	Type ObjectType
 
+	// LFUCounter approximates the logarithm of the number of accesses to the object, for the LFU eviction policy.
+	LFUCounter uint8
+
 	// LastAccessedAt stores the last access timestamp of the object.
 	// It helps track when the object was last accessed and may be used for cache eviction or freshness tracking.
 	LastAccessedAt uint32
 
+	// ClientFlags holds the flags of the memcached item backed by the object, 0 for the other objects.
+	ClientFlags uint32
+
+	// Version identifies the value of the object, changing every time it is stored or modified in place.
+	// It backs the CAS unique of the memcached protocol.
+	Version uint64
+
 	// Value holds the actual content or data of the object, which can be of any type.
 	// This allows flexibility in storing various kinds of objects (simple or complex).
 	Value interface{}
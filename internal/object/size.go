@@ -0,0 +1,131 @@
+// Copyright (c) 2022-present, DiceDB contributors
+// All rights reserved. Licensed under the BSD 3-Clause License. See LICENSE file in the project root for full license information.
+
+package object
+
+import (
+	"unsafe"
+
+	"github.com/axiomhq/hyperloglog"
+)
+
+// Sizer is implemented by the values that estimate the memory they use, in bytes. The aggregate
+// values estimate the size of their elements from the given number of samples, or from all of
+// them when it is not positive.
+type Sizer interface {
+	Size(samples int) int64
+}
+
+var (
+	// objSize is the size of an object, without the value it references.
+	objSize = int64(unsafe.Sizeof(Obj{}))
+
+	// stringHeaderSize is the size of the header of a string, without its bytes.
+	stringHeaderSize = int64(unsafe.Sizeof(""))
+
+	// hllSize is the size of a HyperLogLog sketch, without its registers.
+	hllSize = int64(unsafe.Sizeof(hyperloglog.Sketch{}))
+)
+
+const (
+	// DefaultSizeSamples is the number of elements of the aggregate values sampled to estimate their
+	// size, unless told otherwise.
+	DefaultSizeSamples = 5
+
+	// mapEntryOverhead approximates the bookkeeping of a map for each of its entries.
+	mapEntryOverhead int64 = 8
+)
+
+// KeySize estimates the memory used by a key and its object, in bytes. See Sizer for samples.
+func KeySize(k string, obj *Obj, samples int) int64 {
+	return StringSize(k) + obj.Size(samples)
+}
+
+// Size estimates the memory used by the object and its value, in bytes. See Sizer for samples.
+func (obj *Obj) Size(samples int) int64 {
+	return objSize + ValueSize(obj.Value, samples)
+}
+
+// ValueSize estimates the memory used by the value of an object, in bytes. See Sizer for samples.
+func ValueSize(value interface{}, samples int) int64 {
+	switch v := value.(type) {
+	case Sizer:
+		return v.Size(samples)
+	case string:
+		return StringSize(v)
+	case int64:
+		return int64(unsafe.Sizeof(v))
+	case map[string]struct{}:
+		return MapSize(v, samples, func(member string, _ struct{}) int64 {
+			return StringSize(member)
+		})
+	case *hyperloglog.Sketch:
+		// The registers of the sketch are unexported, their serialized form is close enough.
+		registers, err := v.MarshalBinary()
+		if err != nil {
+			return hllSize
+		}
+		return hllSize + int64(len(registers))
+	default:
+		if size := JSONSize(v); size > 0 {
+			return int64(size)
+		}
+		return int64(unsafe.Sizeof(value))
+	}
+}
+
+// StringSize returns the memory used by a string, in bytes.
+func StringSize(s string) int64 {
+	return stringHeaderSize + int64(len(s))
+}
+
+// MapSize estimates the memory used by a map, in bytes, from the sizes of the given number of
+// its entries, or of all of them when it is not positive.
+func MapSize[K comparable, V any](m map[K]V, samples int, entrySize func(K, V) int64) int64 {
+	size := int64(unsafe.Sizeof(m))
+	if len(m) == 0 {
+		return size
+	}
+
+	var sampled, sampledSize int64
+	for k, v := range m {
+		sampledSize += entrySize(k, v) + mapEntryOverhead
+		sampled++
+		if samples > 0 && sampled >= int64(samples) {
+			break
+		}
+	}
+	return size + sampledSize*int64(len(m))/sampled
+}
+
+// JSONSize returns the memory used by a decoded JSON value, in bytes, or -1 if the value holds
+// a type that JSON does not decode to.
+func JSONSize(value interface{}) int {
+	switch convertedValue := value.(type) {
+	case string:
+		return int(unsafe.Sizeof(value)) + len(convertedValue)
+
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64, bool, nil:
+		return int(unsafe.Sizeof(value))
+
+	// object
+	case map[string]interface{}:
+		size := int(unsafe.Sizeof(value))
+		for k, v := range convertedValue {
+			size += int(unsafe.Sizeof(k)) + len(k) + JSONSize(v)
+		}
+		return size
+
+	// array
+	case []interface{}:
+		size := int(unsafe.Sizeof(value))
+		for _, elem := range convertedValue {
+			size += JSONSize(elem)
+		}
+		return size
+
+	// unknown type
+	default:
+		return -1
+	}
+}
@@ -0,0 +1,57 @@
+// Copyright (c) 2022-present, DiceDB contributors
+// All rights reserved. Licensed under the BSD 3-Clause License. See LICENSE file in the project root for full license information.
+
+package observability
+
+import (
+	"sort"
+	"sync/atomic"
+	"time"
+)
+
+// LatencyBuckets are the upper bounds of the buckets of the latency histograms, in seconds.
+var LatencyBuckets = [...]float64{
+	0.0001, 0.00025, 0.0005, 0.001, 0.0025, 0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5,
+}
+
+// Histogram counts the durations observed in the LatencyBuckets, for the metrics. The zero value
+// is ready to use, and it is safe for concurrent use.
+type Histogram struct {
+	counts [len(LatencyBuckets) + 1]atomic.Uint64 // The last bucket counts the durations above all the bounds.
+	sum    atomic.Int64
+}
+
+// HistogramSnapshot holds the counts of a Histogram at some point in time.
+type HistogramSnapshot struct {
+	// Counts holds the number of durations observed in each of the LatencyBuckets, which are not
+	// cumulative, followed by those above all the bounds.
+	Counts []uint64
+	Sum    time.Duration
+}
+
+// Observe counts a duration in the first bucket it does not exceed.
+func (h *Histogram) Observe(d time.Duration) {
+	h.counts[sort.SearchFloat64s(LatencyBuckets[:], d.Seconds())].Add(1)
+	h.sum.Add(int64(d))
+}
+
+// Snapshot returns the counts of the histogram.
+func (h *Histogram) Snapshot() HistogramSnapshot {
+	snapshot := HistogramSnapshot{
+		Counts: make([]uint64, len(h.counts)),
+		Sum:    time.Duration(h.sum.Load()),
+	}
+	for i := range h.counts {
+		snapshot.Counts[i] = h.counts[i].Load()
+	}
+	return snapshot
+}
+
+// Count returns the number of durations observed.
+func (s HistogramSnapshot) Count() uint64 {
+	var count uint64
+	for _, c := range s.Counts {
+		count += c
+	}
+	return count
+}
@@ -4,21 +4,29 @@
 package ops
 
 import (
+	"context"
+
 	"github.com/dicedb/dice/internal/cmd"
 	"github.com/dicedb/dice/internal/comm"
 	"github.com/dicedb/dice/internal/eval"
 )
 
 type StoreOp struct {
-	SeqID         uint8          // SeqID is the sequence id of the operation within a single request (optional, may be used for ordering)
-	RequestID     uint32         // RequestID identifies the request that this StoreOp belongs to
-	Cmd           *cmd.DiceDBCmd // Cmd is the atomic Store command (e.g., GET, SET)
-	ShardID       uint8          // ShardID of the shard on which the Store command will be executed
-	CmdHandlerID  string         // CmdHandlerID is the ID of the command handler that sent this Store operation
-	Client        *comm.Client   // Client that sent this Store operation. TODO: This can potentially replace the CmdHandlerID in the future
-	HTTPOp        bool           // HTTPOp is true if this Store operation is an HTTP operation
-	WebsocketOp   bool           // WebsocketOp is true if this Store operation is a Websocket operation
-	PreProcessing bool           // PreProcessing indicates whether a comamnd operation requires preprocessing before execution. This is mainly used is multi-step-multi-shard commands
+	SeqID         uint8            // SeqID is the sequence id of the operation within a single request (optional, may be used for ordering)
+	RequestID     uint32           // RequestID identifies the request that this StoreOp belongs to
+	Cmd           *cmd.DiceDBCmd   // Cmd is the atomic Store command (e.g., GET, SET)
+	Batch         []*cmd.DiceDBCmd // Batch, if set, replaces Cmd with commands executed back to back, each sending its own response (optional)
+	ShardID       uint8            // ShardID of the shard on which the Store command will be executed
+	Database      int              // Database is the logical database selected by the client, on which the Store command will be executed
+	CmdHandlerID  string           // CmdHandlerID is the ID of the command handler that sent this Store operation
+	Client        *comm.Client     // Client that sent this Store operation. TODO: This can potentially replace the CmdHandlerID in the future
+	ClientAddr    string           // ClientAddr is the address of the client that sent this Store operation, reported by SLOWLOG (optional)
+	ClientName    string           // ClientName is the name set by the client with CLIENT SETNAME, reported by SLOWLOG (optional)
+	HTTPOp        bool             // HTTPOp is true if this Store operation is an HTTP operation
+	WebsocketOp   bool             // WebsocketOp is true if this Store operation is a Websocket operation
+	MemcachedOp   bool             // MemcachedOp is true if this Store operation is sent by the memcached listener, which may run the internal MC.* commands
+	PreProcessing bool             // PreProcessing indicates whether a comamnd operation requires preprocessing before execution. This is mainly used is multi-step-multi-shard commands
+	Ctx           context.Context  // Ctx is done once the sender stops waiting for the response, e.g. on a timeout (optional)
 }
 
 // StoreResponse represents the response of a Store operation.
@@ -0,0 +1,115 @@
+// Copyright (c) 2022-present, DiceDB contributors
+// All rights reserved. Licensed under the BSD 3-Clause License. See LICENSE file in the project root for full license information.
+
+package httpws
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/dicedb/dice/config"
+	"github.com/dicedb/dice/internal/auth"
+	diceerrors "github.com/dicedb/dice/internal/errors"
+	"github.com/dicedb/dice/internal/server/utils"
+)
+
+var (
+	errAuthRequired          = errors.New("NOAUTH Authentication required")
+	errUnsupportedAuthScheme = errors.New("NOAUTH unsupported authorization scheme, use Basic or Bearer")
+)
+
+type userContextKey struct{}
+
+// authRequired reports whether the clients must authenticate, which is the case once a password
+// is configured.
+func authRequired() bool {
+	return config.DiceConfig.Auth.Password != utils.EmptyStr
+}
+
+// defaultUser returns the configured user, which the clients act as when no password is configured.
+// It is nil if the user is not registered, in which case every command is allowed.
+func defaultUser() *auth.User {
+	user, _ := auth.UserStore.Get(config.DiceConfig.Auth.UserName)
+	return user
+}
+
+// authenticateRequest returns the user whose credentials are carried by the Authorization header of
+// the request. HTTP Basic credentials name the user, while a Bearer token is the password of the
+// configured user. Any request is accepted as the configured user when no password is configured.
+func authenticateRequest(r *http.Request) (*auth.User, error) {
+	if !authRequired() {
+		return defaultUser(), nil
+	}
+
+	header := r.Header.Get("Authorization")
+	if header == "" {
+		return nil, errAuthRequired
+	}
+	if username, password, ok := r.BasicAuth(); ok {
+		return auth.Authenticate(username, password)
+	}
+	if token, ok := strings.CutPrefix(header, "Bearer "); ok {
+		return auth.Authenticate(config.DiceConfig.Auth.UserName, strings.TrimSpace(token))
+	}
+	return nil, errUnsupportedAuthScheme
+}
+
+// authenticateCommand executes the AUTH command of a WebSocket client, with the password, or the
+// username and the password, as its arguments.
+func authenticateCommand(args []string) (*auth.User, error) {
+	if len(args) < 1 || len(args) > 2 {
+		return nil, diceerrors.ErrWrongArgumentCount(auth.Cmd)
+	}
+	if !authRequired() {
+		return nil, diceerrors.ErrAuth
+	}
+
+	username, password := config.DiceConfig.Auth.UserName, args[0]
+	if len(args) == 2 {
+		username, password = args[0], args[1]
+	}
+	return auth.Authenticate(username, password)
+}
+
+// authorizeCommand returns an error if the command is refused to the user.
+func authorizeCommand(user *auth.User, cmd string) error {
+	if user == nil {
+		return nil
+	}
+	return user.Authorize(cmd)
+}
+
+// requireAuth rejects the requests without valid credentials with 401 Unauthorized, and hands the
+// authenticated user over to the handler through the context of the request.
+func requireAuth(next http.HandlerFunc) http.HandlerFunc {
+	return func(writer http.ResponseWriter, request *http.Request) {
+		user, err := authenticateRequest(request)
+		if err != nil {
+			writer.Header().Set("WWW-Authenticate", `Basic realm="dicedb", charset="UTF-8"`)
+			writeErrorResponse(writer, http.StatusUnauthorized, err.Error(), "")
+			slog.Debug("Rejected unauthenticated request", slog.String("remote_addr", request.RemoteAddr), slog.Any("error", err))
+			return
+		}
+
+		next(writer, request.WithContext(context.WithValue(request.Context(), userContextKey{}, user)))
+	}
+}
+
+// requestUser returns the user authenticated by requireAuth.
+func requestUser(request *http.Request) *auth.User {
+	user, _ := request.Context().Value(userContextKey{}).(*auth.User)
+	return user
+}
+
+// authorizeRequest writes 403 Forbidden and returns false if the command is refused to the user
+// of the request.
+func authorizeRequest(writer http.ResponseWriter, request *http.Request, cmd string) bool {
+	if err := authorizeCommand(requestUser(request), cmd); err != nil {
+		writeErrorResponse(writer, http.StatusForbidden, err.Error(), "")
+		return false
+	}
+	return true
+}
@@ -0,0 +1,190 @@
+// Copyright (c) 2022-present, DiceDB contributors
+// All rights reserved. Licensed under the BSD 3-Clause License. See LICENSE file in the project root for full license information.
+
+package httpws
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/dicedb/dice/config"
+	"github.com/dicedb/dice/internal/auth"
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// setupTestUsers requires a password, and registers the configured user along with a user whose
+// admin commands are denied.
+func setupTestUsers(t *testing.T) {
+	store, password := auth.UserStore, config.DiceConfig.Auth.Password
+	t.Cleanup(func() {
+		auth.UserStore, config.DiceConfig.Auth.Password = store, password
+	})
+
+	auth.UserStore = auth.NewUsersStore()
+	config.DiceConfig.Auth.Password = "secret"
+	require.NoError(t, auth.InitDefaultUser())
+
+	user, err := auth.UserStore.Add("reader")
+	require.NoError(t, err)
+	require.NoError(t, user.SetPassword("reader-secret"))
+	user.DenyAdminCommands()
+}
+
+func TestHTTPAuthentication(t *testing.T) {
+	setupTestUsers(t)
+	server := NewHTTPServer(nil, nil, nil, nil)
+
+	tests := []struct {
+		name           string
+		method         string
+		path           string
+		setAuth        func(r *http.Request)
+		expectedStatus int
+		expectedData   string
+	}{
+		{
+			name:           "no credentials",
+			method:         http.MethodGet,
+			path:           OpenAPIPath,
+			expectedStatus: http.StatusUnauthorized,
+			expectedData:   "NOAUTH Authentication required",
+		},
+		{
+			name:           "wrong password",
+			method:         http.MethodGet,
+			path:           OpenAPIPath,
+			setAuth:        func(r *http.Request) { r.SetBasicAuth("reader", "wrong") },
+			expectedStatus: http.StatusUnauthorized,
+			expectedData:   "WRONGPASS invalid username-password pair or user is disabled",
+		},
+		{
+			name:           "unknown user",
+			method:         http.MethodPost,
+			path:           "/abort",
+			setAuth:        func(r *http.Request) { r.SetBasicAuth("nobody", "secret") },
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "unsupported scheme",
+			method:         http.MethodGet,
+			path:           OpenAPIPath,
+			setAuth:        func(r *http.Request) { r.Header.Set("Authorization", "Digest secret") },
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "basic credentials",
+			method:         http.MethodGet,
+			path:           OpenAPIPath,
+			setAuth:        func(r *http.Request) { r.SetBasicAuth("reader", "reader-secret") },
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "bearer token",
+			method:         http.MethodGet,
+			path:           OpenAPIPath,
+			setAuth:        func(r *http.Request) { r.Header.Set("Authorization", "Bearer secret") },
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "denied admin command",
+			method:         http.MethodPost,
+			path:           "/abort",
+			setAuth:        func(r *http.Request) { r.SetBasicAuth("reader", "reader-secret") },
+			expectedStatus: http.StatusForbidden,
+			expectedData:   "NOPERM User reader has no permissions to run the 'abort' command",
+		},
+		{
+			name:           "health without credentials",
+			method:         http.MethodGet,
+			path:           "/health",
+			expectedStatus: http.StatusOK,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(tc.method, tc.path, http.NoBody)
+			if tc.setAuth != nil {
+				tc.setAuth(req)
+			}
+			rec := httptest.NewRecorder()
+			server.httpServer.Handler.ServeHTTP(rec, req)
+
+			require.Equal(t, tc.expectedStatus, rec.Code)
+			if tc.expectedStatus == http.StatusUnauthorized {
+				assert.NotEmpty(t, rec.Header().Get("WWW-Authenticate"))
+			}
+			if tc.expectedData != "" {
+				var resp HTTPResponse
+				require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+				assert.Equal(t, HTTPStatusError, resp.Status)
+				assert.Equal(t, tc.expectedData, resp.Data)
+			}
+		})
+	}
+}
+
+func TestWebsocketAuthentication(t *testing.T) {
+	setupTestUsers(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	url := startTestWebsocketServer(t, ctx, nil, nil)
+
+	// Invalid credentials are refused before upgrading the connection
+	header := http.Header{}
+	header.Set("Authorization", "Bearer wrong")
+	_, resp, err := websocket.DefaultDialer.Dial(url, header)
+	require.Error(t, err)
+	require.NotNil(t, resp)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+
+	conn, resp, err := websocket.DefaultDialer.Dial(url, nil)
+	require.NoError(t, err)
+	resp.Body.Close()
+	defer conn.Close()
+
+	fire := func(msg string) WebsocketReply {
+		require.NoError(t, conn.WriteMessage(websocket.TextMessage, []byte(msg)))
+		return readWebsocketReply(t, conn)
+	}
+
+	reply := fire(`{"id": 1, "cmd": "SET", "args": ["k1", "v1"]}`)
+	assert.Equal(t, "NOAUTH Authentication required", reply.Error)
+
+	reply = fire(`{"id": 2, "cmd": "AUTH", "args": ["reader", "wrong"]}`)
+	assert.Equal(t, "WRONGPASS invalid username-password pair or user is disabled", reply.Error)
+
+	reply = fire(`{"id": 3, "cmd": "AUTH", "args": ["reader", "reader-secret"]}`)
+	assert.JSONEq(t, `"OK"`, string(reply.Result))
+
+	reply = fire(`{"id": 4, "cmd": "SET", "args": ["k1", "v1"]}`)
+	assert.JSONEq(t, `"OK"`, string(reply.Result))
+
+	reply = fire(`{"id": 5, "cmd": "FLUSHDB"}`)
+	assert.Equal(t, "NOPERM User reader has no permissions to run the 'flushdb' command", reply.Error)
+
+	// Plain text commands are refused the same way
+	require.NoError(t, conn.WriteMessage(websocket.TextMessage, []byte("ABORT")))
+	_, msg, err := conn.ReadMessage()
+	require.NoError(t, err)
+	assert.Equal(t, `"NOPERM User reader has no permissions to run the 'abort' command"`, string(msg))
+
+	// Credentials of the upgrade request authenticate the connection
+	header.Set("Authorization", "Basic "+base64.StdEncoding.EncodeToString([]byte("dice:secret")))
+	conn, resp, err = websocket.DefaultDialer.Dial(url, header)
+	require.NoError(t, err)
+	resp.Body.Close()
+	defer conn.Close()
+
+	require.NoError(t, conn.WriteMessage(websocket.TextMessage, []byte("GET k1")))
+	_, msg, err = conn.ReadMessage()
+	require.NoError(t, err)
+	assert.Equal(t, `"v1"`, string(msg))
+}
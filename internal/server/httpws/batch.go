@@ -0,0 +1,316 @@
+// Copyright (c) 2022-present, DiceDB contributors
+// All rights reserved. Licensed under the BSD 3-Clause License. See LICENSE file in the project root for full license information.
+
+package httpws
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/dicedb/dice/internal/cmd"
+	"github.com/dicedb/dice/internal/commandhandler"
+	"github.com/dicedb/dice/internal/ops"
+	"github.com/dicedb/dice/internal/shard"
+)
+
+// MaxBatchSize is the maximum number of commands accepted in a single batch request.
+const MaxBatchSize = 1000
+
+var (
+	errEmptyBatch      = errors.New("batch must contain at least one command")
+	errBatchTooLarge   = fmt.Errorf("batch must not contain more than %d commands", MaxBatchSize)
+	errEmptyBatchCmd   = errors.New("missing command name")
+	errBatchNotAtomic  = errors.New("atomic batches must only contain commands on keys of the same shard")
+	errBatchIncomplete = errors.New("request timed out before the command completed")
+)
+
+// BatchRequest is the body of a POST /batch request. The body may also be a bare JSON array of
+// commands, in which case the batch is not atomic.
+type BatchRequest struct {
+	Commands []BatchCommand `json:"commands"`
+	// Atomic executes all the commands back to back, without any other command running in between.
+	// All the keys of an atomic batch must belong to the same shard.
+	Atomic bool `json:"atomic"`
+}
+
+// BatchCommand is a single command of a batch, e.g. {"cmd": "SET", "args": ["k1", "v1"]}. Arguments
+// that are not strings are sent as their JSON encoding.
+type BatchCommand struct {
+	Cmd  string        `json:"cmd"`
+	Args []interface{} `json:"args"`
+}
+
+// ParseHTTPBatchRequest parses the body of a batch request into DiceDB commands.
+func ParseHTTPBatchRequest(r *http.Request) (*BatchRequest, []*cmd.DiceDBCmd, error) {
+	if r.Body == nil {
+		return nil, nil, errEmptyBatch
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	batch := &BatchRequest{}
+	if trimmed := bytes.TrimSpace(body); len(trimmed) > 0 && trimmed[0] == '[' {
+		err = json.Unmarshal(trimmed, &batch.Commands)
+	} else {
+		err = json.Unmarshal(body, batch)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if len(batch.Commands) == 0 {
+		return nil, nil, errEmptyBatch
+	}
+	if len(batch.Commands) > MaxBatchSize {
+		return nil, nil, errBatchTooLarge
+	}
+
+	commands := make([]*cmd.DiceDBCmd, len(batch.Commands))
+	for i, batchCmd := range batch.Commands {
+		args := make([]string, len(batchCmd.Args))
+		for j, arg := range batchCmd.Args {
+			args[j] = formatValue(false, arg)
+		}
+		commands[i] = &cmd.DiceDBCmd{
+			Cmd:  strings.ToUpper(strings.TrimSpace(batchCmd.Cmd)),
+			Args: args,
+		}
+	}
+
+	return batch, commands, nil
+}
+
+// DiceHTTPBatchHandler executes the commands of a batch request in parallel on their shards. The
+// response holds the result of every command, in the order of the request, each with its own
+// status so that a failing command does not fail the whole batch.
+func (s *HTTPServer) DiceHTTPBatchHandler(writer http.ResponseWriter, request *http.Request) {
+	if request.Method != http.MethodPost {
+		writer.Header().Set("Allow", http.MethodPost)
+		writeErrorResponse(writer, http.StatusMethodNotAllowed, "batch requests must use POST", "")
+		return
+	}
+
+	batch, commands, err := ParseHTTPBatchRequest(request)
+	if err != nil {
+		writeErrorResponse(writer, http.StatusBadRequest, fmt.Sprintf("Invalid batch request: %v", err),
+			"Error parsing HTTP batch request", slog.Any("error", err))
+		return
+	}
+
+	user := requestUser(request)
+	results := make([]HTTPResponse, len(commands))
+	valid := make([]bool, len(commands))
+	for i, diceDBCmd := range commands {
+		if err := validateBatchCommand(diceDBCmd); err != nil {
+			if batch.Atomic {
+				writeErrorResponse(writer, http.StatusBadRequest, fmt.Sprintf("Invalid command at index %d: %v", i, err), "")
+				return
+			}
+			results[i] = HTTPResponse{Status: HTTPStatusError, Data: err.Error()}
+			continue
+		}
+		if err := authorizeCommand(user, diceDBCmd.Cmd); err != nil {
+			if batch.Atomic {
+				writeErrorResponse(writer, http.StatusForbidden, fmt.Sprintf("Refused command at index %d: %v", i, err), "")
+				return
+			}
+			results[i] = HTTPResponse{Status: HTTPStatusError, Data: err.Error()}
+			continue
+		}
+		valid[i] = true
+	}
+
+	ctx, cancel := commandhandler.RequestContext(request.Context(), commands...)
+	defer cancel()
+
+	handlerID := fmt.Sprintf("httpBatch-%d", commandhandler.GenerateUniqueRequestID())
+	responseChan := make(chan *ops.StoreResponse, len(commands))
+	s.shardManager.RegisterCommandHandler(handlerID, responseChan, nil)
+	defer s.shardManager.UnregisterCommandHandler(handlerID)
+
+	var responses []*ops.StoreResponse
+	if batch.Atomic {
+		shardID, ok := s.atomicBatchShard(commands)
+		if !ok {
+			writeErrorResponse(writer, http.StatusBadRequest, errBatchNotAtomic.Error(), "")
+			return
+		}
+		responses = s.executeAtomicBatch(ctx, handlerID, responseChan, shardID, commands)
+	} else {
+		responses = s.executeBatch(ctx, handlerID, responseChan, commands, valid)
+	}
+
+	for i, resp := range responses {
+		if !valid[i] {
+			continue
+		}
+		if resp == nil {
+			results[i] = HTTPResponse{Status: HTTPStatusError, Data: errBatchIncomplete.Error()}
+			continue
+		}
+		if results[i], err = buildHTTPResponse(commands[i], resp.EvalResponse.Result, resp.EvalResponse.Error); err != nil {
+			slog.Error("Error decoding batch response", slog.String("cmd", commands[i].Cmd), slog.Any("error", err))
+			results[i] = HTTPResponse{Status: HTTPStatusError, Data: "Internal Server Error"}
+		}
+	}
+
+	writeJSONResponse(writer, HTTPResponse{Status: HTTPStatusSuccess, Data: results}, http.StatusOK)
+}
+
+// executeBatch sends every valid command to the shard owning its key and waits for the responses.
+// Commands that did not complete in time are left with a nil response.
+func (s *HTTPServer) executeBatch(ctx context.Context, handlerID string, responseChan chan *ops.StoreResponse,
+	commands []*cmd.DiceDBCmd, valid []bool) []*ops.StoreResponse {
+	responses := make([]*ops.StoreResponse, len(commands))
+	requests := make(map[uint32]int, len(commands))
+
+	for i, diceDBCmd := range commands {
+		if !valid[i] {
+			continue
+		}
+
+		shardID, reqChan := s.batchShard(diceDBCmd)
+		requestID := commandhandler.GenerateUniqueRequestID()
+		op := &ops.StoreOp{
+			RequestID:    requestID,
+			Cmd:          diceDBCmd,
+			CmdHandlerID: handlerID,
+			ShardID:      shardID,
+			HTTPOp:       true,
+			Ctx:          ctx,
+		}
+
+		select {
+		case reqChan <- op:
+			requests[requestID] = i
+		case <-ctx.Done():
+			return responses
+		}
+	}
+
+	for len(requests) > 0 {
+		select {
+		case resp := <-responseChan:
+			if i, ok := requests[resp.RequestID]; ok {
+				responses[i] = resp
+				delete(requests, resp.RequestID)
+			}
+		case <-ctx.Done():
+			return responses
+		}
+	}
+
+	return responses
+}
+
+// executeAtomicBatch sends all the commands to the shard as a single operation, so that they are
+// executed back to back. The shard sends the responses in the order of the commands.
+func (s *HTTPServer) executeAtomicBatch(ctx context.Context, handlerID string, responseChan chan *ops.StoreResponse,
+	shardID shard.ShardID, commands []*cmd.DiceDBCmd) []*ops.StoreResponse {
+	responses := make([]*ops.StoreResponse, len(commands))
+	requestID := commandhandler.GenerateUniqueRequestID()
+	op := &ops.StoreOp{
+		RequestID:    requestID,
+		Batch:        commands,
+		CmdHandlerID: handlerID,
+		ShardID:      shardID,
+		HTTPOp:       true,
+		Ctx:          ctx,
+	}
+
+	select {
+	case s.shardManager.GetShard(shardID).ReqChan <- op:
+	case <-ctx.Done():
+		return responses
+	}
+
+	for i := 0; i < len(commands); {
+		select {
+		case resp := <-responseChan:
+			if resp.RequestID == requestID {
+				responses[i] = resp
+				i++
+			}
+		case <-ctx.Done():
+			return responses
+		}
+	}
+
+	return responses
+}
+
+// batchShard returns the shard a batch command is executed on. Commands without a key are
+// executed on the first shard.
+func (s *HTTPServer) batchShard(diceDBCmd *cmd.DiceDBCmd) (shard.ShardID, chan *ops.StoreOp) {
+	if !isKeyedBatchCommand(diceDBCmd) {
+		return 0, s.shardManager.GetShard(0).ReqChan
+	}
+	return s.shardManager.GetShardInfo(diceDBCmd.GetKey())
+}
+
+// atomicBatchShard returns the shard owning all the keys of an atomic batch, or false if the keys
+// belong to more than one shard.
+func (s *HTTPServer) atomicBatchShard(commands []*cmd.DiceDBCmd) (shard.ShardID, bool) {
+	var (
+		shardID shard.ShardID
+		found   bool
+	)
+
+	for _, diceDBCmd := range commands {
+		if !isKeyedBatchCommand(diceDBCmd) {
+			continue
+		}
+
+		id, _ := s.shardManager.GetShardInfo(diceDBCmd.GetKey())
+		if found && id != shardID {
+			return 0, false
+		}
+		shardID, found = id, true
+	}
+
+	return shardID, true
+}
+
+// validateBatchCommand checks that the command can be part of a batch. Commands spanning several
+// shards and commands streaming their results are not supported.
+func validateBatchCommand(diceDBCmd *cmd.DiceDBCmd) error {
+	if diceDBCmd.Cmd == "" {
+		return errEmptyBatchCmd
+	}
+
+	if diceDBCmd.Cmd == Abort || diceDBCmd.Cmd == QWatch || unimplementedCommands[diceDBCmd.Cmd] || commandhandler.IsInternalCommand(diceDBCmd.Cmd) {
+		return fmt.Errorf("command %s is not supported in a batch", diceDBCmd.Cmd)
+	}
+
+	meta, ok := commandhandler.CommandsMeta[diceDBCmd.Cmd]
+	if !ok {
+		return nil
+	}
+
+	switch meta.CmdType {
+	case commandhandler.MultiShard, commandhandler.AllShard, commandhandler.Watch, commandhandler.Unwatch:
+		return fmt.Errorf("command %s is not supported in a batch", diceDBCmd.Cmd)
+	default:
+		return nil
+	}
+}
+
+// isKeyedBatchCommand reports whether the first argument of the command is a key.
+func isKeyedBatchCommand(diceDBCmd *cmd.DiceDBCmd) bool {
+	if len(diceDBCmd.Args) == 0 {
+		return false
+	}
+
+	meta, ok := commandhandler.CommandsMeta[diceDBCmd.Cmd]
+	return !ok || meta.CmdType != commandhandler.Custom
+}
@@ -0,0 +1,154 @@
+// Copyright (c) 2022-present, DiceDB contributors
+// All rights reserved. Licensed under the BSD 3-Clause License. See LICENSE file in the project root for full license information.
+
+package httpws
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/dicedb/dice/config"
+	"github.com/dicedb/dice/internal/shard"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func init() {
+	if err := config.NewConfigParser().ParseDefaults(config.DiceConfig); err != nil {
+		panic(err)
+	}
+}
+
+func TestParseHTTPBatchRequest(t *testing.T) {
+	tests := []struct {
+		name         string
+		body         string
+		expectedCmds []string
+		expectedArgs [][]string
+		atomic       bool
+		expectedErr  bool
+	}{
+		{
+			name:         "commands object",
+			body:         `{"commands": [{"cmd": "set", "args": ["k1", "v1"]}, {"cmd": "GET", "args": ["k1"]}], "atomic": true}`,
+			expectedCmds: []string{"SET", "GET"},
+			expectedArgs: [][]string{{"k1", "v1"}, {"k1"}},
+			atomic:       true,
+		},
+		{
+			name:         "bare array with non-string arguments",
+			body:         `[{"cmd": "SET", "args": ["k1", 10, {"a": 1}]}, {"cmd": "PING"}]`,
+			expectedCmds: []string{"SET", "PING"},
+			expectedArgs: [][]string{{"k1", "10", `{"a":1}`}, {}},
+		},
+		{
+			name:        "empty batch",
+			body:        `{"commands": []}`,
+			expectedErr: true,
+		},
+		{
+			name:        "invalid json",
+			body:        `[{"cmd": "SET"`,
+			expectedErr: true,
+		},
+		{
+			name:        "too many commands",
+			body:        "[" + strings.Repeat(`{"cmd": "PING"},`, MaxBatchSize) + `{"cmd": "PING"}]`,
+			expectedErr: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/batch", strings.NewReader(tc.body))
+			batch, commands, err := ParseHTTPBatchRequest(req)
+			if tc.expectedErr {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.atomic, batch.Atomic)
+			require.Len(t, commands, len(tc.expectedCmds))
+			for i, c := range commands {
+				assert.Equal(t, tc.expectedCmds[i], c.Cmd)
+				assert.Equal(t, tc.expectedArgs[i], c.Args)
+			}
+		})
+	}
+}
+
+func TestDiceHTTPBatchHandler(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	shardManager := shard.NewShardManager(4, nil, make(chan error, 1))
+	go shardManager.Run(ctx)
+	server := NewHTTPServer(shardManager, nil, nil, nil)
+
+	fire := func(body string) (int, HTTPResponse) {
+		req := httptest.NewRequest(http.MethodPost, "/batch", strings.NewReader(body))
+		rec := httptest.NewRecorder()
+		server.httpServer.Handler.ServeHTTP(rec, req)
+
+		var resp HTTPResponse
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+		return rec.Code, resp
+	}
+
+	code, resp := fire(`[
+		{"cmd": "SET", "args": ["k1", "v1"]},
+		{"cmd": "SET", "args": ["k2", "v2"]},
+		{"cmd": "GET", "args": ["k1"]},
+		{"cmd": "GET", "args": ["k2"]},
+		{"cmd": "MGET", "args": ["k1", "k2"]},
+		{"cmd": "GET", "args": ["missing"]}
+	]`)
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, HTTPStatusSuccess, resp.Status)
+
+	results := resp.Data.([]interface{})
+	require.Len(t, results, 6)
+	statuses := make([]string, len(results))
+	data := make([]interface{}, len(results))
+	for i, result := range results {
+		item := result.(map[string]interface{})
+		statuses[i], data[i] = item["status"].(string), item["data"]
+	}
+	assert.Equal(t, []string{HTTPStatusSuccess, HTTPStatusSuccess, HTTPStatusSuccess, HTTPStatusSuccess, HTTPStatusError, HTTPStatusSuccess}, statuses)
+	assert.Equal(t, []interface{}{"OK", "OK", "v1", "v2"}, data[:4])
+	assert.Nil(t, data[5])
+
+	code, resp = fire(`{"atomic": true, "commands": [{"cmd": "INCR", "args": ["counter"]}, {"cmd": "INCR", "args": ["counter"]}, {"cmd": "GET", "args": ["counter"]}]}`)
+	assert.Equal(t, http.StatusOK, code)
+	results = resp.Data.([]interface{})
+	require.Len(t, results, 3)
+	assert.Equal(t, float64(2), results[2].(map[string]interface{})["data"])
+
+	// Atomic batches must stay on a single shard.
+	var otherKey string
+	counterShard, _ := shardManager.GetShardInfo("counter")
+	for i := 0; ; i++ {
+		otherKey = fmt.Sprintf("key-%d", i)
+		if id, _ := shardManager.GetShardInfo(otherKey); id != counterShard {
+			break
+		}
+	}
+	code, resp = fire(fmt.Sprintf(`{"atomic": true, "commands": [{"cmd": "GET", "args": ["counter"]}, {"cmd": "GET", "args": [%q]}]}`, otherKey))
+	assert.Equal(t, http.StatusBadRequest, code)
+	assert.Equal(t, HTTPStatusError, resp.Status)
+
+	code, resp = fire(`{"atomic": true, "commands": [{"cmd": "GET", "args": ["counter"]}, {"cmd": "MGET", "args": ["k1", "k2"]}]}`)
+	assert.Equal(t, http.StatusBadRequest, code)
+	assert.Equal(t, HTTPStatusError, resp.Status)
+
+	req := httptest.NewRequest(http.MethodGet, "/batch", http.NoBody)
+	rec := httptest.NewRecorder()
+	server.httpServer.Handler.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+}
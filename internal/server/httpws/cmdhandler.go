@@ -0,0 +1,148 @@
+// Copyright (c) 2022-present, DiceDB contributors
+// All rights reserved. Licensed under the BSD 3-Clause License. See LICENSE file in the project root for full license information.
+
+package httpws
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/dicedb/dice/internal/cmd"
+	"github.com/dicedb/dice/internal/commandhandler"
+	diceerrors "github.com/dicedb/dice/internal/errors"
+	"github.com/dicedb/dice/internal/eval"
+	"github.com/dicedb/dice/internal/ops"
+	"github.com/dicedb/dice/internal/shard"
+	"github.com/dicedb/dice/internal/wal"
+	"github.com/dicedb/dice/internal/watchmanager"
+)
+
+// newCommandHandler creates the command handler executing HTTP or WebSocket commands and registers
+// it with the shard manager, so that the commands are routed to the shards owning their keys like
+// the commands received over RESP. The handler has no io-thread, as its server parses the requests
+// and writes the responses itself. It must be unregistered from the shard manager once done.
+func newCommandHandler(id string, shardManager *shard.ShardManager, cmdWatchSubscriptionChan chan watchmanager.WatchSubscription,
+	globalErrChan chan error, wl wal.AbstractWAL) *commandhandler.BaseCommandHandler {
+	responseChan := make(chan *ops.StoreResponse)
+	preprocessingChan := make(chan *ops.StoreResponse)
+	shardManager.RegisterCommandHandler(id, responseChan, preprocessingChan)
+
+	return commandhandler.NewCommandHandler(id, responseChan, preprocessingChan,
+		cmdWatchSubscriptionChan, nil, shardManager, globalErrChan, nil, nil, nil, wl)
+}
+
+// commandHandlerPool hands out command handlers to concurrent requests. A handler serves a single
+// request at a time, as the responses to its shard operations share its channels, so every request
+// gets a handler of its own and the requests execute in parallel on the shards. Idle handlers are
+// kept for reuse, up to the size of the pool.
+type commandHandlerPool struct {
+	idPrefix                 string
+	shardManager             *shard.ShardManager
+	cmdWatchSubscriptionChan chan watchmanager.WatchSubscription
+	globalErrChan            chan error
+	wl                       wal.AbstractWAL
+	idle                     chan *commandhandler.BaseCommandHandler
+}
+
+func newCommandHandlerPool(idPrefix string, size int, shardManager *shard.ShardManager,
+	cmdWatchSubscriptionChan chan watchmanager.WatchSubscription, globalErrChan chan error, wl wal.AbstractWAL) *commandHandlerPool {
+	return &commandHandlerPool{
+		idPrefix:                 idPrefix,
+		shardManager:             shardManager,
+		cmdWatchSubscriptionChan: cmdWatchSubscriptionChan,
+		globalErrChan:            globalErrChan,
+		wl:                       wl,
+		idle:                     make(chan *commandhandler.BaseCommandHandler, size),
+	}
+}
+
+// acquire returns an idle command handler, or registers a new one if all of them are busy.
+func (p *commandHandlerPool) acquire() *commandhandler.BaseCommandHandler {
+	select {
+	case h := <-p.idle:
+		return h
+	default:
+		id := fmt.Sprintf("%s-%d", p.idPrefix, commandhandler.GenerateUniqueRequestID())
+		return newCommandHandler(id, p.shardManager, p.cmdWatchSubscriptionChan, p.globalErrChan, p.wl)
+	}
+}
+
+// release returns the command handler to the pool once its request is done. The handler is
+// unregistered if the pool is already full.
+func (p *commandHandlerPool) release(h *commandhandler.BaseCommandHandler) {
+	select {
+	case p.idle <- h:
+	default:
+		p.remove(h)
+	}
+}
+
+// close unregisters all the idle command handlers.
+func (p *commandHandlerPool) close() {
+	for {
+		select {
+		case h := <-p.idle:
+			p.remove(h)
+		default:
+			return
+		}
+	}
+}
+
+func (p *commandHandlerPool) remove(h *commandhandler.BaseCommandHandler) {
+	p.shardManager.UnregisterCommandHandler(h.ID())
+	if err := h.Stop(); err != nil {
+		slog.Warn("Error stopping command handler", slog.String("id", h.ID()), slog.Any("error", err))
+	}
+}
+
+// isRESPEncodedResult reports whether the result of the command is RESP encoded, which is the
+// case for the commands whose evaluation is not migrated yet and for the errors of custom commands.
+//
+// TODO: Remove once all commands are migrated.
+func isRESPEncodedResult(diceDBCmd *cmd.DiceDBCmd) bool {
+	if meta, ok := commandhandler.CommandsMeta[diceDBCmd.Cmd]; ok {
+		switch meta.CmdType {
+		case commandhandler.Custom:
+			return true
+		case commandhandler.MultiShard, commandhandler.AllShard:
+			// Composed responses are never RESP encoded
+			return false
+		}
+	}
+
+	return !eval.DiceCmds[diceDBCmd.Cmd].IsMigrated
+}
+
+// decodeCommandResult converts the result of a command, as returned by the command handler or by
+// a shard, into the value rendered for HTTP and WebSocket clients. It also reports whether the
+// result is an error.
+func decodeCommandResult(diceDBCmd *cmd.DiceDBCmd, result interface{}, err error) (value interface{}, isErr bool, decodeErr error) {
+	if err != nil {
+		// Preprocessing may settle a command without executing it, e.g. COPY of a missing key
+		var preProcessErr *diceerrors.PreProcessError
+		if errors.As(err, &preProcessErr) {
+			return ResponseParser(preProcessErr.Result), false, nil
+		}
+		return err.Error(), true, nil
+	}
+
+	switch v := result.(type) {
+	case error:
+		// Composed responses of multi-shard commands carry the error of the failing shard as their result
+		return v.Error(), true, nil
+	case []byte:
+		if !isRESPEncodedResult(diceDBCmd) {
+			break
+		}
+
+		value, err := DecodeEvalResponse(&eval.EvalResponse{Result: v})
+		if err != nil {
+			return nil, false, err
+		}
+		return ResponseParser(value), len(v) > 0 && v[0] == '-', nil
+	}
+
+	return ResponseParser(result), false, nil
+}
@@ -0,0 +1,219 @@
+// Copyright (c) 2022-present, DiceDB contributors
+// All rights reserved. Licensed under the BSD 3-Clause License. See LICENSE file in the project root for full license information.
+
+package httpws
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/dicedb/dice/internal/clientio"
+	"github.com/dicedb/dice/internal/cmd"
+	diceerrors "github.com/dicedb/dice/internal/errors"
+	"github.com/dicedb/dice/internal/shard"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiceHTTPHandlerRoutesKeysToShards(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	shardManager := shard.NewShardManager(4, nil, make(chan error, 1))
+	go shardManager.Run(ctx)
+	server := NewHTTPServer(shardManager, nil, make(chan error, 1), nil)
+	defer server.cmdHandlers.close()
+
+	fire := func(path, body string) HTTPResponse {
+		req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+		rec := httptest.NewRecorder()
+		server.httpServer.Handler.ServeHTTP(rec, req)
+		require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+		var resp HTTPResponse
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+		return resp
+	}
+
+	keys := make([]string, 8)
+	values := make([]interface{}, len(keys))
+	for i := range keys {
+		keys[i] = fmt.Sprintf("k%d", i)
+		values[i] = fmt.Sprintf("v%d", i)
+		resp := fire("/set", fmt.Sprintf(`{"key": %q, "value": %q}`, keys[i], values[i]))
+		assert.Equal(t, HTTPResponse{Status: HTTPStatusSuccess, Data: "OK"}, resp)
+	}
+
+	// The keys must be on the shards the batch endpoint routes them to
+	batch := make([]string, len(keys))
+	for i, key := range keys {
+		batch[i] = fmt.Sprintf(`{"cmd": "GET", "args": [%q]}`, key)
+	}
+	resp := fire("/batch", "["+strings.Join(batch, ",")+"]")
+	for i, result := range resp.Data.([]interface{}) {
+		assert.Equal(t, values[i], result.(map[string]interface{})["data"], keys[i])
+	}
+
+	keysJSON, err := json.Marshal(keys)
+	require.NoError(t, err)
+	assert.Equal(t, HTTPResponse{Status: HTTPStatusSuccess, Data: values}, fire("/mget", fmt.Sprintf(`{"keys": %s}`, keysJSON)))
+	assert.Equal(t, HTTPResponse{Status: HTTPStatusSuccess, Data: float64(len(keys))}, fire("/dbsize", ""))
+	assert.Equal(t, HTTPResponse{Status: HTTPStatusSuccess, Data: "PONG"}, fire("/ping", ""))
+	assert.Equal(t, HTTPStatusError, fire("/get", "").Status)
+}
+
+func TestDiceHTTPHandlerConcurrentRequests(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	shardManager := shard.NewShardManager(4, nil, make(chan error, 1))
+	go shardManager.Run(ctx)
+	server := NewHTTPServer(shardManager, nil, make(chan error, 1), nil)
+	defer server.cmdHandlers.close()
+
+	fire := func(path, body string) (HTTPResponse, error) {
+		req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+		rec := httptest.NewRecorder()
+		server.httpServer.Handler.ServeHTTP(rec, req)
+
+		var resp HTTPResponse
+		err := json.Unmarshal(rec.Body.Bytes(), &resp)
+		return resp, err
+	}
+
+	const clients = 200
+	var wg sync.WaitGroup
+	errs := make(chan error, clients)
+	for i := 0; i < clients; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			key, value := fmt.Sprintf("key-%d", i), fmt.Sprintf("value-%d", i)
+			peer := fmt.Sprintf("key-%d", (i+1)%clients)
+
+			steps := []struct {
+				path, body string
+				check      func(HTTPResponse) bool
+			}{
+				{"/set", fmt.Sprintf(`{"key": %q, "value": %q}`, key, value), func(r HTTPResponse) bool { return r.Data == "OK" }},
+				{"/incr", `{"key": "counter"}`, func(r HTTPResponse) bool { return r.Status == HTTPStatusSuccess }},
+				{"/get", fmt.Sprintf(`{"key": %q}`, key), func(r HTTPResponse) bool { return r.Data == value }},
+				{"/mget", fmt.Sprintf(`{"keys": [%q, %q]}`, key, peer), func(r HTTPResponse) bool {
+					values, ok := r.Data.([]interface{})
+					// The peer key may not have been set yet
+					return ok && len(values) == 2 && values[0] == value &&
+						(values[1] == nil || values[1] == fmt.Sprintf("value-%d", (i+1)%clients))
+				}},
+			}
+			for _, step := range steps {
+				resp, err := fire(step.path, step.body)
+				if err != nil {
+					errs <- err
+					return
+				}
+				if !step.check(resp) {
+					errs <- fmt.Errorf("client %d: unexpected response to %s: %+v", i, step.path, resp)
+					return
+				}
+			}
+		}(i)
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		t.Error(err)
+	}
+
+	resp, err := fire("/get", `{"key": "counter"}`)
+	require.NoError(t, err)
+	assert.Equal(t, float64(clients), resp.Data)
+}
+
+func TestDiceHTTPHandlerRejectsWatchCommands(t *testing.T) {
+	server := NewHTTPServer(nil, nil, nil, nil)
+
+	req := httptest.NewRequest(http.MethodPost, "/get.watch", strings.NewReader(`{"key": "k1"}`))
+	rec := httptest.NewRecorder()
+	server.httpServer.Handler.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func TestDecodeCommandResult(t *testing.T) {
+	tests := []struct {
+		name          string
+		cmd           string
+		result        interface{}
+		err           error
+		expectedValue interface{}
+		expectedIsErr bool
+	}{
+		{
+			name:          "migrated command",
+			cmd:           "GET",
+			result:        "v1",
+			expectedValue: "v1",
+		},
+		{
+			name:          "migrated command error",
+			cmd:           "GET",
+			err:           errors.New("ERR wrong number of arguments for 'get' command"),
+			expectedValue: "ERR wrong number of arguments for 'get' command",
+			expectedIsErr: true,
+		},
+		{
+			name:          "composed error",
+			cmd:           "MSET",
+			result:        errors.New("ERR syntax error"),
+			expectedValue: "ERR syntax error",
+			expectedIsErr: true,
+		},
+		{
+			name:          "preprocessed result",
+			cmd:           "COPY",
+			err:           &diceerrors.PreProcessError{Result: clientio.IntegerZero},
+			expectedValue: float64(0),
+		},
+		{
+			name:          "RESP encoded result",
+			cmd:           "NOT.MIGRATED",
+			result:        []byte("$2\r\nv1\r\n"),
+			expectedValue: "v1",
+		},
+		{
+			name:          "RESP encoded result of a command evaluated on a shard",
+			cmd:           "SLEEP",
+			result:        []byte("+OK\r\n"),
+			expectedValue: "OK",
+		},
+		{
+			name:          "RESP encoded error",
+			cmd:           "HELLO",
+			result:        []byte("-ERR wrong number of arguments for 'hello' command\r\n"),
+			expectedValue: "ERR wrong number of arguments for 'hello' command",
+			expectedIsErr: true,
+		},
+		{
+			name:          "nil",
+			cmd:           "GET",
+			result:        clientio.NIL,
+			expectedValue: nil,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			value, isErr, err := decodeCommandResult(&cmd.DiceDBCmd{Cmd: tc.cmd}, tc.result, tc.err)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedValue, value)
+			assert.Equal(t, tc.expectedIsErr, isErr)
+		})
+	}
+}
@@ -20,6 +20,7 @@ import (
 	"github.com/dicedb/dice/internal/eval"
 	"github.com/dicedb/dice/internal/server/abstractserver"
 	"github.com/dicedb/dice/internal/wal"
+	"github.com/dicedb/dice/internal/watchmanager"
 
 	"github.com/dicedb/dice/config"
 	"github.com/dicedb/dice/internal/clientio"
@@ -31,22 +32,29 @@ import (
 )
 
 const (
-	Abort            = "ABORT"
-	Nil              = "(nil)"
-	httpCmdHandlerID = "httpServer"
+	Abort = "ABORT"
+	Nil   = "(nil)"
+
+	// httpCommandHandlerPoolSize is the number of idle command handlers kept for reuse by the requests
+	httpCommandHandlerPoolSize = 256
 )
 
 var unimplementedCommands = map[string]bool{
 	"Q.UNWATCH": true,
+	// The requests are stateless, so the commands operate on the default database
+	"SELECT": true,
+	// The command handlers are shared by the requests, so they do not hold a connection to name
+	"CLIENT": true,
 }
 
 type HTTPServer struct {
 	abstractserver.AbstractServer
-	shardManager       *shard.ShardManager
-	ioChan             chan *ops.StoreResponse
-	httpServer         *http.Server
-	qwatchResponseChan chan comm.QwatchResponse
-	shutdownChan       chan struct{}
+	shardManager             *shard.ShardManager
+	cmdHandlers              *commandHandlerPool
+	httpServer               *http.Server
+	qwatchResponseChan       chan comm.QwatchResponse
+	cmdWatchSubscriptionChan chan watchmanager.WatchSubscription // nil if watch is disabled
+	shutdownChan             chan struct{}
 }
 
 type HTTPQwatchResponse struct {
@@ -61,12 +69,18 @@ type CaseInsensitiveMux struct {
 }
 
 func (cim *CaseInsensitiveMux) ServeHTTP(w http.ResponseWriter, r *http.Request) {
-	// Convert the path to lowercase before passing to the underlying mux.
-	r.URL.Path = strings.ToLower(r.URL.Path)
+	// Convert the path to lowercase before passing to the underlying mux. Keys are case-sensitive,
+	// so the key of a key resource is left untouched.
+	path := strings.ToLower(r.URL.Path)
+	if strings.HasPrefix(path, KeysPathPrefix) {
+		path = KeysPathPrefix + r.URL.Path[len(KeysPathPrefix):]
+	}
+	r.URL.Path = path
 	cim.mux.ServeHTTP(w, r)
 }
 
-func NewHTTPServer(shardManager *shard.ShardManager, wl wal.AbstractWAL) *HTTPServer {
+func NewHTTPServer(shardManager *shard.ShardManager, cmdWatchSubscriptionChan chan watchmanager.WatchSubscription,
+	globalErrChan chan error, wl wal.AbstractWAL) *HTTPServer {
 	mux := http.NewServeMux()
 	caseInsensitiveMux := &CaseInsensitiveMux{mux: mux}
 	srv := &http.Server{
@@ -75,15 +89,23 @@ func NewHTTPServer(shardManager *shard.ShardManager, wl wal.AbstractWAL) *HTTPSe
 		ReadHeaderTimeout: 5 * time.Second,
 	}
 
+	cmdHandlers := newCommandHandlerPool("http", httpCommandHandlerPoolSize, shardManager, cmdWatchSubscriptionChan, globalErrChan, wl)
 	httpServer := &HTTPServer{
-		shardManager:       shardManager,
-		ioChan:             make(chan *ops.StoreResponse, 1000),
-		httpServer:         srv,
-		qwatchResponseChan: make(chan comm.QwatchResponse),
-		shutdownChan:       make(chan struct{}),
-	}
-
-	mux.HandleFunc("/", httpServer.DiceHTTPHandler)
+		shardManager:             shardManager,
+		cmdHandlers:              cmdHandlers,
+		httpServer:               srv,
+		qwatchResponseChan:       make(chan comm.QwatchResponse),
+		cmdWatchSubscriptionChan: cmdWatchSubscriptionChan,
+		shutdownChan:             make(chan struct{}),
+	}
+
+	mux.HandleFunc("/", requireAuth(httpServer.DiceHTTPHandler))
+	mux.HandleFunc("/batch", requireAuth(httpServer.DiceHTTPBatchHandler))
+	mux.HandleFunc(WatchPathPrefix, requireAuth(httpServer.DiceHTTPWatchHandler))
+	mux.HandleFunc(OpenAPIPath, requireAuth(httpServer.DiceHTTPOpenAPIHandler))
+	mux.HandleFunc(KeysPath, requireAuth(httpServer.DiceHTTPKeysHandler))
+	mux.HandleFunc(KeysPathPrefix, requireAuth(httpServer.DiceHTTPKeysHandler))
+	mux.HandleFunc(MetricsPath, requireAuth(httpServer.DiceHTTPMetricsHandler))
 	mux.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {
 		_, err := w.Write([]byte("ok"))
 		if err != nil {
@@ -101,7 +123,7 @@ func (s *HTTPServer) Run(ctx context.Context) error {
 	httpCtx, cancelHTTP := context.WithCancel(ctx)
 	defer cancelHTTP()
 
-	s.shardManager.RegisterCommandHandler(httpCmdHandlerID, s.ioChan, nil)
+	defer s.cmdHandlers.close()
 
 	wg.Add(1)
 	go func() {
@@ -145,9 +167,7 @@ func (s *HTTPServer) DiceHTTPHandler(writer http.ResponseWriter, request *http.R
 		return
 	}
 
-	if commandhandler.CommandsMeta[diceDBCmd.Cmd].CmdType == commandhandler.MultiShard {
-		writeErrorResponse(writer, http.StatusBadRequest, "unsupported command",
-			"Unsupported command received", slog.String("cmd", diceDBCmd.Cmd))
+	if !authorizeRequest(writer, request, diceDBCmd.Cmd) {
 		return
 	}
 
@@ -165,18 +185,18 @@ func (s *HTTPServer) DiceHTTPHandler(writer http.ResponseWriter, request *http.R
 		return
 	}
 
-	// send request to Shard Manager
-	s.shardManager.GetShard(0).ReqChan <- &ops.StoreOp{
-		Cmd:          diceDBCmd,
-		CmdHandlerID: httpCmdHandlerID,
-		ShardID:      0,
-		HTTPOp:       true,
+	if cmdType := commandhandler.CommandsMeta[diceDBCmd.Cmd].CmdType; cmdType == commandhandler.Watch || cmdType == commandhandler.Unwatch {
+		writeErrorResponse(writer, http.StatusBadRequest,
+			fmt.Sprintf("Command %s must be streamed with GET %s{command}", diceDBCmd.Cmd, WatchPathPrefix),
+			"Watch command received on the command endpoint", slog.String("cmd", diceDBCmd.Cmd))
+		return
 	}
 
-	// Wait for response
-	resp := <-s.ioChan
+	cmdHandler := s.cmdHandlers.acquire()
+	result, err := cmdHandler.ExecuteCommand(request.Context(), diceDBCmd)
+	s.cmdHandlers.release(cmdHandler)
 
-	s.writeResponse(writer, resp, diceDBCmd)
+	s.writeResponse(writer, diceDBCmd, result, err)
 }
 
 func (s *HTTPServer) DiceHTTPQwatchHandler(writer http.ResponseWriter, request *http.Request) {
@@ -212,6 +232,11 @@ func (s *HTTPServer) DiceHTTPQwatchHandler(writer http.ResponseWriter, request *
 	writer.Header().Set("Cache-Control", "no-cache")
 	writer.Header().Set("Connection", "keep-alive")
 	writer.WriteHeader(http.StatusOK)
+	handlerID := fmt.Sprintf("httpQwatch-%d", commandhandler.GenerateUniqueRequestID())
+	responseChan := make(chan *ops.StoreResponse)
+	s.shardManager.RegisterCommandHandler(handlerID, responseChan, nil)
+	defer s.shardManager.UnregisterCommandHandler(handlerID)
+
 	// We're a generating a unique client id, to keep track in core of requests from registered clients
 	clientIdentifierID := generateUniqueInt32(request)
 	qwatchQuery := diceDBCmd.Args[0]
@@ -219,7 +244,7 @@ func (s *HTTPServer) DiceHTTPQwatchHandler(writer http.ResponseWriter, request *
 	// Prepare the store operation
 	storeOp := &ops.StoreOp{
 		Cmd:          diceDBCmd,
-		CmdHandlerID: httpCmdHandlerID,
+		CmdHandlerID: handlerID,
 		ShardID:      0,
 		Client:       qwatchClient,
 		HTTPOp:       true,
@@ -230,7 +255,7 @@ func (s *HTTPServer) DiceHTTPQwatchHandler(writer http.ResponseWriter, request *
 	s.shardManager.GetShard(0).ReqChan <- storeOp
 
 	// Wait for 1st sync response from server for QWATCH and flush it to client
-	resp := <-s.ioChan
+	resp := <-responseChan
 	s.writeQWatchResponse(writer, resp)
 	flusher.Flush()
 	// Keep listening for context cancellation (client disconnect) and continuous responses
@@ -253,8 +278,8 @@ func (s *HTTPServer) DiceHTTPQwatchHandler(writer http.ResponseWriter, request *
 			}
 			storeOp.Cmd = unWatchCmd
 			s.shardManager.GetShard(0).ReqChan <- storeOp
-			resp := <-s.ioChan
-			s.writeResponse(writer, resp, diceDBCmd)
+			resp := <-responseChan
+			s.writeResponse(writer, diceDBCmd, resp.EvalResponse.Result, resp.EvalResponse.Error)
 			return
 		}
 	}
@@ -332,47 +357,32 @@ func (s *HTTPServer) writeQWatchResponse(writer http.ResponseWriter, response in
 	flusher.Flush() // Flush the response to send it to the client
 }
 
-func (s *HTTPServer) writeResponse(writer http.ResponseWriter, result *ops.StoreResponse, diceDBCmd *cmd.DiceDBCmd) {
-	var (
-		responseValue interface{}
-		err           error
-		httpResponse  HTTPResponse
-		isDiceErr     bool
-	)
-
-	// Check if the command is migrated, if it is we use EvalResponse values
-	// else we use RESPParser to decode the response
-	_, ok := commandhandler.CommandsMeta[diceDBCmd.Cmd]
-	// TODO: Remove this conditional check and if (true) condition when all commands are migrated
-	if !ok || commandhandler.CommandsMeta[diceDBCmd.Cmd].CmdType == commandhandler.Custom {
-		responseValue, err = DecodeEvalResponse(result.EvalResponse)
-		if err != nil {
-			slog.Error("Error decoding response", "error", err)
-			httpResponse = HTTPResponse{Status: HTTPStatusError, Data: "Internal Server Error"}
-			writeJSONResponse(writer, httpResponse, http.StatusInternalServerError)
-			return
-		}
-	} else {
-		if result.EvalResponse.Error != nil {
-			isDiceErr = true
-			responseValue = result.EvalResponse.Error.Error()
-		} else {
-			responseValue = result.EvalResponse.Result
-		}
-	}
-
-	// Create the HTTP response
-	httpResponse = HTTPResponse{Data: ResponseParser(responseValue)}
-	if isDiceErr {
-		httpResponse.Status = HTTPStatusError
-	} else {
-		httpResponse.Status = HTTPStatusSuccess
+func (s *HTTPServer) writeResponse(writer http.ResponseWriter, diceDBCmd *cmd.DiceDBCmd, result interface{}, resultErr error) {
+	httpResponse, err := buildHTTPResponse(diceDBCmd, result, resultErr)
+	if err != nil {
+		slog.Error("Error decoding response", "error", err)
+		httpResponse = HTTPResponse{Status: HTTPStatusError, Data: "Internal Server Error"}
+		writeJSONResponse(writer, httpResponse, http.StatusInternalServerError)
+		return
 	}
 
 	// Write the response back to the client
 	writeJSONResponse(writer, httpResponse, http.StatusOK)
 }
 
+// buildHTTPResponse converts the result of a command into the response rendered for HTTP clients.
+func buildHTTPResponse(diceDBCmd *cmd.DiceDBCmd, result interface{}, resultErr error) (HTTPResponse, error) {
+	value, isErr, err := decodeCommandResult(diceDBCmd, result, resultErr)
+	if err != nil {
+		return HTTPResponse{}, err
+	}
+
+	if isErr {
+		return HTTPResponse{Status: HTTPStatusError, Data: value}, nil
+	}
+	return HTTPResponse{Status: HTTPStatusSuccess, Data: value}, nil
+}
+
 // Helper function to write the JSON response
 func writeJSONResponse(writer http.ResponseWriter, response HTTPResponse, statusCode int) {
 	writer.Header().Set("Content-Type", "application/json")
@@ -0,0 +1,442 @@
+// Copyright (c) 2022-present, DiceDB contributors
+// All rights reserved. Licensed under the BSD 3-Clause License. See LICENSE file in the project root for full license information.
+
+package httpws
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/dicedb/dice/internal/cmd"
+	"github.com/dicedb/dice/internal/commandhandler"
+)
+
+const (
+	// KeysPath lists the keys, and KeysPathPrefix followed by a key is the resource of the key.
+	KeysPath       = "/keys"
+	KeysPathPrefix = KeysPath + "/"
+
+	// TTLHeader carries the remaining time to live of a key, in seconds. It is omitted for keys
+	// without an expiry.
+	TTLHeader = "X-Dice-TTL"
+
+	defaultKeysPageSize = 100
+	maxKeysPageSize     = 10000
+)
+
+var (
+	errKeyNotFound      = errors.New("key not found")
+	errMissingKey       = errors.New("missing key")
+	errInvalidTTL       = errors.New("ttl must be a positive integer")
+	errInvalidCursor    = errors.New("cursor must be a non-negative integer")
+	errInvalidPageSize  = fmt.Errorf("count must be an integer between 1 and %d", maxKeysPageSize)
+	errUnsupportedValue = errors.New("values of this type are not supported by the key routes")
+)
+
+// KeyResource is the value of a key returned by GET /keys/{key}. The value is rendered after the
+// type of the key: a string for strings, an array for lists and sets, an object of fields for
+// hashes, an object of member scores for sorted sets and the document itself for JSON.
+type KeyResource struct {
+	Key   string      `json:"key"`
+	Type  string      `json:"type"`
+	Value interface{} `json:"value"`
+}
+
+// KeysPage is a page of the keys returned by GET /keys. The cursor of the next page is "0" once all
+// the keys were listed.
+type KeysPage struct {
+	Keys   []string `json:"keys"`
+	Cursor string   `json:"cursor"`
+}
+
+// keyReadCommands are the commands reading the whole value of a key, after the type reported by TYPE.
+// The key is the first argument of each command, followed by the arguments listed here.
+var keyReadCommands = map[string][]string{
+	"string":    {"GET"},
+	"list":      {"LRANGE", "0", "-1"},
+	"set":       {"SMEMBERS"},
+	"hash":      {"HGETALL"},
+	"zset":      {"ZRANGE", "0", "-1", "WITHSCORES"},
+	"ReJSON-RL": {"JSON.GET"},
+}
+
+// keyCommandError is the error replied by a command of a key route.
+type keyCommandError struct {
+	msg string
+}
+
+func (e *keyCommandError) Error() string {
+	return e.msg
+}
+
+// DiceHTTPKeysHandler serves the key resources, mapping each route to the commands it is made of:
+//   - GET /keys/{key} reads the key with TYPE, the read command of its type and TTL.
+//   - PUT /keys/{key}?ttl= writes the body with SET, or with JSON.SET for application/json bodies.
+//   - DELETE /keys/{key} deletes the key with DEL.
+//   - GET /keys?match=&cursor=&count= lists the keys with SCAN.
+//
+// Other requests to /keys are served by the command endpoint, as it is the path of the KEYS command.
+// The commands of a route are not executed atomically. GET responses carry an ETag, and requests
+// whose If-None-Match matches it are answered with 304 Not Modified.
+func (s *HTTPServer) DiceHTTPKeysHandler(writer http.ResponseWriter, request *http.Request) {
+	key, isKeyResource := strings.CutPrefix(request.URL.Path, KeysPathPrefix)
+	if !isKeyResource {
+		switch request.Method {
+		case http.MethodGet, http.MethodHead:
+			s.listKeys(writer, request)
+		default:
+			// POST /keys is the KEYS command
+			s.DiceHTTPHandler(writer, request)
+		}
+		return
+	}
+
+	if key == "" {
+		writeErrorResponse(writer, http.StatusBadRequest, errMissingKey.Error(), "")
+		return
+	}
+
+	switch request.Method {
+	case http.MethodGet, http.MethodHead:
+		s.getKey(writer, request, key)
+	case http.MethodPut:
+		s.putKey(writer, request, key)
+	case http.MethodDelete:
+		s.deleteKey(writer, request, key)
+	default:
+		writer.Header().Set("Allow", "GET, HEAD, PUT, DELETE")
+		writeErrorResponse(writer, http.StatusMethodNotAllowed, "key resources support GET, PUT and DELETE", "")
+	}
+}
+
+func (s *HTTPServer) getKey(writer http.ResponseWriter, request *http.Request, key string) {
+	var resource KeyResource
+	var ttl int64
+	err := s.executeKeyCommands(request, func(execute keyCommandExecutor) error {
+		keyType, err := execute(&cmd.DiceDBCmd{Cmd: "TYPE", Args: []string{key}})
+		if err != nil {
+			return err
+		}
+		if keyType == "none" {
+			return errKeyNotFound
+		}
+
+		readCmd, ok := keyReadCommands[fmt.Sprint(keyType)]
+		if !ok {
+			return errUnsupportedValue
+		}
+		value, err := execute(&cmd.DiceDBCmd{Cmd: readCmd[0], Args: append([]string{key}, readCmd[1:]...)})
+		if err != nil {
+			return err
+		}
+		// The key may have been deleted in between
+		if value == nil {
+			return errKeyNotFound
+		}
+		if value, err = renderKeyValue(fmt.Sprint(keyType), value); err != nil {
+			return err
+		}
+
+		result, err := execute(&cmd.DiceDBCmd{Cmd: "TTL", Args: []string{key}})
+		if err != nil {
+			return err
+		}
+		if ttl, err = toInt64(result); err != nil {
+			return err
+		}
+
+		resource = KeyResource{Key: key, Type: fmt.Sprint(keyType), Value: value}
+		return nil
+	})
+	if err != nil {
+		writeKeyError(writer, err)
+		return
+	}
+
+	if ttl >= 0 {
+		writer.Header().Set(TTLHeader, strconv.FormatInt(ttl, 10))
+	}
+	writeCacheableResponse(writer, request, HTTPResponse{Status: HTTPStatusSuccess, Data: resource})
+}
+
+func (s *HTTPServer) putKey(writer http.ResponseWriter, request *http.Request, key string) {
+	var ttl string
+	if ttl = request.URL.Query().Get("ttl"); ttl != "" {
+		if n, err := strconv.ParseInt(ttl, 10, 64); err != nil || n <= 0 {
+			writeErrorResponse(writer, http.StatusBadRequest, errInvalidTTL.Error(), "")
+			return
+		}
+	}
+
+	body, err := io.ReadAll(request.Body)
+	if err != nil {
+		writeErrorResponse(writer, http.StatusBadRequest, "Error reading request body", "")
+		return
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(request.Header.Get("Content-Type"))
+	isJSON := mediaType == "application/json"
+	if isJSON && !json.Valid(body) {
+		writeErrorResponse(writer, http.StatusBadRequest, "invalid JSON body", "")
+		return
+	}
+
+	err = s.executeKeyCommands(request, func(execute keyCommandExecutor) error {
+		if !isJSON {
+			setCmd := &cmd.DiceDBCmd{Cmd: "SET", Args: []string{key, string(body)}}
+			if ttl != "" {
+				setCmd.Args = append(setCmd.Args, "EX", ttl)
+			}
+			_, err := execute(setCmd)
+			return err
+		}
+
+		// JSON.SET refuses to overwrite values of other types and keeps the expiry of the key, so the
+		// key is deleted first to replace it like SET does
+		if _, err := execute(&cmd.DiceDBCmd{Cmd: "DEL", Args: []string{key}}); err != nil {
+			return err
+		}
+		if _, err := execute(&cmd.DiceDBCmd{Cmd: "JSON.SET", Args: []string{key, "$", string(body)}}); err != nil {
+			return err
+		}
+		if ttl == "" {
+			return nil
+		}
+		_, err := execute(&cmd.DiceDBCmd{Cmd: "EXPIRE", Args: []string{key, ttl}})
+		return err
+	})
+	if err != nil {
+		writeKeyError(writer, err)
+		return
+	}
+
+	writeJSONResponse(writer, HTTPResponse{Status: HTTPStatusSuccess, Data: "OK"}, http.StatusOK)
+}
+
+func (s *HTTPServer) deleteKey(writer http.ResponseWriter, request *http.Request, key string) {
+	err := s.executeKeyCommands(request, func(execute keyCommandExecutor) error {
+		deleted, err := execute(&cmd.DiceDBCmd{Cmd: "DEL", Args: []string{key}})
+		if err != nil {
+			return err
+		}
+		n, err := toInt64(deleted)
+		if err != nil {
+			return err
+		}
+		if n == 0 {
+			return errKeyNotFound
+		}
+		return nil
+	})
+	if err != nil {
+		writeKeyError(writer, err)
+		return
+	}
+
+	writeJSONResponse(writer, HTTPResponse{Status: HTTPStatusSuccess, Data: "OK"}, http.StatusOK)
+}
+
+// listKeys lists the keys matching the pattern with SCAN, the cursor of the page being the cursor
+// of SCAN. As with SCAN, a page may hold fewer keys than requested, even none, before the last one.
+func (s *HTTPServer) listKeys(writer http.ResponseWriter, request *http.Request) {
+	query := request.URL.Query()
+	pattern := query.Get("match")
+	if pattern == "" {
+		pattern = "*"
+	}
+
+	cursor := "0"
+	if c := query.Get("cursor"); c != "" {
+		if _, err := strconv.ParseUint(c, 10, 64); err != nil {
+			writeErrorResponse(writer, http.StatusBadRequest, errInvalidCursor.Error(), "")
+			return
+		}
+		cursor = c
+	}
+
+	count := defaultKeysPageSize
+	if c := query.Get("count"); c != "" {
+		var err error
+		if count, err = strconv.Atoi(c); err != nil || count < 1 || count > maxKeysPageSize {
+			writeErrorResponse(writer, http.StatusBadRequest, errInvalidPageSize.Error(), "")
+			return
+		}
+	}
+
+	page := KeysPage{Keys: []string{}, Cursor: "0"}
+	err := s.executeKeyCommands(request, func(execute keyCommandExecutor) error {
+		result, err := execute(&cmd.DiceDBCmd{
+			Cmd:  "SCAN",
+			Args: []string{cursor, "MATCH", pattern, "COUNT", strconv.Itoa(count)},
+		})
+		if err != nil {
+			return err
+		}
+		reply, ok := result.([]interface{})
+		if !ok || len(reply) != 2 {
+			return fmt.Errorf("unexpected SCAN reply %v", result)
+		}
+		page.Cursor = fmt.Sprint(reply[0])
+		page.Keys = append(page.Keys, toStrings(reply[1])...)
+		return nil
+	})
+	if err != nil {
+		writeKeyError(writer, err)
+		return
+	}
+
+	writeCacheableResponse(writer, request, HTTPResponse{Status: HTTPStatusSuccess, Data: page})
+}
+
+// keyCommandExecutor executes a command of a key route and returns its decoded result. The error
+// replied by the command is returned as a *keyCommandError.
+type keyCommandExecutor func(diceDBCmd *cmd.DiceDBCmd) (interface{}, error)
+
+// executeKeyCommands executes the commands of a key route on a single command handler. Every
+// command must be allowed to the user of the request.
+func (s *HTTPServer) executeKeyCommands(request *http.Request, commands func(execute keyCommandExecutor) error) error {
+	cmdHandler := s.cmdHandlers.acquire()
+	defer s.cmdHandlers.release(cmdHandler)
+
+	return commands(func(diceDBCmd *cmd.DiceDBCmd) (interface{}, error) {
+		if err := authorizeCommand(requestUser(request), diceDBCmd.Cmd); err != nil {
+			return nil, err
+		}
+		return executeKeyCommand(request.Context(), cmdHandler, diceDBCmd)
+	})
+}
+
+func executeKeyCommand(ctx context.Context, cmdHandler *commandhandler.BaseCommandHandler, diceDBCmd *cmd.DiceDBCmd) (interface{}, error) {
+	result, err := cmdHandler.ExecuteCommand(ctx, diceDBCmd)
+	value, isErr, err := decodeCommandResult(diceDBCmd, result, err)
+	if err != nil {
+		return nil, err
+	}
+	if isErr {
+		return nil, &keyCommandError{msg: fmt.Sprint(value)}
+	}
+	return value, nil
+}
+
+// renderKeyValue converts the result of the read command of a key into the value of its resource.
+func renderKeyValue(keyType string, value interface{}) (interface{}, error) {
+	switch keyType {
+	case "hash":
+		fields := toStrings(value)
+		hash := make(map[string]string, len(fields)/2)
+		for i := 0; i+1 < len(fields); i += 2 {
+			hash[fields[i]] = fields[i+1]
+		}
+		return hash, nil
+	case "zset":
+		members := toStrings(value)
+		scores := make(map[string]float64, len(members)/2)
+		for i := 0; i+1 < len(members); i += 2 {
+			score, err := strconv.ParseFloat(members[i+1], 64)
+			if err != nil {
+				return nil, err
+			}
+			scores[members[i]] = score
+		}
+		return scores, nil
+	case "list", "set":
+		return toStrings(value), nil
+	case "ReJSON-RL":
+		doc := fmt.Sprint(value)
+		if !json.Valid([]byte(doc)) {
+			return nil, fmt.Errorf("invalid JSON document")
+		}
+		return json.RawMessage(doc), nil
+	default:
+		return value, nil
+	}
+}
+
+// toInt64 converts the integer result of a command, whose type depends on the evaluation of the command.
+func toInt64(value interface{}) (int64, error) {
+	return strconv.ParseInt(fmt.Sprint(value), 10, 64)
+}
+
+// toStrings converts the array result of a command into strings.
+func toStrings(value interface{}) []string {
+	var values []interface{}
+	switch v := value.(type) {
+	case []string:
+		return v
+	case []interface{}:
+		values = v
+	}
+
+	strs := make([]string, len(values))
+	for i, v := range values {
+		strs[i] = fmt.Sprint(v)
+	}
+	return strs
+}
+
+// writeKeyError writes the error of a key route with the matching status code.
+func writeKeyError(writer http.ResponseWriter, err error) {
+	var cmdErr *keyCommandError
+	switch {
+	case errors.Is(err, errKeyNotFound):
+		writeErrorResponse(writer, http.StatusNotFound, err.Error(), "")
+	case errors.Is(err, errUnsupportedValue):
+		writeErrorResponse(writer, http.StatusNotImplemented, err.Error(), "")
+	case errors.As(err, &cmdErr):
+		writeErrorResponse(writer, http.StatusBadRequest, cmdErr.msg, "")
+	case strings.HasPrefix(err.Error(), "NOPERM"):
+		writeErrorResponse(writer, http.StatusForbidden, err.Error(), "")
+	default:
+		writeErrorResponse(writer, http.StatusInternalServerError, "Internal Server Error",
+			"Error executing key route", slog.Any("error", err))
+	}
+}
+
+// writeCacheableResponse writes the response with a strong ETag of its body, or 304 Not Modified
+// when the If-None-Match header of the request matches the ETag.
+func writeCacheableResponse(writer http.ResponseWriter, request *http.Request, response HTTPResponse) {
+	body, err := json.Marshal(response)
+	if err != nil {
+		writeErrorResponse(writer, http.StatusInternalServerError, "Internal Server Error",
+			"Error marshaling response", slog.Any("error", err))
+		return
+	}
+
+	sum := sha256.Sum256(body)
+	etag := `"` + hex.EncodeToString(sum[:16]) + `"`
+	writer.Header().Set("ETag", etag)
+	writer.Header().Set("Cache-Control", "no-cache")
+
+	if etagMatches(request.Header.Get("If-None-Match"), etag) {
+		writer.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	writer.Header().Set("Content-Type", "application/json")
+	writer.WriteHeader(http.StatusOK)
+	if _, err := writer.Write(body); err != nil {
+		slog.Error("Error writing response", "error", err)
+	}
+}
+
+// etagMatches reports whether the If-None-Match header matches the ETag, comparing the tags weakly
+// as required for If-None-Match.
+func etagMatches(ifNoneMatch, etag string) bool {
+	for _, tag := range strings.Split(ifNoneMatch, ",") {
+		tag = strings.TrimSpace(tag)
+		if tag == "*" || strings.TrimPrefix(tag, "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
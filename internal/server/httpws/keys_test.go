@@ -0,0 +1,186 @@
+// Copyright (c) 2022-present, DiceDB contributors
+// All rights reserved. Licensed under the BSD 3-Clause License. See LICENSE file in the project root for full license information.
+
+package httpws
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/dicedb/dice/internal/cmd"
+	"github.com/dicedb/dice/internal/shard"
+	dstore "github.com/dicedb/dice/internal/store"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestKeysServer(t *testing.T) *HTTPServer {
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	shardManager := shard.NewShardManager(2, nil, make(chan error, 1))
+	go shardManager.Run(ctx)
+
+	server := NewHTTPServer(shardManager, nil, make(chan error, 1), nil)
+	t.Cleanup(server.cmdHandlers.close)
+	return server
+}
+
+func serveKeysRequest(server *HTTPServer, method, target string, body io.Reader, header http.Header) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, target, body)
+	for name, values := range header {
+		req.Header[name] = values
+	}
+	rec := httptest.NewRecorder()
+	server.httpServer.Handler.ServeHTTP(rec, req)
+	return rec
+}
+
+func decodeKeysResponse(t *testing.T, rec *httptest.ResponseRecorder, data interface{}) {
+	var resp struct {
+		Status string          `json:"status"`
+		Data   json.RawMessage `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	require.Equal(t, HTTPStatusSuccess, resp.Status, rec.Body.String())
+	require.NoError(t, json.Unmarshal(resp.Data, data))
+}
+
+func TestDiceHTTPKeysHandlerTypedValues(t *testing.T) {
+	server := newTestKeysServer(t)
+
+	cmdHandler := server.cmdHandlers.acquire()
+	for _, c := range []*cmd.DiceDBCmd{
+		{Cmd: "HSET", Args: []string{"h", "f1", "v1", "f2", "v2"}},
+		{Cmd: "RPUSH", Args: []string{"l", "a", "b"}},
+		{Cmd: "SADD", Args: []string{"s", "m"}},
+		{Cmd: "ZADD", Args: []string{"z", "1.5", "m1", "2", "m2"}},
+	} {
+		_, err := cmdHandler.ExecuteCommand(context.Background(), c)
+		require.NoError(t, err)
+	}
+	server.cmdHandlers.release(cmdHandler)
+
+	tests := []struct {
+		key           string
+		expectedType  string
+		expectedValue string
+	}{
+		{key: "h", expectedType: "hash", expectedValue: `{"f1": "v1", "f2": "v2"}`},
+		{key: "l", expectedType: "list", expectedValue: `["a", "b"]`},
+		{key: "s", expectedType: "set", expectedValue: `["m"]`},
+		{key: "z", expectedType: "zset", expectedValue: `{"m1": 1.5, "m2": 2}`},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.expectedType, func(t *testing.T) {
+			rec := serveKeysRequest(server, http.MethodGet, KeysPathPrefix+tc.key, http.NoBody, nil)
+			require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+			var resource struct {
+				Key   string          `json:"key"`
+				Type  string          `json:"type"`
+				Value json.RawMessage `json:"value"`
+			}
+			decodeKeysResponse(t, rec, &resource)
+			assert.Equal(t, tc.key, resource.Key)
+			assert.Equal(t, tc.expectedType, resource.Type)
+			assert.JSONEq(t, tc.expectedValue, string(resource.Value))
+			assert.Empty(t, rec.Header().Get(TTLHeader))
+		})
+	}
+}
+
+func TestDiceHTTPKeysHandlerReadWriteDelete(t *testing.T) {
+	server := newTestKeysServer(t)
+
+	rec := serveKeysRequest(server, http.MethodPut, "/keys/User:1?ttl=100", strings.NewReader("hello"), nil)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+	// Keys are case-sensitive, unlike the rest of the path
+	rec = serveKeysRequest(server, http.MethodGet, "/KEYS/User:1", http.NoBody, nil)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	var resource KeyResource
+	decodeKeysResponse(t, rec, &resource)
+	assert.Equal(t, KeyResource{Key: "User:1", Type: "string", Value: "hello"}, resource)
+	// The TTL is rounded down, so it reads 99 once a millisecond has elapsed
+	assert.Contains(t, []string{"99", "100"}, rec.Header().Get(TTLHeader))
+
+	etag := rec.Header().Get("ETag")
+	require.NotEmpty(t, etag)
+	rec = serveKeysRequest(server, http.MethodGet, "/keys/User:1", http.NoBody, http.Header{"If-None-Match": {`"other", W/` + etag}})
+	assert.Equal(t, http.StatusNotModified, rec.Code)
+	assert.Empty(t, rec.Body.String())
+
+	// Writing the key changes its ETag
+	rec = serveKeysRequest(server, http.MethodPut, "/keys/User:1", strings.NewReader(`{"a": [1, 2]}`),
+		http.Header{"Content-Type": {"application/json; charset=utf-8"}})
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+	rec = serveKeysRequest(server, http.MethodGet, "/keys/User:1", http.NoBody, http.Header{"If-None-Match": {etag}})
+	require.Equal(t, http.StatusOK, rec.Code)
+	var jsonResource struct {
+		Type  string          `json:"type"`
+		Value json.RawMessage `json:"value"`
+	}
+	decodeKeysResponse(t, rec, &jsonResource)
+	assert.Equal(t, "ReJSON-RL", jsonResource.Type)
+	assert.JSONEq(t, `{"a": [1, 2]}`, string(jsonResource.Value))
+	// A value written without ttl does not expire
+	assert.Empty(t, rec.Header().Get(TTLHeader))
+
+	rec = serveKeysRequest(server, http.MethodDelete, "/keys/User:1", http.NoBody, nil)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	rec = serveKeysRequest(server, http.MethodDelete, "/keys/User:1", http.NoBody, nil)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	rec = serveKeysRequest(server, http.MethodGet, "/keys/User:1", http.NoBody, nil)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+
+	rec = serveKeysRequest(server, http.MethodPut, "/keys/k?ttl=-1", strings.NewReader("v"), nil)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	rec = serveKeysRequest(server, http.MethodPost, "/keys/k", strings.NewReader("v"), nil)
+	assert.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+}
+
+func TestDiceHTTPKeysHandlerListKeys(t *testing.T) {
+	server := newTestKeysServer(t)
+
+	for _, key := range []string{"user:3", "user:1", "order:1", "user:2"} {
+		rec := serveKeysRequest(server, http.MethodPut, KeysPathPrefix+key, strings.NewReader("v"), nil)
+		require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	}
+
+	var keys []string
+	cursor := "0"
+	for {
+		rec := serveKeysRequest(server, http.MethodGet, "/keys?match=user:*&count=2&cursor="+cursor, http.NoBody, nil)
+		require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+		var page KeysPage
+		decodeKeysResponse(t, rec, &page)
+		keys = append(keys, page.Keys...)
+		if cursor = page.Cursor; cursor == "0" {
+			break
+		}
+	}
+	assert.ElementsMatch(t, []string{"user:1", "user:2", "user:3"}, keys)
+
+	// The cursor is the one of SCAN, pointing to a shard of the server
+	rec := serveKeysRequest(server, http.MethodGet, "/keys?cursor="+strconv.FormatUint(dstore.EncodeScanCursor(200, 0), 10), http.NoBody, nil)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	rec = serveKeysRequest(server, http.MethodGet, "/keys?cursor=x", http.NoBody, nil)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	// POST /keys is still the KEYS command
+	rec = serveKeysRequest(server, http.MethodPost, KeysPath, strings.NewReader(`{"key": "order:*"}`), nil)
+	require.Equal(t, http.StatusOK, rec.Code)
+	var orderKeys []string
+	decodeKeysResponse(t, rec, &orderKeys)
+	assert.Equal(t, []string{"order:1"}, orderKeys)
+}
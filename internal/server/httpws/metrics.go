@@ -0,0 +1,206 @@
+// Copyright (c) 2022-present, DiceDB contributors
+// All rights reserved. Licensed under the BSD 3-Clause License. See LICENSE file in the project root for full license information.
+
+package httpws
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/dicedb/dice/internal/commandhandler"
+	"github.com/dicedb/dice/internal/iothread"
+	"github.com/dicedb/dice/internal/observability"
+	"github.com/dicedb/dice/internal/shard"
+	"github.com/dicedb/dice/internal/wal"
+)
+
+const (
+	// MetricsPath serves the metrics of the server in the Prometheus text exposition format.
+	MetricsPath = "/metrics"
+
+	metricsContentType = "text/plain; version=0.0.4; charset=utf-8"
+)
+
+// serverMetrics holds the state of the server exported by the metrics.
+type serverMetrics struct {
+	commands            map[string]commandhandler.CommandStats
+	shards              []shardMetrics
+	connectedClients    uint32
+	connectionsReceived uint64
+	watchSubscriptions  int64
+	watchFingerprints   int64
+	watchSlowConsumers  uint64
+	wal                 wal.Stats
+
+	// disconnections is the number of clients of each class disconnected for exceeding their
+	// output buffer limit.
+	disconnections map[iothread.ClientClass]uint64
+}
+
+// shardMetrics holds the state of a shard exported by the metrics.
+type shardMetrics struct {
+	stats      shard.ShardStats
+	queueDepth int
+}
+
+// collectMetrics gathers the state of the server and of its shards.
+func collectMetrics(shardManager *shard.ShardManager) serverMetrics {
+	metrics := serverMetrics{
+		commands:       commandhandler.GetCommandStats(),
+		disconnections: make(map[iothread.ClientClass]uint64),
+	}
+	for _, class := range iothread.ClientClasses {
+		metrics.disconnections[class] = iothread.OutputBufferDisconnections(class)
+	}
+	if shardManager != nil {
+		for i := 0; i < int(shardManager.GetShardCount()); i++ {
+			s := shardManager.GetShard(shard.ShardID(i))
+			metrics.shards = append(metrics.shards, shardMetrics{stats: s.Stats(), queueDepth: s.QueueDepth()})
+		}
+	}
+
+	info := commandhandler.GetServerInfo()
+	if info.IOThreads != nil {
+		metrics.connectedClients = info.IOThreads.IOThreadCount()
+		metrics.connectionsReceived = info.IOThreads.TotalIOThreadCount()
+	}
+	if info.Watches != nil {
+		metrics.watchSubscriptions = info.Watches.Subscriptions()
+		metrics.watchFingerprints = info.Watches.Fingerprints()
+		metrics.watchSlowConsumers = info.Watches.SlowConsumers()
+	}
+	if info.WAL != nil {
+		metrics.wal = info.WAL.Stats()
+	}
+	return metrics
+}
+
+// String renders the metrics in the Prometheus text exposition format.
+func (m *serverMetrics) String() string {
+	w := &metricsWriter{}
+
+	names := make([]string, 0, len(m.commands))
+	for name := range m.commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	w.family("dicedb_commands_total", "counter", "Number of calls of each command.")
+	for _, name := range names {
+		w.sample("dicedb_commands_total", label("command", name), float64(m.commands[name].Calls))
+	}
+	w.family("dicedb_commands_failed_total", "counter", "Number of calls of each command which returned an error.")
+	for _, name := range names {
+		w.sample("dicedb_commands_failed_total", label("command", name), float64(m.commands[name].FailedCalls))
+	}
+	w.family("dicedb_command_duration_seconds", "histogram", "Time taken by the calls of each command.")
+	for _, name := range names {
+		w.histogram("dicedb_command_duration_seconds", label("command", name), m.commands[name].Latency)
+	}
+
+	w.family("dicedb_shard_keys", "gauge", "Number of keys held in memory by each shard.")
+	for i := range m.shards {
+		w.sample("dicedb_shard_keys", label("shard", strconv.Itoa(i)), float64(m.shards[i].stats.Keys))
+	}
+	w.family("dicedb_shard_queue_depth", "gauge", "Number of requests waiting to be processed by each shard.")
+	for i := range m.shards {
+		w.sample("dicedb_shard_queue_depth", label("shard", strconv.Itoa(i)), float64(m.shards[i].queueDepth))
+	}
+	w.family("dicedb_expired_keys_total", "counter", "Number of keys deleted by each shard once expired.")
+	for i := range m.shards {
+		w.sample("dicedb_expired_keys_total", label("shard", strconv.Itoa(i)), float64(m.shards[i].stats.Store.ExpiredKeys))
+	}
+	w.family("dicedb_evicted_keys_total", "counter", "Number of keys evicted by each shard.")
+	for i := range m.shards {
+		w.sample("dicedb_evicted_keys_total", label("shard", strconv.Itoa(i)), float64(m.shards[i].stats.Store.EvictedKeys))
+	}
+
+	w.family("dicedb_connected_clients", "gauge", "Number of clients connected.")
+	w.sample("dicedb_connected_clients", "", float64(m.connectedClients))
+	w.family("dicedb_connections_received_total", "counter", "Number of connections accepted.")
+	w.sample("dicedb_connections_received_total", "", float64(m.connectionsReceived))
+	w.family("dicedb_output_buffer_disconnections_total", "counter",
+		"Number of clients of each class disconnected for exceeding their output buffer limit.")
+	for _, class := range iothread.ClientClasses {
+		w.sample("dicedb_output_buffer_disconnections_total", label("class", class.String()), float64(m.disconnections[class]))
+	}
+
+	w.family("dicedb_watch_subscriptions", "gauge", "Number of active watch subscriptions.")
+	w.sample("dicedb_watch_subscriptions", "", float64(m.watchSubscriptions))
+	w.family("dicedb_watch_fingerprints", "gauge", "Number of distinct watched queries.")
+	w.sample("dicedb_watch_fingerprints", "", float64(m.watchFingerprints))
+	w.family("dicedb_watch_slow_consumers_total", "counter", "Number of times a watch subscriber fell behind its notifications.")
+	w.sample("dicedb_watch_slow_consumers_total", "", float64(m.watchSlowConsumers))
+
+	w.family("dicedb_wal_logged_bytes_total", "counter", "Number of bytes written to the write-ahead log.")
+	w.sample("dicedb_wal_logged_bytes_total", "", float64(m.wal.LoggedBytes))
+	w.family("dicedb_wal_sync_duration_seconds", "histogram", "Time taken by the syncs of the write-ahead log.")
+	w.histogram("dicedb_wal_sync_duration_seconds", "", m.wal.SyncLatency)
+
+	return w.b.String()
+}
+
+// metricsWriter writes metric families in the Prometheus text exposition format.
+type metricsWriter struct {
+	b strings.Builder
+}
+
+// family writes the HELP and TYPE lines of a metric family, followed by its samples.
+func (w *metricsWriter) family(name, typ, help string) {
+	fmt.Fprintf(&w.b, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, typ)
+}
+
+// sample writes a sample of a metric, its labels being rendered by label and joined by commas.
+func (w *metricsWriter) sample(name, labels string, value float64) {
+	w.b.WriteString(name)
+	if labels != "" {
+		w.b.WriteString("{" + labels + "}")
+	}
+	w.b.WriteString(" " + strconv.FormatFloat(value, 'g', -1, 64) + "\n")
+}
+
+// histogram writes the cumulative buckets of a histogram, followed by its sum and count.
+func (w *metricsWriter) histogram(name, labels string, h observability.HistogramSnapshot) {
+	prefix := labels
+	if prefix != "" {
+		prefix += ","
+	}
+
+	var cumulative uint64
+	for i, bound := range observability.LatencyBuckets {
+		if i < len(h.Counts) {
+			cumulative += h.Counts[i]
+		}
+		w.sample(name+"_bucket", prefix+label("le", strconv.FormatFloat(bound, 'g', -1, 64)), float64(cumulative))
+	}
+	w.sample(name+"_bucket", prefix+label("le", "+Inf"), float64(h.Count()))
+	w.sample(name+"_sum", labels, h.Sum.Seconds())
+	w.sample(name+"_count", labels, float64(h.Count()))
+}
+
+// labelValueReplacer escapes the label values as required by the exposition format.
+var labelValueReplacer = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// label renders a label of a sample.
+func label(name, value string) string {
+	return name + `="` + labelValueReplacer.Replace(value) + `"`
+}
+
+// DiceHTTPMetricsHandler serves the metrics of the server for Prometheus to scrape.
+func (s *HTTPServer) DiceHTTPMetricsHandler(writer http.ResponseWriter, request *http.Request) {
+	if request.Method != http.MethodGet {
+		writer.Header().Set("Allow", http.MethodGet)
+		writeErrorResponse(writer, http.StatusMethodNotAllowed, "metrics must be fetched with GET", "")
+		return
+	}
+
+	metrics := collectMetrics(s.shardManager)
+	writer.Header().Set("Content-Type", metricsContentType)
+	if _, err := writer.Write([]byte(metrics.String())); err != nil {
+		slog.Debug("Error writing metrics", slog.Any("error", err))
+	}
+}
@@ -0,0 +1,97 @@
+// Copyright (c) 2022-present, DiceDB contributors
+// All rights reserved. Licensed under the BSD 3-Clause License. See LICENSE file in the project root for full license information.
+
+package httpws
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/dicedb/dice/internal/commandhandler"
+	"github.com/dicedb/dice/internal/iothread"
+	"github.com/dicedb/dice/internal/observability"
+	"github.com/dicedb/dice/internal/shard"
+	dstore "github.com/dicedb/dice/internal/store"
+	"github.com/dicedb/dice/internal/wal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServerMetricsString(t *testing.T) {
+	var latency observability.Histogram
+	latency.Observe(50 * time.Microsecond)
+	latency.Observe(3 * time.Millisecond)
+	latency.Observe(5 * time.Second)
+
+	metrics := serverMetrics{
+		commands: map[string]commandhandler.CommandStats{
+			"set": {Calls: 3, FailedCalls: 1, Latency: latency.Snapshot()},
+		},
+		shards: []shardMetrics{
+			{stats: shard.ShardStats{Keys: 7, Store: dstore.Stats{ExpiredKeys: 2, EvictedKeys: 4}}, queueDepth: 1},
+			{stats: shard.ShardStats{Keys: 5}},
+		},
+		connectedClients:    2,
+		connectionsReceived: 9,
+		watchSubscriptions:  3,
+		watchFingerprints:   1,
+		watchSlowConsumers:  4,
+		wal:                 wal.Stats{LoggedBytes: 1024},
+		disconnections:      map[iothread.ClientClass]uint64{iothread.ClientClassWatch: 2},
+	}
+	text := metrics.String()
+
+	for _, line := range []string{
+		"# HELP dicedb_commands_total Number of calls of each command.",
+		"# TYPE dicedb_commands_total counter",
+		`dicedb_commands_total{command="set"} 3`,
+		`dicedb_commands_failed_total{command="set"} 1`,
+		"# TYPE dicedb_command_duration_seconds histogram",
+		`dicedb_command_duration_seconds_bucket{command="set",le="0.0001"} 1`,
+		`dicedb_command_duration_seconds_bucket{command="set",le="0.0025"} 1`,
+		`dicedb_command_duration_seconds_bucket{command="set",le="0.005"} 2`,
+		`dicedb_command_duration_seconds_bucket{command="set",le="2.5"} 2`,
+		`dicedb_command_duration_seconds_bucket{command="set",le="+Inf"} 3`,
+		`dicedb_command_duration_seconds_sum{command="set"} 5.00305`,
+		`dicedb_command_duration_seconds_count{command="set"} 3`,
+		`dicedb_shard_keys{shard="0"} 7`,
+		`dicedb_shard_keys{shard="1"} 5`,
+		`dicedb_shard_queue_depth{shard="0"} 1`,
+		`dicedb_expired_keys_total{shard="0"} 2`,
+		`dicedb_evicted_keys_total{shard="0"} 4`,
+		"dicedb_connected_clients 2",
+		"dicedb_connections_received_total 9",
+		"dicedb_watch_subscriptions 3",
+		"dicedb_watch_fingerprints 1",
+		"dicedb_watch_slow_consumers_total 4",
+		`dicedb_output_buffer_disconnections_total{class="normal"} 0`,
+		`dicedb_output_buffer_disconnections_total{class="watch"} 2`,
+		"dicedb_wal_logged_bytes_total 1024",
+		`dicedb_wal_sync_duration_seconds_bucket{le="+Inf"} 0`,
+		"dicedb_wal_sync_duration_seconds_count 0",
+	} {
+		assert.Contains(t, text, line+"\n")
+	}
+}
+
+func TestMetricsLabelEscaping(t *testing.T) {
+	assert.Equal(t, `command="a\"b\\c\nd"`, label("command", "a\"b\\c\nd"))
+}
+
+func TestDiceHTTPMetricsHandler(t *testing.T) {
+	server := NewHTTPServer(nil, nil, nil, nil)
+
+	req := httptest.NewRequest(http.MethodGet, MetricsPath, http.NoBody)
+	rec := httptest.NewRecorder()
+	server.httpServer.Handler.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, metricsContentType, rec.Header().Get("Content-Type"))
+	assert.Contains(t, rec.Body.String(), "# TYPE dicedb_connected_clients gauge\n")
+
+	req = httptest.NewRequest(http.MethodPost, MetricsPath, http.NoBody)
+	rec = httptest.NewRecorder()
+	server.httpServer.Handler.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+}
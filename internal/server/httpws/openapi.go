@@ -0,0 +1,566 @@
+// Copyright (c) 2022-present, DiceDB contributors
+// All rights reserved. Licensed under the BSD 3-Clause License. See LICENSE file in the project root for full license information.
+
+package httpws
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/dicedb/dice/internal/commandhandler"
+	"github.com/dicedb/dice/internal/eval"
+)
+
+// OpenAPIPath is the path serving the OpenAPI document of the HTTP API.
+const OpenAPIPath = "/openapi.json"
+
+// OpenAPIDocument is an OpenAPI 3.0 document describing the HTTP API.
+type OpenAPIDocument struct {
+	OpenAPI    string                      `json:"openapi"`
+	Info       OpenAPIInfo                 `json:"info"`
+	Paths      map[string]*OpenAPIPathItem `json:"paths"`
+	Components OpenAPIComponents           `json:"components"`
+}
+
+type OpenAPIInfo struct {
+	Title   string `json:"title"`
+	Version string `json:"version"`
+}
+
+type OpenAPIComponents struct {
+	Schemas map[string]*OpenAPISchema `json:"schemas"`
+}
+
+type OpenAPIPathItem struct {
+	Get    *OpenAPIOperation `json:"get,omitempty"`
+	Put    *OpenAPIOperation `json:"put,omitempty"`
+	Post   *OpenAPIOperation `json:"post,omitempty"`
+	Delete *OpenAPIOperation `json:"delete,omitempty"`
+}
+
+type OpenAPIOperation struct {
+	OperationID string                      `json:"operationId"`
+	Summary     string                      `json:"summary,omitempty"`
+	Description string                      `json:"description,omitempty"`
+	Tags        []string                    `json:"tags,omitempty"`
+	Parameters  []*OpenAPIParameter         `json:"parameters,omitempty"`
+	RequestBody *OpenAPIRequestBody         `json:"requestBody,omitempty"`
+	Responses   map[string]*OpenAPIResponse `json:"responses"`
+}
+
+type OpenAPIParameter struct {
+	Name        string         `json:"name"`
+	In          string         `json:"in"`
+	Description string         `json:"description,omitempty"`
+	Required    bool           `json:"required,omitempty"`
+	Schema      *OpenAPISchema `json:"schema"`
+}
+
+type OpenAPIRequestBody struct {
+	Required bool                         `json:"required,omitempty"`
+	Content  map[string]*OpenAPIMediaType `json:"content"`
+}
+
+type OpenAPIResponse struct {
+	Description string                       `json:"description"`
+	Content     map[string]*OpenAPIMediaType `json:"content,omitempty"`
+}
+
+type OpenAPIMediaType struct {
+	Schema *OpenAPISchema `json:"schema"`
+}
+
+// OpenAPISchema is the subset of the OpenAPI schema object used to describe the commands.
+type OpenAPISchema struct {
+	Ref                  string                    `json:"$ref,omitempty"`
+	Type                 string                    `json:"type,omitempty"`
+	Description          string                    `json:"description,omitempty"`
+	Nullable             bool                      `json:"nullable,omitempty"`
+	Enum                 []string                  `json:"enum,omitempty"`
+	Items                *OpenAPISchema            `json:"items,omitempty"`
+	MinItems             *int                      `json:"minItems,omitempty"`
+	Properties           map[string]*OpenAPISchema `json:"properties,omitempty"`
+	Required             []string                  `json:"required,omitempty"`
+	AdditionalProperties interface{}               `json:"additionalProperties,omitempty"`
+	OneOf                []*OpenAPISchema          `json:"oneOf,omitempty"`
+}
+
+// commandArg is a property of the JSON body of a command request. ParseHTTPRequest maps the
+// properties onto the arguments of the command in the order of getPriorityKeys.
+type commandArg struct {
+	name     string
+	required bool
+}
+
+var (
+	// bodyArgSchemas are the schemas of the body properties that ParseHTTPRequest knows about.
+	bodyArgSchemas = map[string]*OpenAPISchema{
+		Key:         {Type: "string", Description: "Key the command operates on"},
+		Keys:        {Type: "array", Items: &OpenAPISchema{Type: "string"}, Description: "Keys the command operates on"},
+		Field:       {Type: "string", Description: "Field of the hash"},
+		Path:        {Type: "string", Description: "JSONPath within the JSON document"},
+		JSON:        {Type: "object", Description: "JSON document, sent as its JSON encoding"},
+		Index:       {Type: "integer"},
+		Value:       {Description: "Value of the command. Values that are not strings are sent as their JSON encoding"},
+		Values:      {Type: "array", Items: &OpenAPISchema{}, Description: "Positional arguments following the key, in order"},
+		Seconds:     {Type: "integer", Description: "Duration in seconds"},
+		User:        {Type: "string"},
+		Password:    {Type: "string"},
+		KeyValues:   {Type: "object", AdditionalProperties: &OpenAPISchema{}, Description: "Pairs of keys, or fields, and values"},
+		QwatchQuery: {Type: "string", Description: "Query to watch"},
+		Offset:      {Type: "integer"},
+		Member:      {Type: "string"},
+		Members:     {Type: "array", Items: &OpenAPISchema{Type: "string"}},
+	}
+
+	// commandArgs names the body properties of the commands whose arguments cannot be inferred
+	// from their arity and key specs.
+	commandArgs = map[string][]commandArg{
+		"SET":         {{Key, true}, {Value, true}},
+		"GETSET":      {{Key, true}, {Value, true}},
+		"APPEND":      {{Key, true}, {Value, true}},
+		"INCRBY":      {{Key, true}, {Value, true}},
+		"DECRBY":      {{Key, true}, {Value, true}},
+		"INCRBYFLOAT": {{Key, true}, {Value, true}},
+		"SETEX":       {{Key, true}, {Values, true}},
+		"MSET":        {{KeyValues, true}},
+		"HSET":        {{Key, true}, {KeyValues, true}},
+		"HMSET":       {{Key, true}, {KeyValues, true}},
+		"HSETNX":      {{Key, true}, {KeyValues, true}},
+		"HGET":        {{Key, true}, {Field, true}},
+		"HEXISTS":     {{Key, true}, {Field, true}},
+		"HSTRLEN":     {{Key, true}, {Field, true}},
+		"HDEL":        {{Key, true}, {Values, true}},
+		"HMGET":       {{Key, true}, {Values, true}},
+		"EXPIRE":      {{Key, true}, {Seconds, true}},
+		"EXPIREAT":    {{Key, true}, {Seconds, true}},
+		"GETEX":       {{Key, true}},
+		"SETBIT":      {{Key, true}, {Values, true}},
+		"GETBIT":      {{Key, true}, {Offset, true}},
+		"SADD":        {{Key, true}, {Members, true}},
+		"SREM":        {{Key, true}, {Members, true}},
+		"KEYS":        {{Key, true}},
+		"AUTH":        {{User, false}, {Password, true}},
+		"JSON.SET":    {{Key, true}, {Path, true}, {JSON, true}},
+		"JSON.GET":    {{Key, true}, {Path, false}},
+		"JSON.DEL":    {{Key, true}, {Path, false}},
+		"JSON.FORGET": {{Key, true}, {Path, false}},
+		"JSON.TYPE":   {{Key, true}, {Path, false}},
+		"JSON.CLEAR":  {{Key, true}, {Path, false}},
+		"JSON.MGET":   {{Keys, true}, {Path, true}},
+		"JSON.INGEST": {{JSON, true}},
+		"Q.WATCH":     {{QwatchQuery, true}},
+	}
+
+	// commandResponseData are the schemas of the data returned by the commands, for the commands
+	// whose result is not a plain value.
+	commandResponseData = map[string]*OpenAPISchema{
+		"GET":      {Type: "string", Nullable: true},
+		"SET":      {Type: "string", Nullable: true, Enum: []string{"OK"}},
+		"MSET":     {Type: "string", Enum: []string{"OK"}},
+		"MGET":     {Type: "array", Items: &OpenAPISchema{Type: "string", Nullable: true}},
+		"DEL":      {Type: "integer"},
+		"EXISTS":   {Type: "integer"},
+		"TOUCH":    {Type: "integer"},
+		"DBSIZE":   {Type: "integer"},
+		"KEYS":     {Type: "array", Items: &OpenAPISchema{Type: "string"}},
+		"TTL":      {Type: "integer", Description: "-2 if the key does not exist, -1 if it has no expiry"},
+		"PTTL":     {Type: "integer", Description: "-2 if the key does not exist, -1 if it has no expiry"},
+		"EXPIRE":   {Type: "integer"},
+		"INCR":     {Type: "integer"},
+		"INCRBY":   {Type: "integer"},
+		"DECR":     {Type: "integer"},
+		"DECRBY":   {Type: "integer"},
+		"HGET":     {Type: "string", Nullable: true},
+		"HSET":     {Type: "integer"},
+		"HLEN":     {Type: "integer"},
+		"HKEYS":    {Type: "array", Items: &OpenAPISchema{Type: "string"}},
+		"HVALS":    {Type: "array", Items: &OpenAPISchema{Type: "string"}},
+		"SMEMBERS": {Type: "array", Items: &OpenAPISchema{Type: "string"}},
+		"SCARD":    {Type: "integer"},
+		"ZCARD":    {Type: "integer"},
+		"PFCOUNT":  {Type: "integer"},
+		"TYPE":     {Type: "string"},
+		"PING":     {Type: "string"},
+		"JSON.GET": {Type: "string", Nullable: true, Description: "JSON encoding of the value at the path"},
+	}
+
+	openAPIOnce     sync.Once
+	openAPIDocument []byte
+)
+
+// BuildOpenAPIDocument generates the OpenAPI document of the HTTP API from the command registry.
+// Every command is a POST /{command} operation, whose request body is described by the names of
+// its arguments, or, if they are not known, by its arity and key specs.
+func BuildOpenAPIDocument() *OpenAPIDocument {
+	doc := &OpenAPIDocument{
+		OpenAPI: "3.0.3",
+		Info:    OpenAPIInfo{Title: "DiceDB HTTP API", Version: "1.0.0"},
+		Paths:   make(map[string]*OpenAPIPathItem),
+		Components: OpenAPIComponents{Schemas: map[string]*OpenAPISchema{
+			"Status": {Type: "string", Enum: []string{HTTPStatusSuccess, HTTPStatusError}},
+			"HTTPResponse": {
+				Type: "object",
+				Properties: map[string]*OpenAPISchema{
+					"status": {Ref: "#/components/schemas/Status"},
+					"data":   {Description: "Result of the command, or the error message"},
+				},
+				Required: []string{"status", "data"},
+			},
+			"BatchCommand": {
+				Type: "object",
+				Properties: map[string]*OpenAPISchema{
+					"cmd":  {Type: "string"},
+					"args": {Type: "array", Items: &OpenAPISchema{}},
+				},
+				Required: []string{"cmd"},
+			},
+		}},
+	}
+
+	for _, name := range httpCommandNames() {
+		meta := eval.DiceCmds[name]
+		op := commandOperation(name, meta)
+		doc.Paths["/"+strings.ToLower(name)] = &OpenAPIPathItem{Post: op}
+
+		for _, sub := range meta.SubCommands {
+			subOp := *op
+			subOp.OperationID = fmt.Sprintf("%s_%s", op.OperationID, strings.ToLower(sub))
+			subOp.Summary = fmt.Sprintf("%s %s", name, sub)
+			doc.Paths[fmt.Sprintf("/%s/%s", strings.ToLower(name), strings.ToLower(sub))] = &OpenAPIPathItem{Post: &subOp}
+		}
+	}
+
+	doc.Paths["/batch"] = &OpenAPIPathItem{Post: batchOperation()}
+	doc.Paths[WatchPathPrefix+"{command}"] = &OpenAPIPathItem{Get: watchOperation()}
+	// POST /keys remains the KEYS command
+	doc.Paths[KeysPath].Get = listKeysOperation()
+	doc.Paths[KeysPathPrefix+"{key}"] = keyPathItem()
+	doc.Paths["/health"] = &OpenAPIPathItem{Get: &OpenAPIOperation{
+		OperationID: "health",
+		Summary:     "Health check",
+		Responses:   map[string]*OpenAPIResponse{"200": {Description: "The server is up"}},
+	}}
+	doc.Paths[MetricsPath] = &OpenAPIPathItem{Get: &OpenAPIOperation{
+		OperationID: "metrics",
+		Summary:     "Metrics in the Prometheus text exposition format",
+		Responses:   map[string]*OpenAPIResponse{"200": {Description: "The metrics of the server"}},
+	}}
+
+	return doc
+}
+
+// httpCommandNames returns the sorted names of the commands served by the command endpoint.
+func httpCommandNames() []string {
+	names := make(map[string]struct{})
+	for name := range eval.DiceCmds {
+		names[name] = struct{}{}
+	}
+	for name := range commandhandler.CommandsMeta {
+		names[name] = struct{}{}
+	}
+
+	sorted := make([]string, 0, len(names))
+	for name := range names {
+		if unimplementedCommands[name] {
+			continue
+		}
+		// Watch commands are streamed from the watch endpoint
+		if cmdType := commandhandler.CommandsMeta[name].CmdType; cmdType == commandhandler.Watch || cmdType == commandhandler.Unwatch {
+			continue
+		}
+		sorted = append(sorted, name)
+	}
+	sort.Strings(sorted)
+	return sorted
+}
+
+func commandOperation(name string, meta eval.DiceCmdMeta) *OpenAPIOperation {
+	op := &OpenAPIOperation{
+		OperationID: strings.ToLower(strings.ReplaceAll(name, ".", "_")),
+		Summary:     name,
+		Description: strings.TrimSpace(meta.Info),
+		Tags:        []string{commandTag(name)},
+		Responses: map[string]*OpenAPIResponse{
+			"200": jsonResponse("Result of the command", commandResponseSchema(name)),
+			"400": jsonResponse("Invalid request", &OpenAPISchema{Ref: "#/components/schemas/HTTPResponse"}),
+		},
+	}
+
+	if body := commandRequestSchema(name, meta); body != nil {
+		op.RequestBody = &OpenAPIRequestBody{
+			Required: len(body.Required) > 0,
+			Content:  map[string]*OpenAPIMediaType{"application/json": {Schema: body}},
+		}
+	}
+	return op
+}
+
+// commandTag groups the commands by their module, e.g. JSON for JSON.SET.
+func commandTag(name string) string {
+	if module, _, found := strings.Cut(name, "."); found {
+		return module
+	}
+	return "core"
+}
+
+// commandRequestSchema describes the JSON body of the command. Options without a value, e.g. NX,
+// are sent as properties set to "true". It returns nil for the commands without arguments.
+func commandRequestSchema(name string, meta eval.DiceCmdMeta) *OpenAPISchema {
+	args, ok := commandArgs[name]
+	if !ok {
+		args = inferCommandArgs(name, meta)
+	}
+	if len(args) == 0 {
+		return nil
+	}
+
+	schema := &OpenAPISchema{
+		Type:       "object",
+		Properties: make(map[string]*OpenAPISchema, len(args)),
+		AdditionalProperties: &OpenAPISchema{
+			Description: `Options of the command, e.g. {"nx": "true"} or {"ex": 10}`,
+		},
+	}
+	for _, arg := range args {
+		argSchema := *bodyArgSchemas[arg.name]
+		if arg.name == Values && !ok {
+			setItemsBounds(&argSchema, meta.Arity)
+		}
+		schema.Properties[arg.name] = &argSchema
+		if arg.required {
+			schema.Required = append(schema.Required, arg.name)
+		}
+	}
+	return schema
+}
+
+// inferCommandArgs derives the body properties of the command from its arity, which counts the
+// command name, and its key specs. The arguments following the key are positional values.
+func inferCommandArgs(name string, meta eval.DiceCmdMeta) []commandArg {
+	cmdType := commandhandler.CommandsMeta[name].CmdType
+	if meta.LastKey == -1 || cmdType == commandhandler.MultiShard {
+		return []commandArg{{Keys, true}}
+	}
+
+	var args []commandArg
+	minValues := minCommandArgs(meta.Arity)
+	if meta.BeginIndex > 0 || cmdType == commandhandler.SingleShard {
+		args = append(args, commandArg{Key, meta.Arity != 1})
+		minValues--
+	}
+	// Commands on all the shards that are not in the registry, e.g. DBSIZE, take no arguments
+	if meta.Arity != 1 && (meta.Arity != 0 || cmdType != commandhandler.AllShard) {
+		args = append(args, commandArg{Values, minValues > 0})
+	}
+	return args
+}
+
+// minCommandArgs returns the minimum number of arguments of the command. The arity of some fixed
+// arity commands is understated, so it is only used as a lower bound.
+func minCommandArgs(arity int) int {
+	if arity < 0 {
+		return -arity - 1
+	}
+	return max(arity-1, 0)
+}
+
+// setItemsBounds sets the minimum number of positional values, given the arity of the command.
+func setItemsBounds(schema *OpenAPISchema, arity int) {
+	// The command name and the key are not positional values
+	if n := minCommandArgs(arity) - 1; n > 0 {
+		schema.MinItems = &n
+	}
+}
+
+func commandResponseSchema(name string) *OpenAPISchema {
+	data, ok := commandResponseData[name]
+	if !ok {
+		data = &OpenAPISchema{Description: "Result of the command"}
+	}
+
+	return &OpenAPISchema{
+		Type: "object",
+		Properties: map[string]*OpenAPISchema{
+			"status": {Ref: "#/components/schemas/Status"},
+			"data": {OneOf: []*OpenAPISchema{
+				data,
+				{Type: "string", Description: "Error message, if the status is error"},
+			}},
+		},
+		Required: []string{"status", "data"},
+	}
+}
+
+func jsonResponse(description string, schema *OpenAPISchema) *OpenAPIResponse {
+	return &OpenAPIResponse{
+		Description: description,
+		Content:     map[string]*OpenAPIMediaType{"application/json": {Schema: schema}},
+	}
+}
+
+func batchOperation() *OpenAPIOperation {
+	commands := &OpenAPISchema{Type: "array", Items: &OpenAPISchema{Ref: "#/components/schemas/BatchCommand"}}
+	return &OpenAPIOperation{
+		OperationID: "batch",
+		Summary:     "Execute many commands in one request",
+		Description: "Executes the commands in parallel on their shards. Atomic batches execute back to back on a single shard.",
+		RequestBody: &OpenAPIRequestBody{
+			Required: true,
+			Content: map[string]*OpenAPIMediaType{"application/json": {Schema: &OpenAPISchema{OneOf: []*OpenAPISchema{
+				commands,
+				{
+					Type: "object",
+					Properties: map[string]*OpenAPISchema{
+						"commands": commands,
+						"atomic":   {Type: "boolean"},
+					},
+					Required: []string{"commands"},
+				},
+			}}}},
+		},
+		Responses: map[string]*OpenAPIResponse{
+			"200": jsonResponse("Result of every command, in the order of the request", &OpenAPISchema{
+				Type: "object",
+				Properties: map[string]*OpenAPISchema{
+					"status": {Ref: "#/components/schemas/Status"},
+					"data":   {Type: "array", Items: &OpenAPISchema{Ref: "#/components/schemas/HTTPResponse"}},
+				},
+			}),
+			"400": jsonResponse("Invalid batch", &OpenAPISchema{Ref: "#/components/schemas/HTTPResponse"}),
+		},
+	}
+}
+
+func watchOperation() *OpenAPIOperation {
+	var watchCommands []string
+	for name, meta := range commandhandler.CommandsMeta {
+		if meta.CmdType == commandhandler.Watch {
+			watchCommands = append(watchCommands, strings.ToLower(name))
+		}
+	}
+	sort.Strings(watchCommands)
+
+	return &OpenAPIOperation{
+		OperationID: "watch",
+		Summary:     "Stream the result of a .WATCH command as Server-Sent Events",
+		Parameters: []*OpenAPIParameter{
+			{Name: "command", In: "path", Required: true, Schema: &OpenAPISchema{Type: "string", Enum: watchCommands}},
+			{Name: Key, In: "query", Required: true, Schema: &OpenAPISchema{Type: "string"}},
+			{Name: "args", In: "query", Description: "Arguments following the key, in order", Schema: &OpenAPISchema{
+				Type: "array", Items: &OpenAPISchema{Type: "string"},
+			}},
+		},
+		Responses: map[string]*OpenAPIResponse{
+			"200": {Description: "Stream of watch events", Content: map[string]*OpenAPIMediaType{
+				"text/event-stream": {Schema: &OpenAPISchema{Type: "string"}},
+			}},
+			"400": jsonResponse("Invalid watch request", &OpenAPISchema{Ref: "#/components/schemas/HTTPResponse"}),
+		},
+	}
+}
+
+func listKeysOperation() *OpenAPIOperation {
+	return &OpenAPIOperation{
+		OperationID: "listKeys",
+		Summary:     "List the keys matching a pattern, a page at a time",
+		Parameters: []*OpenAPIParameter{
+			{Name: "match", In: "query", Description: "Glob-style pattern of the keys", Schema: &OpenAPISchema{Type: "string"}},
+			{Name: "cursor", In: "query", Description: "Cursor returned with the previous page", Schema: &OpenAPISchema{Type: "string"}},
+			{Name: "count", In: "query", Description: "Number of keys of the page", Schema: &OpenAPISchema{Type: "integer"}},
+		},
+		Responses: map[string]*OpenAPIResponse{
+			"200": jsonResponse("Page of keys, with the cursor of the next page or \"0\" once done", &OpenAPISchema{
+				Type: "object",
+				Properties: map[string]*OpenAPISchema{
+					"status": {Ref: "#/components/schemas/Status"},
+					"data": {Type: "object", Properties: map[string]*OpenAPISchema{
+						"keys":   {Type: "array", Items: &OpenAPISchema{Type: "string"}},
+						"cursor": {Type: "string"},
+					}},
+				},
+			}),
+			"304": {Description: "The page matches the ETag of If-None-Match"},
+		},
+	}
+}
+
+func keyPathItem() *OpenAPIPathItem {
+	key := &OpenAPIParameter{Name: "key", In: "path", Required: true, Schema: &OpenAPISchema{Type: "string"}}
+	httpResponse := &OpenAPISchema{Ref: "#/components/schemas/HTTPResponse"}
+	return &OpenAPIPathItem{
+		Get: &OpenAPIOperation{
+			OperationID: "getKey",
+			Summary:     "Read the value of a key, rendered after its type",
+			Parameters:  []*OpenAPIParameter{key},
+			Responses: map[string]*OpenAPIResponse{
+				"200": jsonResponse("Value of the key. Its TTL in seconds is sent in the "+TTLHeader+" header", &OpenAPISchema{
+					Type: "object",
+					Properties: map[string]*OpenAPISchema{
+						"status": {Ref: "#/components/schemas/Status"},
+						"data": {Type: "object", Properties: map[string]*OpenAPISchema{
+							"key":   {Type: "string"},
+							"type":  {Type: "string", Enum: []string{"string", "list", "set", "hash", "zset", "ReJSON-RL"}},
+							"value": {},
+						}},
+					},
+				}),
+				"304": {Description: "The value matches the ETag of If-None-Match"},
+				"404": jsonResponse("The key does not exist", httpResponse),
+			},
+		},
+		Put: &OpenAPIOperation{
+			OperationID: "putKey",
+			Summary:     "Write the value of a key, stored as a JSON document for application/json bodies",
+			Parameters: []*OpenAPIParameter{key, {
+				Name: "ttl", In: "query", Description: "Time to live in seconds", Schema: &OpenAPISchema{Type: "integer"},
+			}},
+			RequestBody: &OpenAPIRequestBody{Required: true, Content: map[string]*OpenAPIMediaType{
+				"text/plain":       {Schema: &OpenAPISchema{Type: "string"}},
+				"application/json": {Schema: &OpenAPISchema{}},
+			}},
+			Responses: map[string]*OpenAPIResponse{"200": jsonResponse("The value is written", httpResponse)},
+		},
+		Delete: &OpenAPIOperation{
+			OperationID: "deleteKey",
+			Summary:     "Delete a key",
+			Parameters:  []*OpenAPIParameter{key},
+			Responses: map[string]*OpenAPIResponse{
+				"200": jsonResponse("The key is deleted", httpResponse),
+				"404": jsonResponse("The key does not exist", httpResponse),
+			},
+		},
+	}
+}
+
+// DiceHTTPOpenAPIHandler serves the OpenAPI document of the HTTP API. The document only depends on
+// the command registry, so it is generated once.
+func (s *HTTPServer) DiceHTTPOpenAPIHandler(writer http.ResponseWriter, request *http.Request) {
+	if request.Method != http.MethodGet {
+		writer.Header().Set("Allow", http.MethodGet)
+		writeErrorResponse(writer, http.StatusMethodNotAllowed, "openapi document must be fetched with GET", "")
+		return
+	}
+
+	openAPIOnce.Do(func() {
+		var err error
+		openAPIDocument, err = json.Marshal(BuildOpenAPIDocument())
+		if err != nil {
+			slog.Error("Error marshaling OpenAPI document", slog.Any("error", err))
+		}
+	})
+	if openAPIDocument == nil {
+		writeErrorResponse(writer, http.StatusInternalServerError, "Internal Server Error", "")
+		return
+	}
+
+	writer.Header().Set("Content-Type", "application/json")
+	if _, err := writer.Write(openAPIDocument); err != nil {
+		slog.Debug("Error writing OpenAPI document", slog.Any("error", err))
+	}
+}
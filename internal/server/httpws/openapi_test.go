@@ -0,0 +1,74 @@
+// Copyright (c) 2022-present, DiceDB contributors
+// All rights reserved. Licensed under the BSD 3-Clause License. See LICENSE file in the project root for full license information.
+
+package httpws
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/dicedb/dice/internal/eval"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildOpenAPIDocument(t *testing.T) {
+	doc := BuildOpenAPIDocument()
+
+	for name := range eval.DiceCmds {
+		if unimplementedCommands[name] {
+			continue
+		}
+		_, ok := doc.Paths["/"+strings.ToLower(name)]
+		assert.True(t, ok, "missing path for %s", name)
+	}
+
+	assert.NotContains(t, doc.Paths, "/get.watch")
+	assert.Contains(t, doc.Paths, "/batch")
+	assert.Contains(t, doc.Paths, WatchPathPrefix+"{command}")
+	assert.Contains(t, doc.Paths, "/command/docs")
+	assert.NotNil(t, doc.Paths[KeysPath].Get)
+	assert.NotNil(t, doc.Paths[KeysPathPrefix+"{key}"].Put)
+
+	set := doc.Paths["/set"].Post
+	body := set.RequestBody.Content["application/json"].Schema
+	assert.Equal(t, []string{Key, Value}, body.Required)
+	assert.Equal(t, "SET", set.Summary)
+
+	// Positional arguments are bounded by the arity of the command
+	hincrBy := doc.Paths["/hincrby"].Post.RequestBody.Content["application/json"].Schema
+	assert.Equal(t, []string{Key, Values}, hincrBy.Required)
+	require.NotNil(t, hincrBy.Properties[Values].MinItems)
+	assert.Equal(t, 2, *hincrBy.Properties[Values].MinItems)
+
+	assert.Equal(t, []string{Keys}, doc.Paths["/mget"].Post.RequestBody.Content["application/json"].Schema.Required)
+	assert.Equal(t, []string{Key}, doc.Paths["/incr"].Post.RequestBody.Content["application/json"].Schema.Required)
+	assert.Nil(t, doc.Paths["/dbsize"].Post.RequestBody)
+
+	data := doc.Paths["/get"].Post.Responses["200"].Content["application/json"].Schema.Properties["data"]
+	assert.Equal(t, "string", data.OneOf[0].Type)
+	assert.True(t, data.OneOf[0].Nullable)
+}
+
+func TestDiceHTTPOpenAPIHandler(t *testing.T) {
+	server := NewHTTPServer(nil, nil, nil, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/OpenAPI.json", http.NoBody)
+	rec := httptest.NewRecorder()
+	server.httpServer.Handler.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+
+	var doc map[string]interface{}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &doc))
+	assert.Equal(t, "3.0.3", doc["openapi"])
+	assert.Contains(t, doc["paths"], "/hset")
+
+	req = httptest.NewRequest(http.MethodPost, "/openapi.json", http.NoBody)
+	rec = httptest.NewRecorder()
+	server.httpServer.Handler.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+}
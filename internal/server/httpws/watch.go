@@ -0,0 +1,380 @@
+// Copyright (c) 2022-present, DiceDB contributors
+// All rights reserved. Licensed under the BSD 3-Clause License. See LICENSE file in the project root for full license information.
+
+package httpws
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/dicedb/dice/config"
+	"github.com/dicedb/dice/internal/cmd"
+	"github.com/dicedb/dice/internal/commandhandler"
+	"github.com/dicedb/dice/internal/iothread"
+	"github.com/dicedb/dice/internal/ops"
+	"github.com/dicedb/dice/internal/shard"
+	"github.com/dicedb/dice/internal/watchmanager"
+)
+
+const (
+	WatchPathPrefix = "/watch/"
+	watchSuffix     = ".WATCH"
+
+	// watchKeepAliveInterval is how often a comment is sent on idle streams, so that proxies
+	// do not close them.
+	watchKeepAliveInterval = 15 * time.Second
+
+	// watchCloseTimeout is how long a client disconnected for exceeding its output buffer limit is
+	// given to read the error ending its stream.
+	watchCloseTimeout = time.Second
+)
+
+var errWatchDisabled = errors.New("watch is disabled on this server")
+
+// HTTPWatchEvent is the data of a Server-Sent Event streamed to watch clients. The first event
+// holds the result of the command at subscription time, the following ones its updated result.
+type HTTPWatchEvent struct {
+	Cmd         string      `json:"cmd"`
+	Fingerprint string      `json:"fingerprint"`
+	Status      string      `json:"status"`
+	Data        interface{} `json:"data"`
+}
+
+// ParseHTTPWatchRequest parses a GET /watch/{CMD} request into the command being watched,
+// i.e. without its .WATCH suffix. The key is given by the key query parameter and any other
+// argument, in order, by repeated args query parameters, e.g.
+// /watch/zrange.watch?key=leaderboard&args=0&args=10.
+func ParseHTTPWatchRequest(r *http.Request) (*cmd.DiceDBCmd, error) {
+	name := strings.ToUpper(strings.Trim(strings.TrimPrefix(r.URL.Path, WatchPathPrefix), "/"))
+	if name == "" || strings.Contains(name, "/") {
+		return nil, errors.New("invalid watch command")
+	}
+	if !strings.HasSuffix(name, watchSuffix) {
+		name += watchSuffix
+	}
+
+	meta, ok := commandhandler.CommandsMeta[name]
+	if !ok || meta.CmdType != commandhandler.Watch {
+		return nil, fmt.Errorf("command %s cannot be watched", name)
+	}
+
+	query := r.URL.Query()
+	var args []string
+	if key := query.Get(Key); key != "" {
+		args = append(args, key)
+	}
+	args = append(args, query["args"]...)
+	if len(args) == 0 {
+		return nil, fmt.Errorf("missing key for %s", name)
+	}
+
+	return &cmd.DiceDBCmd{
+		Cmd:  strings.TrimSuffix(name, watchSuffix),
+		Args: args,
+	}, nil
+}
+
+// DiceHTTPWatchHandler streams the result of a .WATCH command as Server-Sent Events. The command
+// is executed once when the client subscribes and again every time the watched key changes. The
+// subscription is removed from the watch manager when the client disconnects.
+func (s *HTTPServer) DiceHTTPWatchHandler(writer http.ResponseWriter, request *http.Request) {
+	if request.Method != http.MethodGet {
+		writer.Header().Set("Allow", http.MethodGet)
+		writeErrorResponse(writer, http.StatusMethodNotAllowed, "watch requests must use GET", "")
+		return
+	}
+
+	if s.cmdWatchSubscriptionChan == nil {
+		writeErrorResponse(writer, http.StatusServiceUnavailable, errWatchDisabled.Error(), "")
+		return
+	}
+
+	watchCmd, err := ParseHTTPWatchRequest(request)
+	if err != nil {
+		writeErrorResponse(writer, http.StatusBadRequest, err.Error(),
+			"Error parsing HTTP watch request", slog.Any("error", err))
+		return
+	}
+
+	if !authorizeRequest(writer, request, watchCmd.Cmd) {
+		return
+	}
+
+	flusher, ok := writer.(http.Flusher)
+	if !ok {
+		writeErrorResponse(writer, http.StatusInternalServerError, "Streaming unsupported", "")
+		return
+	}
+
+	ctx := request.Context()
+	handlerID := fmt.Sprintf("httpWatch-%d", commandhandler.GenerateUniqueRequestID())
+	responseChan := make(chan *ops.StoreResponse)
+	s.shardManager.RegisterCommandHandler(handlerID, responseChan, nil)
+	defer s.shardManager.UnregisterCommandHandler(handlerID)
+
+	initial, err := executeWatchCommand(ctx, s.shardManager, handlerID, responseChan, watchCmd)
+	if err != nil {
+		writeErrorResponse(writer, http.StatusGatewayTimeout, "Timed out executing the watch command",
+			"Error executing watch command", slog.String("cmd", watchCmd.Cmd), slog.Any("error", err))
+		return
+	}
+
+	adhocReqChan := make(chan *cmd.DiceDBCmd, config.DiceConfig.Performance.AdhocReqChanBufSize)
+	subscribed := sendWatchSubscription(ctx, s.cmdWatchSubscriptionChan, s.shutdownChan, watchmanager.WatchSubscription{
+		Subscribe:    true,
+		WatchCmd:     watchCmd,
+		AdhocReqChan: adhocReqChan,
+	})
+	if !subscribed {
+		return
+	}
+	defer sendWatchSubscription(context.Background(), s.cmdWatchSubscriptionChan, s.shutdownChan, watchmanager.WatchSubscription{
+		Subscribe:    false,
+		AdhocReqChan: adhocReqChan,
+		Fingerprint:  watchCmd.GetFingerprint(),
+	})
+
+	writer.Header().Set("Content-Type", "text/event-stream")
+	writer.Header().Set("Cache-Control", "no-cache")
+	writer.Header().Set("Connection", "keep-alive")
+	writer.WriteHeader(http.StatusOK)
+
+	var eventID uint64
+	stream := newWatchStream(func(data []byte) error {
+		if _, err := writer.Write(data); err != nil {
+			return err
+		}
+		flusher.Flush()
+		return nil
+	})
+	defer stream.close()
+
+	pushEvent := func(resp *ops.StoreResponse) error {
+		eventID++
+		data, err := encodeWatchEvent(eventID, watchCmd, resp)
+		if err != nil {
+			return err
+		}
+		return stream.push(data)
+	}
+	// closeWithError ends the stream of a client that exceeded its output buffer limit with an error
+	// event, giving up after watchCloseTimeout if the client does not read it.
+	closeWithError := func(err error) {
+		slog.Warn("Disconnecting HTTP watch client for exceeding its output buffer limit",
+			slog.String("cmd", watchCmd.Cmd), slog.Int("pending_bytes", stream.outputBuffer.Size()))
+		controller := http.NewResponseController(writer)
+		if deadlineErr := controller.SetWriteDeadline(time.Now().Add(watchCloseTimeout)); deadlineErr != nil {
+			slog.Debug("Error setting write deadline", slog.Any("error", deadlineErr))
+		}
+		stream.close()
+		if _, writeErr := fmt.Fprintf(writer, "event: error\ndata: ERR %s\n\n", err.Error()); writeErr == nil {
+			flusher.Flush()
+		}
+	}
+
+	if err := pushEvent(initial); err != nil {
+		closeWithError(err)
+		return
+	}
+
+	keepAlive := time.NewTicker(watchKeepAliveInterval)
+	defer keepAlive.Stop()
+	limitTicker := time.NewTicker(iothread.OutputBufferCheckInterval)
+	defer limitTicker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			slog.Debug("HTTP watch client disconnected", slog.String("cmd", watchCmd.Cmd))
+			return
+		case <-s.shutdownChan:
+			return
+		case <-stream.done:
+			return
+		case <-keepAlive.C:
+			if err := stream.push([]byte(": keepalive\n\n")); err != nil {
+				closeWithError(err)
+				return
+			}
+		case <-limitTicker.C:
+			if err := stream.checkLimit(); err != nil {
+				closeWithError(err)
+				return
+			}
+		case diceDBCmd := <-adhocReqChan:
+			resp, err := executeWatchCommand(ctx, s.shardManager, handlerID, responseChan, diceDBCmd)
+			if err != nil {
+				slog.Warn("Error executing watch command", slog.String("cmd", diceDBCmd.Cmd), slog.Any("error", err))
+				continue
+			}
+			if err := pushEvent(resp); err != nil {
+				if errors.Is(err, iothread.ErrOutputBufferLimitReached) {
+					closeWithError(err)
+				} else {
+					slog.Error("Error encoding watch event", slog.String("cmd", diceDBCmd.Cmd), slog.Any("error", err))
+				}
+				return
+			}
+		}
+	}
+}
+
+// watchStream writes the updates of a watch subscription from its own goroutine, so that the watched
+// command is executed again as soon as it is notified even if the client is slow to read. The updates
+// wait in an output buffer bounded by the output buffer limits of the watch clients, the stream being
+// closed with an error once the client exceeds them.
+type watchStream struct {
+	outputBuffer *iothread.OutputBuffer
+	write        func(data []byte) error
+	stop         chan struct{}
+	done         chan struct{} // done is closed once the writer is stopped, or failed to write
+}
+
+func newWatchStream(write func(data []byte) error) *watchStream {
+	stream := &watchStream{
+		outputBuffer: iothread.NewOutputBuffer(),
+		write:        write,
+		stop:         make(chan struct{}),
+		done:         make(chan struct{}),
+	}
+	go stream.run()
+	return stream
+}
+
+// run writes the queued updates in order until the stream is closed.
+func (s *watchStream) run() {
+	defer close(s.done)
+
+	for {
+		select {
+		case <-s.stop:
+			return
+		case <-s.outputBuffer.Notify():
+		}
+
+		for {
+			select {
+			case <-s.stop:
+				return
+			default:
+			}
+
+			data, ok := s.outputBuffer.Pop()
+			if !ok {
+				break
+			}
+			if err := s.write(data); err != nil {
+				slog.Debug("Error writing watch update", slog.Any("error", err))
+				return
+			}
+		}
+	}
+}
+
+// push queues an update. It returns iothread.ErrOutputBufferLimitReached once the updates pending
+// exceed the output buffer limit of the watch clients.
+func (s *watchStream) push(data []byte) error {
+	s.outputBuffer.Push(data)
+	return s.checkLimit()
+}
+
+// checkLimit returns iothread.ErrOutputBufferLimitReached if the updates pending exceed the output
+// buffer limit of the watch clients, counting the client as disconnected. It is called periodically
+// as well, the soft limit being exceeded only after its duration.
+func (s *watchStream) checkLimit() error {
+	if !s.outputBuffer.Exceeds(iothread.OutputBufferLimitFor(iothread.ClientClassWatch), time.Now()) {
+		return nil
+	}
+	iothread.RecordOutputBufferDisconnection(iothread.ClientClassWatch)
+	return iothread.ErrOutputBufferLimitReached
+}
+
+// close stops the writer once its current write is done, dropping the updates still pending.
+func (s *watchStream) close() {
+	select {
+	case <-s.stop:
+	default:
+		close(s.stop)
+	}
+	<-s.done
+}
+
+// executeWatchCommand executes the watched command on the shard owning its key.
+func executeWatchCommand(ctx context.Context, shardManager *shard.ShardManager, handlerID string,
+	responseChan chan *ops.StoreResponse, diceDBCmd *cmd.DiceDBCmd) (*ops.StoreResponse, error) {
+	execCtx, cancel := commandhandler.RequestContext(ctx, diceDBCmd)
+	defer cancel()
+
+	shardID, reqChan := shardManager.GetShardInfo(diceDBCmd.GetKey())
+	requestID := commandhandler.GenerateUniqueRequestID()
+	op := &ops.StoreOp{
+		RequestID:    requestID,
+		Cmd:          diceDBCmd,
+		CmdHandlerID: handlerID,
+		ShardID:      shardID,
+		HTTPOp:       true,
+		Ctx:          execCtx,
+	}
+
+	select {
+	case reqChan <- op:
+	case <-execCtx.Done():
+		return nil, execCtx.Err()
+	}
+
+	for {
+		select {
+		case resp := <-responseChan:
+			if resp.RequestID == requestID {
+				return resp, nil
+			}
+		case <-execCtx.Done():
+			return nil, execCtx.Err()
+		}
+	}
+}
+
+// sendWatchSubscription hands the subscription over to the watch manager. It returns false if the
+// request is done, or the server shuts down, before the watch manager accepts it.
+func sendWatchSubscription(ctx context.Context, cmdWatchSubscriptionChan chan watchmanager.WatchSubscription,
+	shutdownChan chan struct{}, sub watchmanager.WatchSubscription) bool {
+	timer := time.NewTimer(commandhandler.DefaultRequestTimeout())
+	defer timer.Stop()
+
+	select {
+	case cmdWatchSubscriptionChan <- sub:
+		return true
+	case <-ctx.Done():
+	case <-shutdownChan:
+	case <-timer.C:
+		slog.Warn("Timed out sending watch subscription", slog.Bool("subscribe", sub.Subscribe))
+	}
+	return false
+}
+
+// encodeWatchEvent encodes the result of the watched command as a Server-Sent Event.
+func encodeWatchEvent(eventID uint64, watchCmd *cmd.DiceDBCmd, resp *ops.StoreResponse) ([]byte, error) {
+	httpResponse, err := buildHTTPResponse(watchCmd, resp.EvalResponse.Result, resp.EvalResponse.Error)
+	if err != nil {
+		slog.Error("Error decoding watch response", slog.String("cmd", watchCmd.Cmd), slog.Any("error", err))
+		httpResponse = HTTPResponse{Status: HTTPStatusError, Data: "Internal Server Error"}
+	}
+
+	data, err := json.Marshal(HTTPWatchEvent{
+		Cmd:         watchCmd.Cmd + watchSuffix,
+		Fingerprint: fmt.Sprintf("%d", watchCmd.GetFingerprint()),
+		Status:      httpResponse.Status,
+		Data:        httpResponse.Data,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return []byte(fmt.Sprintf("id: %d\ndata: %s\n\n", eventID, data)), nil
+}
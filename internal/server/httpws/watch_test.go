@@ -0,0 +1,185 @@
+// Copyright (c) 2022-present, DiceDB contributors
+// All rights reserved. Licensed under the BSD 3-Clause License. See LICENSE file in the project root for full license information.
+
+package httpws
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dicedb/dice/config"
+	"github.com/dicedb/dice/internal/iothread"
+	"github.com/dicedb/dice/internal/shard"
+	dstore "github.com/dicedb/dice/internal/store"
+	"github.com/dicedb/dice/internal/watchmanager"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseHTTPWatchRequest(t *testing.T) {
+	tests := []struct {
+		name         string
+		url          string
+		expectedCmd  string
+		expectedArgs []string
+		expectedErr  bool
+	}{
+		{
+			name:         "command with suffix",
+			url:          "/watch/get.watch?key=k1",
+			expectedCmd:  "GET",
+			expectedArgs: []string{"k1"},
+		},
+		{
+			name:         "command without suffix",
+			url:          "/watch/zrange?key=z&args=0&args=-1",
+			expectedCmd:  "ZRANGE",
+			expectedArgs: []string{"z", "0", "-1"},
+		},
+		{
+			name:        "command that cannot be watched",
+			url:         "/watch/set?key=k1",
+			expectedErr: true,
+		},
+		{
+			name:        "missing key",
+			url:         "/watch/pfcount.watch",
+			expectedErr: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tc.url, http.NoBody)
+			diceDBCmd, err := ParseHTTPWatchRequest(req)
+			if tc.expectedErr {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedCmd, diceDBCmd.Cmd)
+			assert.Equal(t, tc.expectedArgs, diceDBCmd.Args)
+		})
+	}
+}
+
+func TestDiceHTTPWatchHandler(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	cmdWatchChan := make(chan dstore.CmdWatchEvent, 16)
+	cmdWatchSubscriptionChan := make(chan watchmanager.WatchSubscription)
+	shardManager := shard.NewShardManager(2, cmdWatchChan, make(chan error, 1))
+	go shardManager.Run(ctx)
+	go watchmanager.NewManager(cmdWatchSubscriptionChan, cmdWatchChan).Run(ctx)
+
+	server := NewHTTPServer(shardManager, cmdWatchSubscriptionChan, nil, nil)
+	ts := httptest.NewServer(server.httpServer.Handler)
+	defer ts.Close()
+
+	set := func(value string) {
+		body := `[{"cmd": "SET", "args": ["k1", "` + value + `"]}]`
+		resp, err := http.Post(ts.URL+"/batch", "application/json", strings.NewReader(body))
+		require.NoError(t, err)
+		resp.Body.Close()
+	}
+	set("v1")
+
+	reqCtx, cancelReq := context.WithTimeout(ctx, 5*time.Second)
+	defer cancelReq()
+	req, err := http.NewRequestWithContext(reqCtx, http.MethodGet, ts.URL+"/watch/GET.WATCH?key=k1", http.NoBody)
+	require.NoError(t, err)
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+
+	reader := bufio.NewReader(resp.Body)
+	readEvent := func() (string, HTTPWatchEvent) {
+		var id string
+		var event HTTPWatchEvent
+		for {
+			line, err := reader.ReadString('\n')
+			require.NoError(t, err)
+			line = strings.TrimRight(line, "\n")
+			switch {
+			case line == "":
+				return id, event
+			case strings.HasPrefix(line, "id: "):
+				id = strings.TrimPrefix(line, "id: ")
+			case strings.HasPrefix(line, "data: "):
+				require.NoError(t, json.Unmarshal([]byte(strings.TrimPrefix(line, "data: ")), &event))
+			}
+		}
+	}
+
+	id, event := readEvent()
+	assert.Equal(t, "1", id)
+	assert.Equal(t, "GET.WATCH", event.Cmd)
+	assert.Equal(t, HTTPStatusSuccess, event.Status)
+	assert.Equal(t, "v1", event.Data)
+
+	set("v2")
+	id, event = readEvent()
+	assert.Equal(t, "2", id)
+	assert.Equal(t, "v2", event.Data)
+}
+
+func TestDiceHTTPWatchHandlerWatchDisabled(t *testing.T) {
+	server := NewHTTPServer(nil, nil, nil, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/watch/get.watch?key=k1", http.NoBody)
+	rec := httptest.NewRecorder()
+	server.httpServer.Handler.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+}
+
+func TestWatchStreamOutputBufferLimit(t *testing.T) {
+	limits := config.DiceConfig.ClientOutputBufferLimit
+	defer func() { config.DiceConfig.ClientOutputBufferLimit = limits }()
+	config.DiceConfig.ClientOutputBufferLimit.WatchHardLimit = 10
+	config.DiceConfig.ClientOutputBufferLimit.WatchSoftLimit = 0
+
+	// the writer is stuck on the first update, the next ones pile up in the output buffer
+	unblock := make(chan struct{})
+	var written []string
+	stream := newWatchStream(func(data []byte) error {
+		<-unblock
+		written = append(written, string(data))
+		return nil
+	})
+
+	before := iothread.OutputBufferDisconnections(iothread.ClientClassWatch)
+	require.NoError(t, stream.push([]byte("first")))
+	require.Eventually(t, func() bool { return stream.outputBuffer.Size() == 0 }, time.Second, time.Millisecond)
+	require.NoError(t, stream.push([]byte("second")))
+	assert.ErrorIs(t, stream.push([]byte("third")), iothread.ErrOutputBufferLimitReached)
+	assert.Equal(t, before+1, iothread.OutputBufferDisconnections(iothread.ClientClassWatch))
+
+	// closing the stream drops the updates still pending once the current write is done
+	closed := make(chan struct{})
+	go func() {
+		stream.close()
+		close(closed)
+	}()
+	require.Eventually(t, func() bool {
+		select {
+		case <-stream.stop:
+			return true
+		default:
+			return false
+		}
+	}, time.Second, time.Millisecond)
+	close(unblock)
+	<-closed
+	assert.Equal(t, []string{"first"}, written)
+}
@@ -0,0 +1,481 @@
+// Copyright (c) 2022-present, DiceDB contributors
+// All rights reserved. Licensed under the BSD 3-Clause License. See LICENSE file in the project root for full license information.
+
+package httpws
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dicedb/dice/config"
+	"github.com/dicedb/dice/internal/auth"
+	"github.com/dicedb/dice/internal/clientio"
+	"github.com/dicedb/dice/internal/cmd"
+	"github.com/dicedb/dice/internal/commandhandler"
+	"github.com/dicedb/dice/internal/iothread"
+	"github.com/dicedb/dice/internal/ops"
+	"github.com/dicedb/dice/internal/watchmanager"
+	"github.com/gorilla/websocket"
+)
+
+const (
+	// UnwatchSubscription cancels a watch subscription of the connection, given its subscription ID.
+	UnwatchSubscription = "UNWATCH"
+
+	// maxInflightWebsocketRequests is the number of requests of a connection executed concurrently.
+	// The connection is not read any further while all of them are in flight.
+	maxInflightWebsocketRequests = 128
+)
+
+var (
+	errMissingRequestID    = errors.New("missing request id")
+	errUnknownSubscription = errors.New("no such subscription")
+)
+
+// WebsocketRequest is a request of the multiplexed WebSocket protocol, e.g.
+// {"id": 1, "cmd": "SET", "args": ["k1", "v1"]}. The ID is chosen by the client and echoed in the
+// reply, so that requests can be pipelined and their replies matched when they complete out of order.
+// Arguments that are not strings are sent as their JSON encoding.
+type WebsocketRequest struct {
+	ID   json.RawMessage `json:"id"`
+	Cmd  string          `json:"cmd"`
+	Args []interface{}   `json:"args"`
+}
+
+// WebsocketReply is a message sent to clients of the multiplexed WebSocket protocol. A reply to a
+// request carries the ID of the request and either its result or its error. An update of a watch
+// subscription carries the subscription ID instead, which is also returned in the reply to the
+// request that created the subscription.
+type WebsocketReply struct {
+	ID           json.RawMessage `json:"id,omitempty"`
+	Subscription string          `json:"subscription,omitempty"`
+	Result       json.RawMessage `json:"result,omitempty"`
+	Error        string          `json:"error,omitempty"`
+}
+
+// isWebsocketRequest reports whether the message is a JSON request envelope rather than a plain
+// text command.
+func isWebsocketRequest(msg []byte) bool {
+	trimmed := bytes.TrimSpace(msg)
+	return len(trimmed) > 0 && trimmed[0] == '{'
+}
+
+// ParseWebsocketRequest parses a JSON request envelope into a DiceDB command. The returned request
+// is set whenever the envelope is valid JSON, so that errors can be correlated with the request.
+func ParseWebsocketRequest(msg []byte) (*WebsocketRequest, *cmd.DiceDBCmd, error) {
+	req := &WebsocketRequest{}
+	if err := json.Unmarshal(msg, req); err != nil {
+		return nil, nil, err
+	}
+
+	if len(req.ID) == 0 || string(req.ID) == "null" {
+		return req, nil, errMissingRequestID
+	}
+
+	name := strings.ToUpper(strings.TrimSpace(req.Cmd))
+	if name == "" {
+		return req, nil, errEmptyBatchCmd
+	}
+
+	args := make([]string, len(req.Args))
+	for i, arg := range req.Args {
+		args[i] = formatValue(false, arg)
+	}
+
+	return req, &cmd.DiceDBCmd{Cmd: name, Args: args}, nil
+}
+
+// websocketConn is the state of a WebSocket connection. Messages may be written by the request
+// being read, the requests in flight and the watch subscriptions at the same time, so all the
+// writes go through write.
+type websocketConn struct {
+	conn    *websocket.Conn
+	writeMu sync.Mutex
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	// wg tracks the requests in flight and the watch subscriptions of the connection
+	wg       sync.WaitGroup
+	inflight chan struct{}
+
+	mu                 sync.Mutex
+	subscriptions      map[string]*websocketSubscription
+	nextSubscriptionID uint64
+
+	// user is the user the client authenticated as, either with the credentials of the upgrade
+	// request or with AUTH. Both fields are only accessed by the goroutine reading the connection.
+	user          *auth.User
+	authenticated bool
+}
+
+// websocketSubscription is a watch subscription of a connection. Its updates are streamed until
+// it is cancelled by UNWATCH or the connection is closed.
+type websocketSubscription struct {
+	id     string
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+func newWebsocketConn(ctx context.Context, conn *websocket.Conn) *websocketConn {
+	ctx, cancel := context.WithCancel(ctx)
+	return &websocketConn{
+		conn:          conn,
+		ctx:           ctx,
+		cancel:        cancel,
+		inflight:      make(chan struct{}, maxInflightWebsocketRequests),
+		subscriptions: make(map[string]*websocketSubscription),
+	}
+}
+
+// authorize executes the AUTH command of the client, and refuses the other commands until the client
+// is authenticated or when they are refused to its user. It reports whether the command is handled,
+// in which case the result is replied instead of executing the command.
+func (c *websocketConn) authorize(diceDBCmd *cmd.DiceDBCmd) (handled bool, result interface{}, err error) {
+	if diceDBCmd.Cmd == auth.Cmd {
+		user, err := authenticateCommand(diceDBCmd.Args)
+		if err != nil {
+			return true, nil, err
+		}
+		c.user, c.authenticated = user, true
+		return true, clientio.OK, nil
+	}
+
+	if !c.authenticated {
+		return true, nil, errAuthRequired
+	}
+	if err := authorizeCommand(c.user, diceDBCmd.Cmd); err != nil {
+		return true, nil, err
+	}
+	return false, nil, nil
+}
+
+// write writes a message to the client, retrying transient errors.
+func (c *websocketConn) write(data []byte) error {
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+
+	return WriteResponseWithRetries(c.conn, data, config.DiceConfig.WebSocket.MaxWriteResponseRetries)
+}
+
+func (c *websocketConn) writeReply(reply *WebsocketReply) error {
+	data, err := json.Marshal(reply)
+	if err != nil {
+		return err
+	}
+	return c.write(data)
+}
+
+// replyWithValue replies to the request with the value of its result, or with its error.
+func (c *websocketConn) replyWithValue(reply *WebsocketReply, value interface{}, isErr bool) error {
+	data, err := encodeReplyWithValue(reply, value, isErr)
+	if err != nil {
+		return err
+	}
+	return c.write(data)
+}
+
+// encodeReplyWithValue encodes the reply to a request with the value of its result, or with its error.
+func encodeReplyWithValue(reply *WebsocketReply, value interface{}, isErr bool) ([]byte, error) {
+	if isErr {
+		reply.Error = fmt.Sprint(value)
+		return json.Marshal(reply)
+	}
+
+	result, err := json.Marshal(value)
+	if err != nil {
+		slog.Debug("Error marshaling json", slog.Any("error", err))
+		reply.Error = "ERR marshaling json"
+		return json.Marshal(reply)
+	}
+	reply.Result = result
+	return json.Marshal(reply)
+}
+
+// closeWithError closes the connection, sending the error as the reason of the close frame.
+func (c *websocketConn) closeWithError(err error) {
+	deadline := time.Now().Add(config.DiceConfig.WebSocket.WriteResponseTimeout)
+	message := websocket.FormatCloseMessage(websocket.ClosePolicyViolation, "ERR "+err.Error())
+	if err := c.conn.WriteControl(websocket.CloseMessage, message, deadline); err != nil {
+		slog.Debug("Error sending websocket close message", slog.Any("error", err))
+	}
+	c.conn.Close()
+}
+
+// replyWithError replies to the request with an error.
+func (c *websocketConn) replyWithError(id json.RawMessage, err error) error {
+	return c.writeReply(&WebsocketReply{ID: id, Error: "ERR " + err.Error()})
+}
+
+// startHeartbeats pings the client every ping interval, and expires the read deadline of the
+// connection once the client neither sent a message nor answered a ping within the pong timeout.
+// The read of the next message then fails, which closes the connection of a dead peer.
+func (c *websocketConn) startHeartbeats() {
+	interval := config.DiceConfig.WebSocket.PingInterval
+	if interval <= 0 {
+		return
+	}
+
+	c.extendReadDeadline()
+	c.conn.SetPongHandler(func(string) error {
+		c.extendReadDeadline()
+		return nil
+	})
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-c.ctx.Done():
+				return
+			case <-ticker.C:
+				deadline := time.Now().Add(config.DiceConfig.WebSocket.WriteResponseTimeout)
+				if err := c.conn.WriteControl(websocket.PingMessage, nil, deadline); err != nil {
+					slog.Debug("Error sending websocket ping", slog.Any("error", err))
+					return
+				}
+			}
+		}
+	}()
+}
+
+// extendReadDeadline keeps the connection alive for another ping interval and pong timeout.
+func (c *websocketConn) extendReadDeadline() {
+	interval := config.DiceConfig.WebSocket.PingInterval
+	if interval <= 0 {
+		return
+	}
+
+	deadline := time.Now().Add(interval + config.DiceConfig.WebSocket.PongTimeout)
+	if err := c.conn.SetReadDeadline(deadline); err != nil {
+		slog.Debug("Error setting read deadline", slog.Any("error", err))
+	}
+}
+
+// dispatch executes the request in the background, so that the replies of slow requests do not
+// hold back the ones pipelined after them. It blocks while the connection has too many requests
+// in flight.
+func (c *websocketConn) dispatch(execute func()) {
+	select {
+	case c.inflight <- struct{}{}:
+	case <-c.ctx.Done():
+		return
+	}
+
+	c.wg.Add(1)
+	go func() {
+		defer c.wg.Done()
+		defer func() { <-c.inflight }()
+		execute()
+	}()
+}
+
+// close cancels the requests in flight and the watch subscriptions, and waits for them to be done.
+func (c *websocketConn) close() {
+	c.cancel()
+	c.wg.Wait()
+}
+
+// addSubscription registers a new watch subscription of the connection.
+func (c *websocketConn) addSubscription(cancel context.CancelFunc) *websocketSubscription {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.nextSubscriptionID++
+	sub := &websocketSubscription{
+		id:     strconv.FormatUint(c.nextSubscriptionID, 10),
+		cancel: cancel,
+		done:   make(chan struct{}),
+	}
+	c.subscriptions[sub.id] = sub
+	return sub
+}
+
+func (c *websocketConn) removeSubscription(id string) *websocketSubscription {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	sub, ok := c.subscriptions[id]
+	if ok {
+		delete(c.subscriptions, id)
+	}
+	return sub
+}
+
+// handleRequest executes a request of the multiplexed protocol and replies to it.
+func (s *WebsocketServer) handleRequest(c *websocketConn, req *WebsocketRequest, diceDBCmd *cmd.DiceDBCmd) {
+	var err error
+	switch {
+	case diceDBCmd.Cmd == UnwatchSubscription:
+		err = s.unwatch(c, req, diceDBCmd)
+	case commandhandler.CommandsMeta[diceDBCmd.Cmd].CmdType == commandhandler.Watch:
+		err = s.watch(c, req, diceDBCmd)
+	case commandhandler.CommandsMeta[diceDBCmd.Cmd].CmdType == commandhandler.Unwatch:
+		err = c.replyWithError(req.ID, fmt.Errorf("use %s <subscription> to cancel a watch subscription", UnwatchSubscription))
+	case diceDBCmd.Cmd == Qwatch || diceDBCmd.Cmd == Subscribe || unimplementedCommandsWebsocket[diceDBCmd.Cmd]:
+		err = c.replyWithError(req.ID, fmt.Errorf("command %s is not supported with the websocket protocol", diceDBCmd.Cmd))
+	default:
+		cmdHandler := s.cmdHandlers.acquire()
+		result, execErr := cmdHandler.ExecuteCommand(c.ctx, diceDBCmd)
+		s.cmdHandlers.release(cmdHandler)
+
+		value, isErr, decodeErr := decodeCommandResult(diceDBCmd, result, execErr)
+		if decodeErr != nil {
+			slog.Debug("Error decoding response", slog.Any("error", decodeErr))
+			value, isErr = "ERR 500 Internal Server Error", true
+		}
+		err = c.replyWithValue(&WebsocketReply{ID: req.ID}, value, isErr)
+	}
+
+	if err != nil {
+		slog.Debug("Error writing websocket reply", slog.String("cmd", diceDBCmd.Cmd), slog.Any("error", err))
+	}
+}
+
+// watch subscribes the connection to a .WATCH command. The reply holds the subscription ID and the
+// result of the command at subscription time. The updated results are then pushed with the
+// subscription ID every time the watched key changes.
+func (s *WebsocketServer) watch(c *websocketConn, req *WebsocketRequest, diceDBCmd *cmd.DiceDBCmd) error {
+	if s.cmdWatchSubscriptionChan == nil {
+		return c.replyWithError(req.ID, errWatchDisabled)
+	}
+	if len(diceDBCmd.Args) == 0 {
+		return c.replyWithError(req.ID, fmt.Errorf("missing key for %s", diceDBCmd.Cmd))
+	}
+
+	watchCmd := &cmd.DiceDBCmd{
+		Cmd:  strings.TrimSuffix(diceDBCmd.Cmd, watchSuffix),
+		Args: diceDBCmd.Args,
+	}
+
+	handlerID := fmt.Sprintf("wsWatch-%d", commandhandler.GenerateUniqueRequestID())
+	responseChan := make(chan *ops.StoreResponse)
+	s.shardManager.RegisterCommandHandler(handlerID, responseChan, nil)
+
+	initial, err := executeWatchCommand(c.ctx, s.shardManager, handlerID, responseChan, watchCmd)
+	if err != nil {
+		s.shardManager.UnregisterCommandHandler(handlerID)
+		return c.replyWithError(req.ID, err)
+	}
+
+	adhocReqChan := make(chan *cmd.DiceDBCmd, config.DiceConfig.Performance.AdhocReqChanBufSize)
+	subscribed := sendWatchSubscription(c.ctx, s.cmdWatchSubscriptionChan, s.shutdownChan, watchmanager.WatchSubscription{
+		Subscribe:    true,
+		WatchCmd:     watchCmd,
+		AdhocReqChan: adhocReqChan,
+	})
+	if !subscribed {
+		s.shardManager.UnregisterCommandHandler(handlerID)
+		return c.replyWithError(req.ID, errors.New("could not subscribe to the watch command"))
+	}
+
+	subCtx, cancel := context.WithCancel(c.ctx)
+	sub := c.addSubscription(cancel)
+
+	// The subscription ID is sent before the stream starts, so that the client never receives an
+	// update of an unknown subscription.
+	err = s.writeWatchResult(c, &WebsocketReply{ID: req.ID, Subscription: sub.id}, watchCmd, initial)
+
+	c.wg.Add(1)
+	go func() {
+		defer c.wg.Done()
+		defer close(sub.done)
+		defer c.removeSubscription(sub.id)
+		defer s.shardManager.UnregisterCommandHandler(handlerID)
+		defer sendWatchSubscription(context.Background(), s.cmdWatchSubscriptionChan, s.shutdownChan, watchmanager.WatchSubscription{
+			Subscribe:    false,
+			AdhocReqChan: adhocReqChan,
+			Fingerprint:  watchCmd.GetFingerprint(),
+		})
+
+		s.streamSubscription(subCtx, c, sub, handlerID, responseChan, adhocReqChan)
+	}()
+
+	return err
+}
+
+// streamSubscription pushes the updated results of the watched command until the subscription is
+// cancelled. The connection is closed once the updates pending exceed the output buffer limit of the
+// watch clients.
+func (s *WebsocketServer) streamSubscription(ctx context.Context, c *websocketConn, sub *websocketSubscription,
+	handlerID string, responseChan chan *ops.StoreResponse, adhocReqChan chan *cmd.DiceDBCmd) {
+	stream := newWatchStream(c.write)
+	defer stream.close()
+
+	limitTicker := time.NewTicker(iothread.OutputBufferCheckInterval)
+	defer limitTicker.Stop()
+
+	var err error
+	for err == nil {
+		select {
+		case <-ctx.Done():
+			return
+		case <-s.shutdownChan:
+			return
+		case <-stream.done:
+			return
+		case <-limitTicker.C:
+			err = stream.checkLimit()
+		case diceDBCmd := <-adhocReqChan:
+			resp, execErr := executeWatchCommand(ctx, s.shardManager, handlerID, responseChan, diceDBCmd)
+			if execErr != nil {
+				slog.Warn("Error executing watch command", slog.String("cmd", diceDBCmd.Cmd), slog.Any("error", execErr))
+				continue
+			}
+			data, encodeErr := encodeWatchResult(&WebsocketReply{Subscription: sub.id}, diceDBCmd, resp)
+			if encodeErr != nil {
+				slog.Debug("Error encoding watch update", slog.String("subscription", sub.id), slog.Any("error", encodeErr))
+				return
+			}
+			err = stream.push(data)
+		}
+	}
+
+	slog.Warn("Disconnecting websocket client for exceeding its output buffer limit",
+		slog.String("subscription", sub.id), slog.Int("pending_bytes", stream.outputBuffer.Size()))
+	c.closeWithError(err)
+}
+
+func (s *WebsocketServer) writeWatchResult(c *websocketConn, reply *WebsocketReply, watchCmd *cmd.DiceDBCmd, resp *ops.StoreResponse) error {
+	data, err := encodeWatchResult(reply, watchCmd, resp)
+	if err != nil {
+		return err
+	}
+	return c.write(data)
+}
+
+// encodeWatchResult encodes the reply holding a result of the watched command.
+func encodeWatchResult(reply *WebsocketReply, watchCmd *cmd.DiceDBCmd, resp *ops.StoreResponse) ([]byte, error) {
+	value, isErr, err := decodeCommandResult(watchCmd, resp.EvalResponse.Result, resp.EvalResponse.Error)
+	if err != nil {
+		slog.Error("Error decoding watch response", slog.String("cmd", watchCmd.Cmd), slog.Any("error", err))
+		value, isErr = "ERR 500 Internal Server Error", true
+	}
+	return encodeReplyWithValue(reply, value, isErr)
+}
+
+// unwatch cancels a watch subscription of the connection. No update of the subscription is sent
+// after the reply.
+func (s *WebsocketServer) unwatch(c *websocketConn, req *WebsocketRequest, diceDBCmd *cmd.DiceDBCmd) error {
+	if len(diceDBCmd.Args) != 1 {
+		return c.replyWithError(req.ID, fmt.Errorf("usage: %s <subscription>", UnwatchSubscription))
+	}
+
+	sub := c.removeSubscription(diceDBCmd.Args[0])
+	if sub == nil {
+		return c.replyWithError(req.ID, errUnknownSubscription)
+	}
+
+	sub.cancel()
+	<-sub.done
+	return c.replyWithValue(&WebsocketReply{ID: req.ID}, "OK", false)
+}
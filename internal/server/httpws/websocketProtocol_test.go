@@ -0,0 +1,241 @@
+// Copyright (c) 2022-present, DiceDB contributors
+// All rights reserved. Licensed under the BSD 3-Clause License. See LICENSE file in the project root for full license information.
+
+package httpws
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dicedb/dice/config"
+	"github.com/dicedb/dice/internal/shard"
+	dstore "github.com/dicedb/dice/internal/store"
+	"github.com/dicedb/dice/internal/watchmanager"
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseWebsocketRequest(t *testing.T) {
+	tests := []struct {
+		name         string
+		msg          string
+		expectedID   string
+		expectedCmd  string
+		expectedArgs []string
+		expectedErr  bool
+	}{
+		{
+			name:         "numeric id",
+			msg:          `{"id": 1, "cmd": "set", "args": ["k1", "v1"]}`,
+			expectedID:   "1",
+			expectedCmd:  "SET",
+			expectedArgs: []string{"k1", "v1"},
+		},
+		{
+			name:         "string id and non-string args",
+			msg:          `{"id": "a", "cmd": "EXPIRE", "args": ["k1", 10]}`,
+			expectedID:   `"a"`,
+			expectedCmd:  "EXPIRE",
+			expectedArgs: []string{"k1", "10"},
+		},
+		{
+			name:        "missing id",
+			msg:         `{"cmd": "PING"}`,
+			expectedErr: true,
+		},
+		{
+			name:        "missing command",
+			msg:         `{"id": 2, "args": ["k1"]}`,
+			expectedID:  "2",
+			expectedErr: true,
+		},
+		{
+			name:        "invalid json",
+			msg:         `{"id": 3, "cmd": `,
+			expectedErr: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			req, diceDBCmd, err := ParseWebsocketRequest([]byte(tc.msg))
+			if tc.expectedErr {
+				assert.Error(t, err)
+				if tc.expectedID != "" {
+					assert.Equal(t, tc.expectedID, string(req.ID))
+				}
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedID, string(req.ID))
+			assert.Equal(t, tc.expectedCmd, diceDBCmd.Cmd)
+			assert.Equal(t, tc.expectedArgs, diceDBCmd.Args)
+		})
+	}
+}
+
+// startTestWebsocketServer starts a websocket server and returns its URL.
+func startTestWebsocketServer(t *testing.T, ctx context.Context, cmdWatchSubscriptionChan chan watchmanager.WatchSubscription,
+	cmdWatchChan chan dstore.CmdWatchEvent) string {
+	shardManager := shard.NewShardManager(2, cmdWatchChan, make(chan error, 1))
+	go shardManager.Run(ctx)
+
+	server := NewWebSocketServer(shardManager, cmdWatchSubscriptionChan, 0, make(chan error, 1), nil)
+	ts := httptest.NewServer(server.websocketServer.Handler)
+	t.Cleanup(ts.Close)
+	t.Cleanup(server.cmdHandlers.close)
+	return "ws" + strings.TrimPrefix(ts.URL, "http")
+}
+
+func newTestWebsocketServer(t *testing.T, ctx context.Context, cmdWatchSubscriptionChan chan watchmanager.WatchSubscription,
+	cmdWatchChan chan dstore.CmdWatchEvent) *websocket.Conn {
+	conn, resp, err := websocket.DefaultDialer.Dial(startTestWebsocketServer(t, ctx, cmdWatchSubscriptionChan, cmdWatchChan), nil)
+	require.NoError(t, err)
+	resp.Body.Close()
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+func readWebsocketReply(t *testing.T, conn *websocket.Conn) WebsocketReply {
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(5*time.Second)))
+	var reply WebsocketReply
+	require.NoError(t, conn.ReadJSON(&reply))
+	return reply
+}
+
+func TestWebsocketHandlerPipelinedRequests(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	conn := newTestWebsocketServer(t, ctx, nil, nil)
+
+	const requests = 50
+	for i := 0; i < requests; i++ {
+		msg := fmt.Sprintf(`{"id": %d, "cmd": "SET", "args": ["k%d", "v%d"]}`, i, i, i)
+		require.NoError(t, conn.WriteMessage(websocket.TextMessage, []byte(msg)))
+	}
+
+	seen := make(map[string]bool)
+	for i := 0; i < requests; i++ {
+		reply := readWebsocketReply(t, conn)
+		assert.Empty(t, reply.Error)
+		assert.JSONEq(t, `"OK"`, string(reply.Result))
+		seen[string(reply.ID)] = true
+	}
+	assert.Len(t, seen, requests)
+
+	require.NoError(t, conn.WriteMessage(websocket.TextMessage, []byte(`{"id": "get", "cmd": "GET", "args": ["k7"]}`)))
+	reply := readWebsocketReply(t, conn)
+	assert.Equal(t, `"get"`, string(reply.ID))
+	assert.JSONEq(t, `"v7"`, string(reply.Result))
+
+	require.NoError(t, conn.WriteMessage(websocket.TextMessage, []byte(`{"id": "missing", "cmd": "GET", "args": ["nope"]}`)))
+	reply = readWebsocketReply(t, conn)
+	assert.JSONEq(t, `null`, string(reply.Result))
+
+	require.NoError(t, conn.WriteMessage(websocket.TextMessage, []byte(`{"id": "err", "cmd": "GET"}`)))
+	reply = readWebsocketReply(t, conn)
+	assert.Equal(t, `"err"`, string(reply.ID))
+	assert.Equal(t, "ERR wrong number of arguments for 'get' command", reply.Error)
+	assert.Empty(t, reply.Result)
+
+	// Plain text commands are still replied to with their bare result
+	require.NoError(t, conn.WriteMessage(websocket.TextMessage, []byte("GET k7")))
+	_, msg, err := conn.ReadMessage()
+	require.NoError(t, err)
+	assert.Equal(t, `"v7"`, string(msg))
+}
+
+func TestWebsocketHandlerWatchAndUnwatch(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	cmdWatchChan := make(chan dstore.CmdWatchEvent, 16)
+	cmdWatchSubscriptionChan := make(chan watchmanager.WatchSubscription)
+	go watchmanager.NewManager(cmdWatchSubscriptionChan, cmdWatchChan).Run(ctx)
+	conn := newTestWebsocketServer(t, ctx, cmdWatchSubscriptionChan, cmdWatchChan)
+
+	fire := func(msg string) WebsocketReply {
+		require.NoError(t, conn.WriteMessage(websocket.TextMessage, []byte(msg)))
+		return readWebsocketReply(t, conn)
+	}
+
+	fire(`{"id": 1, "cmd": "SET", "args": ["k1", "v1"]}`)
+
+	reply := fire(`{"id": 2, "cmd": "GET.WATCH", "args": ["k1"]}`)
+	assert.Equal(t, "2", string(reply.ID))
+	assert.JSONEq(t, `"v1"`, string(reply.Result))
+	subscription := reply.Subscription
+	require.NotEmpty(t, subscription)
+
+	// The update of the subscription and the reply to the SET may arrive in any order
+	require.NoError(t, conn.WriteMessage(websocket.TextMessage, []byte(`{"id": 3, "cmd": "SET", "args": ["k1", "v2"]}`)))
+	for i := 0; i < 2; i++ {
+		reply = readWebsocketReply(t, conn)
+		if reply.Subscription != "" {
+			assert.Equal(t, subscription, reply.Subscription)
+			assert.Empty(t, reply.ID)
+			assert.JSONEq(t, `"v2"`, string(reply.Result))
+		} else {
+			assert.Equal(t, "3", string(reply.ID))
+		}
+	}
+
+	reply = fire(fmt.Sprintf(`{"id": 4, "cmd": "UNWATCH", "args": [%q]}`, subscription))
+	assert.Equal(t, "4", string(reply.ID))
+	assert.JSONEq(t, `"OK"`, string(reply.Result))
+
+	// No update is sent once the subscription is cancelled
+	reply = fire(`{"id": 5, "cmd": "SET", "args": ["k1", "v3"]}`)
+	assert.Equal(t, "5", string(reply.ID))
+	reply = fire(`{"id": 6, "cmd": "PING"}`)
+	assert.Equal(t, "6", string(reply.ID))
+
+	reply = fire(fmt.Sprintf(`{"id": 7, "cmd": "UNWATCH", "args": [%q]}`, subscription))
+	assert.Equal(t, "ERR no such subscription", reply.Error)
+}
+
+func TestWebsocketHandlerClosesUnresponsiveClients(t *testing.T) {
+	interval, timeout := config.DiceConfig.WebSocket.PingInterval, config.DiceConfig.WebSocket.PongTimeout
+	config.DiceConfig.WebSocket.PingInterval = 50 * time.Millisecond
+	config.DiceConfig.WebSocket.PongTimeout = 50 * time.Millisecond
+	defer func() {
+		config.DiceConfig.WebSocket.PingInterval, config.DiceConfig.WebSocket.PongTimeout = interval, timeout
+	}()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	conn := newTestWebsocketServer(t, ctx, nil, nil)
+
+	// The client does not read, so that the pings of the server are never answered
+	time.Sleep(300 * time.Millisecond)
+
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(5*time.Second)))
+	for {
+		_, _, err := conn.ReadMessage()
+		if err != nil {
+			// The connection is closed by the server rather than timing out on the client
+			var netErr net.Error
+			assert.False(t, errors.As(err, &netErr) && netErr.Timeout(), err.Error())
+			return
+		}
+	}
+}
+
+func TestWebsocketReplyEncoding(t *testing.T) {
+	data, err := json.Marshal(WebsocketReply{ID: json.RawMessage(`1`), Result: json.RawMessage(`0`)})
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"id": 1, "result": 0}`, string(data))
+
+	data, err = json.Marshal(WebsocketReply{Subscription: "1", Result: json.RawMessage(`null`)})
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"subscription": "1", "result": null}`, string(data))
+}
@@ -23,12 +23,15 @@ import (
 	"github.com/dicedb/dice/internal/wal"
 
 	"github.com/dicedb/dice/config"
+	"github.com/dicedb/dice/internal/auth"
 	"github.com/dicedb/dice/internal/clientio"
 	"github.com/dicedb/dice/internal/cmd"
 	"github.com/dicedb/dice/internal/comm"
 	diceerrors "github.com/dicedb/dice/internal/errors"
+	"github.com/dicedb/dice/internal/iothread"
 	"github.com/dicedb/dice/internal/ops"
 	"github.com/dicedb/dice/internal/shard"
+	"github.com/dicedb/dice/internal/watchmanager"
 	"github.com/gorilla/websocket"
 	"golang.org/x/exp/rand"
 )
@@ -38,23 +41,33 @@ const (
 	Qunwatch       = "Q.UNWATCH"
 	Subscribe      = "SUBSCRIBE"
 	wsCmdHandlerID = "wsServer"
+
+	// wsCommandHandlerPoolSize is the number of idle command handlers kept for reuse by the requests
+	wsCommandHandlerPoolSize = 256
 )
 
 var unimplementedCommandsWebsocket = map[string]bool{
 	Qunwatch: true,
+	// The command handlers are not bound to a connection, so the commands operate on the default database
+	"SELECT": true,
+	// Nor do they hold the name of a connection, as they are shared by the connections
+	"CLIENT": true,
 }
 
 type WebsocketServer struct {
 	abstractserver.AbstractServer
-	shardManager       *shard.ShardManager
-	ioChan             chan *ops.StoreResponse
-	websocketServer    *http.Server
-	upgrader           websocket.Upgrader
-	qwatchResponseChan chan comm.QwatchResponse
-	shutdownChan       chan struct{}
+	shardManager             *shard.ShardManager
+	cmdHandlers              *commandHandlerPool
+	ioChan                   chan *ops.StoreResponse
+	websocketServer          *http.Server
+	upgrader                 websocket.Upgrader
+	qwatchResponseChan       chan comm.QwatchResponse
+	cmdWatchSubscriptionChan chan watchmanager.WatchSubscription // nil if watch is disabled
+	shutdownChan             chan struct{}
 }
 
-func NewWebSocketServer(shardManager *shard.ShardManager, port int, wl wal.AbstractWAL) *WebsocketServer {
+func NewWebSocketServer(shardManager *shard.ShardManager, cmdWatchSubscriptionChan chan watchmanager.WatchSubscription,
+	port int, globalErrChan chan error, wl wal.AbstractWAL) *WebsocketServer {
 	mux := http.NewServeMux()
 	srv := &http.Server{
 		Addr:              fmt.Sprintf(":%d", port),
@@ -67,12 +80,14 @@ func NewWebSocketServer(shardManager *shard.ShardManager, port int, wl wal.Abstr
 	}
 
 	websocketServer := &WebsocketServer{
-		shardManager:       shardManager,
-		ioChan:             make(chan *ops.StoreResponse, 1000),
-		websocketServer:    srv,
-		upgrader:           upgrader,
-		qwatchResponseChan: make(chan comm.QwatchResponse),
-		shutdownChan:       make(chan struct{}),
+		shardManager:             shardManager,
+		cmdHandlers:              newCommandHandlerPool("ws", wsCommandHandlerPoolSize, shardManager, cmdWatchSubscriptionChan, globalErrChan, wl),
+		ioChan:                   make(chan *ops.StoreResponse, 1000),
+		websocketServer:          srv,
+		upgrader:                 upgrader,
+		qwatchResponseChan:       make(chan comm.QwatchResponse),
+		cmdWatchSubscriptionChan: cmdWatchSubscriptionChan,
+		shutdownChan:             make(chan struct{}),
 	}
 
 	mux.HandleFunc("/", websocketServer.WebsocketHandler)
@@ -86,6 +101,8 @@ func (s *WebsocketServer) Run(ctx context.Context) error {
 	websocketCtx, cancelWebsocket := context.WithCancel(ctx)
 	defer cancelWebsocket()
 
+	defer s.cmdHandlers.close()
+
 	s.shardManager.RegisterCommandHandler(wsCmdHandlerID, s.ioChan, nil)
 
 	wg.Add(1)
@@ -119,23 +136,48 @@ func (s *WebsocketServer) Run(ctx context.Context) error {
 	return err
 }
 
+// WebsocketHandler serves a WebSocket connection. Messages are either JSON request envelopes of the
+// multiplexed protocol, executed concurrently and replied to with their request ID, or plain text
+// commands, executed in order and replied to with their bare result.
 func (s *WebsocketServer) WebsocketHandler(w http.ResponseWriter, r *http.Request) {
+	// The credentials of the upgrade request authenticate the connection. Without any, the client
+	// must authenticate with AUTH before sending other commands.
+	var (
+		user *auth.User
+		err  error
+	)
+	authenticated := !authRequired() || r.Header.Get("Authorization") != ""
+	if authenticated {
+		if user, err = authenticateRequest(r); err != nil {
+			w.Header().Set("WWW-Authenticate", `Basic realm="dicedb", charset="UTF-8"`)
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		}
+	}
+
 	// upgrade http connection to websocket
 	conn, err := s.upgrader.Upgrade(w, r, nil)
 	if err != nil {
 		return
 	}
 
+	c := newWebsocketConn(r.Context(), conn)
+	c.user, c.authenticated = user, authenticated
+
 	// closing handshake
 	defer func() {
-		closeErr := conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, "close 1000 (normal)"))
+		c.cancel()
+		closeErr := conn.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, "close 1000 (normal)"),
+			time.Now().Add(config.DiceConfig.WebSocket.WriteResponseTimeout))
 		if closeErr != nil {
 			slog.Debug("Error during closing handshake", slog.Any("error", closeErr))
 		}
 		conn.Close()
+		c.close()
 	}()
 
-	maxRetries := config.DiceConfig.WebSocket.MaxWriteResponseRetries
+	c.startHeartbeats()
+
 	for {
 		// read incoming message
 		_, msg, err := conn.ReadMessage()
@@ -145,73 +187,139 @@ func (s *WebsocketServer) WebsocketHandler(w http.ResponseWriter, r *http.Reques
 			if websocket.IsCloseError(err, errs...) {
 				break
 			}
+			var netErr net.Error
+			if errors.As(err, &netErr) && netErr.Timeout() {
+				slog.Debug("Closing unresponsive websocket client", slog.String("remote_addr", r.RemoteAddr))
+				break
+			}
 			slog.Error("Error reading message", slog.Any("error", err))
 			break
 		}
+		c.extendReadDeadline()
+
+		if isWebsocketRequest(msg) {
+			req, diceDBCmd, err := ParseWebsocketRequest(msg)
+			if err != nil {
+				var id json.RawMessage
+				if req != nil {
+					id = req.ID
+				}
+				if err := c.replyWithError(id, fmt.Errorf("invalid request: %w", err)); err != nil {
+					slog.Debug(fmt.Sprintf("Error writing message: %v", err))
+				}
+				continue
+			}
 
-		// parse message to dice command
-		diceDBCmd, err := ParseWebsocketMessage(msg)
-		if errors.Is(err, diceerrors.ErrEmptyCommand) {
-			continue
-		} else if err != nil {
-			if err := WriteResponseWithRetries(conn, []byte("error: parsing failed"), maxRetries); err != nil {
-				slog.Debug(fmt.Sprintf("Error writing message: %v", err))
+			if handled, result, err := c.authorize(diceDBCmd); handled {
+				value, isErr, _ := decodeCommandResult(diceDBCmd, result, err)
+				if err := c.replyWithValue(&WebsocketReply{ID: req.ID}, value, isErr); err != nil {
+					slog.Debug(fmt.Sprintf("Error writing message: %v", err))
+				}
+				continue
 			}
-			continue
-		}
 
-		if commandhandler.CommandsMeta[diceDBCmd.Cmd].CmdType == commandhandler.MultiShard {
-			if err := WriteResponseWithRetries(conn, []byte("error: unsupported command"), maxRetries); err != nil {
-				slog.Debug(fmt.Sprintf("Error writing message: %v", err))
+			// TODO - on abort, close client connection instead of closing server?
+			if diceDBCmd.Cmd == Abort {
+				close(s.shutdownChan)
+				break
 			}
+
+			c.dispatch(func() { s.handleRequest(c, req, diceDBCmd) })
 			continue
 		}
 
-		// TODO - on abort, close client connection instead of closing server?
-		if diceDBCmd.Cmd == Abort {
-			close(s.shutdownChan)
+		if !s.handleTextCommand(c, r, msg) {
 			break
 		}
+	}
+}
 
-		if unimplementedCommandsWebsocket[diceDBCmd.Cmd] {
-			if err := WriteResponseWithRetries(conn, []byte("Command is not implemented with Websocket"), maxRetries); err != nil {
-				slog.Debug(fmt.Sprintf("Error writing message: %v", err))
-			}
-			continue
+// handleTextCommand executes a plain text command and writes its bare result. It returns false
+// once the connection must be closed.
+func (s *WebsocketServer) handleTextCommand(c *websocketConn, r *http.Request, msg []byte) bool {
+	// parse message to dice command
+	diceDBCmd, err := ParseWebsocketMessage(msg)
+	if errors.Is(err, diceerrors.ErrEmptyCommand) {
+		return true
+	} else if err != nil {
+		if err := c.write([]byte("error: parsing failed")); err != nil {
+			slog.Debug(fmt.Sprintf("Error writing message: %v", err))
+		}
+		return true
+	}
+
+	if handled, result, err := c.authorize(diceDBCmd); handled {
+		return s.processResponse(c, diceDBCmd, result, err) == nil
+	}
+
+	// TODO - on abort, close client connection instead of closing server?
+	if diceDBCmd.Cmd == Abort {
+		close(s.shutdownChan)
+		return false
+	}
+
+	if unimplementedCommandsWebsocket[diceDBCmd.Cmd] {
+		if err := c.write([]byte("Command is not implemented with Websocket")); err != nil {
+			slog.Debug(fmt.Sprintf("Error writing message: %v", err))
+		}
+		return true
+	}
+
+	if cmdType := commandhandler.CommandsMeta[diceDBCmd.Cmd].CmdType; cmdType == commandhandler.Watch || cmdType == commandhandler.Unwatch {
+		if err := c.write([]byte("error: command is not supported with Websocket")); err != nil {
+			slog.Debug(fmt.Sprintf("Error writing message: %v", err))
 		}
+		return true
+	}
 
-		// create request
+	// handle q.watch commands, whose updates are delivered by the query manager
+	if diceDBCmd.Cmd == Qwatch || diceDBCmd.Cmd == Subscribe {
+		clientIdentifierID := generateUniqueInt32(r)
 		sp := &ops.StoreOp{
 			Cmd:          diceDBCmd,
 			CmdHandlerID: wsCmdHandlerID,
 			ShardID:      0,
 			WebsocketOp:  true,
+			Client:       comm.NewHTTPQwatchClient(s.qwatchResponseChan, clientIdentifierID),
 		}
 
-		// handle q.watch commands
-		if diceDBCmd.Cmd == Qwatch || diceDBCmd.Cmd == Subscribe {
-			clientIdentifierID := generateUniqueInt32(r)
-			sp.Client = comm.NewHTTPQwatchClient(s.qwatchResponseChan, clientIdentifierID)
-
-			// start a goroutine for subsequent updates
-			go s.processQwatchUpdates(clientIdentifierID, conn)
-		}
+		// start a goroutine for subsequent updates
+		go s.processQwatchUpdates(clientIdentifierID, c)
 
 		s.shardManager.GetShard(0).ReqChan <- sp
 		resp := <-s.ioChan
-		if err := s.processResponse(conn, diceDBCmd, resp); err != nil {
-			break
-		}
+		return s.processResponse(c, diceDBCmd, resp.EvalResponse.Result, resp.EvalResponse.Error) == nil
 	}
+
+	cmdHandler := s.cmdHandlers.acquire()
+	result, err := cmdHandler.ExecuteCommand(c.ctx, diceDBCmd)
+	s.cmdHandlers.release(cmdHandler)
+
+	return s.processResponse(c, diceDBCmd, result, err) == nil
 }
 
-func (s *WebsocketServer) processQwatchUpdates(clientIdentifierID uint32, conn *websocket.Conn) {
+// processQwatchUpdates forwards the updates of a q.watch subscription to the client. Updates are queued
+// in an output buffer drained by a separate writer, and the client is disconnected once the backlog
+// exceeds the pubsub output buffer limit, instead of retrying writes to a slow client indefinitely.
+func (s *WebsocketServer) processQwatchUpdates(clientIdentifierID uint32, c *websocketConn) {
+	outputBuffer := iothread.NewOutputBuffer()
+	writerDone := make(chan struct{})
+	defer close(writerDone)
+
+	go s.writeQwatchUpdates(clientIdentifierID, c, outputBuffer, writerDone)
+
+	limit := iothread.OutputBufferLimitFor(iothread.ClientClassPubSub)
 	for {
 		select {
 		case resp := <-s.qwatchResponseChan:
 			if resp.ClientIdentifierID == clientIdentifierID {
-				if err := s.processQwatchResponse(conn, resp); err != nil {
-					slog.Debug("Error writing response to client. Shutting down goroutine for q.watch updates", slog.Any("clientIdentifierID", clientIdentifierID), slog.Any("error", err))
+				outputBuffer.Push(s.processQwatchResponse(resp))
+				if outputBuffer.Exceeds(limit, time.Now()) {
+					slog.Warn("Disconnecting websocket client for exceeding its output buffer limit",
+						slog.Any("clientIdentifierID", clientIdentifierID),
+						slog.Int("pending_bytes", outputBuffer.Size()))
+					iothread.RecordOutputBufferDisconnection(iothread.ClientClassPubSub)
+					c.conn.Close()
 					return
 				}
 			}
@@ -221,10 +329,33 @@ func (s *WebsocketServer) processQwatchUpdates(clientIdentifierID uint32, conn *
 	}
 }
 
-func (s *WebsocketServer) processQwatchResponse(conn *websocket.Conn, response interface{}) error {
+// writeQwatchUpdates writes the queued q.watch updates to the client until done is closed.
+func (s *WebsocketServer) writeQwatchUpdates(clientIdentifierID uint32, c *websocketConn, outputBuffer *iothread.OutputBuffer, done chan struct{}) {
+	for {
+		select {
+		case <-done:
+			return
+		case <-outputBuffer.Notify():
+		}
+
+		for {
+			data, ok := outputBuffer.Pop()
+			if !ok {
+				break
+			}
+
+			if err := c.write(data); err != nil {
+				slog.Debug("Error writing response to client. Shutting down goroutine for q.watch updates", slog.Any("clientIdentifierID", clientIdentifierID), slog.Any("error", err))
+				return
+			}
+		}
+	}
+}
+
+// processQwatchResponse converts a q.watch update into the message to be sent to the client.
+func (s *WebsocketServer) processQwatchResponse(response interface{}) []byte {
 	var result interface{}
 	var err error
-	maxRetries := config.DiceConfig.WebSocket.MaxWriteResponseRetries
 
 	// check response type
 	switch resp := response.(type) {
@@ -233,11 +364,7 @@ func (s *WebsocketServer) processQwatchResponse(conn *websocket.Conn, response i
 		err = resp.Error
 	default:
 		slog.Debug("Unsupported response type")
-		if err := WriteResponseWithRetries(conn, []byte("error: 500 Internal Server Error"), maxRetries); err != nil {
-			slog.Debug(fmt.Sprintf("Error writing message: %v", err))
-			return fmt.Errorf("error writing response: %v", err)
-		}
-		return nil
+		return []byte("error: 500 Internal Server Error")
 	}
 
 	var responseValue interface{}
@@ -251,66 +378,34 @@ func (s *WebsocketServer) processQwatchResponse(conn *websocket.Conn, response i
 	responseValue, err = rp.DecodeOne()
 	if err != nil {
 		slog.Debug("Error decoding response", "error", err)
-		if err := WriteResponseWithRetries(conn, []byte("error: 500 Internal Server Error"), maxRetries); err != nil {
-			slog.Debug(fmt.Sprintf("Error writing message: %v", err))
-			return fmt.Errorf("error writing response: %v", err)
-		}
-		return nil
+		return []byte("error: 500 Internal Server Error")
 	}
 
 	respBytes, err := json.Marshal(responseValue)
 	if err != nil {
 		slog.Debug("Error marshaling json", "error", err)
-		if err := WriteResponseWithRetries(conn, []byte("error: marshaling json"), maxRetries); err != nil {
-			slog.Debug(fmt.Sprintf("Error writing message: %v", err))
-			return fmt.Errorf("error writing response: %v", err)
-		}
-		return nil
+		return []byte("error: marshaling json")
 	}
 
-	// success
-	// Write response with retries for transient errors
-	if err := WriteResponseWithRetries(conn, respBytes, config.DiceConfig.WebSocket.MaxWriteResponseRetries); err != nil {
-		slog.Debug(fmt.Sprintf("Error writing message: %v", err))
-		return fmt.Errorf("error writing response: %v", err)
-	}
-
-	return nil
+	return respBytes
 }
 
-func (s *WebsocketServer) processResponse(conn *websocket.Conn, diceDBCmd *cmd.DiceDBCmd, response *ops.StoreResponse) error {
-	var err error
-	maxRetries := config.DiceConfig.WebSocket.MaxWriteResponseRetries
-
-	var responseValue interface{}
-	// Check if the command is migrated, if it is we use EvalResponse values
-	// else we use RESPParser to decode the response
-	_, ok := commandhandler.CommandsMeta[diceDBCmd.Cmd]
-	// TODO: Remove this conditional check and if (true) condition when all commands are migrated
-	if !ok {
-		responseValue, err = DecodeEvalResponse(response.EvalResponse)
-		if err != nil {
-			slog.Debug("Error decoding response", "error", err)
-			if err := WriteResponseWithRetries(conn, []byte("error: 500 Internal Server Error"), maxRetries); err != nil {
-				slog.Debug(fmt.Sprintf("Error writing message: %v", err))
-				return fmt.Errorf("error writing response: %v", err)
-			}
-			return nil
-		}
-	} else {
-		if response.EvalResponse.Error != nil {
-			responseValue = response.EvalResponse.Error.Error()
-		} else {
-			responseValue = response.EvalResponse.Result
+func (s *WebsocketServer) processResponse(c *websocketConn, diceDBCmd *cmd.DiceDBCmd, result interface{}, resultErr error) error {
+	responseValue, _, err := decodeCommandResult(diceDBCmd, result, resultErr)
+	if err != nil {
+		slog.Debug("Error decoding response", "error", err)
+		if err := c.write([]byte("error: 500 Internal Server Error")); err != nil {
+			slog.Debug(fmt.Sprintf("Error writing message: %v", err))
+			return fmt.Errorf("error writing response: %v", err)
 		}
+		return nil
 	}
 
 	// Create websocket response
-	wsResponse := ResponseParser(responseValue)
-	respBytes, err := json.Marshal(wsResponse)
+	respBytes, err := json.Marshal(responseValue)
 	if err != nil {
 		slog.Debug("Error marshaling json", "error", err)
-		if err := WriteResponseWithRetries(conn, []byte("error: marshaling json"), maxRetries); err != nil {
+		if err := c.write([]byte("error: marshaling json")); err != nil {
 			slog.Debug(fmt.Sprintf("Error writing message: %v", err))
 			return fmt.Errorf("error writing response: %v", err)
 		}
@@ -319,7 +414,7 @@ func (s *WebsocketServer) processResponse(conn *websocket.Conn, diceDBCmd *cmd.D
 
 	// success
 	// Write response with retries for transient errors
-	if err := WriteResponseWithRetries(conn, respBytes, config.DiceConfig.WebSocket.MaxWriteResponseRetries); err != nil {
+	if err := c.write(respBytes); err != nil {
 		slog.Debug(fmt.Sprintf("Error writing message: %v", err))
 		return fmt.Errorf("error writing response: %v", err)
 	}
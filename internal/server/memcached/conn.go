@@ -0,0 +1,497 @@
+// Copyright (c) 2022-present, DiceDB contributors
+// All rights reserved. Licensed under the BSD 3-Clause License. See LICENSE file in the project root for full license information.
+
+package memcached
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dicedb/dice/config"
+	"github.com/dicedb/dice/internal/cmd"
+	diceerrors "github.com/dicedb/dice/internal/errors"
+	"github.com/dicedb/dice/internal/eval"
+	"github.com/dicedb/dice/internal/ops"
+	"github.com/dicedb/dice/internal/server/utils"
+	dstore "github.com/dicedb/dice/internal/store"
+)
+
+const (
+	maxKeyLength  = 250
+	maxLineLength = 8192
+	maxValueSize  = 1 << 20
+
+	// maxRelativeExpiry is the largest expiry, in seconds, treated as relative to now. Larger
+	// values are absolute unix timestamps, as in memcached.
+	maxRelativeExpiry = 60 * 60 * 24 * 30
+
+	noReply = "noreply"
+)
+
+var (
+	errBadCommandLine = errors.New("bad command line format")
+	errBadDataChunk   = errors.New("bad data chunk")
+	errLineTooLong    = errors.New("line too long")
+	errQuit           = errors.New("quit")
+)
+
+// conn serves the memcached requests of a single client connection.
+type conn struct {
+	server       *Server
+	id           string
+	netConn      net.Conn
+	reader       *bufio.Reader
+	writer       *bufio.Writer
+	responseChan chan *ops.StoreResponse
+}
+
+func newConn(server *Server, id string, netConn net.Conn, responseChan chan *ops.StoreResponse) *conn {
+	return &conn{
+		server:       server,
+		id:           id,
+		netConn:      netConn,
+		reader:       bufio.NewReaderSize(netConn, maxLineLength),
+		writer:       bufio.NewWriter(netConn),
+		responseChan: responseChan,
+	}
+}
+
+// serve reads and processes requests until the connection is closed or the client quits.
+func (c *conn) serve(ctx context.Context) error {
+	for {
+		line, err := c.readLine()
+		if err != nil {
+			if errors.Is(err, errLineTooLong) {
+				c.writeLine("CLIENT_ERROR " + err.Error())
+				c.writer.Flush()
+			}
+			return err
+		}
+
+		if err := c.handle(ctx, line); err != nil {
+			if errors.Is(err, errQuit) {
+				return nil
+			}
+			return err
+		}
+
+		if err := c.writer.Flush(); err != nil {
+			return err
+		}
+	}
+}
+
+// handle processes a single request line.
+func (c *conn) handle(ctx context.Context, line string) error {
+	fields := strings.Fields(line)
+	if len(fields) == 0 {
+		c.writeLine("ERROR")
+		return nil
+	}
+
+	name, args := strings.ToLower(fields[0]), fields[1:]
+	switch name {
+	case "get", "gets":
+		return c.handleGet(ctx, args, name == "gets", "")
+	case "gat", "gats":
+		if len(args) < 2 {
+			c.writeLine("ERROR")
+			return nil
+		}
+		return c.handleGet(ctx, args[1:], name == "gats", args[0])
+	case eval.MCModeSet, eval.MCModeAdd, eval.MCModeReplace, eval.MCModeAppend, eval.MCModePrepend, eval.MCModeCas:
+		return c.handleStore(ctx, name, args)
+	case "delete":
+		return c.handleDelete(ctx, args)
+	case "incr", "decr":
+		return c.handleIncrDecr(ctx, name, args)
+	case "touch":
+		return c.handleTouch(ctx, args)
+	case "flush_all":
+		return c.handleFlushAll(ctx, args)
+	case "stats":
+		c.handleStats()
+		return nil
+	case "version":
+		c.writeLine("VERSION " + config.DiceDBVersion)
+		return nil
+	case "quit":
+		return errQuit
+	default:
+		c.writeLine("ERROR")
+		return nil
+	}
+}
+
+// handleGet serves get, gets, gat and gats. exptime is only set for gat and gats.
+func (c *conn) handleGet(ctx context.Context, keys []string, withCas bool, exptime string) error {
+	if len(keys) == 0 {
+		c.writeLine("ERROR")
+		return nil
+	}
+
+	var expiryMs string
+	if exptime != "" {
+		ttl, err := parseExpiry(exptime)
+		if err != nil {
+			c.writeClientError(errBadCommandLine)
+			return nil
+		}
+		expiryMs = strconv.FormatInt(ttl, 10)
+		c.server.stats.cmdTouch.Add(uint64(len(keys)))
+	}
+
+	cmds := make([]*cmd.DiceDBCmd, 0, len(keys))
+	for _, key := range keys {
+		if !validKey(key) {
+			c.writeClientError(errBadCommandLine)
+			return nil
+		}
+
+		args := []string{key}
+		if expiryMs != "" {
+			args = append(args, expiryMs)
+		}
+		cmds = append(cmds, &cmd.DiceDBCmd{Cmd: eval.MCGet, Args: args})
+	}
+
+	responses, err := c.server.execute(ctx, c, cmds)
+	if err != nil {
+		c.writeServerError(err)
+		return nil
+	}
+
+	c.server.stats.cmdGet.Add(uint64(len(keys)))
+	for i, resp := range responses {
+		item, ok := resp.EvalResponse.Result.([]interface{})
+		if resp.EvalResponse.Error != nil || !ok || len(item) != 3 {
+			c.server.stats.getMisses.Add(1)
+			continue
+		}
+		c.server.stats.getHits.Add(1)
+
+		value, _ := item[0].(string)
+		casUnique, _ := item[1].(uint64)
+		flags, _ := item[2].(uint32)
+		if withCas {
+			c.writeLine(fmt.Sprintf("VALUE %s %d %d %d", keys[i], flags, len(value), casUnique))
+		} else {
+			c.writeLine(fmt.Sprintf("VALUE %s %d %d", keys[i], flags, len(value)))
+		}
+		c.writeLine(value)
+	}
+
+	c.writeLine("END")
+	return nil
+}
+
+// handleStore serves set, add, replace, append, prepend and cas:
+//
+//	<command> <key> <flags> <exptime> <bytes> [noreply]
+//	cas <key> <flags> <exptime> <bytes> <cas unique> [noreply]
+func (c *conn) handleStore(ctx context.Context, mode string, args []string) error {
+	numArgs := 4
+	if mode == eval.MCModeCas {
+		numArgs = 5
+	}
+
+	args, quiet := parseNoReply(args, numArgs)
+	if len(args) != numArgs || !validKey(args[0]) {
+		c.writeClientError(errBadCommandLine)
+		return nil
+	}
+
+	_, flagsErr := strconv.ParseUint(args[1], 10, 32)
+	expiryMs, expiryErr := parseExpiry(args[2])
+	size, sizeErr := strconv.Atoi(args[3])
+	if flagsErr != nil || expiryErr != nil || sizeErr != nil || size < 0 {
+		c.writeClientError(errBadCommandLine)
+		return nil
+	}
+
+	if size > maxValueSize {
+		// Swallow the data block so that the connection stays usable.
+		if _, err := c.reader.Discard(size + 2); err != nil {
+			return err
+		}
+		c.writeLine("SERVER_ERROR object too large for cache")
+		return nil
+	}
+
+	data := make([]byte, size+2)
+	if _, err := io.ReadFull(c.reader, data); err != nil {
+		return err
+	}
+	if data[size] != '\r' || data[size+1] != '\n' {
+		c.writeClientError(errBadDataChunk)
+		return nil
+	}
+
+	storeArgs := []string{args[0], mode, string(data[:size]), args[1], strconv.FormatInt(expiryMs, 10)}
+	if mode == eval.MCModeCas {
+		if _, err := strconv.ParseUint(args[4], 10, 64); err != nil {
+			c.writeClientError(errBadCommandLine)
+			return nil
+		}
+		storeArgs = append(storeArgs, args[4])
+	}
+
+	c.server.stats.cmdSet.Add(1)
+	resp, err := c.executeOne(ctx, &cmd.DiceDBCmd{Cmd: eval.MCStore, Args: storeArgs})
+	if quiet {
+		return nil
+	}
+	if err != nil {
+		c.writeServerError(err)
+		return nil
+	}
+
+	c.writeResult(resp)
+	return nil
+}
+
+// handleDelete serves delete <key> [noreply].
+func (c *conn) handleDelete(ctx context.Context, args []string) error {
+	args, quiet := parseNoReply(args, 1)
+	if len(args) != 1 || !validKey(args[0]) {
+		c.writeClientError(errBadCommandLine)
+		return nil
+	}
+
+	resp, err := c.executeOne(ctx, &cmd.DiceDBCmd{Cmd: dstore.Del, Args: args})
+	if quiet {
+		return nil
+	}
+	if err != nil {
+		c.writeServerError(err)
+		return nil
+	}
+
+	if deleted, ok := resp.Result.(int64); ok && deleted > 0 {
+		c.writeLine("DELETED")
+	} else {
+		c.writeLine(eval.MCNotFound)
+	}
+	return nil
+}
+
+// handleIncrDecr serves incr and decr <key> <value> [noreply].
+func (c *conn) handleIncrDecr(ctx context.Context, name string, args []string) error {
+	args, quiet := parseNoReply(args, 2)
+	if len(args) != 2 || !validKey(args[0]) {
+		c.writeClientError(errBadCommandLine)
+		return nil
+	}
+
+	if _, err := strconv.ParseUint(args[1], 10, 64); err != nil {
+		c.writeLine("CLIENT_ERROR invalid numeric delta argument")
+		return nil
+	}
+
+	command := eval.MCIncr
+	if name == "decr" {
+		command = eval.MCDecr
+	}
+
+	resp, err := c.executeOne(ctx, &cmd.DiceDBCmd{Cmd: command, Args: args})
+	if quiet {
+		return nil
+	}
+	if err != nil {
+		c.writeServerError(err)
+		return nil
+	}
+
+	// The shard being out of memory is the only error of the server, see writeResult
+	if resp.Error != nil && !errors.Is(resp.Error, diceerrors.ErrOOM) {
+		c.writeLine("CLIENT_ERROR " + strings.TrimPrefix(resp.Error.Error(), "ERR "))
+		return nil
+	}
+
+	c.writeResult(resp)
+	return nil
+}
+
+// handleTouch serves touch <key> <exptime> [noreply].
+func (c *conn) handleTouch(ctx context.Context, args []string) error {
+	args, quiet := parseNoReply(args, 2)
+	if len(args) != 2 || !validKey(args[0]) {
+		c.writeClientError(errBadCommandLine)
+		return nil
+	}
+
+	expiryMs, err := parseExpiry(args[1])
+	if err != nil {
+		c.writeClientError(errBadCommandLine)
+		return nil
+	}
+
+	c.server.stats.cmdTouch.Add(1)
+	resp, err := c.executeOne(ctx, &cmd.DiceDBCmd{Cmd: eval.MCTouch, Args: []string{args[0], strconv.FormatInt(expiryMs, 10)}})
+	if quiet {
+		return nil
+	}
+	if err != nil {
+		c.writeServerError(err)
+		return nil
+	}
+
+	c.writeResult(resp)
+	return nil
+}
+
+// handleFlushAll serves flush_all [delay] [noreply]. A delayed flush is carried out in the background.
+func (c *conn) handleFlushAll(ctx context.Context, args []string) error {
+	args, quiet := parseNoReply(args, 1)
+	if len(args) > 1 {
+		c.writeClientError(errBadCommandLine)
+		return nil
+	}
+
+	var delay int64
+	if len(args) == 1 {
+		var err error
+		if delay, err = strconv.ParseInt(args[0], 10, 64); err != nil || delay < 0 {
+			c.writeClientError(errBadCommandLine)
+			return nil
+		}
+	}
+
+	c.server.stats.cmdFlush.Add(1)
+	flushCmd := &cmd.DiceDBCmd{Cmd: dstore.FlushDB}
+	if delay > 0 {
+		time.AfterFunc(time.Duration(delay)*time.Second, func() {
+			c.server.flushAll(context.Background())
+		})
+	} else if _, err := c.server.executeOnAllShards(ctx, c, flushCmd); err != nil {
+		if !quiet {
+			c.writeServerError(err)
+		}
+		return nil
+	}
+
+	if !quiet {
+		c.writeLine("OK")
+	}
+	return nil
+}
+
+// handleStats serves the general-purpose statistics.
+func (c *conn) handleStats() {
+	s := &c.server.stats
+	stats := [][2]string{
+		{"pid", strconv.Itoa(pid())},
+		{"uptime", strconv.FormatInt(c.server.uptime(), 10)},
+		{"time", strconv.FormatInt(utils.GetCurrentTime().Unix(), 10)},
+		{"version", config.DiceDBVersion},
+		{"curr_connections", strconv.FormatInt(s.currConnections.Load(), 10)},
+		{"total_connections", strconv.FormatUint(s.totalConnections.Load(), 10)},
+		{"cmd_get", strconv.FormatUint(s.cmdGet.Load(), 10)},
+		{"cmd_set", strconv.FormatUint(s.cmdSet.Load(), 10)},
+		{"cmd_flush", strconv.FormatUint(s.cmdFlush.Load(), 10)},
+		{"cmd_touch", strconv.FormatUint(s.cmdTouch.Load(), 10)},
+		{"get_hits", strconv.FormatUint(s.getHits.Load(), 10)},
+		{"get_misses", strconv.FormatUint(s.getMisses.Load(), 10)},
+		{"threads", strconv.Itoa(int(c.server.shardManager.GetShardCount()))},
+	}
+
+	for _, stat := range stats {
+		c.writeLine(fmt.Sprintf("STAT %s %s", stat[0], stat[1]))
+	}
+	c.writeLine("END")
+}
+
+// executeOne runs a single command on the shard owning its key.
+func (c *conn) executeOne(ctx context.Context, diceDBCmd *cmd.DiceDBCmd) (*eval.EvalResponse, error) {
+	responses, err := c.server.execute(ctx, c, []*cmd.DiceDBCmd{diceDBCmd})
+	if err != nil {
+		return nil, err
+	}
+	return responses[0].EvalResponse, nil
+}
+
+// writeResult writes the reply of an internal memcached command.
+func (c *conn) writeResult(resp *eval.EvalResponse) {
+	if resp.Error != nil {
+		c.writeServerError(resp.Error)
+		return
+	}
+	c.writeLine(fmt.Sprintf("%v", resp.Result))
+}
+
+func (c *conn) writeClientError(err error) {
+	c.writeLine("CLIENT_ERROR " + err.Error())
+}
+
+func (c *conn) writeServerError(err error) {
+	c.writeLine("SERVER_ERROR " + strings.TrimPrefix(err.Error(), "ERR "))
+}
+
+func (c *conn) writeLine(line string) {
+	c.writer.WriteString(line)
+	c.writer.WriteString("\r\n")
+}
+
+// readLine reads a request line, without its line terminator.
+func (c *conn) readLine() (string, error) {
+	line, err := c.reader.ReadSlice('\n')
+	if errors.Is(err, bufio.ErrBufferFull) {
+		return "", errLineTooLong
+	}
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimRight(string(line), "\r\n"), nil
+}
+
+// parseNoReply strips the optional trailing noreply argument.
+func parseNoReply(args []string, numArgs int) ([]string, bool) {
+	if len(args) == numArgs+1 && args[numArgs] == noReply {
+		return args[:numArgs], true
+	}
+	return args, false
+}
+
+// parseExpiry converts a memcached exptime into the expiry understood by the internal
+// commands: -1 for no expiry, 0 for an item that expires immediately, or a duration in ms.
+func parseExpiry(exptime string) (int64, error) {
+	seconds, err := strconv.ParseInt(exptime, 10, 64)
+	if err != nil {
+		return 0, err
+	}
+
+	switch {
+	case seconds == 0:
+		return -1, nil
+	case seconds < 0:
+		return 0, nil
+	case seconds <= maxRelativeExpiry:
+		return seconds * 1000, nil
+	default:
+		expiryMs := seconds*1000 - utils.GetCurrentTime().UnixMilli()
+		if expiryMs < 0 {
+			expiryMs = 0
+		}
+		return expiryMs, nil
+	}
+}
+
+// validKey reports whether key is a valid memcached key.
+func validKey(key string) bool {
+	if key == "" || len(key) > maxKeyLength {
+		return false
+	}
+	for i := 0; i < len(key); i++ {
+		if key[i] <= ' ' || key[i] == 0x7f {
+			return false
+		}
+	}
+	return true
+}
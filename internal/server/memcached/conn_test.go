@@ -0,0 +1,59 @@
+// Copyright (c) 2022-present, DiceDB contributors
+// All rights reserved. Licensed under the BSD 3-Clause License. See LICENSE file in the project root for full license information.
+
+package memcached
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/dicedb/dice/internal/server/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseExpiry(t *testing.T) {
+	tests := map[string]int64{
+		"0":    -1,
+		"-1":   0,
+		"10":   10000,
+		"1000": 1000000,
+	}
+	for exptime, expected := range tests {
+		expiryMs, err := parseExpiry(exptime)
+		assert.NoError(t, err, exptime)
+		assert.Equal(t, expected, expiryMs, exptime)
+	}
+
+	// Values larger than 30 days are absolute unix timestamps.
+	past := strconv.FormatInt(utils.GetCurrentTime().Unix()-maxRelativeExpiry, 10)
+	expiryMs, err := parseExpiry(past)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(0), expiryMs)
+
+	future := strconv.FormatInt(utils.GetCurrentTime().Unix()+2*maxRelativeExpiry, 10)
+	expiryMs, err = parseExpiry(future)
+	assert.NoError(t, err)
+	assert.Greater(t, expiryMs, int64(maxRelativeExpiry*1000))
+
+	_, err = parseExpiry("abc")
+	assert.Error(t, err)
+}
+
+func TestValidKey(t *testing.T) {
+	assert.True(t, validKey("foo:bar"))
+	assert.False(t, validKey(""))
+	assert.False(t, validKey("foo bar"))
+	assert.False(t, validKey("foo\x7f"))
+	assert.False(t, validKey(strings.Repeat("k", maxKeyLength+1)))
+}
+
+func TestParseNoReply(t *testing.T) {
+	args, quiet := parseNoReply([]string{"k", "0", "0", "1", noReply}, 4)
+	assert.True(t, quiet)
+	assert.Equal(t, []string{"k", "0", "0", "1"}, args)
+
+	args, quiet = parseNoReply([]string{"k", "0", "0", "1"}, 4)
+	assert.False(t, quiet)
+	assert.Len(t, args, 4)
+}
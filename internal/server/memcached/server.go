@@ -0,0 +1,215 @@
+// Copyright (c) 2022-present, DiceDB contributors
+// All rights reserved. Licensed under the BSD 3-Clause License. See LICENSE file in the project root for full license information.
+
+// Package memcached implements a listener for the memcached text protocol. Memcached items
+// are stored as regular DiceDB strings through the ShardManager, so the same keys can be
+// read and written over both protocols.
+//
+// The client flags of an item are stored with its object, and its CAS unique is the version of the
+// object, which changes every time the item is stored or modified.
+package memcached
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/dicedb/dice/internal/cmd"
+	"github.com/dicedb/dice/internal/commandhandler"
+	"github.com/dicedb/dice/internal/ops"
+	"github.com/dicedb/dice/internal/server/abstractserver"
+	"github.com/dicedb/dice/internal/shard"
+	dstore "github.com/dicedb/dice/internal/store"
+)
+
+var connCounter uint64
+
+// stats holds the counters reported by the stats command.
+type stats struct {
+	currConnections  atomic.Int64
+	totalConnections atomic.Uint64
+	cmdGet           atomic.Uint64
+	cmdSet           atomic.Uint64
+	cmdTouch         atomic.Uint64
+	cmdFlush         atomic.Uint64
+	getHits          atomic.Uint64
+	getMisses        atomic.Uint64
+}
+
+type Server struct {
+	abstractserver.AbstractServer
+	Port         int
+	shardManager *shard.ShardManager
+	listener     net.Listener
+	startTime    time.Time
+	stats        stats
+}
+
+func NewServer(shardManager *shard.ShardManager, port int) *Server {
+	return &Server{
+		Port:         port,
+		shardManager: shardManager,
+		startTime:    time.Now(),
+	}
+}
+
+func (s *Server) Run(ctx context.Context) error {
+	listener, err := net.Listen("tcp", fmt.Sprintf(":%d", s.Port))
+	if err != nil {
+		return fmt.Errorf("failed to listen for memcached connections: %w", err)
+	}
+	s.listener = listener
+
+	slog.Info("also listening memcached on", slog.Int("port", s.Port))
+
+	var wg sync.WaitGroup
+	defer wg.Wait()
+
+	go func() {
+		<-ctx.Done()
+		listener.Close()
+	}()
+
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			if ctx.Err() != nil || errors.Is(err, net.ErrClosed) {
+				return ctx.Err()
+			}
+			slog.Error("error accepting memcached connection", slog.Any("error", err))
+			continue
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			s.serveConn(ctx, conn)
+		}()
+	}
+}
+
+// serveConn processes the requests of a single connection until it is closed.
+func (s *Server) serveConn(ctx context.Context, netConn net.Conn) {
+	id := fmt.Sprintf("memcached-%d", atomic.AddUint64(&connCounter, 1))
+	responseChan := make(chan *ops.StoreResponse)
+	s.shardManager.RegisterCommandHandler(id, responseChan, nil)
+	defer s.shardManager.UnregisterCommandHandler(id)
+
+	s.stats.currConnections.Add(1)
+	s.stats.totalConnections.Add(1)
+	defer s.stats.currConnections.Add(-1)
+
+	connCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	go func() {
+		<-connCtx.Done()
+		netConn.Close()
+	}()
+
+	c := newConn(s, id, netConn, responseChan)
+	if err := c.serve(connCtx); err != nil && !errors.Is(err, net.ErrClosed) {
+		slog.Debug("memcached connection closed", slog.String("id", id), slog.Any("error", err))
+	}
+}
+
+// execute runs the commands on the shards owning their keys and returns the responses in order.
+func (s *Server) execute(ctx context.Context, c *conn, cmds []*cmd.DiceDBCmd) ([]ops.StoreResponse, error) {
+	execCtx, cancel := commandhandler.RequestContext(ctx, cmds...)
+	defer cancel()
+
+	requests := make(map[uint32]int, len(cmds))
+	for i, diceDBCmd := range cmds {
+		shardID, reqChan := s.shardManager.GetShardInfo(diceDBCmd.GetKey())
+		requestID := commandhandler.GenerateUniqueRequestID()
+		requests[requestID] = i
+		op := &ops.StoreOp{
+			RequestID:    requestID,
+			Cmd:          diceDBCmd,
+			CmdHandlerID: c.id,
+			ShardID:      shardID,
+			MemcachedOp:  true,
+			Ctx:          execCtx,
+		}
+
+		select {
+		case reqChan <- op:
+		case <-execCtx.Done():
+			return nil, execCtx.Err()
+		}
+	}
+
+	return s.gather(execCtx, c, requests)
+}
+
+// executeOnAllShards runs the command on every shard.
+func (s *Server) executeOnAllShards(ctx context.Context, c *conn, diceDBCmd *cmd.DiceDBCmd) ([]ops.StoreResponse, error) {
+	execCtx, cancel := commandhandler.RequestContext(ctx, diceDBCmd)
+	defer cancel()
+
+	numShards := int(s.shardManager.GetShardCount())
+	requests := make(map[uint32]int, numShards)
+	for i := 0; i < numShards; i++ {
+		requestID := commandhandler.GenerateUniqueRequestID()
+		requests[requestID] = i
+		op := &ops.StoreOp{
+			RequestID:    requestID,
+			Cmd:          diceDBCmd,
+			CmdHandlerID: c.id,
+			ShardID:      uint8(i),
+			MemcachedOp:  true,
+			Ctx:          execCtx,
+		}
+
+		select {
+		case s.shardManager.GetShard(uint8(i)).ReqChan <- op:
+		case <-execCtx.Done():
+			return nil, execCtx.Err()
+		}
+	}
+
+	return s.gather(execCtx, c, requests)
+}
+
+// gather waits for the responses to the given requests on the connection's response channel.
+// The responses are ordered by the index that each request ID maps to.
+func (s *Server) gather(ctx context.Context, c *conn, requests map[uint32]int) ([]ops.StoreResponse, error) {
+	responses := make([]ops.StoreResponse, len(requests))
+	for pending := len(requests); pending > 0; {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case resp := <-c.responseChan:
+			if i, ok := requests[resp.RequestID]; ok {
+				responses[i] = *resp
+				delete(requests, resp.RequestID)
+				pending--
+			}
+		}
+	}
+	return responses, nil
+}
+
+// flushAll deletes all the keys on every shard.
+func (s *Server) flushAll(ctx context.Context) {
+	c := &conn{id: fmt.Sprintf("memcached-flush-%d", time.Now().UnixNano()), responseChan: make(chan *ops.StoreResponse)}
+	s.shardManager.RegisterCommandHandler(c.id, c.responseChan, nil)
+	defer s.shardManager.UnregisterCommandHandler(c.id)
+
+	if _, err := s.executeOnAllShards(ctx, c, &cmd.DiceDBCmd{Cmd: dstore.FlushDB}); err != nil {
+		slog.Warn("delayed flush_all failed", slog.Any("error", err))
+	}
+}
+
+func (s *Server) uptime() int64 {
+	return int64(time.Since(s.startTime).Seconds())
+}
+
+func pid() int {
+	return os.Getpid()
+}
@@ -9,6 +9,7 @@ import (
 	"fmt"
 	"log/slog"
 	"net"
+	"strconv"
 	"sync"
 	"sync/atomic"
 	"syscall"
@@ -84,6 +85,13 @@ func (s *Server) Run(ctx context.Context) (err error) {
 
 	defer s.ReleasePort()
 
+	commandhandler.SetServerInfo(&commandhandler.ServerInfo{
+		StartTime: time.Now(),
+		IOThreads: s.ioThreadManager,
+		WAL:       s.wl,
+		Watches:   s.watchManager,
+	})
+
 	// Start a go routine to accept connections
 	errChan := make(chan error, 1)
 	wg := &sync.WaitGroup{}
@@ -183,7 +191,7 @@ func (s *Server) AcceptConnectionRequests(ctx context.Context, wg *sync.WaitGrou
 
 			return ctx.Err()
 		default:
-			clientFD, _, err := syscall.Accept(s.serverFD)
+			clientFD, clientAddr, err := syscall.Accept(s.serverFD)
 			if err != nil {
 				if errors.Is(err, syscall.EAGAIN) || errors.Is(err, syscall.EWOULDBLOCK) {
 					continue // No more connections to accept at this time
@@ -215,6 +223,7 @@ func (s *Server) AcceptConnectionRequests(ctx context.Context, wg *sync.WaitGrou
 			handler := commandhandler.NewCommandHandler(cmdHandlerID, responseChan, preprocessingChan,
 				s.cmdWatchSubscriptionChan, parser, s.shardManager, s.globalErrorChan,
 				ioThreadReadChan, ioThreadWriteChan, ioThreadErrChan, s.wl)
+			handler.Session.ClientAddr = sockaddrString(clientAddr)
 
 			// Register the io-thread with the manager
 			err = s.ioThreadManager.RegisterIOThread(thread)
@@ -230,15 +239,20 @@ func (s *Server) AcceptConnectionRequests(ctx context.Context, wg *sync.WaitGrou
 				continue
 			}
 
-			// Registration for both IO thread and command handler is done to ensure there is no error before starting the goroutines
+			// Let the io-thread know when the client class changes, so the right output buffer limit applies
+			handler.SetClientClassNotifier(thread.SetClientClass)
+
+			// Registration for both IO thread and command handler is done to ensure there is no error before starting the goroutines.
+			// Both share the connection context, so that whichever stops first also stops the other one.
+			connCtx, connCancel := context.WithCancel(ctx)
 			wg.Add(2)
-			go s.startIOThread(ctx, wg, thread)
-			go s.startCommandHandler(ctx, wg, handler)
+			go s.startIOThread(connCtx, connCancel, wg, thread)
+			go s.startCommandHandler(connCtx, connCancel, wg, handler)
 		}
 	}
 }
 
-func (s *Server) startIOThread(ctx context.Context, wg *sync.WaitGroup, thread *iothread.BaseIOThread) {
+func (s *Server) startIOThread(ctx context.Context, cancel context.CancelFunc, wg *sync.WaitGroup, thread *iothread.BaseIOThread) {
 	wg.Done()
 	defer func(wm *iothread.Manager, id string) {
 		err := wm.UnregisterIOThread(id)
@@ -246,15 +260,14 @@ func (s *Server) startIOThread(ctx context.Context, wg *sync.WaitGroup, thread *
 			slog.Warn("Failed to unregister io-thread", slog.String("id", id), slog.Any("error", err))
 		}
 	}(s.ioThreadManager, thread.ID())
-	ctx2, cancel := context.WithCancel(ctx)
 	defer cancel()
-	err := thread.Start(ctx2)
+	err := thread.Start(ctx)
 	if err != nil {
 		slog.Debug("IOThread stopped", slog.String("id", thread.ID()), slog.Any("error", err))
 	}
 }
 
-func (s *Server) startCommandHandler(ctx context.Context, wg *sync.WaitGroup, cmdHandler *commandhandler.BaseCommandHandler) {
+func (s *Server) startCommandHandler(ctx context.Context, cancel context.CancelFunc, wg *sync.WaitGroup, cmdHandler *commandhandler.BaseCommandHandler) {
 	wg.Done()
 	defer func(wm *commandhandler.Registry, id string) {
 		err := wm.UnregisterCommandHandler(id)
@@ -262,9 +275,8 @@ func (s *Server) startCommandHandler(ctx context.Context, wg *sync.WaitGroup, cm
 			slog.Warn("Failed to unregister command handler", slog.String("id", id), slog.Any("error", err))
 		}
 	}(s.cmdHandlerManager, cmdHandler.ID())
-	ctx2, cancel := context.WithCancel(ctx)
 	defer cancel()
-	err := cmdHandler.Start(ctx2)
+	err := cmdHandler.Start(ctx)
 	if err != nil {
 		slog.Debug("CommandHandler stopped", slog.String("id", cmdHandler.ID()), slog.Any("error", err))
 	}
@@ -287,3 +299,16 @@ func GenerateUniqueID(prefix string, counter *uint64) string {
 func (s *Server) Shutdown() {
 	// Not implemented
 }
+
+// sockaddrString formats the address of a client as host:port, or returns an empty string if the
+// address is not an IP one.
+func sockaddrString(sa syscall.Sockaddr) string {
+	switch addr := sa.(type) {
+	case *syscall.SockaddrInet4:
+		return net.JoinHostPort(net.IP(addr.Addr[:]).String(), strconv.Itoa(addr.Port))
+	case *syscall.SockaddrInet6:
+		return net.JoinHostPort(net.IP(addr.Addr[:]).String(), strconv.Itoa(addr.Port))
+	default:
+		return ""
+	}
+}
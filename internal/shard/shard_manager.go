@@ -36,10 +36,11 @@ func NewShardManager(shardCount uint8, cmdWatchChan chan dstore.CmdWatchEvent, g
 	shardErrorChan := make(chan *ShardError)
 
 	maxKeysPerShard := config.DiceConfig.Memory.KeysLimit / int(shardCount)
+	maxMemoryPerShard := config.DiceConfig.Memory.MaxMemory / int64(shardCount)
 	for i := uint8(0); i < shardCount; i++ {
-		evictionStrategy := dstore.NewBatchEvictionLRU(maxKeysPerShard, config.DiceConfig.Memory.EvictionRatio)
+		evictionStrategy := newEvictionStrategy(maxKeysPerShard)
 		// Shards are numbered from 0 to shardCount-1
-		shard := NewShardThread(i, globalErrorChan, shardErrorChan, cmdWatchChan, evictionStrategy)
+		shard := NewShardThread(i, globalErrorChan, shardErrorChan, cmdWatchChan, evictionStrategy, maxMemoryPerShard)
 		shards[i] = shard
 		shardReqMap[i] = shard.ReqChan
 	}
@@ -54,6 +55,33 @@ func NewShardManager(shardCount uint8, cmdWatchChan chan dstore.CmdWatchEvent, g
 	}
 }
 
+// newEvictionStrategy returns the eviction strategy of a shard for the configured eviction policy.
+func newEvictionStrategy(maxKeys int) dstore.EvictionStrategy {
+	evictionRatio := config.DiceConfig.Memory.EvictionRatio
+	switch config.DiceConfig.Memory.EvictionPolicy {
+	case config.EvictSimpleFirst:
+		return dstore.NewSimpleFirst(maxKeys, evictionRatio)
+	case config.EvictAllKeysRandom:
+		return dstore.NewAllKeysRandom(maxKeys, evictionRatio)
+	case config.EvictAllKeysLRU:
+		return dstore.NewAllKeysLRU(maxKeys, evictionRatio)
+	case config.EvictAllKeysLFU:
+		return dstore.NewAllKeysLFU(maxKeys, evictionRatio, config.DiceConfig.Memory.LFULogFactor)
+	case config.EvictVolatileLRU:
+		return dstore.NewVolatileLRU(maxKeys, evictionRatio)
+	case config.EvictVolatileLFU:
+		return dstore.NewVolatileLFU(maxKeys, evictionRatio, config.DiceConfig.Memory.LFULogFactor)
+	case config.EvictVolatileRandom:
+		return dstore.NewVolatileRandom(maxKeys, evictionRatio)
+	case config.EvictVolatileTTL:
+		return dstore.NewVolatileTTL(maxKeys, evictionRatio)
+	case config.EvictNoEviction:
+		return dstore.NewNoEviction()
+	default:
+		return dstore.NewBatchEvictionLRU(maxKeys, evictionRatio)
+	}
+}
+
 // Run starts the ShardManager, manages its lifecycle, and listens for errors.
 func (manager *ShardManager) Run(ctx context.Context) {
 	signal.Notify(manager.sigChan, syscall.SIGINT, syscall.SIGTERM)
@@ -6,10 +6,15 @@ package shard
 import (
 	"context"
 	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/dicedb/dice/config"
+	"github.com/dicedb/dice/internal/cmd"
 	diceerrors "github.com/dicedb/dice/internal/errors"
 	"github.com/dicedb/dice/internal/eval"
 	"github.com/dicedb/dice/internal/ops"
@@ -42,14 +47,30 @@ type ShardThread struct {
 	shardErrorChan   chan *ShardError // ShardErrorChan is the channel for sending shard-level errors.
 	lastCronExecTime time.Time        // lastCronExecTime is the last time the shard executed cron tasks.
 	cronFrequency    time.Duration    // cronFrequency is the frequency at which the shard executes cron tasks.
+
+	// stats is refreshed by the cron tasks, see Stats.
+	stats atomic.Pointer[ShardStats]
+}
+
+// ShardStats is a snapshot of the statistics of a shard, which its cron tasks refresh so that the
+// other goroutines can read them, see ShardThread.Stats.
+type ShardStats struct {
+	Keys  int // Keys is the number of keys of all the databases.
+	Store dstore.Stats
 }
 
-// NewShardThread creates a new ShardThread instance with the given shard id and error channel.
+// NewShardThread creates a new ShardThread instance with the given shard id and error channel. The
+// keys of the shard may use up to maxMemory bytes, without limit if it is 0.
 func NewShardThread(id ShardID, gec chan error, sec chan *ShardError,
-	cmdWatchChan chan dstore.CmdWatchEvent, evictionStrategy dstore.EvictionStrategy) *ShardThread {
-	return &ShardThread{
+	cmdWatchChan chan dstore.CmdWatchEvent, evictionStrategy dstore.EvictionStrategy, maxMemory int64) *ShardThread {
+	store := dstore.NewStore(cmdWatchChan, evictionStrategy)
+	store.SetMaxMemory(maxMemory)
+	if dir := config.DiceConfig.Memory.SpillDir; dir != "" {
+		openDiskTier(id, dir, store)
+	}
+	shard := &ShardThread{
 		id:               id,
-		store:            dstore.NewStore(cmdWatchChan, evictionStrategy),
+		store:            store,
 		ReqChan:          make(chan *ops.StoreOp, 1000),
 		cmdHandlerMap:    make(map[string]CmdHandlerChannels),
 		globalErrorChan:  gec,
@@ -57,6 +78,24 @@ func NewShardThread(id ShardID, gec chan error, sec chan *ShardError,
 		lastCronExecTime: utils.GetCurrentTime(),
 		cronFrequency:    config.DiceConfig.Performance.ShardCronFrequency,
 	}
+	shard.refreshStats()
+	return shard
+}
+
+// openDiskTier makes the store of the shard spill the keys it evicts to a file of dir. The keys are
+// evicted as usual if the file cannot be opened.
+func openDiskTier(id ShardID, dir string, store *dstore.Store) {
+	path := filepath.Join(dir, fmt.Sprintf("shard-%d.spill", id))
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		slog.Error("could not create the spill directory, evicting the keys", slog.String("dir", dir), slog.Any("error", err))
+		return
+	}
+	tier, err := dstore.NewDiskTier(path, eval.DumpCodec{})
+	if err != nil {
+		slog.Error("could not open the disk tier, evicting the keys", slog.String("path", path), slog.Any("error", err))
+		return
+	}
+	store.SetDiskTier(tier)
 }
 
 // Start starts the shard thread, listening for incoming requests.
@@ -77,12 +116,35 @@ func (shard *ShardThread) Start(ctx context.Context) {
 	}
 }
 
-// runCronTasks runs the cron tasks for the shard. This includes deleting expired keys.
+// runCronTasks runs the cron tasks for the shard. This includes deleting expired keys, for at most
+// a quarter of the cron period so that the shard keeps serving the requests, the keys left being
+// deleted by the next runs.
 func (shard *ShardThread) runCronTasks() {
-	dstore.DeleteExpiredKeys(shard.store)
+	dstore.DeleteExpiredKeysWithin(shard.store, shard.cronFrequency/4)
+	shard.refreshStats()
 	shard.lastCronExecTime = utils.GetCurrentTime()
 }
 
+// refreshStats takes a snapshot of the statistics of the shard.
+func (shard *ShardThread) refreshStats() {
+	stats := &ShardStats{Store: shard.store.Stats()}
+	for _, db := range shard.store.DatabaseStats() {
+		stats.Keys += db.Keys
+	}
+	shard.stats.Store(stats)
+}
+
+// Stats returns the statistics of the shard as of its last cron tasks. It is safe to call from any
+// goroutine.
+func (shard *ShardThread) Stats() ShardStats {
+	return *shard.stats.Load()
+}
+
+// QueueDepth returns the number of requests waiting to be processed by the shard.
+func (shard *ShardThread) QueueDepth() int {
+	return len(shard.ReqChan)
+}
+
 func (shard *ShardThread) registerCommandHandler(id string, responseChan, preprocessingChan chan *ops.StoreResponse) {
 	shard.mu.Lock()
 	shard.cmdHandlerMap[id] = CmdHandlerChannels{
@@ -101,6 +163,11 @@ func (shard *ShardThread) unregisterCommandHandler(id string) {
 
 // processRequest processes a Store operation for the shard.
 func (shard *ShardThread) processRequest(op *ops.StoreOp) {
+	// Skip the operation altogether if its sender has already given up on it, e.g. on a timeout.
+	if op.Ctx != nil && op.Ctx.Err() != nil {
+		return
+	}
+
 	shard.mu.RLock()
 	channels, ok := shard.cmdHandlerMap[op.CmdHandlerID]
 	shard.mu.RUnlock()
@@ -113,7 +180,13 @@ func (shard *ShardThread) processRequest(op *ops.StoreOp) {
 		SeqID:     op.SeqID,
 	}
 
-	e := eval.NewEval(op.Cmd, op.Client, shard.store, op.HTTPOp, op.WebsocketOp, op.PreProcessing)
+	if len(op.Batch) > 0 {
+		shard.processBatch(op, ok, cmdHandlerChan)
+		return
+	}
+
+	shard.store.SelectDB(op.Database)
+	e := eval.NewEval(op.Cmd, op.Client, shard.store, op.HTTPOp, op.WebsocketOp, op.MemcachedOp, op.PreProcessing)
 
 	if op.PreProcessing {
 		resp := e.PreProcessCommand()
@@ -122,7 +195,7 @@ func (shard *ShardThread) processRequest(op *ops.StoreOp) {
 		return
 	}
 
-	resp := e.ExecuteCommand()
+	resp := shard.execute(e, op.Cmd, op)
 	if ok {
 		sp.EvalResponse = resp
 	} else {
@@ -132,12 +205,80 @@ func (shard *ShardThread) processRequest(op *ops.StoreOp) {
 		}
 	}
 
-	cmdHandlerChan <- sp
+	shard.sendResponse(op, cmdHandlerChan, sp)
+}
+
+// processBatch executes the commands of a batch one after the other, so that no other operation
+// runs on the shard in between. Each command gets its own response, sent in the order of the batch
+// and carrying the SeqID of the operation offset by the index of the command.
+func (shard *ShardThread) processBatch(op *ops.StoreOp, ok bool, cmdHandlerChan chan *ops.StoreResponse) {
+	if !ok {
+		shard.shardErrorChan <- &ShardError{
+			ShardID: shard.id,
+			Error:   fmt.Errorf(diceerrors.CmdHandlerNotFoundErr, op.CmdHandlerID),
+		}
+		return
+	}
+
+	shard.store.SelectDB(op.Database)
+	responses := make([]*ops.StoreResponse, len(op.Batch))
+	for i, diceDBCmd := range op.Batch {
+		e := eval.NewEval(diceDBCmd, op.Client, shard.store, op.HTTPOp, op.WebsocketOp, op.MemcachedOp, false)
+		responses[i] = &ops.StoreResponse{
+			RequestID:    op.RequestID,
+			SeqID:        op.SeqID + uint8(i),
+			EvalResponse: shard.execute(e, diceDBCmd, op),
+		}
+	}
+
+	for _, sp := range responses {
+		if !shard.sendResponse(op, cmdHandlerChan, sp) {
+			return
+		}
+	}
+}
+
+// execute executes a command of the operation, recording it in the slowlog if it took at least the
+// slowlog-log-slower-than threshold. SLOWLOG itself is not recorded, so that reading the slowlog does
+// not fill it.
+func (shard *ShardThread) execute(e *eval.Eval, diceDBCmd *cmd.DiceDBCmd, op *ops.StoreOp) *eval.EvalResponse {
+	start := time.Now()
+	resp := e.ExecuteCommand()
+	duration := time.Since(start)
+
+	threshold := config.DiceConfig.Performance.SlowlogLogSlowerThan
+	if threshold >= 0 && duration.Microseconds() >= threshold && diceDBCmd.Cmd != eval.SlowLogCmd {
+		shard.store.SlowLog().Add(diceDBCmd.Cmd, diceDBCmd.Args, duration, op.ClientAddr, op.ClientName)
+	}
+	return resp
+}
+
+// sendResponse sends the response of the operation to its command handler. It returns false if the
+// sender stopped waiting for the response.
+func (shard *ShardThread) sendResponse(op *ops.StoreOp, cmdHandlerChan chan *ops.StoreResponse, sp *ops.StoreResponse) bool {
+	if op.Ctx == nil {
+		cmdHandlerChan <- sp
+		return true
+	}
+
+	// The sender may stop waiting while the command executes. Drop the response in that case, instead of
+	// blocking the shard or leaving it to be picked up as the response of a later request.
+	select {
+	case cmdHandlerChan <- sp:
+		return true
+	case <-op.Ctx.Done():
+		return false
+	}
 }
 
 // cleanup handles cleanup logic when the shard stops.
 func (shard *ShardThread) cleanup() {
 	close(shard.ReqChan)
+	if tier := shard.store.DiskTier(); tier != nil {
+		if err := tier.Close(); err != nil {
+			slog.Warn("could not remove the disk tier", slog.Any("error", err))
+		}
+	}
 	if !config.DiceConfig.Persistence.Enabled || !config.DiceConfig.Persistence.WriteAOFOnCleanup {
 		return
 	}
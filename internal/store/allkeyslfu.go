@@ -0,0 +1,100 @@
+// Copyright (c) 2022-present, DiceDB contributors
+// All rights reserved. Licensed under the BSD 3-Clause License. See LICENSE file in the project root for full license information.
+
+package store
+
+import (
+	"math"
+	"math/rand"
+
+	"github.com/dicedb/dice/internal/object"
+)
+
+const (
+	// lfuInitialCount is the access counter of the keys just written, so that they are not evicted
+	// before they get a chance to be accessed.
+	lfuInitialCount = 5
+
+	// lfuDecayPeriod is the number of seconds a key must be idle for to decrement its access
+	// counter by one.
+	lfuDecayPeriod = 60
+)
+
+// AllKeysLFU approximates the eviction of the least frequently used keys: each victim is the key
+// with the lowest access counter out of a sample of the keys, or the key idle for the longest time
+// among them if their counters are equal.
+type AllKeysLFU struct {
+	BaseEvictionStrategy
+	keysLimit
+	lfuCounter
+}
+
+func NewAllKeysLFU(maxKeys int, evictionRatio float64, logFactor int) *AllKeysLFU {
+	return &AllKeysLFU{
+		keysLimit:  keysLimit{maxKeys: maxKeys, evictionRatio: evictionRatio},
+		lfuCounter: lfuCounter{logFactor: logFactor},
+	}
+}
+
+// EvictVictims deletes toEvict keys from the store, each the least frequently used of its sample.
+func (e *AllKeysLFU) EvictVictims(store *Store, toEvict int) {
+	if toEvict <= 0 {
+		return
+	}
+
+	evicted := evictSampled(store, toEvict, sampleKeys, evictionSamples, lfuScore)
+	e.stats.recordEviction(evicted)
+}
+
+// lfuCounter counts the accesses to the objects for the LFU eviction strategies.
+//
+// The access counter of an object is a Morris counter, which fits in the byte of
+// object.Obj.LFUCounter: it is incremented with a probability that decreases as it grows, at a
+// rate set by logFactor, so that it grows with the logarithm of the number of accesses. It is also
+// decremented for each lfuDecayPeriod the object has been idle for, so that the keys accessed a lot
+// in the past can be evicted once they are not anymore.
+type lfuCounter struct {
+	logFactor int
+}
+
+// OnAccess decays the access counter of the object, before the store updates its access time,
+// then increments it.
+func (c lfuCounter) OnAccess(key string, obj *object.Obj, accessType AccessType) {
+	switch accessType {
+	case AccessGet:
+		obj.LFUCounter = c.increment(lfuDecayedCount(obj))
+	case AccessSet:
+		// The keys written count at least as much as new keys, else they could be evicted right away
+		obj.LFUCounter = c.increment(max(lfuDecayedCount(obj), lfuInitialCount))
+	}
+}
+
+// increment increments the access counter with a probability of 1 / ((counter - lfuInitialCount) *
+// logFactor + 1), until the counter saturates.
+func (c lfuCounter) increment(counter uint8) uint8 {
+	if counter == math.MaxUint8 {
+		return counter
+	}
+
+	base := max(int(counter)-lfuInitialCount, 0)
+	if rand.Float64() < 1/float64(base*c.logFactor+1) {
+		counter++
+	}
+	return counter
+}
+
+// lfuDecayedCount returns the access counter of the object, decremented by one for each
+// lfuDecayPeriod it has been idle for.
+func lfuDecayedCount(obj *object.Obj) uint8 {
+	periods := GetIdleTime(obj.LastAccessedAt) / lfuDecayPeriod
+	if periods >= uint32(obj.LFUCounter) {
+		return 0
+	}
+	return obj.LFUCounter - uint8(periods)
+}
+
+// lfuScore scores the objects by their access counter, inverted so that the lowest one scores the
+// highest, then by their idle time to break ties.
+func lfuScore(obj *object.Obj) uint64 {
+	return uint64(math.MaxUint8-lfuDecayedCount(obj))<<32 | lruScore(obj)
+}
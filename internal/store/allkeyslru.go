@@ -0,0 +1,41 @@
+// Copyright (c) 2022-present, DiceDB contributors
+// All rights reserved. Licensed under the BSD 3-Clause License. See LICENSE file in the project root for full license information.
+
+package store
+
+import (
+	"github.com/dicedb/dice/internal/object"
+)
+
+// AllKeysLRU approximates the eviction of the least recently used keys: each victim is the key
+// idle for the longest time out of a sample of the keys. Unlike BatchEvictionLRU, it does not
+// iterate over all the keys to evict them.
+type AllKeysLRU struct {
+	BaseEvictionStrategy
+	keysLimit
+}
+
+func NewAllKeysLRU(maxKeys int, evictionRatio float64) *AllKeysLRU {
+	return &AllKeysLRU{
+		keysLimit: keysLimit{maxKeys: maxKeys, evictionRatio: evictionRatio},
+	}
+}
+
+// EvictVictims deletes toEvict keys from the store, each the least recently used of its sample.
+func (e *AllKeysLRU) EvictVictims(store *Store, toEvict int) {
+	if toEvict <= 0 {
+		return
+	}
+
+	evicted := evictSampled(store, toEvict, sampleKeys, evictionSamples, lruScore)
+	e.stats.recordEviction(evicted)
+}
+
+// lruScore scores the objects by their idle time.
+func lruScore(obj *object.Obj) uint64 {
+	return uint64(GetIdleTime(obj.LastAccessedAt))
+}
+
+func (e *AllKeysLRU) OnAccess(key string, obj *object.Obj, accessType AccessType) {
+	// Nothing to do, the store keeps the access time of the objects
+}
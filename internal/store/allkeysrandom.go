@@ -0,0 +1,40 @@
+// Copyright (c) 2022-present, DiceDB contributors
+// All rights reserved. Licensed under the BSD 3-Clause License. See LICENSE file in the project root for full license information.
+
+package store
+
+import (
+	"github.com/dicedb/dice/internal/object"
+)
+
+// AllKeysRandom evicts random keys. Unlike SimpleFirst, each victim is picked by a new iteration
+// over the store, so that the victims are spread over the keys.
+type AllKeysRandom struct {
+	BaseEvictionStrategy
+	keysLimit
+}
+
+func NewAllKeysRandom(maxKeys int, evictionRatio float64) *AllKeysRandom {
+	return &AllKeysRandom{
+		keysLimit: keysLimit{maxKeys: maxKeys, evictionRatio: evictionRatio},
+	}
+}
+
+// EvictVictims deletes toEvict random keys from the store.
+func (e *AllKeysRandom) EvictVictims(store *Store, toEvict int) {
+	if toEvict <= 0 {
+		return
+	}
+
+	evicted := evictSampled(store, toEvict, sampleKeys, 1, randomScore)
+	e.stats.recordEviction(evicted)
+}
+
+// randomScore scores all the objects the same, for the first sampled one to be evicted.
+func randomScore(obj *object.Obj) uint64 {
+	return 0
+}
+
+func (e *AllKeysRandom) OnAccess(key string, obj *object.Obj, accessType AccessType) {
+	// Nothing to do, the accesses do not matter
+}
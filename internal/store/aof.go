@@ -10,6 +10,7 @@ import (
 	"io/fs"
 	"log"
 	"os"
+	"strconv"
 	"strings"
 	"sync"
 
@@ -111,7 +112,26 @@ func dumpKey(aof *AOF, key string, obj *object.Obj) (err error) {
 	return aof.Write(string(encode(tokens)))
 }
 
-// DumpAllAOF dumps all keys in the store to the AOF file
+// dumpHash dumps the hash as HSET, followed by HPEXPIREAT for each field that expires.
+func dumpHash(aof *AOF, key string, fields FieldMap, deadlines map[string]uint64) error {
+	tokens := []string{"HSET", key}
+	fields.ForEachField(func(field, value string) {
+		tokens = append(tokens, field, value)
+	})
+	if err := aof.Write(string(encode(tokens))); err != nil {
+		return err
+	}
+
+	for field, deadline := range deadlines {
+		tokens := []string{"HPEXPIREAT", key, strconv.FormatUint(deadline, 10), "FIELDS", "1", field}
+		if err := aof.Write(string(encode(tokens))); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// DumpAllAOF dumps all keys in the store to the AOF file, each database after a SELECT of it.
 func DumpAllAOF(store *Store) error {
 	var (
 		aof *AOF
@@ -124,11 +144,28 @@ func DumpAllAOF(store *Store) error {
 
 	log.Println("rewriting AOF file at", config.DiceConfig.Persistence.AOFFile)
 
-	store.store.All(func(k string, obj *object.Obj) bool {
-		err = dumpKey(aof, k, obj)
-		// continue if no error
-		return err == nil
-	})
+	selectedDB := store.SelectedDB()
+	defer store.SelectDB(selectedDB)
+
+	for db := 0; db < store.NumDatabases() && err == nil; db++ {
+		store.SelectDB(db)
+		if store.GetKeyCount() == 0 {
+			continue
+		}
+		if err = aof.Write(string(encode([]string{"SELECT", strconv.Itoa(db)}))); err != nil {
+			break
+		}
+
+		store.store.All(func(k string, obj *object.Obj) bool {
+			if fields, ok := obj.Value.(FieldMap); ok {
+				err = dumpHash(aof, k, fields, store.GetFieldExpiries(obj))
+			} else {
+				err = dumpKey(aof, k, obj)
+			}
+			// continue if no error
+			return err == nil
+		})
+	}
 
 	log.Println("AOF file rewrite complete")
 	return err
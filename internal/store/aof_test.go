@@ -6,10 +6,13 @@ package store
 import (
 	"fmt"
 	"os"
+	"path/filepath"
 	"reflect"
 	"strconv"
 	"testing"
 	"time"
+
+	"github.com/dicedb/dice/config"
 )
 
 func TestAOF(t *testing.T) {
@@ -282,3 +285,33 @@ func BenchmarkAOFWithExat(b *testing.B) {
 		}
 	}
 }
+
+func TestDumpAllAOFDumpsEveryDatabase(t *testing.T) {
+	previous := config.DiceConfig.Persistence.AOFFile
+	config.DiceConfig.Persistence.AOFFile = filepath.Join(t.TempDir(), "dump.aof")
+	t.Cleanup(func() { config.DiceConfig.Persistence.AOFFile = previous })
+
+	store := newDatabaseTestStore(t, 3)
+	putTestString(store, "k0", "v0")
+	store.SelectDB(2)
+	putTestString(store, "k2", "v2")
+
+	if err := DumpAllAOF(store); err != nil {
+		t.Fatalf("Failed to dump the store: %v", err)
+	}
+	if store.SelectedDB() != 2 {
+		t.Errorf("Dumping changed the selected database to %d", store.SelectedDB())
+	}
+
+	data, err := os.ReadFile(config.DiceConfig.Persistence.AOFFile)
+	if err != nil {
+		t.Fatalf("Failed to read the AOF file: %v", err)
+	}
+	expected := string(encode([]string{"SELECT", "0"})) + "\n" +
+		string(encode([]string{"SET", "k0", "v0"})) + "\n" +
+		string(encode([]string{"SELECT", "2"})) + "\n" +
+		string(encode([]string{"SET", "k2", "v2"})) + "\n"
+	if string(data) != expected {
+		t.Errorf("Dumped %q, want %q", data, expected)
+	}
+}
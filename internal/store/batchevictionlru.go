@@ -5,7 +5,6 @@ package store
 
 import (
 	"container/heap"
-	"math"
 
 	"github.com/dicedb/dice/internal/object"
 )
@@ -46,37 +45,15 @@ func (h *evictionItemHeap) pop() evictionItem {
 // BatchEvictionLRU implements batch eviction of least recently used keys
 type BatchEvictionLRU struct {
 	BaseEvictionStrategy
-	maxKeys       int
-	evictionRatio float64
+	keysLimit
 }
 
 func NewBatchEvictionLRU(maxKeys int, evictionRatio float64) *BatchEvictionLRU {
 	return &BatchEvictionLRU{
-		maxKeys:       maxKeys,
-		evictionRatio: evictionRatio,
+		keysLimit: keysLimit{maxKeys: maxKeys, evictionRatio: evictionRatio},
 	}
 }
 
-func (e *BatchEvictionLRU) ShouldEvict(store *Store) int {
-	currentKeyCount := store.GetKeyCount()
-
-	// Check if eviction is necessary only till the number of keys remains less than maxKeys
-	if currentKeyCount < e.maxKeys {
-		return 0 // No eviction needed
-	}
-
-	// Calculate target key count after eviction
-	targetKeyCount := int(math.Ceil(float64(e.maxKeys) * (1 - e.evictionRatio)))
-
-	// Calculate the number of keys to evict to reach the target key count
-	toEvict := currentKeyCount - targetKeyCount
-	if toEvict < 1 {
-		toEvict = 1 // Ensure at least one key is evicted if eviction is triggered
-	}
-
-	return toEvict
-}
-
 // EvictVictims deletes keys with the lowest LastAccessedAt values from the store.
 func (e *BatchEvictionLRU) EvictVictims(store *Store, toEvict int) {
 	if toEvict <= 0 {
@@ -21,5 +21,9 @@ const (
 	SingleShardSize  string = "SINGLEDBSIZE"
 	SingleShardTouch string = "SINGLETOUCH"
 	SingleShardKeys  string = "SINGLEKEYS"
+	SingleShardScan  string = "SINGLESCAN"
 	FlushDB          string = "FLUSHDB"
+	SwapDB           string = "SWAPDB"
+	SortLookup       string = "SORTLOOKUP"
+	SortStore        string = "SORTSTORE"
 )
@@ -0,0 +1,92 @@
+// Copyright (c) 2022-present, DiceDB contributors
+// All rights reserved. Licensed under the BSD 3-Clause License. See LICENSE file in the project root for full license information.
+
+package store
+
+import (
+	"testing"
+
+	"github.com/dicedb/dice/config"
+	"github.com/dicedb/dice/internal/object"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newDatabaseTestStore(t *testing.T, databases int) *Store {
+	previous := config.DiceConfig.Memory.Databases
+	config.DiceConfig.Memory.Databases = databases
+	t.Cleanup(func() { config.DiceConfig.Memory.Databases = previous })
+
+	return NewStore(nil, NewBatchEvictionLRU(1_000_000, 0.1))
+}
+
+func TestSelectDB(t *testing.T) {
+	store := newDatabaseTestStore(t, 4)
+	require.Equal(t, 4, store.NumDatabases())
+	assert.Equal(t, 0, store.SelectedDB())
+
+	store.Put("k", store.NewObj("v0", -1, object.ObjTypeString))
+	store.SelectDB(2)
+	assert.Equal(t, 2, store.SelectedDB())
+	assert.Nil(t, store.Get("k"))
+	assert.Zero(t, store.GetKeyCount())
+
+	store.Put("k", store.NewObj("v2", -1, object.ObjTypeString))
+	store.Put("other", store.NewObj("v2", -1, object.ObjTypeString))
+	assert.Equal(t, 2, store.GetKeyCount())
+
+	// Flushing a database leaves the other ones untouched
+	store.ResetStore()
+	assert.Equal(t, 0, store.GetKeyCount())
+	store.SelectDB(0)
+	assert.Equal(t, 1, store.GetKeyCount())
+	assert.Equal(t, "v0", store.Get("k").Value)
+}
+
+func TestMove(t *testing.T) {
+	store := newDatabaseTestStore(t, 2)
+	store.Put("k", store.NewObj("v", 60_000, object.ObjTypeString))
+	store.Put("plain", store.NewObj("v", -1, object.ObjTypeString))
+
+	assert.True(t, store.Move("k", 1))
+	assert.True(t, store.Move("plain", 1))
+	assert.Nil(t, store.Get("k"))
+	assert.Equal(t, 0, store.GetKeyCount())
+	assert.False(t, store.Move("missing", 1))
+
+	store.SelectDB(1)
+	obj := store.Get("k")
+	require.NotNil(t, obj)
+	assert.Equal(t, "v", obj.Value)
+	_, hasExpiry := GetExpiry(obj, store)
+	assert.True(t, hasExpiry)
+	_, hasExpiry = GetExpiry(store.Get("plain"), store)
+	assert.False(t, hasExpiry)
+	assert.Equal(t, 2, store.GetKeyCount())
+
+	// A key is not moved over an existing one
+	store.SelectDB(0)
+	store.Put("k", store.NewObj("other", -1, object.ObjTypeString))
+	assert.False(t, store.Move("k", 1))
+	assert.Equal(t, "other", store.Get("k").Value)
+}
+
+func TestSwapDB(t *testing.T) {
+	store := newDatabaseTestStore(t, 3)
+	store.Put("a", store.NewObj("v", -1, object.ObjTypeString))
+	store.SelectDB(2)
+	store.Put("b", store.NewObj("v", -1, object.ObjTypeString))
+	store.Put("c", store.NewObj("v", -1, object.ObjTypeString))
+
+	store.SwapDB(0, 2)
+	assert.Equal(t, 2, store.SelectedDB())
+	keys, err := store.Keys("*")
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"a"}, keys)
+
+	store.SelectDB(0)
+	keys, err = store.Keys("*")
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"b", "c"}, keys)
+	assert.Equal(t, 2, store.GetKeyCount())
+}
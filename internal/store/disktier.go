@@ -0,0 +1,310 @@
+// Copyright (c) 2022-present, DiceDB contributors
+// All rights reserved. Licensed under the BSD 3-Clause License. See LICENSE file in the project root for full license information.
+
+package store
+
+import (
+	"io/fs"
+	"log/slog"
+	"os"
+	"time"
+
+	"github.com/dicedb/dice/internal/object"
+	"github.com/dicedb/dice/internal/server/utils"
+)
+
+const (
+	TierMemory = "memory"
+	TierDisk   = "disk"
+)
+
+const (
+	// diskTierCompactionRatio is the share of the file the released records must reach for the
+	// file to be rewritten without them, see DiskTier.needsCompaction.
+	diskTierCompactionRatio = 0.5
+
+	// diskTierCompactionMinSize is the number of bytes of the released records under which the file
+	// is not rewritten, however large their share.
+	diskTierCompactionMinSize = 1 << 20
+)
+
+// ObjectCodec serializes the objects spilled to the disk tier, along with the deadlines of their
+// fields if they are hashes. The eval package serializes them in the format of DUMP.
+type ObjectCodec interface {
+	Serialize(obj *object.Obj, fieldExpiries map[string]uint64) ([]byte, error)
+	Deserialize(data []byte) (*object.Obj, map[string]uint64, error)
+}
+
+// DiskTier holds the keys evicted from the store, serialized in an append-only file, so that they
+// are not lost but promoted back to memory once accessed. The keyspaces index the records of
+// their keys, see Store.SetDiskTier. The keys in the disk tier are not listed by Keys and Scan, nor
+// counted by GetDBSize.
+//
+// The file only holds the keys while the shard runs: it is truncated when the tier is opened, once
+// it holds no more records, and removed when the tier is closed. In between, it is rewritten
+// without the records that are not indexed anymore once they make up most of it.
+type DiskTier struct {
+	path    string
+	file    *os.File
+	size    int64 // Offset of the end of the file, where the next record is written.
+	live    int64 // Number of bytes of the records of the file that are still indexed.
+	records int   // Number of the records of the file that are still indexed.
+	codec   ObjectCodec
+
+	compactionMinSize int64 // See diskTierCompactionMinSize.
+}
+
+// spillRecord locates the serialized object of a key spilled to the disk tier.
+type spillRecord struct {
+	offset   int64
+	length   int
+	deadline uint64 // Expiry of the key in milliseconds since the epoch, or 0 if it has none.
+}
+
+func NewDiskTier(path string, codec ObjectCodec) (*DiskTier, error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR|os.O_TRUNC, fs.FileMode(FileMode))
+	if err != nil {
+		return nil, err
+	}
+
+	return &DiskTier{
+		path:  path,
+		file:  f,
+		codec: codec,
+
+		compactionMinSize: diskTierCompactionMinSize,
+	}, nil
+}
+
+// Size returns the number of bytes of the file, including the records that are not indexed
+// anymore.
+func (t *DiskTier) Size() int64 {
+	return t.size
+}
+
+// Close closes and removes the file.
+func (t *DiskTier) Close() error {
+	if err := t.file.Close(); err != nil {
+		return err
+	}
+	return os.Remove(t.path)
+}
+
+// write appends the serialized object to the file.
+func (t *DiskTier) write(obj *object.Obj, fieldExpiries map[string]uint64) (spillRecord, error) {
+	data, err := t.codec.Serialize(obj, fieldExpiries)
+	if err != nil {
+		return spillRecord{}, err
+	}
+	if _, err := t.file.WriteAt(data, t.size); err != nil {
+		return spillRecord{}, err
+	}
+
+	record := spillRecord{offset: t.size, length: len(data)}
+	t.size += int64(len(data))
+	t.live += int64(len(data))
+	t.records++
+	return record, nil
+}
+
+// read returns the object of a record.
+func (t *DiskTier) read(record spillRecord) (*object.Obj, map[string]uint64, error) {
+	data := make([]byte, record.length)
+	if _, err := t.file.ReadAt(data, record.offset); err != nil {
+		return nil, nil, err
+	}
+	return t.codec.Deserialize(data)
+}
+
+// release forgets a record, and truncates the file once none of its records are indexed.
+func (t *DiskTier) release(record spillRecord) {
+	t.records--
+	t.live -= int64(record.length)
+	if t.records > 0 {
+		return
+	}
+	if err := t.file.Truncate(0); err != nil {
+		slog.Warn("could not truncate the disk tier", slog.String("path", t.path), slog.Any("error", err))
+		return
+	}
+	t.size = 0
+}
+
+// needsCompaction returns whether the records that are not indexed anymore make up enough of the
+// file for it to be rewritten without them.
+func (t *DiskTier) needsCompaction() bool {
+	released := t.size - t.live
+	return released >= t.compactionMinSize && float64(released) >= diskTierCompactionRatio*float64(t.size)
+}
+
+// compact rewrites the file with the given records only, one after the other, and returns where
+// each of them ends up. The file is left as it was if it cannot be rewritten.
+func (t *DiskTier) compact(records []spillRecord) ([]spillRecord, error) {
+	f, err := os.OpenFile(t.path+".compact", os.O_CREATE|os.O_RDWR|os.O_TRUNC, fs.FileMode(FileMode))
+	if err != nil {
+		return nil, err
+	}
+
+	moved := make([]spillRecord, len(records))
+	size := int64(0)
+	for i, record := range records {
+		data := make([]byte, record.length)
+		if _, err = t.file.ReadAt(data, record.offset); err == nil {
+			_, err = f.WriteAt(data, size)
+		}
+		if err != nil {
+			break
+		}
+		moved[i] = spillRecord{offset: size, length: record.length, deadline: record.deadline}
+		size += int64(record.length)
+	}
+	if err == nil {
+		err = os.Rename(f.Name(), t.path)
+	}
+	if err != nil {
+		f.Close()
+		os.Remove(f.Name())
+		return nil, err
+	}
+
+	if err := t.file.Close(); err != nil {
+		slog.Warn("could not close the compacted disk tier", slog.String("path", t.path), slog.Any("error", err))
+	}
+	t.file, t.size, t.live = f, size, size
+	return moved, nil
+}
+
+// SetDiskTier makes the store spill the keys it evicts to the disk tier, instead of deleting them.
+func (store *Store) SetDiskTier(tier *DiskTier) {
+	store.diskTier = tier
+}
+
+// DiskTier returns the disk tier of the store, or nil if it has none.
+func (store *Store) DiskTier() *DiskTier {
+	return store.diskTier
+}
+
+// Tier returns the tier holding the key in the selected database, TierMemory or TierDisk, without
+// promoting it, or an empty string if the key does not exist.
+func (store *Store) Tier(k string) string {
+	if obj, ok := store.store.Get(k); ok && !hasExpired(obj, store) {
+		return TierMemory
+	}
+	if record, ok := store.spilled[k]; ok && !record.expired() {
+		return TierDisk
+	}
+	return ""
+}
+
+// spill writes the object of the key being evicted to the disk tier.
+func (store *Store) spill(k string, obj *object.Obj) {
+	if hasExpired(obj, store) {
+		return
+	}
+	if store.diskTier.needsCompaction() {
+		store.compactDiskTier()
+	}
+
+	record, err := store.diskTier.write(obj, store.GetFieldExpiries(obj))
+	if err != nil {
+		slog.Warn("could not spill the key to the disk tier, evicting it", slog.String("key", k), slog.Any("error", err))
+		return
+	}
+	record.deadline, _ = store.expires.Get(obj)
+	store.spilled[k] = record
+	store.stats.SpilledKeys++
+}
+
+// promote moves the key spilled to the disk tier back to the store, and returns its object, or nil
+// if the key was not spilled or has expired since.
+func (store *Store) promote(k string) *object.Obj {
+	record, ok := store.spilled[k]
+	if !ok {
+		return nil
+	}
+	if record.expired() {
+		store.dropSpilled(k)
+		store.stats.ExpiredKeys++
+		return nil
+	}
+
+	// The record is read before being released, which truncates the file if it was the last one
+	obj, fieldExpiries, err := store.diskTier.read(record)
+	store.dropSpilled(k)
+	if err != nil {
+		slog.Warn("could not promote the key from the disk tier", slog.String("key", k), slog.Any("error", err))
+		return nil
+	}
+
+	// The key did not stop existing while it was spilled, the watchers are not told
+	store.putHelper(k, obj, withoutNotify())
+	if record.deadline > 0 {
+		store.setExpiryAt(obj, record.deadline)
+	}
+	for field, deadline := range fieldExpiries {
+		store.SetFieldExpiry(k, obj, field, deadline)
+	}
+	store.stats.PromotedKeys++
+	return obj
+}
+
+// compactDiskTier rewrites the file of the disk tier with the records indexed by the databases of
+// the store only, and indexes them where they end up.
+func (store *Store) compactDiskTier() {
+	type indexedRecord struct {
+		keyspace *keyspace
+		key      string
+	}
+	indexed := make([]indexedRecord, 0, store.diskTier.records)
+	records := make([]spillRecord, 0, store.diskTier.records)
+	for _, keyspace := range store.databases {
+		for k, record := range keyspace.spilled {
+			indexed = append(indexed, indexedRecord{keyspace: keyspace, key: k})
+			records = append(records, record)
+		}
+	}
+
+	moved, err := store.diskTier.compact(records)
+	if err != nil {
+		slog.Warn("could not compact the disk tier", slog.String("path", store.diskTier.path), slog.Any("error", err))
+		return
+	}
+	for i, record := range moved {
+		indexed[i].keyspace.spilled[indexed[i].key] = record
+	}
+}
+
+// dropSpilled forgets the record of the key in the disk tier, and returns false if the key was not
+// spilled.
+func (store *Store) dropSpilled(k string) bool {
+	record, ok := store.spilled[k]
+	if !ok {
+		return false
+	}
+	delete(store.spilled, k)
+	store.diskTier.release(record)
+	return true
+}
+
+// deleteExpiredSpilled forgets the records of the keys of the selected database which expired while
+// spilled to the disk tier, as they are not in the expiry index. It returns false if it spent the
+// time budget of the expire cycle started at start before checking all of them.
+func (store *Store) deleteExpiredSpilled(start time.Time, budget time.Duration) bool {
+	checked := 0
+	for k, record := range store.spilled {
+		if record.expired() {
+			store.dropSpilled(k)
+			store.stats.ExpiredKeys++
+		}
+		checked++
+		if budget > 0 && checked%expireCycleCheckInterval == 0 && time.Since(start) >= budget {
+			return false
+		}
+	}
+	return true
+}
+
+// expired returns whether the key of the record has expired.
+func (record spillRecord) expired() bool {
+	return record.deadline > 0 && record.deadline <= uint64(utils.GetCurrentTime().UnixMilli())
+}
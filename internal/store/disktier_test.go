@@ -0,0 +1,183 @@
+// Copyright (c) 2022-present, DiceDB contributors
+// All rights reserved. Licensed under the BSD 3-Clause License. See LICENSE file in the project root for full license information.
+
+package store
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/dicedb/dice/config"
+	"github.com/dicedb/dice/internal/object"
+	"github.com/dicedb/dice/internal/server/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stringCodec serializes the string objects, the eval package serializing the others.
+type stringCodec struct{}
+
+func (stringCodec) Serialize(obj *object.Obj, _ map[string]uint64) ([]byte, error) {
+	return json.Marshal(obj.Value)
+}
+
+func (stringCodec) Deserialize(data []byte) (*object.Obj, map[string]uint64, error) {
+	var value string
+	if err := json.Unmarshal(data, &value); err != nil {
+		return nil, nil, err
+	}
+	return &object.Obj{Type: object.ObjTypeString, Value: value}, nil, nil
+}
+
+func newDiskTierTestStore(t *testing.T) *Store {
+	tier, err := NewDiskTier(filepath.Join(t.TempDir(), "shard-0.spill"), stringCodec{})
+	require.NoError(t, err)
+	t.Cleanup(func() { tier.Close() })
+
+	store := NewStore(nil, NewAllKeysLRU(100, 0.5))
+	store.SetDiskTier(tier)
+	return store
+}
+
+func TestDiskTierSpillAndPromote(t *testing.T) {
+	store := newDiskTierTestStore(t)
+	for i := 0; i < 4; i++ {
+		putTestString(store, "key"+strconv.Itoa(i), "value"+strconv.Itoa(i))
+	}
+
+	store.evict(4)
+	assert.Zero(t, store.GetKeyCount())
+	assert.Zero(t, store.UsedMemory())
+	assert.Equal(t, TierDisk, store.Tier("key1"))
+
+	stats := store.Stats()
+	assert.Equal(t, uint64(4), stats.SpilledKeys)
+	assert.Equal(t, 4, stats.DiskTierKeys)
+	assert.Positive(t, stats.DiskTierBytes)
+
+	// Reading a spilled key moves it back to memory
+	obj := store.Get("key1")
+	require.NotNil(t, obj)
+	assert.Equal(t, "value1", obj.Value)
+	assert.Equal(t, TierMemory, store.Tier("key1"))
+	assert.Equal(t, 1, store.GetKeyCount())
+	assert.Equal(t, uint64(1), store.Stats().PromotedKeys)
+
+	// Overwriting or deleting a spilled key drops it from the disk tier
+	putTestString(store, "key2", "overwritten")
+	assert.Equal(t, "overwritten", store.Get("key2").Value)
+	assert.True(t, store.Del("key3"))
+	assert.Empty(t, store.Tier("key3"))
+	assert.Equal(t, 1, store.Stats().DiskTierKeys)
+
+	// The file is truncated once it holds no more keys
+	assert.NotNil(t, store.GetDel("key0"))
+	assert.Zero(t, store.Stats().DiskTierKeys)
+	assert.Zero(t, store.Stats().DiskTierBytes)
+}
+
+func TestDiskTierKeepsExpiry(t *testing.T) {
+	defer func() { utils.CurrentTime = utils.RealClock{} }()
+	mockTime := &utils.MockClock{CurrTime: time.Now()}
+	utils.CurrentTime = mockTime
+
+	store := newDiskTierTestStore(t)
+	store.Put("short", store.NewObj("value", 1000, object.ObjTypeString))
+	store.Put("long", store.NewObj("value", 60000, object.ObjTypeString))
+	store.evict(2)
+
+	mockTime.SetTime(mockTime.CurrTime.Add(2 * time.Second))
+	assert.Empty(t, store.Tier("short"))
+	assert.Nil(t, store.Get("short"))
+	assert.Equal(t, uint64(1), store.Stats().ExpiredKeys)
+
+	obj := store.Get("long")
+	require.NotNil(t, obj)
+	exp, ok := GetExpiry(obj, store)
+	assert.True(t, ok)
+	assert.Greater(t, exp, uint64(mockTime.CurrTime.UnixMilli()))
+}
+
+func TestDiskTierExpireCycle(t *testing.T) {
+	defer func() { utils.CurrentTime = utils.RealClock{} }()
+	mockTime := &utils.MockClock{CurrTime: time.Now()}
+	utils.CurrentTime = mockTime
+	previous := config.DiceConfig.Memory.Databases
+	config.DiceConfig.Memory.Databases = 2
+	defer func() { config.DiceConfig.Memory.Databases = previous }()
+
+	store := newDiskTierTestStore(t)
+	store.SelectDB(1)
+	store.Put("short", store.NewObj("value", 1000, object.ObjTypeString))
+	store.Put("long", store.NewObj("value", 60000, object.ObjTypeString))
+	store.evict(2)
+	store.SelectDB(0)
+
+	// The expired keys are forgotten without being accessed, in every database
+	mockTime.SetTime(mockTime.CurrTime.Add(2 * time.Second))
+	assert.True(t, DeleteExpiredKeysWithin(store, 0))
+	assert.Equal(t, uint64(1), store.Stats().ExpiredKeys)
+	assert.Equal(t, 1, store.Stats().DiskTierKeys)
+
+	store.SelectDB(1)
+	assert.Empty(t, store.Tier("short"))
+	assert.Equal(t, TierDisk, store.Tier("long"))
+}
+
+func TestDiskTierReset(t *testing.T) {
+	store := newDiskTierTestStore(t)
+	putTestString(store, "key", "value")
+	store.evict(1)
+
+	store.ResetStore()
+	assert.Empty(t, store.Tier("key"))
+	assert.Nil(t, store.Get("key"))
+	assert.Zero(t, store.Stats().DiskTierKeys)
+}
+
+func TestDiskTierCompaction(t *testing.T) {
+	store := newDiskTierTestStore(t)
+	store.diskTier.compactionMinSize = 1
+	for i := 0; i < 4; i++ {
+		putTestString(store, "key"+strconv.Itoa(i), "value"+strconv.Itoa(i))
+	}
+	store.evict(4)
+	size := store.Stats().DiskTierBytes
+
+	assert.True(t, store.Del("key0"))
+	assert.True(t, store.Del("key1"))
+	assert.NotNil(t, store.Get("key2"))
+	assert.Equal(t, size, store.Stats().DiskTierBytes)
+
+	// Spilling a key once most of the file is released rewrites it without the released records
+	store.evict(1)
+	assert.Equal(t, 2, store.Stats().DiskTierKeys)
+	assert.Equal(t, size/2, store.Stats().DiskTierBytes)
+	assert.Equal(t, "value3", store.Get("key3").Value)
+	assert.Equal(t, "value2", store.Get("key2").Value)
+}
+
+func TestDiskTierWatchEvents(t *testing.T) {
+	tier, err := NewDiskTier(filepath.Join(t.TempDir(), "shard-0.spill"), stringCodec{})
+	require.NoError(t, err)
+	t.Cleanup(func() { tier.Close() })
+
+	cmdWatchChan := make(chan CmdWatchEvent, 10)
+	store := NewStore(cmdWatchChan, NewAllKeysLRU(100, 0.5))
+	store.SetDiskTier(tier)
+	putTestString(store, "promoted", "value")
+	putTestString(store, "deleted", "value")
+	store.evict(2)
+	for len(cmdWatchChan) > 0 {
+		<-cmdWatchChan
+	}
+
+	// Promoting a key does not change it, deleting a spilled key does
+	assert.NotNil(t, store.Get("promoted"))
+	assert.True(t, store.Del("deleted"))
+	require.Len(t, cmdWatchChan, 1)
+	assert.Equal(t, CmdWatchEvent{Cmd: Del, AffectedKey: "deleted"}, <-cmdWatchChan)
+}
@@ -4,6 +4,7 @@
 package store
 
 import (
+	"math"
 	"time"
 
 	"github.com/dicedb/dice/internal/object"
@@ -62,6 +63,9 @@ type EvictionStrategy interface {
 	// OnAccess is called when an item is accessed (get/set)
 	// This allows strategies to update access patterns/statistics
 	OnAccess(key string, obj *object.Obj, accessType AccessType)
+
+	// GetStats returns the statistics of the evictions so far
+	GetStats() EvictionStats
 }
 
 // BaseEvictionStrategy provides common functionality for all eviction strategies
@@ -77,6 +81,82 @@ func (b *BaseEvictionStrategy) GetStats() EvictionStats {
 	return b.stats
 }
 
+// keysLimit triggers the eviction once the store holds maxKeys keys, of enough keys to go back to
+// (1 - evictionRatio) of maxKeys.
+type keysLimit struct {
+	maxKeys       int
+	evictionRatio float64
+}
+
+func (l keysLimit) ShouldEvict(store *Store) int {
+	currentKeyCount := store.GetKeyCount()
+
+	// Check if eviction is necessary only till the number of keys remains less than maxKeys
+	if currentKeyCount < l.maxKeys {
+		return 0 // No eviction needed
+	}
+
+	// Calculate target key count after eviction
+	targetKeyCount := int(math.Ceil(float64(l.maxKeys) * (1 - l.evictionRatio)))
+
+	// Calculate the number of keys to evict to reach the target key count
+	toEvict := currentKeyCount - targetKeyCount
+	if toEvict < 1 {
+		toEvict = 1 // Ensure at least one key is evicted if eviction is triggered
+	}
+
+	return toEvict
+}
+
+// evictionSamples is the number of keys the sampling eviction strategies compare to pick each
+// of their victims.
+const evictionSamples = 5
+
+// keySampler calls f with up to n keys of the selected database, sampled at random.
+type keySampler func(store *Store, n int, f func(k string, obj *object.Obj))
+
+// sampleKeys samples any of the keys. Each iteration of the keys starts at a random position, so
+// that every call samples different keys.
+func sampleKeys(store *Store, n int, f func(k string, obj *object.Obj)) {
+	store.GetStore().All(func(k string, obj *object.Obj) bool {
+		f(k, obj)
+		n--
+		return n > 0
+	})
+}
+
+// sampleVolatileKeys samples the keys with an expiry, from the expiry table.
+func sampleVolatileKeys(store *Store, n int, f func(k string, obj *object.Obj)) {
+	store.expires.All(func(obj *object.Obj, _ uint64) bool {
+		// The table may hold objects that have not been put in the store yet
+		if k, ok := store.keyOf(obj); ok {
+			f(k, obj)
+		}
+		n--
+		return n > 0
+	})
+}
+
+// evictSampled evicts up to toEvict keys, each being the key with the highest score out of
+// samples keys picked by sample. It returns the number of evicted keys, which is lower than
+// toEvict once there is no key left to sample.
+func evictSampled(store *Store, toEvict int, sample keySampler, samples int, score func(obj *object.Obj) uint64) int64 {
+	evicted := int64(0)
+	for ; evicted < int64(toEvict); evicted++ {
+		victim, victimScore, sampled := "", uint64(0), false
+		sample(store, samples, func(k string, obj *object.Obj) {
+			if s := score(obj); !sampled || s > victimScore {
+				victim, victimScore, sampled = k, s, true
+			}
+		})
+		if !sampled {
+			break
+		}
+		store.Del(victim, WithDelCmd(Evict))
+	}
+	return evicted
+}
+
 func getCurrentClock() uint32 {
 	return uint32(utils.GetCurrentTime().Unix()) & 0x00FFFFFF
 }
@@ -0,0 +1,101 @@
+// Copyright (c) 2022-present, DiceDB contributors
+// All rights reserved. Licensed under the BSD 3-Clause License. See LICENSE file in the project root for full license information.
+
+package store
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/dicedb/dice/internal/object"
+	"github.com/dicedb/dice/internal/server/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEvictionStrategies_ExceedMaxKeys(t *testing.T) {
+	strategies := map[string]EvictionStrategy{
+		"simple-first":   NewSimpleFirst(10, 0.5),
+		"allkeys-random": NewAllKeysRandom(10, 0.5),
+		"allkeys-lru":    NewAllKeysLRU(10, 0.5),
+		"allkeys-lfu":    NewAllKeysLFU(10, 0.5, 10),
+	}
+
+	for name, eviction := range strategies {
+		t.Run(name, func(t *testing.T) {
+			s := NewStore(nil, eviction)
+			for i := 0; i <= 10; i++ {
+				s.Put("key"+strconv.Itoa(i), &object.Obj{})
+			}
+
+			// 5 keys remain after eviction, then the key that triggered it is added
+			assert.Equal(t, 6, s.GetKeyCount())
+			assert.NotNil(t, s.GetNoTouch("key10"), "The key put is not evicted")
+		})
+	}
+}
+
+func TestAllKeysLRU_EvictsIdlest(t *testing.T) {
+	mockTime := &utils.MockClock{CurrTime: time.Now()}
+	utils.CurrentTime = mockTime
+	defer func() { utils.CurrentTime = utils.RealClock{} }()
+
+	s := NewStore(nil, NewAllKeysLRU(100, 0.5))
+	for i := 0; i < evictionSamples; i++ {
+		s.Put("key"+strconv.Itoa(i), &object.Obj{})
+		mockTime.SetTime(mockTime.GetTime().Add(time.Second))
+	}
+	s.Get("key0")
+
+	// The sample holds all the keys, key1 is the least recently used one
+	s.evict(1)
+	assert.Nil(t, s.GetNoTouch("key1"))
+	assert.Equal(t, evictionSamples-1, s.GetKeyCount())
+}
+
+func TestAllKeysLFU_EvictsLeastFrequentlyUsed(t *testing.T) {
+	mockTime := &utils.MockClock{CurrTime: time.Now()}
+	utils.CurrentTime = mockTime
+	defer func() { utils.CurrentTime = utils.RealClock{} }()
+
+	// Without log factor, every access increments the counters
+	s := NewStore(nil, NewAllKeysLFU(100, 0.5, 0))
+	for i := 0; i < evictionSamples; i++ {
+		s.Put("key"+strconv.Itoa(i), &object.Obj{})
+	}
+	for i := 0; i < evictionSamples; i++ {
+		for j := 0; j < i; j++ {
+			s.Get("key" + strconv.Itoa(i))
+		}
+	}
+	assert.Equal(t, uint8(lfuInitialCount+1), s.GetNoTouch("key0").LFUCounter)
+	assert.Equal(t, uint8(lfuInitialCount+3), s.GetNoTouch("key2").LFUCounter)
+
+	// The most recently used key goes first when it is the least frequently used one
+	mockTime.SetTime(mockTime.GetTime().Add(time.Second))
+	s.Get("key0")
+	s.evict(1)
+	assert.Nil(t, s.GetNoTouch("key1"))
+
+	// Idle keys lose a count per decay period
+	mockTime.SetTime(mockTime.GetTime().Add(3 * lfuDecayPeriod * time.Second))
+	assert.Equal(t, uint8(lfuInitialCount), lfuDecayedCount(s.GetNoTouch("key2")))
+	s.Get("key2")
+	assert.Equal(t, uint8(lfuInitialCount+1), s.GetNoTouch("key2").LFUCounter)
+}
+
+func TestAllKeysLFU_CounterGrowsLogarithmically(t *testing.T) {
+	eviction := NewAllKeysLFU(100, 0.5, 10)
+	obj := &object.Obj{LastAccessedAt: getCurrentClock()}
+	eviction.OnAccess("key", obj, AccessSet)
+	for i := 0; i < 1000; i++ {
+		eviction.OnAccess("key", obj, AccessGet)
+	}
+
+	assert.Greater(t, obj.LFUCounter, uint8(lfuInitialCount))
+	assert.Less(t, obj.LFUCounter, uint8(50))
+
+	obj.LFUCounter = 255
+	eviction.OnAccess("key", obj, AccessGet)
+	assert.Equal(t, uint8(255), obj.LFUCounter, "The counter saturates")
+}
@@ -5,6 +5,7 @@ package store
 
 import (
 	"strings"
+	"time"
 
 	diceerrors "github.com/dicedb/dice/internal/errors"
 	"github.com/dicedb/dice/internal/object"
@@ -32,48 +33,87 @@ func GetExpiry(obj *object.Obj, store *Store) (uint64, bool) {
 }
 
 func DelExpiry(obj *object.Obj, store *Store) {
+	store.delExpiry(obj)
+}
+
+// setExpiryAt sets the deadline of an object, in milliseconds since the epoch.
+func (store *Store) setExpiryAt(obj *object.Obj, deadline uint64) {
+	store.expires.Put(obj, deadline)
+	store.expiryIndex.set(obj, deadline)
+}
+
+// delExpiry removes the deadline of an object.
+func (store *Store) delExpiry(obj *object.Obj) {
 	store.expires.Delete(obj)
+	store.expiryIndex.remove(obj)
 }
 
-// TODO: Optimize
-//   - Sampling
-//   - Unnecessary iteration
-func expireSample(store *Store) float32 {
-	var limit = 20
-	var expiredCount = 0
-	var keysToDelete []string
-
-	// Collect keys to be deleted
-	store.store.All(func(keyPtr string, obj *object.Obj) bool {
-		limit--
-		if hasExpired(obj, store) {
-			keysToDelete = append(keysToDelete, keyPtr)
-			expiredCount++
+// expireCycleCheckInterval is the number of keys an expire cycle deletes between two checks of
+// its time budget.
+const expireCycleCheckInterval = 16
+
+// DeleteExpiredKeys deletes all the expired keys and hash fields of every database - the active way
+func DeleteExpiredKeys(store *Store) {
+	DeleteExpiredKeysWithin(store, 0)
+}
+
+// DeleteExpiredKeysWithin deletes the expired keys of every database in the order of their
+// deadlines, until it has spent the time budget, or until there are none left without budget. Then
+// it forgets the expired keys spilled to the disk tier, and deletes the expired hash fields,
+// sampling the hashes having fields with a deadline. It returns false if the budget was spent
+// before all the expired keys and hash fields were deleted.
+func DeleteExpiredKeysWithin(store *Store, budget time.Duration) bool {
+	start := time.Now()
+	selectedDB := store.SelectedDB()
+	defer store.SelectDB(selectedDB)
+
+	now := uint64(utils.GetCurrentTime().UnixMilli())
+	done := true
+	volatile, expired := 0, 0
+	for db := 0; db < store.NumDatabases(); db++ {
+		store.SelectDB(db)
+		volatile += store.expiryIndex.Len()
+		for done {
+			entry, ok := store.expiryIndex.first()
+			if !ok || entry.deadline > now {
+				break
+			}
+
+			store.expireObject(entry.obj)
+			expired++
+			if budget > 0 && expired%expireCycleCheckInterval == 0 && time.Since(start) >= budget {
+				done = false
+			}
+		}
+		if done && !store.deleteExpiredSpilled(start, budget) {
+			done = false
+		}
+		for done {
+			if expireFieldsSample(store) < 0.25 {
+				break
+			}
+			if budget > 0 && time.Since(start) >= budget {
+				done = false
+			}
 		}
-		// once we iterated to 20 keys that have some expiration set
-		// we break the loop
-		return limit >= 0
-	})
-
-	// Delete the keys outside the read lock
-	for _, keyPtr := range keysToDelete {
-		store.DelByPtr(keyPtr, WithDelCmd(Del))
 	}
 
-	return float32(expiredCount) / float32(20.0)
+	staleRatio := 0.0
+	if volatile > 0 {
+		staleRatio = float64(expired) / float64(volatile)
+	}
+	store.recordExpireCycle(time.Since(start), staleRatio)
+	return done
 }
 
-// DeleteExpiredKeys deletes all the expired keys - the active way
-// Sampling approach: https://redis.io/commands/expire/
-func DeleteExpiredKeys(store *Store) {
-	for {
-		frac := expireSample(store)
-		// if the sample had less than 25% keys expired
-		// we break the loop.
-		if frac < 0.25 {
-			break
-		}
+// expireObject deletes the key of an expired object. The objects which are not in the store, such
+// as the objects created with an expiry but never put, only lose their expiry.
+func (store *Store) expireObject(obj *object.Obj) {
+	if k, ok := store.keyOf(obj); ok {
+		store.deleteExpiredKey(k, obj, WithDelCmd(Del))
+		return
 	}
+	store.delExpiry(obj)
 }
 
 // NX: Set the expiration only if the key does not already have an expiration time.
@@ -85,20 +125,17 @@ func DeleteExpiredKeys(store *Store) {
 // Returns Boolean False and error not-nil if invalid combination of subCommands or if subCommand is invalid
 func EvaluateAndSetExpiry(subCommands []string, newExpiry int64, key string,
 	store *Store) (shouldSetExpiry bool, err error) {
-	var newExpInMilli = newExpiry * 1000
 	var prevExpiry *uint64 = nil
-	var nxCmd, xxCmd, gtCmd, ltCmd bool
 
 	obj := store.Get(key)
 	//  key doesn't exist
 	if obj == nil {
 		return false, nil
 	}
-	shouldSetExpiry = true
 	// if no condition exists
 	if len(subCommands) == 0 {
 		store.SetUnixTimeExpiry(obj, newExpiry)
-		return shouldSetExpiry, nil
+		return true, nil
 	}
 
 	expireTime, ok := GetExpiry(obj, store)
@@ -106,6 +143,35 @@ func EvaluateAndSetExpiry(subCommands []string, newExpiry int64, key string,
 		prevExpiry = &expireTime
 	}
 
+	shouldSetExpiry, err = evaluateExpiryConditions(subCommands, prevExpiry, newExpiry*1000)
+	if err != nil {
+		return false, err
+	}
+
+	if shouldSetExpiry {
+		store.SetUnixTimeExpiry(obj, newExpiry)
+	}
+	return shouldSetExpiry, nil
+}
+
+// EvaluateFieldExpiry reports whether the NX, XX, GT and LT conditions, which are the ones of
+// EvaluateAndSetExpiry, allow setting the deadline of a field of the hash to newExpInMilli, in
+// Unix milliseconds.
+func EvaluateFieldExpiry(subCommands []string, newExpInMilli int64, obj *object.Obj, field string,
+	store *Store) (bool, error) {
+	var prevExpiry *uint64 = nil
+	if deadline, ok := store.GetFieldExpiry(obj, field); ok {
+		prevExpiry = &deadline
+	}
+	return evaluateExpiryConditions(subCommands, prevExpiry, newExpInMilli)
+}
+
+// evaluateExpiryConditions reports whether the NX, XX, GT and LT conditions allow replacing the
+// previous expiry, if any, with the new one, both in Unix milliseconds.
+func evaluateExpiryConditions(subCommands []string, prevExpiry *uint64, newExpInMilli int64) (bool, error) {
+	var nxCmd, xxCmd, gtCmd, ltCmd bool
+	shouldSetExpiry := true
+
 	for i := range subCommands {
 		subCommand := strings.ToUpper(subCommands[i])
 
@@ -144,8 +210,5 @@ func EvaluateAndSetExpiry(subCommands []string, newExpiry int64, key string,
 			" GT or LT options at the same time are not compatible")
 	}
 
-	if shouldSetExpiry {
-		store.SetUnixTimeExpiry(obj, newExpiry)
-	}
 	return shouldSetExpiry, nil
 }
@@ -4,9 +4,13 @@
 package store
 
 import (
+	"strconv"
 	"testing"
+	"time"
 
 	"github.com/dicedb/dice/internal/object"
+	"github.com/dicedb/dice/internal/server/utils"
+	"github.com/stretchr/testify/assert"
 )
 
 func TestDelExpiry(t *testing.T) {
@@ -57,3 +61,110 @@ func TestDelExpiry(t *testing.T) {
 		})
 	}
 }
+
+func TestDeleteExpiredKeysInDeadlineOrder(t *testing.T) {
+	mockTime := &utils.MockClock{CurrTime: time.Now()}
+	utils.CurrentTime = mockTime
+	defer func() { utils.CurrentTime = utils.RealClock{} }()
+
+	store := NewStore(nil, nil)
+	for i := 1; i <= 5; i++ {
+		store.Put("key"+strconv.Itoa(i), store.NewObj("value", int64(i)*1000, object.ObjTypeString))
+	}
+	putTestString(store, "durable", "value")
+
+	// The deadlines changed or removed are updated in the index
+	store.SetExpiry(store.GetNoTouch("key5"), 500)
+	DelExpiry(store.GetNoTouch("key1"), store)
+	store.Del("key2")
+	assert.Equal(t, 3, store.expiryIndex.Len())
+
+	mockTime.SetTime(mockTime.GetTime().Add(3500 * time.Millisecond))
+	assert.True(t, DeleteExpiredKeysWithin(store, 0))
+	assert.Nil(t, store.GetNoTouch("key3"))
+	assert.Nil(t, store.GetNoTouch("key5"))
+	assert.NotNil(t, store.GetNoTouch("key1"))
+	assert.NotNil(t, store.GetNoTouch("key4"))
+	assert.NotNil(t, store.GetNoTouch("durable"))
+	assert.Equal(t, 1, store.expiryIndex.Len())
+
+	// The objects never put only lose their expiry
+	store.NewObj("value", 0, object.ObjTypeString)
+	assert.True(t, DeleteExpiredKeysWithin(store, 0))
+	assert.Equal(t, 1, store.expiryIndex.Len())
+	assert.Equal(t, 1, store.expires.Len())
+	assert.Equal(t, 3, store.GetKeyCount())
+}
+
+func TestDeleteExpiredKeysWithinBudget(t *testing.T) {
+	mockTime := &utils.MockClock{CurrTime: time.Now()}
+	utils.CurrentTime = mockTime
+	defer func() { utils.CurrentTime = utils.RealClock{} }()
+
+	store := NewStore(nil, nil)
+	for i := 0; i < 2*expireCycleCheckInterval; i++ {
+		store.Put("key"+strconv.Itoa(i), store.NewObj("value", 1000, object.ObjTypeString))
+	}
+	mockTime.SetTime(mockTime.GetTime().Add(2 * time.Second))
+
+	// The budget is checked after each batch of keys
+	assert.False(t, DeleteExpiredKeysWithin(store, time.Nanosecond))
+	assert.Equal(t, expireCycleCheckInterval, store.GetKeyCount())
+
+	assert.True(t, DeleteExpiredKeysWithin(store, time.Minute))
+	assert.Zero(t, store.GetKeyCount())
+}
+
+// sampleExpiredKeys is the expire cycle which preceded the expiry index, kept to compare them. It
+// deletes the expired keys out of samples of 20 keys, until less than a quarter of a sample expired.
+func sampleExpiredKeys(store *Store) {
+	for {
+		limit, expiredCount := 20, 0
+		var keysToDelete []string
+		store.store.All(func(k string, obj *object.Obj) bool {
+			limit--
+			if hasExpired(obj, store) {
+				keysToDelete = append(keysToDelete, k)
+				expiredCount++
+			}
+			return limit >= 0
+		})
+		for _, k := range keysToDelete {
+			store.DelByPtr(k, WithDelCmd(Del))
+		}
+		if float32(expiredCount)/20.0 < 0.25 {
+			return
+		}
+	}
+}
+
+// BenchmarkExpireCycle runs an expire cycle on many keys with a long TTL and a few expired keys,
+// and reports the share of the expired keys which are left once it is done.
+func BenchmarkExpireCycle(b *testing.B) {
+	cycles := map[string]func(store *Store){
+		"index":    DeleteExpiredKeys,
+		"sampling": sampleExpiredKeys,
+	}
+
+	for name, cycle := range cycles {
+		b.Run(name, func(b *testing.B) {
+			const longKeys, shortKeys = 100000, 1000
+			left := 0
+			for i := 0; i < b.N; i++ {
+				b.StopTimer()
+				store := NewStore(nil, NewNoEviction())
+				for j := 0; j < longKeys; j++ {
+					store.Put("long"+strconv.Itoa(j), store.NewObj("value", 3600000, object.ObjTypeString))
+				}
+				for j := 0; j < shortKeys; j++ {
+					store.Put("short"+strconv.Itoa(j), store.NewObj("value", 0, object.ObjTypeString))
+				}
+				b.StartTimer()
+
+				cycle(store)
+				left += store.GetKeyCount() - longKeys
+			}
+			b.ReportMetric(float64(left)/float64(b.N*shortKeys), "expired-left/op")
+		})
+	}
+}
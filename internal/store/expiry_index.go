@@ -0,0 +1,93 @@
+// Copyright (c) 2022-present, DiceDB contributors
+// All rights reserved. Licensed under the BSD 3-Clause License. See LICENSE file in the project root for full license information.
+
+package store
+
+import (
+	"container/heap"
+
+	"github.com/dicedb/dice/internal/object"
+)
+
+// expiryEntry is the deadline of an object in the expiry index, in milliseconds since the epoch.
+type expiryEntry struct {
+	obj      *object.Obj
+	deadline uint64
+	index    int // Position of the entry in the heap, kept up to date by the heap operations.
+}
+
+// expiryHeap is a min-heap of expiryEntries based on deadline.
+type expiryHeap []*expiryEntry
+
+func (h *expiryHeap) Len() int { return len(*h) }
+
+func (h *expiryHeap) Less(i, j int) bool {
+	return (*h)[i].deadline < (*h)[j].deadline
+}
+
+func (h *expiryHeap) Swap(i, j int) {
+	(*h)[i], (*h)[j] = (*h)[j], (*h)[i]
+	(*h)[i].index = i
+	(*h)[j].index = j
+}
+
+func (h *expiryHeap) Push(x interface{}) {
+	entry := x.(*expiryEntry)
+	entry.index = len(*h)
+	*h = append(*h, entry)
+}
+
+func (h *expiryHeap) Pop() interface{} {
+	old := *h
+	n := len(old)
+	x := old[n-1]
+	old[n-1] = nil
+	*h = old[:n-1]
+	return x
+}
+
+// expiryIndex orders the objects with an expiry by deadline, so that the expire cycles find the
+// expired keys without sampling the keys. It mirrors the expiry table of a keyspace.
+type expiryIndex struct {
+	heap    expiryHeap
+	entries map[*object.Obj]*expiryEntry
+}
+
+func newExpiryIndex() *expiryIndex {
+	return &expiryIndex{
+		entries: make(map[*object.Obj]*expiryEntry),
+	}
+}
+
+func (idx *expiryIndex) Len() int {
+	return len(idx.heap)
+}
+
+// set sets the deadline of the object, in place of its previous one.
+func (idx *expiryIndex) set(obj *object.Obj, deadline uint64) {
+	if entry, ok := idx.entries[obj]; ok {
+		entry.deadline = deadline
+		heap.Fix(&idx.heap, entry.index)
+		return
+	}
+
+	entry := &expiryEntry{obj: obj, deadline: deadline}
+	heap.Push(&idx.heap, entry)
+	idx.entries[obj] = entry
+}
+
+// remove removes the deadline of the object, if it has one.
+func (idx *expiryIndex) remove(obj *object.Obj) {
+	if entry, ok := idx.entries[obj]; ok {
+		heap.Remove(&idx.heap, entry.index)
+		delete(idx.entries, obj)
+	}
+}
+
+// first returns the entry with the earliest deadline, or false if there is none.
+func (idx *expiryIndex) first() (*expiryEntry, bool) {
+	if len(idx.heap) == 0 {
+		return nil, false
+	}
+	return idx.heap[0], true
+}
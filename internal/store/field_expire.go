@@ -0,0 +1,146 @@
+// Copyright (c) 2022-present, DiceDB contributors
+// All rights reserved. Licensed under the BSD 3-Clause License. See LICENSE file in the project root for full license information.
+
+package store
+
+import (
+	"github.com/dicedb/dice/internal/common"
+	"github.com/dicedb/dice/internal/object"
+	"github.com/dicedb/dice/internal/server/utils"
+)
+
+// FieldMap is implemented by the values whose fields can expire, which are the hashes.
+type FieldMap interface {
+	// DelField deletes the field, and returns false if it does not exist.
+	DelField(field string) bool
+	// Len returns the number of fields.
+	Len() int
+	// ForEachField calls f with every field and its value.
+	ForEachField(f func(field, value string))
+}
+
+// fieldExpiry holds the deadlines of the fields of a hash, in Unix milliseconds, along with the
+// key of the hash, which the active expiry deletes once its last field expires.
+type fieldExpiry struct {
+	key       string
+	deadlines map[string]uint64
+}
+
+func newFieldExpireRegMap() common.ITable[*object.Obj, *fieldExpiry] {
+	return &common.RegMap[*object.Obj, *fieldExpiry]{
+		M: make(map[*object.Obj]*fieldExpiry),
+	}
+}
+
+// SetFieldExpiry sets the deadline of a field of the hash stored at key k, in Unix milliseconds.
+func (store *Store) SetFieldExpiry(k string, obj *object.Obj, field string, deadline uint64) {
+	fe, ok := store.fieldExpires.Get(obj)
+	if !ok {
+		fe = &fieldExpiry{key: k, deadlines: make(map[string]uint64)}
+		store.fieldExpires.Put(obj, fe)
+	}
+	fe.deadlines[field] = deadline
+}
+
+// GetFieldExpiry returns the deadline of a field of the hash, in Unix milliseconds, and false if
+// the field does not expire.
+func (store *Store) GetFieldExpiry(obj *object.Obj, field string) (uint64, bool) {
+	fe, ok := store.fieldExpires.Get(obj)
+	if !ok {
+		return 0, false
+	}
+	deadline, ok := fe.deadlines[field]
+	return deadline, ok
+}
+
+// GetFieldExpiries returns the deadlines of the fields of the hash which expire, which must not be
+// modified.
+func (store *Store) GetFieldExpiries(obj *object.Obj) map[string]uint64 {
+	fe, ok := store.fieldExpires.Get(obj)
+	if !ok {
+		return nil
+	}
+	return fe.deadlines
+}
+
+// DelFieldExpiry removes the deadline of a field of the hash, and returns false if the field does
+// not expire.
+func (store *Store) DelFieldExpiry(obj *object.Obj, field string) bool {
+	fe, ok := store.fieldExpires.Get(obj)
+	if !ok {
+		return false
+	}
+	if _, ok := fe.deadlines[field]; !ok {
+		return false
+	}
+	delete(fe.deadlines, field)
+	if len(fe.deadlines) == 0 {
+		store.fieldExpires.Delete(obj)
+	}
+	return true
+}
+
+// expireFields deletes the expired fields of the hash stored at key k - the passive way. The key
+// is deleted along with its last field, in which case it returns true.
+func (store *Store) expireFields(k string, obj *object.Obj) bool {
+	fe, ok := store.fieldExpires.Get(obj)
+	if !ok {
+		return false
+	}
+
+	fields, ok := obj.Value.(FieldMap)
+	if !ok {
+		store.fieldExpires.Delete(obj)
+		return false
+	}
+
+	now := uint64(utils.GetCurrentTime().UnixMilli())
+	for field, deadline := range fe.deadlines {
+		if deadline <= now {
+			fields.DelField(field)
+			delete(fe.deadlines, field)
+		}
+	}
+	if len(fe.deadlines) == 0 {
+		store.fieldExpires.Delete(obj)
+	}
+
+	if fields.Len() == 0 {
+		store.deleteExpiredKey(k, obj, WithDelCmd(Del))
+		return true
+	}
+	return false
+}
+
+// expireFieldsSample deletes the expired fields of up to 20 hashes having fields with a deadline,
+// and returns the fraction of them that had expired fields.
+func expireFieldsSample(store *Store) float32 {
+	var limit = 20
+	var expiredCount = 0
+	now := uint64(utils.GetCurrentTime().UnixMilli())
+
+	type sampledHash struct {
+		key string
+		obj *object.Obj
+	}
+	var hashes []sampledHash
+
+	store.fieldExpires.All(func(obj *object.Obj, fe *fieldExpiry) bool {
+		limit--
+		for _, deadline := range fe.deadlines {
+			if deadline <= now {
+				hashes = append(hashes, sampledHash{key: fe.key, obj: obj})
+				expiredCount++
+				break
+			}
+		}
+		return limit > 0
+	})
+
+	// Delete the fields outside the iteration
+	for _, h := range hashes {
+		store.expireFields(h.key, h.obj)
+	}
+
+	return float32(expiredCount) / float32(20.0)
+}
@@ -0,0 +1,137 @@
+// Copyright (c) 2022-present, DiceDB contributors
+// All rights reserved. Licensed under the BSD 3-Clause License. See LICENSE file in the project root for full license information.
+
+package store
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/dicedb/dice/internal/object"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testFieldMap is the FieldMap of the hashes of these tests.
+type testFieldMap map[string]string
+
+func (m testFieldMap) DelField(field string) bool {
+	if _, ok := m[field]; !ok {
+		return false
+	}
+	delete(m, field)
+	return true
+}
+
+func (m testFieldMap) Len() int {
+	return len(m)
+}
+
+func (m testFieldMap) ForEachField(f func(field, value string)) {
+	for field, value := range m {
+		f(field, value)
+	}
+}
+
+func putTestHash(store *Store, key string, fields testFieldMap) *object.Obj {
+	obj := store.NewObj(fields, -1, object.ObjTypeHashMap)
+	store.Put(key, obj)
+	return obj
+}
+
+func TestFieldExpiryLazy(t *testing.T) {
+	store := NewStore(nil, nil)
+	now := uint64(time.Now().UnixMilli())
+	obj := putTestHash(store, "h", testFieldMap{"f1": "v1", "f2": "v2", "f3": "v3"})
+
+	store.SetFieldExpiry("h", obj, "f1", now-1)
+	store.SetFieldExpiry("h", obj, "f2", now+60_000)
+
+	deadline, ok := store.GetFieldExpiry(obj, "f2")
+	assert.True(t, ok)
+	assert.Equal(t, now+60_000, deadline)
+	_, ok = store.GetFieldExpiry(obj, "f3")
+	assert.False(t, ok)
+
+	got := store.Get("h")
+	require.NotNil(t, got)
+	assert.Equal(t, testFieldMap{"f2": "v2", "f3": "v3"}, got.Value)
+	_, ok = store.GetFieldExpiry(obj, "f1")
+	assert.False(t, ok)
+
+	assert.True(t, store.DelFieldExpiry(obj, "f2"))
+	assert.False(t, store.DelFieldExpiry(obj, "f2"))
+	assert.Nil(t, store.GetFieldExpiries(obj))
+}
+
+func TestFieldExpiryDeletesEmptyHash(t *testing.T) {
+	store := NewStore(nil, nil)
+	now := uint64(time.Now().UnixMilli())
+	obj := putTestHash(store, "h", testFieldMap{"f1": "v1", "f2": "v2"})
+
+	store.SetFieldExpiry("h", obj, "f1", now-1)
+	store.SetFieldExpiry("h", obj, "f2", now-1)
+
+	assert.Nil(t, store.Get("h"))
+	assert.Zero(t, store.GetKeyCount())
+}
+
+func TestFieldExpiryActive(t *testing.T) {
+	store := NewStore(nil, nil)
+	now := uint64(time.Now().UnixMilli())
+	expired := putTestHash(store, "expired", testFieldMap{"f": "v"})
+	partial := putTestHash(store, "partial", testFieldMap{"f1": "v1", "f2": "v2"})
+
+	store.SetFieldExpiry("expired", expired, "f", now-1)
+	store.SetFieldExpiry("partial", partial, "f1", now-1)
+
+	DeleteExpiredKeys(store)
+
+	assert.Equal(t, 1, store.GetKeyCount())
+	assert.Equal(t, testFieldMap{"f2": "v2"}, partial.Value)
+	assert.Nil(t, store.GetFieldExpiries(partial))
+	assert.Nil(t, store.GetFieldExpiries(expired))
+}
+
+func TestFieldExpiryActiveWithinBudget(t *testing.T) {
+	store := NewStore(nil, nil)
+	now := uint64(time.Now().UnixMilli())
+	for i := 0; i < 60; i++ {
+		k := "hash" + strconv.Itoa(i)
+		obj := putTestHash(store, k, testFieldMap{"f1": "v1", "f2": "v2"})
+		store.SetFieldExpiry(k, obj, "f1", now-1)
+	}
+
+	// The budget is checked after each sample of hashes
+	assert.False(t, DeleteExpiredKeysWithin(store, time.Nanosecond))
+	assert.Equal(t, 40, store.fieldExpires.Len())
+
+	assert.True(t, DeleteExpiredKeysWithin(store, time.Minute))
+	assert.Zero(t, store.fieldExpires.Len())
+}
+
+func TestFieldExpiryFollowsTheHash(t *testing.T) {
+	store := NewStore(nil, nil)
+	now := uint64(time.Now().UnixMilli())
+	obj := putTestHash(store, "h", testFieldMap{"f": "v"})
+	store.SetFieldExpiry("h", obj, "f", now+60_000)
+
+	// Putting back the same hash keeps the deadlines of its fields
+	store.Put("h", obj)
+	_, ok := store.GetFieldExpiry(obj, "f")
+	assert.True(t, ok)
+
+	// Renaming the hash carries them along
+	require.True(t, store.Rename("h", "renamed"))
+	store.SetFieldExpiry("renamed", obj, "f", now-1)
+	DeleteExpiredKeys(store)
+	assert.Zero(t, store.GetKeyCount())
+
+	// Overwriting the hash drops them
+	obj = putTestHash(store, "h", testFieldMap{"f": "v"})
+	store.SetFieldExpiry("h", obj, "f", now+60_000)
+	replacement := putTestHash(store, "h", testFieldMap{"f": "v"})
+	assert.Nil(t, store.GetFieldExpiries(obj))
+	assert.Nil(t, store.GetFieldExpiries(replacement))
+}
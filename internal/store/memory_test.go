@@ -0,0 +1,108 @@
+// Copyright (c) 2022-present, DiceDB contributors
+// All rights reserved. Licensed under the BSD 3-Clause License. See LICENSE file in the project root for full license information.
+
+package store
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/dicedb/dice/internal/object"
+	"github.com/stretchr/testify/assert"
+)
+
+func putTestString(store *Store, key, value string) *object.Obj {
+	obj := store.NewObj(value, -1, object.ObjTypeString)
+	store.Put(key, obj)
+	return obj
+}
+
+func TestUsedMemory(t *testing.T) {
+	store := NewStore(nil, nil)
+	assert.Zero(t, store.UsedMemory())
+
+	obj := putTestString(store, "k1", "value")
+	size := object.KeySize("k1", obj, object.DefaultSizeSamples)
+	assert.Equal(t, size, store.UsedMemory())
+
+	// Overwriting a key replaces the size of its object
+	obj = putTestString(store, "k1", strings.Repeat("v", 100))
+	assert.Equal(t, object.KeySize("k1", obj, object.DefaultSizeSamples), store.UsedMemory())
+
+	// Objects modified in place are measured again
+	obj.Value = "value"
+	store.UpdateMemory("k1")
+	assert.Equal(t, size, store.UsedMemory())
+
+	// Renaming the key measures it with its new name
+	assert.True(t, store.Rename("k1", "renamed"))
+	assert.Equal(t, object.KeySize("renamed", obj, object.DefaultSizeSamples), store.UsedMemory())
+
+	assert.True(t, store.Del("renamed"))
+	assert.Zero(t, store.UsedMemory())
+}
+
+func TestUsedMemoryAcrossDatabases(t *testing.T) {
+	store := newDatabaseTestStore(t, 2)
+
+	putTestString(store, "k1", "value")
+	used := store.UsedMemory()
+
+	// Moving the key keeps its size
+	assert.True(t, store.Move("k1", 1))
+	assert.Equal(t, used, store.UsedMemory())
+
+	// Flushing a database drops the size of its keys
+	store.SelectDB(1)
+	store.ResetStore()
+	assert.Zero(t, store.UsedMemory())
+}
+
+func TestFreeMemory(t *testing.T) {
+	store := NewStore(nil, NewBatchEvictionLRU(1000, 0.5))
+	assert.True(t, store.FreeMemory(), "Keys are not limited without max memory")
+
+	for i := 0; i < 10; i++ {
+		putTestString(store, "key"+strconv.Itoa(i), "value")
+	}
+	used := store.UsedMemory()
+
+	store.SetMaxMemory(used / 2)
+	assert.True(t, store.FreeMemory())
+	assert.LessOrEqual(t, store.UsedMemory(), used/2)
+	assert.Equal(t, 5, store.GetKeyCount())
+}
+
+func TestFreeMemoryAcrossDatabases(t *testing.T) {
+	store := newDatabaseTestStore(t, 2)
+	store.SelectDB(1)
+	for i := 0; i < 10; i++ {
+		putTestString(store, "key"+strconv.Itoa(i), "value")
+	}
+	used := store.UsedMemory()
+
+	// The keys of the other databases are evicted as well, the selected one holding none
+	store.SelectDB(0)
+	store.SetMaxMemory(used / 2)
+	assert.True(t, store.FreeMemory())
+	assert.LessOrEqual(t, store.UsedMemory(), used/2)
+	assert.Equal(t, 0, store.SelectedDB())
+
+	store.SelectDB(1)
+	assert.Equal(t, 5, store.GetKeyCount())
+}
+
+func TestFreeMemoryWithoutEviction(t *testing.T) {
+	store := NewStore(nil, NewNoEviction())
+	for i := 0; i < 10; i++ {
+		putTestString(store, "key"+strconv.Itoa(i), "value")
+	}
+
+	store.SetMaxMemory(store.UsedMemory() - 1)
+	assert.False(t, store.FreeMemory())
+	assert.Equal(t, 10, store.GetKeyCount())
+
+	store.SetMaxMemory(store.UsedMemory())
+	assert.True(t, store.FreeMemory())
+}
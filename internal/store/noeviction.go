@@ -0,0 +1,30 @@
+// Copyright (c) 2022-present, DiceDB contributors
+// All rights reserved. Licensed under the BSD 3-Clause License. See LICENSE file in the project root for full license information.
+
+package store
+
+import (
+	"github.com/dicedb/dice/internal/object"
+)
+
+// NoEviction never evicts keys. Once the keys use more than the max memory of the store, the
+// commands which may grow it are rejected instead, see Store.FreeMemory.
+type NoEviction struct {
+	BaseEvictionStrategy
+}
+
+func NewNoEviction() *NoEviction {
+	return &NoEviction{}
+}
+
+func (e *NoEviction) ShouldEvict(store *Store) int {
+	return 0
+}
+
+func (e *NoEviction) EvictVictims(store *Store, toEvict int) {
+	// Nothing to evict
+}
+
+func (e *NoEviction) OnAccess(key string, obj *object.Obj, accessType AccessType) {
+	// Nothing to do without eviction
+}
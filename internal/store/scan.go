@@ -0,0 +1,73 @@
+// Copyright (c) 2022-present, DiceDB contributors
+// All rights reserved. Licensed under the BSD 3-Clause License. See LICENSE file in the project root for full license information.
+
+package store
+
+import (
+	"github.com/cespare/xxhash/v2"
+	"github.com/google/btree"
+)
+
+// A SCAN cursor is made of the ID of the shard being scanned, in its upper ScanShardBits bits, and of
+// a position in that shard in the remaining bits. The keys of a shard are scanned in the order of a
+// hash of their name, and the position is the smallest hash that is yet to be returned. As the order
+// of a key does not depend on the other keys, every key present for the whole scan is returned, no
+// matter how many keys are added or deleted in between.
+const (
+	ScanShardBits    = 8
+	scanPositionBits = 64 - ScanShardBits
+	scanPositionMask = 1<<scanPositionBits - 1
+)
+
+// EncodeScanCursor returns the cursor resuming the scan of the shard at the given position.
+func EncodeScanCursor(shardID uint8, position uint64) uint64 {
+	return uint64(shardID)<<scanPositionBits | position&scanPositionMask
+}
+
+// DecodeScanCursor returns the shard and the position in that shard encoded in the cursor.
+func DecodeScanCursor(cursor uint64) (shardID uint8, position uint64) {
+	return uint8(cursor >> scanPositionBits), cursor & scanPositionMask
+}
+
+// scanEntry is the entry of a key in the scan index, ordered by the hash of the key and then by
+// the key itself, so that colliding keys have a stable order too.
+type scanEntry struct {
+	hash uint64
+	key  string
+}
+
+func scanEntryLess(a, b scanEntry) bool {
+	if a.hash != b.hash {
+		return a.hash < b.hash
+	}
+	return a.key < b.key
+}
+
+func newScanIndex() *btree.BTreeG[scanEntry] {
+	return btree.NewG(32, scanEntryLess)
+}
+
+func newScanEntry(k string) scanEntry {
+	return scanEntry{hash: xxhash.Sum64String(k) & scanPositionMask, key: k}
+}
+
+// Scan returns at least count keys whose position is greater than or equal to the given position,
+// unless fewer are left, along with the position to resume the scan from. The keys sharing the
+// position of the last key are all returned, so that resuming the scan never skips a key. The next
+// position is 0 once the whole store is scanned. Expired keys are not returned.
+func (store *Store) Scan(position uint64, count int) (keys []string, next uint64) {
+	keys = make([]string, 0, count)
+	var last uint64
+	store.scanIndex.AscendGreaterOrEqual(scanEntry{hash: position}, func(entry scanEntry) bool {
+		if len(keys) >= count && entry.hash != last {
+			next = entry.hash
+			return false
+		}
+		last = entry.hash
+		if obj, _ := store.store.Get(entry.key); obj != nil && !hasExpired(obj, store) {
+			keys = append(keys, entry.key)
+		}
+		return true
+	})
+	return keys, next
+}
@@ -0,0 +1,126 @@
+// Copyright (c) 2022-present, DiceDB contributors
+// All rights reserved. Licensed under the BSD 3-Clause License. See LICENSE file in the project root for full license information.
+
+package store
+
+import (
+	"fmt"
+	"sort"
+	"testing"
+
+	"github.com/dicedb/dice/internal/object"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newScanTestStore() *Store {
+	return NewStore(nil, NewBatchEvictionLRU(1_000_000, 0.1))
+}
+
+func putScanTestKey(store *Store, k string) {
+	store.Put(k, store.NewObj("v", -1, object.ObjTypeString))
+}
+
+// scanAll scans the whole store with the given page size, calling between each page.
+func scanAll(t *testing.T, store *Store, count int, between func(page int)) []string {
+	var keys []string
+	position := uint64(0)
+	for page := 0; ; page++ {
+		require.Less(t, page, 10_000, "the scan does not terminate")
+		var pageKeys []string
+		pageKeys, position = store.Scan(position, count)
+		keys = append(keys, pageKeys...)
+		if position == 0 {
+			return keys
+		}
+		if between != nil {
+			between(page)
+		}
+	}
+}
+
+func TestScanCursorEncoding(t *testing.T) {
+	cursor := EncodeScanCursor(3, 12345)
+	shardID, position := DecodeScanCursor(cursor)
+	assert.Equal(t, uint8(3), shardID)
+	assert.Equal(t, uint64(12345), position)
+
+	shardID, position = DecodeScanCursor(0)
+	assert.Equal(t, uint8(0), shardID)
+	assert.Equal(t, uint64(0), position)
+}
+
+func TestScanReturnsEveryKeyOnce(t *testing.T) {
+	store := newScanTestStore()
+	expected := make([]string, 0, 1000)
+	for i := 0; i < 1000; i++ {
+		k := fmt.Sprintf("key:%d", i)
+		putScanTestKey(store, k)
+		expected = append(expected, k)
+	}
+	// Overwriting a key does not index it twice
+	putScanTestKey(store, "key:0")
+
+	for _, count := range []int{1, 7, 100, 5000} {
+		keys := scanAll(t, store, count, nil)
+		assert.ElementsMatch(t, expected, keys, "count %d", count)
+	}
+
+	keys, next := store.Scan(0, 10)
+	assert.Len(t, keys, 10)
+	assert.NotZero(t, next)
+}
+
+func TestScanUnderMutation(t *testing.T) {
+	store := newScanTestStore()
+	stable := make([]string, 0, 500)
+	for i := 0; i < 500; i++ {
+		k := fmt.Sprintf("stable:%d", i)
+		putScanTestKey(store, k)
+		stable = append(stable, k)
+	}
+	for i := 0; i < 500; i++ {
+		putScanTestKey(store, fmt.Sprintf("volatile:%d", i))
+	}
+
+	// Keys are added, deleted and renamed between the pages
+	keys := scanAll(t, store, 10, func(page int) {
+		for i := 0; i < 20; i++ {
+			putScanTestKey(store, fmt.Sprintf("added:%d:%d", page, i))
+		}
+		for i := page * 10; i < page*10+10 && i < 500; i++ {
+			store.Del(fmt.Sprintf("volatile:%d", i))
+		}
+		store.Rename(fmt.Sprintf("added:%d:0", page), fmt.Sprintf("renamed:%d", page))
+	})
+
+	seen := make(map[string]bool, len(keys))
+	for _, k := range keys {
+		seen[k] = true
+	}
+	for _, k := range stable {
+		assert.True(t, seen[k], "%s was not returned", k)
+	}
+}
+
+func TestScanIndexFollowsDeletes(t *testing.T) {
+	store := newScanTestStore()
+	for _, k := range []string{"a", "b", "c", "d"} {
+		putScanTestKey(store, k)
+	}
+	store.Del("a")
+	store.GetDel("b")
+	assert.True(t, store.Rename("c", "e"))
+
+	// Expired keys are skipped
+	store.Put("f", store.NewObj("v", 0, object.ObjTypeString))
+
+	keys := scanAll(t, store, 10, nil)
+	sort.Strings(keys)
+	assert.Equal(t, []string{"d", "e"}, keys)
+	assert.Equal(t, 3, store.scanIndex.Len())
+
+	store.ResetStore()
+	assert.Empty(t, scanAll(t, store, 10, nil))
+	assert.Equal(t, 0, store.scanIndex.Len())
+}
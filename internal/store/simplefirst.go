@@ -0,0 +1,43 @@
+// Copyright (c) 2022-present, DiceDB contributors
+// All rights reserved. Licensed under the BSD 3-Clause License. See LICENSE file in the project root for full license information.
+
+package store
+
+import (
+	"github.com/dicedb/dice/internal/object"
+)
+
+// SimpleFirst evicts the first keys found while iterating over the store, without regard for how
+// they are accessed. It is the cheapest eviction strategy.
+type SimpleFirst struct {
+	BaseEvictionStrategy
+	keysLimit
+}
+
+func NewSimpleFirst(maxKeys int, evictionRatio float64) *SimpleFirst {
+	return &SimpleFirst{
+		keysLimit: keysLimit{maxKeys: maxKeys, evictionRatio: evictionRatio},
+	}
+}
+
+// EvictVictims deletes the first toEvict keys of the store.
+func (e *SimpleFirst) EvictVictims(store *Store, toEvict int) {
+	if toEvict <= 0 {
+		return
+	}
+
+	victims := make([]string, 0, toEvict)
+	sampleKeys(store, toEvict, func(k string, obj *object.Obj) {
+		victims = append(victims, k)
+	})
+
+	for _, k := range victims {
+		store.Del(k, WithDelCmd(Evict))
+	}
+
+	e.stats.recordEviction(int64(len(victims)))
+}
+
+func (e *SimpleFirst) OnAccess(key string, obj *object.Obj, accessType AccessType) {
+	// Nothing to do, the accesses do not matter
+}
@@ -0,0 +1,124 @@
+// Copyright (c) 2022-present, DiceDB contributors
+// All rights reserved. Licensed under the BSD 3-Clause License. See LICENSE file in the project root for full license information.
+
+package store
+
+import (
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/dicedb/dice/internal/server/utils"
+)
+
+const (
+	// SlowLogMaxArgs is the number of arguments kept by an entry of the slowlog, the last one
+	// standing for those left out.
+	SlowLogMaxArgs = 32
+
+	// SlowLogMaxArgLength is the number of bytes kept of each argument of an entry of the slowlog.
+	SlowLogMaxArgLength = 128
+)
+
+// slowLogID numbers the entries of the slowlogs of all the shards, so that they can be merged in
+// the order they were recorded.
+var slowLogID atomic.Uint64
+
+// SlowLogEntry is a command which took at least the slowlog-log-slower-than threshold to execute.
+type SlowLogEntry struct {
+	ID       uint64
+	Time     time.Time
+	Duration time.Duration
+
+	// Args holds the name of the command followed by its arguments, truncated after SlowLogMaxArgs
+	// and SlowLogMaxArgLength.
+	Args []string
+
+	// ClientAddr and ClientName identify the client which sent the command, when known.
+	ClientAddr string
+	ClientName string
+}
+
+// SlowLog holds the last slow commands executed by a shard, in a ring of bounded length.
+type SlowLog struct {
+	entries []SlowLogEntry
+	next    int // next is the index of the entry overwritten by the next command recorded.
+	maxLen  int
+}
+
+// NewSlowLog returns a slowlog keeping at most maxLen commands.
+func NewSlowLog(maxLen int) *SlowLog {
+	return &SlowLog{maxLen: max(maxLen, 0)}
+}
+
+// Add records a command which took the given time, dropping the oldest entry once the slowlog is
+// full.
+func (l *SlowLog) Add(name string, args []string, duration time.Duration, clientAddr, clientName string) {
+	if l.maxLen == 0 {
+		return
+	}
+
+	entry := SlowLogEntry{
+		ID:         slowLogID.Add(1),
+		Time:       utils.GetCurrentTime(),
+		Duration:   duration,
+		Args:       slowLogArgs(name, args),
+		ClientAddr: clientAddr,
+		ClientName: clientName,
+	}
+	if len(l.entries) < l.maxLen {
+		l.entries = append(l.entries, entry)
+	} else {
+		l.entries[l.next] = entry
+	}
+	l.next = (l.next + 1) % l.maxLen
+}
+
+// Entries returns at most count entries, the most recent first. A negative count returns all of
+// them.
+func (l *SlowLog) Entries(count int) []SlowLogEntry {
+	if count < 0 || count > len(l.entries) {
+		count = len(l.entries)
+	}
+
+	entries := make([]SlowLogEntry, 0, count)
+	for i := 1; i <= count; i++ {
+		entries = append(entries, l.entries[(l.next-i+len(l.entries))%len(l.entries)])
+	}
+	return entries
+}
+
+// Len returns the number of entries of the slowlog.
+func (l *SlowLog) Len() int {
+	return len(l.entries)
+}
+
+// Reset deletes all the entries of the slowlog.
+func (l *SlowLog) Reset() {
+	l.entries = nil
+	l.next = 0
+}
+
+// slowLogArgs returns the name and arguments of a command as recorded by the slowlog.
+func slowLogArgs(name string, args []string) []string {
+	argv := append([]string{name}, args...)
+	n := min(len(argv), SlowLogMaxArgs)
+	truncated := make([]string, n)
+	for i := 0; i < n; i++ {
+		if i == n-1 && len(argv) > n {
+			truncated[i] = fmt.Sprintf("... (%d more arguments)", len(argv)-n+1)
+			break
+		}
+		arg := argv[i]
+		if len(arg) > SlowLogMaxArgLength {
+			arg = fmt.Sprintf("%s... (%d more bytes)", arg[:SlowLogMaxArgLength], len(arg)-SlowLogMaxArgLength)
+		}
+		truncated[i] = arg
+	}
+	return truncated
+}
+
+// SlowLog returns the slowlog of the store.
+func (store *Store) SlowLog() *SlowLog {
+	return store.slowLog
+}
@@ -0,0 +1,63 @@
+// Copyright (c) 2022-present, DiceDB contributors
+// All rights reserved. Licensed under the BSD 3-Clause License. See LICENSE file in the project root for full license information.
+
+package store
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSlowLogRing(t *testing.T) {
+	slowLog := NewSlowLog(3)
+	for i := 0; i < 5; i++ {
+		slowLog.Add("GET", []string{"key" + strconv.Itoa(i)}, time.Duration(i)*time.Millisecond, "127.0.0.1:5000", "client")
+	}
+
+	// The oldest entries were dropped, and the most recent ones are listed first
+	assert.Equal(t, 3, slowLog.Len())
+	entries := slowLog.Entries(-1)
+	require.Len(t, entries, 3)
+	for i, key := range []string{"key4", "key3", "key2"} {
+		assert.Equal(t, []string{"GET", key}, entries[i].Args)
+	}
+	assert.Greater(t, entries[0].ID, entries[1].ID)
+	assert.Equal(t, 4*time.Millisecond, entries[0].Duration)
+	assert.Equal(t, "127.0.0.1:5000", entries[0].ClientAddr)
+	assert.Equal(t, "client", entries[0].ClientName)
+
+	entries = slowLog.Entries(2)
+	require.Len(t, entries, 2)
+	assert.Equal(t, []string{"GET", "key3"}, entries[1].Args)
+	assert.Empty(t, slowLog.Entries(0))
+
+	slowLog.Reset()
+	assert.Zero(t, slowLog.Len())
+	assert.Empty(t, slowLog.Entries(-1))
+
+	disabled := NewSlowLog(0)
+	disabled.Add("GET", []string{"key"}, time.Second, "", "")
+	assert.Zero(t, disabled.Len())
+}
+
+func TestSlowLogTruncatesArgs(t *testing.T) {
+	args := make([]string, 40)
+	for i := range args {
+		args[i] = "arg"
+	}
+	args[0] = strings.Repeat("a", SlowLogMaxArgLength+10)
+
+	slowLog := NewSlowLog(1)
+	slowLog.Add("MSET", args, time.Second, "", "")
+	recorded := slowLog.Entries(1)[0].Args
+	require.Len(t, recorded, SlowLogMaxArgs)
+	assert.Equal(t, "MSET", recorded[0])
+	assert.Equal(t, strings.Repeat("a", SlowLogMaxArgLength)+"... (10 more bytes)", recorded[1])
+	assert.Equal(t, "arg", recorded[SlowLogMaxArgs-2])
+	assert.Equal(t, "... (10 more arguments)", recorded[SlowLogMaxArgs-1])
+}
@@ -0,0 +1,101 @@
+// Copyright (c) 2022-present, DiceDB contributors
+// All rights reserved. Licensed under the BSD 3-Clause License. See LICENSE file in the project root for full license information.
+
+package store
+
+import (
+	"time"
+
+	"github.com/dicedb/dice/internal/object"
+)
+
+// Stats holds the counters of a store since it was created, reported by INFO.
+type Stats struct {
+	// KeyspaceHits and KeyspaceMisses count the lookups of the keys by the commands which found them,
+	// or not.
+	KeyspaceHits   uint64
+	KeyspaceMisses uint64
+
+	// ExpiredKeys counts the keys deleted once expired, either when accessed or by the expire cycles.
+	ExpiredKeys uint64
+
+	// ExpireCycles counts the runs of DeleteExpiredKeys, ExpireCycleTime is the time they took in
+	// total and LastExpireCycleTime the time of the last one.
+	ExpireCycles        uint64
+	ExpireCycleTime     time.Duration
+	LastExpireCycleTime time.Duration
+
+	// ExpiredStaleRatio is the ratio of the keys with an expiry that the last expire cycle deleted,
+	// which had expired but were not deleted yet.
+	ExpiredStaleRatio float64
+
+	// EvictedKeys counts the keys evicted, Evictions the runs of the eviction strategy which evicted
+	// them and LastEvictionCount the number of keys evicted by the last one.
+	EvictedKeys       uint64
+	Evictions         uint64
+	LastEvictionCount int64
+
+	// SpilledKeys counts the evicted keys spilled to the disk tier and PromotedKeys those moved back
+	// to memory once accessed, while DiskTierKeys and DiskTierBytes are the number of keys in the
+	// disk tier and the size of its file, see DiskTier.
+	SpilledKeys   uint64
+	PromotedKeys  uint64
+	DiskTierKeys  int
+	DiskTierBytes int64
+}
+
+// DatabaseStats holds the number of keys of a database, and how many of them have an expiry.
+type DatabaseStats struct {
+	Keys    int
+	Expires int
+}
+
+// DatabaseStats returns the number of keys of each database, indexed by database.
+func (store *Store) DatabaseStats() []DatabaseStats {
+	stats := make([]DatabaseStats, len(store.databases))
+	for i, db := range store.databases {
+		stats[i] = DatabaseStats{Keys: db.numKeys, Expires: db.expires.Len()}
+	}
+	return stats
+}
+
+// Stats returns the counters of the store, along with those of its eviction strategy.
+func (store *Store) Stats() Stats {
+	stats := store.stats
+	evictionStats := store.evictionStrategy.GetStats()
+	stats.EvictedKeys = evictionStats.totalKeysEvicted
+	stats.Evictions = evictionStats.totalEvictions
+	stats.LastEvictionCount = evictionStats.lastEvictionCount
+	if store.diskTier != nil {
+		stats.DiskTierKeys = store.diskTier.records
+		stats.DiskTierBytes = store.diskTier.size
+	}
+	return stats
+}
+
+// recordLookup counts a lookup of a key by a command.
+func (store *Store) recordLookup(found bool) {
+	if found {
+		store.stats.KeyspaceHits++
+	} else {
+		store.stats.KeyspaceMisses++
+	}
+}
+
+// recordExpireCycle counts a run of DeleteExpiredKeys which took the given time, and deleted
+// staleRatio of the keys with an expiry.
+func (store *Store) recordExpireCycle(duration time.Duration, staleRatio float64) {
+	store.stats.ExpireCycles++
+	store.stats.ExpireCycleTime += duration
+	store.stats.LastExpireCycleTime = duration
+	store.stats.ExpiredStaleRatio = staleRatio
+}
+
+// deleteExpiredKey deletes the key k, whose object has expired.
+func (store *Store) deleteExpiredKey(k string, obj *object.Obj, opts ...DelOption) bool {
+	if !store.deleteKey(k, obj, opts...) {
+		return false
+	}
+	store.stats.ExpiredKeys++
+	return true
+}
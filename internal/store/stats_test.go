@@ -0,0 +1,65 @@
+// Copyright (c) 2022-present, DiceDB contributors
+// All rights reserved. Licensed under the BSD 3-Clause License. See LICENSE file in the project root for full license information.
+
+package store
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/dicedb/dice/internal/object"
+	"github.com/dicedb/dice/internal/server/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStatsKeyspaceLookups(t *testing.T) {
+	store := NewStore(nil, nil)
+	putTestString(store, "k1", "value")
+
+	store.Get("k1")
+	store.Get("missing")
+	store.GetAll([]string{"k1", "missing", "missing"})
+	store.GetNoTouch("missing")
+
+	stats := store.Stats()
+	assert.Equal(t, uint64(2), stats.KeyspaceHits)
+	assert.Equal(t, uint64(3), stats.KeyspaceMisses)
+}
+
+func TestStatsExpiredKeys(t *testing.T) {
+	mockTime := &utils.MockClock{CurrTime: time.Now()}
+	utils.CurrentTime = mockTime
+	defer func() { utils.CurrentTime = utils.RealClock{} }()
+
+	store := NewStore(nil, nil)
+	for i := 0; i < 4; i++ {
+		store.Put("k"+strconv.Itoa(i), store.NewObj("value", 1000, object.ObjTypeString))
+	}
+	putTestString(store, "durable", "value")
+	mockTime.SetTime(mockTime.GetTime().Add(2 * time.Second))
+
+	// The expired keys are deleted when accessed, or by the expire cycles
+	assert.Nil(t, store.Get("k0"))
+	assert.Equal(t, uint64(1), store.Stats().ExpiredKeys)
+
+	DeleteExpiredKeys(store)
+	stats := store.Stats()
+	assert.Equal(t, uint64(4), stats.ExpiredKeys)
+	assert.Equal(t, uint64(1), stats.ExpireCycles)
+	assert.Greater(t, stats.ExpiredStaleRatio, 0.0)
+	assert.Equal(t, stats.ExpireCycleTime, stats.LastExpireCycleTime)
+	assert.Equal(t, 1, store.GetKeyCount())
+}
+
+func TestStatsEvictedKeys(t *testing.T) {
+	store := NewStore(nil, NewAllKeysLRU(10, 0.5))
+	for i := 0; i <= 10; i++ {
+		putTestString(store, "key"+strconv.Itoa(i), "value")
+	}
+
+	stats := store.Stats()
+	assert.Equal(t, uint64(5), stats.EvictedKeys)
+	assert.Equal(t, uint64(1), stats.Evictions)
+	assert.Equal(t, int64(5), stats.LastEvictionCount)
+}
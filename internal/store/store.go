@@ -4,15 +4,26 @@
 package store
 
 import (
+	"math"
 	"path"
+	"sync/atomic"
 
 	"github.com/dicedb/dice/config"
 
 	"github.com/dicedb/dice/internal/common"
 	"github.com/dicedb/dice/internal/object"
 	"github.com/dicedb/dice/internal/server/utils"
+	"github.com/google/btree"
 )
 
+// objectVersions numbers the versions of the objects of all the stores, see BumpVersion.
+var objectVersions atomic.Uint64
+
+// BumpVersion gives a new version to the object, once stored or modified in place.
+func BumpVersion(obj *object.Obj) {
+	obj.Version = objectVersions.Add(1)
+}
+
 func NewStoreRegMap() common.ITable[string, *object.Obj] {
 	return &common.RegMap[string, *object.Obj]{
 		M: make(map[string]*object.Obj),
@@ -25,6 +36,12 @@ func NewExpireRegMap() common.ITable[*object.Obj, uint64] {
 	}
 }
 
+func newObjectRegMap() common.ITable[*object.Obj, objectEntry] {
+	return &common.RegMap[*object.Obj, objectEntry]{
+		M: make(map[*object.Obj]objectEntry),
+	}
+}
+
 func NewStoreMap() common.ITable[string, *object.Obj] {
 	return NewStoreRegMap()
 }
@@ -34,10 +51,7 @@ func NewExpireMap() common.ITable[*object.Obj, uint64] {
 }
 
 func NewDefaultEviction() EvictionStrategy {
-	return &BatchEvictionLRU{
-		maxKeys:       config.DefaultKeysLimit,
-		evictionRatio: config.DefaultEvictionRatio,
-	}
+	return NewBatchEvictionLRU(config.DefaultKeysLimit, config.DefaultEvictionRatio)
 }
 
 // QueryWatchEvent represents a change in a watched key.
@@ -52,21 +66,79 @@ type CmdWatchEvent struct {
 	AffectedKey string
 }
 
+// keyspace holds the keys of a logical database.
+type keyspace struct {
+	store       common.ITable[string, *object.Obj]
+	expires     common.ITable[*object.Obj, uint64] // Does not need to be thread-safe as it is only accessed by a single thread.
+	expiryIndex *expiryIndex                       // Orders the expires by deadline for the expire cycles, see DeleteExpiredKeys.
+	scanIndex   *btree.BTreeG[scanEntry]           // Orders the keys for SCAN, see Scan.
+	numKeys     int
+
+	// fieldExpires holds the deadlines of the hash fields, see SetFieldExpiry.
+	fieldExpires common.ITable[*object.Obj, *fieldExpiry]
+
+	// objects holds the key of each object and the estimated memory they use, and usedMemory the sum
+	// of the latter, see UsedMemory.
+	objects    common.ITable[*object.Obj, objectEntry]
+	usedMemory int64
+
+	// spilled holds the records of the keys evicted to the disk tier, see DiskTier.
+	spilled map[string]spillRecord
+}
+
+// objectEntry holds the key of an object of the store and the estimated memory used by both.
+type objectEntry struct {
+	key  string
+	size int64
+}
+
+func newKeyspace() *keyspace {
+	return &keyspace{
+		store:       NewStoreRegMap(),
+		expires:     NewExpireRegMap(),
+		expiryIndex: newExpiryIndex(),
+		scanIndex:   newScanIndex(),
+
+		fieldExpires: newFieldExpireRegMap(),
+
+		objects: newObjectRegMap(),
+		spilled: make(map[string]spillRecord),
+	}
+}
+
+// Store holds the logical databases of a shard. The commands operate on the keyspace of the
+// selected database, which the shard selects before executing each command of a client.
 type Store struct {
-	store            common.ITable[string, *object.Obj]
-	expires          common.ITable[*object.Obj, uint64] // Does not need to be thread-safe as it is only accessed by a single thread.
-	numKeys          int
+	*keyspace
+	databases        []*keyspace
+	selectedDB       int
 	cmdWatchChan     chan CmdWatchEvent
 	evictionStrategy EvictionStrategy
+
+	// maxMemory is the number of bytes the keys of the store may use before keys are evicted, or 0
+	// if they are not limited.
+	maxMemory int64
+
+	// diskTier holds the evicted keys, if the store spills them to disk, see SetDiskTier.
+	diskTier *DiskTier
+
+	// slowLog holds the last commands which were slow to execute on the store, see SlowLog.
+	slowLog *SlowLog
+
+	stats Stats
 }
 
 func NewStore(cmdWatchChan chan CmdWatchEvent, evictionStrategy EvictionStrategy) *Store {
 	store := &Store{
-		store:            NewStoreRegMap(),
-		expires:          NewExpireRegMap(),
+		databases:        make([]*keyspace, DatabaseCount()),
 		cmdWatchChan:     cmdWatchChan,
 		evictionStrategy: evictionStrategy,
+		slowLog:          NewSlowLog(config.DiceConfig.Performance.SlowlogMaxLen),
 	}
+	for i := range store.databases {
+		store.databases[i] = newKeyspace()
+	}
+	store.keyspace = store.databases[0]
 	if evictionStrategy == nil {
 		store.evictionStrategy = NewDefaultEviction()
 	}
@@ -74,10 +146,15 @@ func NewStore(cmdWatchChan chan CmdWatchEvent, evictionStrategy EvictionStrategy
 	return store
 }
 
+// DatabaseCount returns the configured number of logical databases, or a single one if the
+// configuration is not loaded.
+func DatabaseCount() int {
+	return max(config.DiceConfig.Memory.Databases, 1)
+}
+
+// ResetStore deletes all the keys of the selected database.
 func ResetStore(store *Store) *Store {
-	store.numKeys = 0
-	store.store = NewStoreMap()
-	store.expires = NewExpireMap()
+	store.ResetStore()
 
 	return store
 }
@@ -94,10 +171,149 @@ func (store *Store) NewObj(value interface{}, expDurationMs int64, oType object.
 	return obj
 }
 
+// SetMaxMemory sets the number of bytes the keys of all the databases may use before keys are
+// evicted, or no limit with 0.
+func (store *Store) SetMaxMemory(maxMemory int64) {
+	store.maxMemory = maxMemory
+}
+
+// UsedMemory returns the estimated number of bytes used by the keys of all the databases.
+func (store *Store) UsedMemory() int64 {
+	used := int64(0)
+	for _, db := range store.databases {
+		used += db.usedMemory
+	}
+	return used
+}
+
+// trackMemory records the size of the object put at key k, in place of its previous size.
+func (store *Store) trackMemory(k string, obj *object.Obj) {
+	size := object.KeySize(k, obj, object.DefaultSizeSamples)
+	if previous, ok := store.objects.Get(obj); ok {
+		store.usedMemory -= previous.size
+	}
+	store.objects.Put(obj, objectEntry{key: k, size: size})
+	store.usedMemory += size
+}
+
+// UpdateMemory estimates again the size of the object of key k, once modified in place.
+func (store *Store) UpdateMemory(k string) {
+	if obj, ok := store.store.Get(k); ok {
+		store.trackMemory(k, obj)
+	}
+}
+
+// untrackMemory forgets the size of an object removed from the store.
+func (store *Store) untrackMemory(obj *object.Obj) {
+	if entry, ok := store.objects.Get(obj); ok {
+		store.usedMemory -= entry.size
+		store.objects.Delete(obj)
+	}
+}
+
+// keyOf returns the key of an object of the selected database.
+func (store *Store) keyOf(obj *object.Obj) (string, bool) {
+	entry, ok := store.objects.Get(obj)
+	return entry.key, ok
+}
+
+// FreeMemory evicts keys if the keys use more than the max memory of the store, or if the selected
+// database holds as many keys as the eviction strategy allows. As the max memory is the one of the
+// whole store, every database gives up its share of the excess, estimating the number of its keys
+// to evict from their average size. It returns false if the keys are still over either limit, e.g.
+// when the eviction policy does not evict keys, or a volatile one finds no key with an expiry.
+func (store *Store) FreeMemory() bool {
+	if toEvict := store.evictionStrategy.ShouldEvict(store); toEvict > 0 {
+		store.evict(toEvict)
+		if store.evictionStrategy.ShouldEvict(store) > 0 {
+			return false
+		}
+	}
+
+	if store.maxMemory <= 0 {
+		return true
+	}
+
+	used := store.UsedMemory()
+	if excess := used - store.maxMemory; excess > 0 {
+		selected := store.selectedDB
+		for db, keyspace := range store.databases {
+			if keyspace.numKeys == 0 {
+				continue
+			}
+			share := int64(math.Ceil(float64(excess) * float64(keyspace.usedMemory) / float64(used)))
+			averageSize := max(keyspace.usedMemory/int64(keyspace.numKeys), 1)
+			store.SelectDB(db)
+			store.evict(int(min((share+averageSize-1)/averageSize, int64(keyspace.numKeys))))
+		}
+		store.SelectDB(selected)
+	}
+	return store.UsedMemory() <= store.maxMemory
+}
+
+// ResetStore deletes all the keys of the selected database.
 func (store *Store) ResetStore() {
-	store.numKeys = 0
-	store.store = NewStoreMap()
-	store.expires = NewExpireMap()
+	for k := range store.spilled {
+		store.dropSpilled(k)
+	}
+	*store.keyspace = *newKeyspace()
+}
+
+// NumDatabases returns the number of logical databases of the store.
+func (store *Store) NumDatabases() int {
+	return len(store.databases)
+}
+
+// SelectedDB returns the index of the selected database.
+func (store *Store) SelectedDB() int {
+	return store.selectedDB
+}
+
+// SelectDB selects the database the following operations apply to. The index must be lower than
+// NumDatabases.
+func (store *Store) SelectDB(db int) {
+	store.selectedDB = db
+	store.keyspace = store.databases[db]
+}
+
+// SwapDB swaps the keys of two databases, so that the clients connected to one of them see the
+// keys of the other one.
+func (store *Store) SwapDB(db1, db2 int) {
+	store.databases[db1], store.databases[db2] = store.databases[db2], store.databases[db1]
+	store.keyspace = store.databases[store.selectedDB]
+}
+
+// Move moves the key from the selected database to the given one, along with its expiry. It
+// returns false if the key does not exist, or if it already exists in the destination database.
+func (store *Store) Move(k string, db int) bool {
+	obj := store.getHelper(k, false)
+	if obj == nil {
+		return false
+	}
+
+	src := store.selectedDB
+	store.SelectDB(db)
+	exists := store.getHelper(k, false) != nil
+	store.SelectDB(src)
+	if exists {
+		return false
+	}
+
+	exp, hasExpiry := store.expires.Get(obj)
+	fe, hasFieldExpiry := store.fieldExpires.Get(obj)
+	store.deleteKey(k, obj, WithDelCmd(Del))
+
+	store.SelectDB(db)
+	store.putHelper(k, obj, WithPutCmd(Set))
+	if hasExpiry {
+		store.setExpiryAt(obj, exp)
+	}
+	if hasFieldExpiry {
+		store.fieldExpires.Put(obj, fe)
+	}
+	store.SelectDB(src)
+
+	return true
 }
 
 func (store *Store) Put(k string, obj *object.Obj, opts ...PutOption) {
@@ -129,17 +345,21 @@ func (store *Store) putHelper(k string, obj *object.Obj, opts ...PutOption) {
 		optApplier(options)
 	}
 
-	obj.LastAccessedAt = getCurrentClock()
 	currentObject, ok := store.store.Get(k)
 	if ok {
-		v, ok1 := store.expires.Get(currentObject)
-		if ok1 && options.KeepTTL && v > 0 {
-			v1, ok2 := store.expires.Get(currentObject)
-			if ok2 {
-				store.expires.Put(obj, v1)
+		// Putting back the object of the key, once modified, keeps its expiry
+		if currentObject != obj {
+			v, ok1 := store.expires.Get(currentObject)
+			if ok1 && options.KeepTTL && v > 0 {
+				v1, ok2 := store.expires.Get(currentObject)
+				if ok2 {
+					store.setExpiryAt(obj, v1)
+				}
 			}
+			store.delExpiry(currentObject)
+			store.fieldExpires.Delete(currentObject)
+			store.untrackMemory(currentObject)
 		}
-		store.expires.Delete(currentObject)
 	} else {
 		// TODO: Inform all the io-threads and shards about the eviction.
 		// TODO: Start the eviction only when all the io-thread and shards have acknowledged the eviction.
@@ -148,12 +368,16 @@ func (store *Store) putHelper(k string, obj *object.Obj, opts ...PutOption) {
 			store.evict(evictCount)
 		}
 		store.numKeys++
+		store.scanIndex.ReplaceOrInsert(newScanEntry(k))
+		store.dropSpilled(k)
 	}
 
+	BumpVersion(obj)
 	store.store.Put(k, obj)
-	store.evictionStrategy.OnAccess(k, obj, AccessSet)
+	store.trackMemory(k, obj)
+	store.touch(k, obj, AccessSet)
 
-	if store.cmdWatchChan != nil {
+	if store.cmdWatchChan != nil && options.Notify {
 		store.notifyWatchManager(options.PutCmd, k)
 	}
 }
@@ -162,33 +386,53 @@ func (store *Store) putHelper(k string, obj *object.Obj, opts ...PutOption) {
 func (store *Store) getHelper(k string, touch bool) *object.Obj {
 	var obj *object.Obj
 	obj, _ = store.store.Get(k)
+	if obj == nil {
+		obj = store.promote(k)
+	}
 	if obj != nil {
 		if hasExpired(obj, store) {
-			store.deleteKey(k, obj)
+			store.deleteExpiredKey(k, obj)
+			obj = nil
+		} else if store.expireFields(k, obj) {
 			obj = nil
 		} else if touch {
-			obj.LastAccessedAt = getCurrentClock()
-			store.evictionStrategy.OnAccess(k, obj, AccessGet)
+			store.touch(k, obj, AccessGet)
 		}
 	}
+	if touch {
+		store.recordLookup(obj != nil)
+	}
 	return obj
 }
 
+// touch records an access to the object of key k. The eviction strategy is told first, so that it
+// can tell for how long the object was idle.
+func (store *Store) touch(k string, obj *object.Obj, accessType AccessType) {
+	store.evictionStrategy.OnAccess(k, obj, accessType)
+	obj.LastAccessedAt = getCurrentClock()
+}
+
 func (store *Store) GetAll(keys []string) []*object.Obj {
 	response := make([]*object.Obj, 0, len(keys))
 	for _, k := range keys {
 		v, _ := store.store.Get(k)
+		if v == nil {
+			v = store.promote(k)
+		}
 		if v != nil {
 			if hasExpired(v, store) {
-				store.deleteKey(k, v)
+				store.deleteExpiredKey(k, v)
+				response = append(response, nil)
+			} else if store.expireFields(k, v) {
 				response = append(response, nil)
 			} else {
-				v.LastAccessedAt = getCurrentClock()
+				store.touch(k, v, AccessGet)
 				response = append(response, v)
 			}
 		} else {
 			response = append(response, nil)
 		}
+		store.recordLookup(response[len(response)-1] != nil)
 	}
 	return response
 }
@@ -198,6 +442,20 @@ func (store *Store) Del(k string, opts ...DelOption) bool {
 	if ok {
 		return store.deleteKey(k, v, opts...)
 	}
+	if record, ok := store.spilled[k]; ok {
+		store.dropSpilled(k)
+		if record.expired() {
+			return false
+		}
+		if store.cmdWatchChan != nil {
+			options := getDefaultDelOptions()
+			for _, optApplier := range opts {
+				optApplier(options)
+			}
+			store.notifyWatchManager(options.DelCmd, k)
+		}
+		return true
+	}
 	return false
 }
 
@@ -239,18 +497,25 @@ func (store *Store) Rename(sourceKey, destKey string) bool {
 	}
 
 	sourceObj, _ := store.store.Get(sourceKey)
+	if sourceObj == nil {
+		sourceObj = store.promote(sourceKey)
+	}
 	if sourceObj == nil || hasExpired(sourceObj, store) {
 		if sourceObj != nil {
-			store.deleteKey(sourceKey, sourceObj, WithDelCmd(Rename))
+			store.deleteExpiredKey(sourceKey, sourceObj, WithDelCmd(Rename))
 		}
 		return false
 	}
 
 	// Use putHelper to handle putting the object at the destination key
 	store.putHelper(destKey, sourceObj, WithPutCmd(Set))
+	if fe, ok := store.fieldExpires.Get(sourceObj); ok {
+		fe.key = destKey
+	}
 
 	// Remove the source key
 	store.store.Delete(sourceKey)
+	store.scanIndex.Delete(newScanEntry(sourceKey))
 	store.numKeys--
 
 	if store.cmdWatchChan != nil {
@@ -267,11 +532,15 @@ func (store *Store) Get(k string) *object.Obj {
 func (store *Store) GetDel(k string, opts ...DelOption) *object.Obj {
 	var v *object.Obj
 	v, _ = store.store.Get(k)
+	if v == nil {
+		v = store.promote(k)
+	}
 	if v != nil {
-		expired := hasExpired(v, store)
-		store.deleteKey(k, v, opts...)
-		if expired {
+		if hasExpired(v, store) {
+			store.deleteExpiredKey(k, v, opts...)
 			v = nil
+		} else {
+			store.deleteKey(k, v, opts...)
 		}
 	}
 	return v
@@ -280,14 +549,14 @@ func (store *Store) GetDel(k string, opts ...DelOption) *object.Obj {
 // SetExpiry sets the expiry time for an object.
 // This method is not thread-safe. It should be called within a lock.
 func (store *Store) SetExpiry(obj *object.Obj, expDurationMs int64) {
-	store.expires.Put(obj, uint64(utils.GetCurrentTime().UnixMilli())+uint64(expDurationMs))
+	store.setExpiryAt(obj, uint64(utils.GetCurrentTime().UnixMilli())+uint64(expDurationMs))
 }
 
 // SetUnixTimeExpiry sets the expiry time for an object.
 // This method is not thread-safe. It should be called within a lock.
 func (store *Store) SetUnixTimeExpiry(obj *object.Obj, exUnixTimeSec int64) {
 	// convert unix-time-seconds to unix-time-milliseconds
-	store.expires.Put(obj, uint64(exUnixTimeSec*1000))
+	store.setExpiryAt(obj, uint64(exUnixTimeSec*1000))
 }
 
 func (store *Store) deleteKey(k string, obj *object.Obj, opts ...DelOption) bool {
@@ -298,13 +567,22 @@ func (store *Store) deleteKey(k string, obj *object.Obj, opts ...DelOption) bool
 	}
 
 	if obj != nil {
+		// The keys spilled to the disk tier once evicted still exist, the watchers are not told
+		if options.DelCmd == Evict && store.diskTier != nil {
+			store.spill(k, obj)
+		}
+		_, spilled := store.spilled[k]
+
 		store.store.Delete(k)
-		store.expires.Delete(obj)
+		store.scanIndex.Delete(newScanEntry(k))
+		store.delExpiry(obj)
+		store.fieldExpires.Delete(obj)
+		store.untrackMemory(obj)
 		store.numKeys--
 
 		store.evictionStrategy.OnAccess(k, obj, AccessDel)
 
-		if store.cmdWatchChan != nil {
+		if store.cmdWatchChan != nil && !spilled {
 			store.notifyWatchManager(options.DelCmd, k)
 		}
 
@@ -6,12 +6,14 @@ package store
 type PutOptions struct {
 	KeepTTL bool
 	PutCmd  string
+	Notify  bool // Notify tells the watch manager about the put, with PutCmd.
 }
 
 func getDefaultPutOptions() *PutOptions {
 	return &PutOptions{
 		KeepTTL: false,
 		PutCmd:  Set,
+		Notify:  true,
 	}
 }
 
@@ -29,6 +31,14 @@ func WithPutCmd(cmd string) PutOption {
 	}
 }
 
+// withoutNotify puts the key without telling the watch manager, e.g. when the key already existed
+// in the disk tier.
+func withoutNotify() PutOption {
+	return func(po *PutOptions) {
+		po.Notify = false
+	}
+}
+
 type DelOptions struct {
 	DelCmd string
 }
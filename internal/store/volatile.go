@@ -0,0 +1,117 @@
+// Copyright (c) 2022-present, DiceDB contributors
+// All rights reserved. Licensed under the BSD 3-Clause License. See LICENSE file in the project root for full license information.
+
+package store
+
+import (
+	"math"
+
+	"github.com/dicedb/dice/internal/object"
+)
+
+// The volatile eviction strategies only evict the keys with an expiry, sampled from the expiry
+// table, so that the keys without one are kept. When no key has an expiry, they do not evict any
+// key, and the commands which may grow the memory are rejected instead, see Store.FreeMemory.
+
+// VolatileLRU evicts the least recently used keys with an expiry, see AllKeysLRU.
+type VolatileLRU struct {
+	BaseEvictionStrategy
+	keysLimit
+}
+
+func NewVolatileLRU(maxKeys int, evictionRatio float64) *VolatileLRU {
+	return &VolatileLRU{
+		keysLimit: keysLimit{maxKeys: maxKeys, evictionRatio: evictionRatio},
+	}
+}
+
+func (e *VolatileLRU) EvictVictims(store *Store, toEvict int) {
+	if toEvict <= 0 {
+		return
+	}
+
+	evicted := evictSampled(store, toEvict, sampleVolatileKeys, evictionSamples, lruScore)
+	e.stats.recordEviction(evicted)
+}
+
+func (e *VolatileLRU) OnAccess(key string, obj *object.Obj, accessType AccessType) {
+	// Nothing to do, the store keeps the access time of the objects
+}
+
+// VolatileLFU evicts the least frequently used keys with an expiry, see AllKeysLFU.
+type VolatileLFU struct {
+	BaseEvictionStrategy
+	keysLimit
+	lfuCounter
+}
+
+func NewVolatileLFU(maxKeys int, evictionRatio float64, logFactor int) *VolatileLFU {
+	return &VolatileLFU{
+		keysLimit:  keysLimit{maxKeys: maxKeys, evictionRatio: evictionRatio},
+		lfuCounter: lfuCounter{logFactor: logFactor},
+	}
+}
+
+func (e *VolatileLFU) EvictVictims(store *Store, toEvict int) {
+	if toEvict <= 0 {
+		return
+	}
+
+	evicted := evictSampled(store, toEvict, sampleVolatileKeys, evictionSamples, lfuScore)
+	e.stats.recordEviction(evicted)
+}
+
+// VolatileRandom evicts random keys with an expiry, see AllKeysRandom.
+type VolatileRandom struct {
+	BaseEvictionStrategy
+	keysLimit
+}
+
+func NewVolatileRandom(maxKeys int, evictionRatio float64) *VolatileRandom {
+	return &VolatileRandom{
+		keysLimit: keysLimit{maxKeys: maxKeys, evictionRatio: evictionRatio},
+	}
+}
+
+func (e *VolatileRandom) EvictVictims(store *Store, toEvict int) {
+	if toEvict <= 0 {
+		return
+	}
+
+	evicted := evictSampled(store, toEvict, sampleVolatileKeys, 1, randomScore)
+	e.stats.recordEviction(evicted)
+}
+
+func (e *VolatileRandom) OnAccess(key string, obj *object.Obj, accessType AccessType) {
+	// Nothing to do, the accesses do not matter
+}
+
+// VolatileTTL evicts the keys closest to their expiry: each victim is the key expiring first out
+// of a sample of the keys with an expiry.
+type VolatileTTL struct {
+	BaseEvictionStrategy
+	keysLimit
+}
+
+func NewVolatileTTL(maxKeys int, evictionRatio float64) *VolatileTTL {
+	return &VolatileTTL{
+		keysLimit: keysLimit{maxKeys: maxKeys, evictionRatio: evictionRatio},
+	}
+}
+
+func (e *VolatileTTL) EvictVictims(store *Store, toEvict int) {
+	if toEvict <= 0 {
+		return
+	}
+
+	evicted := evictSampled(store, toEvict, sampleVolatileKeys, evictionSamples, func(obj *object.Obj) uint64 {
+		// Invert the expiry so that the earliest one scores the highest
+		exp, _ := store.expires.Get(obj)
+		return math.MaxUint64 - exp
+	})
+	e.stats.recordEviction(evicted)
+}
+
+func (e *VolatileTTL) OnAccess(key string, obj *object.Obj, accessType AccessType) {
+	// Nothing to do, the accesses do not matter
+}
@@ -0,0 +1,81 @@
+// Copyright (c) 2022-present, DiceDB contributors
+// All rights reserved. Licensed under the BSD 3-Clause License. See LICENSE file in the project root for full license information.
+
+package store
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/dicedb/dice/internal/object"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVolatileStrategies_EvictOnlyVolatileKeys(t *testing.T) {
+	strategies := map[string]EvictionStrategy{
+		"volatile-lru":    NewVolatileLRU(100, 0.5),
+		"volatile-lfu":    NewVolatileLFU(100, 0.5, 10),
+		"volatile-random": NewVolatileRandom(100, 0.5),
+		"volatile-ttl":    NewVolatileTTL(100, 0.5),
+	}
+
+	for name, eviction := range strategies {
+		t.Run(name, func(t *testing.T) {
+			s := NewStore(nil, eviction)
+			for i := 0; i < 5; i++ {
+				s.Put("durable"+strconv.Itoa(i), s.NewObj("value", -1, object.ObjTypeString))
+				s.Put("volatile"+strconv.Itoa(i), s.NewObj("value", 60000, object.ObjTypeString))
+			}
+
+			// Only the volatile keys are evicted, even when asked for more of them
+			s.evict(8)
+			assert.Equal(t, 5, s.GetKeyCount())
+			for i := 0; i < 5; i++ {
+				assert.NotNil(t, s.GetNoTouch("durable"+strconv.Itoa(i)))
+			}
+		})
+	}
+}
+
+func TestVolatileTTL_EvictsClosestToExpiry(t *testing.T) {
+	s := NewStore(nil, NewVolatileTTL(100, 0.5))
+	for i := 1; i < evictionSamples; i++ {
+		s.Put("key"+strconv.Itoa(i), s.NewObj("value", int64(i)*60000, object.ObjTypeString))
+	}
+
+	s.evict(2)
+	assert.Nil(t, s.GetNoTouch("key1"))
+	assert.Nil(t, s.GetNoTouch("key2"))
+	assert.NotNil(t, s.GetNoTouch("key3"))
+	assert.NotNil(t, s.GetNoTouch("key4"))
+}
+
+func TestVolatileStrategies_OutOfMemoryWithoutVolatileKeys(t *testing.T) {
+	s := NewStore(nil, NewVolatileLRU(100, 0.5))
+	for i := 0; i < 10; i++ {
+		putTestString(s, "key"+strconv.Itoa(i), "value")
+	}
+
+	s.SetMaxMemory(s.UsedMemory() / 2)
+	assert.False(t, s.FreeMemory())
+	assert.Equal(t, 10, s.GetKeyCount())
+}
+
+func TestVolatileStrategies_KeysLimitWithoutVolatileKeys(t *testing.T) {
+	s := NewStore(nil, NewVolatileLRU(10, 0.5))
+	for i := 0; i < 10; i++ {
+		putTestString(s, "key"+strconv.Itoa(i), "value")
+	}
+
+	// No key can be evicted to make room for another one
+	assert.False(t, s.FreeMemory())
+	assert.Equal(t, 10, s.GetKeyCount())
+
+	s = NewStore(nil, NewVolatileLRU(10, 0.5))
+	for i := 0; i < 5; i++ {
+		s.Put("durable"+strconv.Itoa(i), s.NewObj("value", -1, object.ObjTypeString))
+		s.Put("volatile"+strconv.Itoa(i), s.NewObj("value", 60000, object.ObjTypeString))
+	}
+	assert.True(t, s.FreeMemory())
+	assert.Equal(t, 5, s.GetKeyCount())
+}
@@ -4,19 +4,37 @@
 package wal
 
 import (
-	"fmt"
 	"log/slog"
 	sync "sync"
 	"time"
 
 	"github.com/dicedb/dice/internal/cmd"
+	"github.com/dicedb/dice/internal/observability"
 )
 
+// AbstractWAL logs the commands along with the logical database they are executed on.
 type AbstractWAL interface {
-	LogCommand([]byte) error
+	LogCommand(database uint32, data []byte) error
 	Close() error
 	Init(t time.Time) error
-	ForEachCommand(f func(c cmd.DiceDBCmd) error) error
+	ForEachCommand(f func(database uint32, c cmd.DiceDBCmd) error) error
+	Stats() Stats
+}
+
+// Stats holds the counters of a WAL since it was created, reported by INFO.
+type Stats struct {
+	// LoggedCommands and LoggedBytes count the commands logged and the bytes of their entries.
+	LoggedCommands uint64
+	LoggedBytes    uint64
+
+	// Syncs counts the writes of the buffered entries to the log, SyncLatency holds the time they
+	// took and LastSync the time the last one completed, or the zero time if none did.
+	Syncs       uint64
+	SyncLatency observability.HistogramSnapshot
+	LastSync    time.Time
+
+	// LastSyncFailed is true if the last write of the buffered entries to the log failed.
+	LastSyncFailed bool
 }
 
 var (
@@ -64,8 +82,8 @@ func ShutdownBG() {
 }
 
 func ReplayWAL(wl AbstractWAL) {
-	err := wl.ForEachCommand(func(c cmd.DiceDBCmd) error {
-		fmt.Println("replaying", c.Cmd, c.Args)
+	err := wl.ForEachCommand(func(database uint32, c cmd.DiceDBCmd) error {
+		slog.Debug("replaying", slog.Any("database", database), slog.String("cmd", c.Cmd), slog.Any("args", c.Args))
 		return nil
 	})
 
@@ -19,6 +19,7 @@ import (
 
 	"github.com/dicedb/dice/config"
 	"github.com/dicedb/dice/internal/cmd"
+	"github.com/dicedb/dice/internal/observability"
 )
 
 const (
@@ -48,6 +49,8 @@ type AOF struct {
 	bufferSyncTicker       *time.Ticker
 	segmentRotationTicker  *time.Ticker
 	segmentRetentionTicker *time.Ticker
+	stats                  Stats
+	syncLatency            observability.Histogram
 	mu                     sync.Mutex
 	ctx                    context.Context
 	cancel                 context.CancelFunc
@@ -122,11 +125,11 @@ func (wal *AOF) Init(t time.Time) error {
 }
 
 // WriteEntry writes an entry to the WAL.
-func (wal *AOF) LogCommand(data []byte) error {
-	return wal.writeEntry(data)
+func (wal *AOF) LogCommand(database uint32, data []byte) error {
+	return wal.writeEntry(database, data)
 }
 
-func (wal *AOF) writeEntry(data []byte) error {
+func (wal *AOF) writeEntry(database uint32, data []byte) error {
 	wal.mu.Lock()
 	defer wal.mu.Unlock()
 
@@ -137,6 +140,7 @@ func (wal *AOF) writeEntry(data []byte) error {
 		Data:              data,
 		Crc32:             crc32.ChecksumIEEE(append(data, byte(wal.lastSequenceNo))),
 		Timestamp:         time.Now().UnixNano(),
+		Database:          database,
 	}
 
 	entrySize := getEntrySize(data)
@@ -149,6 +153,8 @@ func (wal *AOF) writeEntry(data []byte) error {
 	if err := wal.writeEntryToBuffer(entry); err != nil {
 		return err
 	}
+	wal.stats.LoggedCommands++
+	wal.stats.LoggedBytes += uint64(entrySize)
 
 	// if wal-mode unbuffered immediately sync to disk
 	if wal.walMode == WALModeUnbuffered { 
@@ -239,6 +245,20 @@ func (wal *AOF) Close() error {
 // Writes out any data in the WAL's in-memory buffer to the segment file. If
 // fsync is enabled, it also calls fsync on the segment file.
 func (wal *AOF) Sync() error {
+	start := time.Now()
+	err := wal.sync()
+	wal.stats.LastSyncFailed = err != nil
+	if err != nil {
+		return err
+	}
+
+	wal.stats.Syncs++
+	wal.syncLatency.Observe(time.Since(start))
+	wal.stats.LastSync = time.Now()
+	return nil
+}
+
+func (wal *AOF) sync() error {
 	if err := wal.bufWriter.Flush(); err != nil {
 		return err
 	}
@@ -251,6 +271,16 @@ func (wal *AOF) Sync() error {
 	return nil
 }
 
+// Stats returns the counters of the WAL.
+func (wal *AOF) Stats() Stats {
+	wal.mu.Lock()
+	defer wal.mu.Unlock()
+
+	stats := wal.stats
+	stats.SyncLatency = wal.syncLatency.Snapshot()
+	return stats
+}
+
 func (wal *AOF) keepSyncingBuffer() {
 	for {
 		select {
@@ -305,7 +335,7 @@ func (wal *AOF) deleteSegmentPeriodically() {
 	}
 }
 
-func (wal *AOF) ForEachCommand(f func(c cmd.DiceDBCmd) error) error {
+func (wal *AOF) ForEachCommand(f func(database uint32, c cmd.DiceDBCmd) error) error {
 	// TODO: implement this method
 	return nil
 }
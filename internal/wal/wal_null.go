@@ -21,7 +21,7 @@ func (w *WALNull) Init(t time.Time) error {
 }
 
 // LogCommand serializes a WALLogEntry and writes it to the current WAL file.
-func (w *WALNull) LogCommand(b []byte) error {
+func (w *WALNull) LogCommand(database uint32, b []byte) error {
 	return nil
 }
 
@@ -29,6 +29,10 @@ func (w *WALNull) Close() error {
 	return nil
 }
 
-func (w *WALNull) ForEachCommand(f func(c cmd.DiceDBCmd) error) error {
+func (w *WALNull) ForEachCommand(f func(database uint32, c cmd.DiceDBCmd) error) error {
 	return nil
 }
+
+func (w *WALNull) Stats() Stats {
+	return Stats{}
+}
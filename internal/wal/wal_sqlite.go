@@ -52,7 +52,8 @@ func (w *WALSQLite) Init(t time.Time) error {
 
 	_, err = db.Exec(`CREATE TABLE IF NOT EXISTS wal (
 		id INTEGER PRIMARY KEY AUTOINCREMENT,
-		command TEXT NOT NULL
+		command TEXT NOT NULL,
+		database INTEGER NOT NULL DEFAULT 0
 	);`)
 	if err != nil {
 		return err
@@ -62,11 +63,11 @@ func (w *WALSQLite) Init(t time.Time) error {
 	return nil
 }
 
-func (w *WALSQLite) LogCommand(c *cmd.DiceDBCmd) {
+func (w *WALSQLite) LogCommand(database uint32, c *cmd.DiceDBCmd) {
 	w.mu.Lock()
 	defer w.mu.Unlock()
 
-	if _, err := w.curDB.Exec("INSERT INTO wal (command) VALUES (?)", c.Repr()); err != nil {
+	if _, err := w.curDB.Exec("INSERT INTO wal (command, database) VALUES (?, ?)", c.Repr(), database); err != nil {
 		slog.Error("failed to log command in WAL", slog.Any("error", err))
 	} else {
 		slog.Debug("logged command in WAL", slog.Any("command", c.Repr()))
@@ -77,7 +78,7 @@ func (w *WALSQLite) Close() error {
 	return w.curDB.Close()
 }
 
-func (w *WALSQLite) ForEachCommand(f func(c cmd.DiceDBCmd) error) error {
+func (w *WALSQLite) ForEachCommand(f func(database uint32, c cmd.DiceDBCmd) error) error {
 	files, err := os.ReadDir(w.logDir)
 	if err != nil {
 		return fmt.Errorf("failed to read log directory: %v", err)
@@ -117,19 +118,20 @@ func (w *WALSQLite) ForEachCommand(f func(c cmd.DiceDBCmd) error) error {
 			return fmt.Errorf("failed to open WAL file %s: %v", file.Name(), err)
 		}
 
-		rows, err := db.Query("SELECT command FROM wal")
+		rows, err := db.Query("SELECT command, database FROM wal")
 		if err != nil {
 			return fmt.Errorf("failed to query WAL file %s: %v", file.Name(), err)
 		}
 
 		for rows.Next() {
 			var command string
-			if err := rows.Scan(&command); err != nil {
+			var database uint32
+			if err := rows.Scan(&command, &database); err != nil {
 				return fmt.Errorf("failed to scan WAL file %s: %v", file.Name(), err)
 			}
 
 			tokens := strings.Split(command, " ")
-			if err := f(cmd.DiceDBCmd{
+			if err := f(database, cmd.DiceDBCmd{
 				Cmd:  tokens[0],
 				Args: tokens[1:],
 			}); err != nil {
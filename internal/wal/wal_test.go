@@ -24,6 +24,6 @@ func BenchmarkLogCommandAOF(b *testing.B) {
 	}
 
 	for i := 0; i < b.N; i++ {
-		wl.LogCommand([]byte("SET key value"))
+		wl.LogCommand(0, []byte("SET key value"))
 	}
 }
@@ -18,6 +18,7 @@ const (
 	dataLengthPrefixSize    = 1 // Length prefix for "data"
 	CRCSize                 = 4
 	timestampSize           = 8
+	databaseSize            = 4
 )
 
 // Marshals
@@ -41,5 +42,6 @@ func getEntrySize(data []byte) int {
 		logSequenceNumberSize + // Log Sequence Number field
 		dataTagSize + dataLengthPrefixSize + len(data) + // Data field
 		CRCSize + // CRC field
-		timestampSize // Timestamp field
+		timestampSize + // Timestamp field
+		databaseSize // Database field
 }
@@ -6,7 +6,10 @@ package watchmanager
 import (
 	"context"
 	"log/slog"
+	"slices"
 	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/dicedb/dice/internal/cmd"
 	dstore "github.com/dicedb/dice/internal/store"
@@ -18,6 +21,7 @@ type (
 		AdhocReqChan chan *cmd.DiceDBCmd // AdhocReqChan is the channel to send adhoc requests to the io-thread. Required.
 		WatchCmd     *cmd.DiceDBCmd      // WatchCmd Represents a unique key for each watch artifact, only populated for subscriptions.
 		Fingerprint  uint32              // Fingerprint is a unique identifier for each watch artifact, only populated for unsubscriptions.
+		All          bool                // All unsubscribes the channel from every fingerprint once its client is gone, in place of Fingerprint.
 	}
 
 	Manager struct {
@@ -26,9 +30,17 @@ type (
 		fingerprintCmdMap        map[uint32]*cmd.DiceDBCmd                   // fingerprintCmdMap is a map of fingerprint -> DiceDBCmd
 		cmdWatchSubscriptionChan chan WatchSubscription                      // cmdWatchSubscriptionChan is the channel to send/receive watch subscription requests.
 		cmdWatchChan             chan dstore.CmdWatchEvent                   // cmdWatchChan is the channel to send/receive watch events.
+		backlogs                 map[chan *cmd.DiceDBCmd][]uint32            // backlogs is a map of clientChan -> [fingerprint1, ...] for the clients whose channel is full.
+		slowConsumers            atomic.Uint64                               // slowConsumers counts the times a client fell behind and had its notifications held in a backlog.
+		subscriptions            atomic.Int64                                // subscriptions counts the clients subscribed to each fingerprint, for the metrics.
+		fingerprints             atomic.Int64                                // fingerprints is the number of fingerprints watched, for the metrics.
 	}
 )
 
+// backlogFlushInterval is how often the notifications held for the clients that fell behind are
+// retried.
+const backlogFlushInterval = 100 * time.Millisecond
+
 var (
 	affectedCmdMap = map[string]map[string]struct{}{
 		dstore.Set:     {dstore.Get: struct{}{}},
@@ -45,6 +57,7 @@ func NewManager(cmdWatchSubscriptionChan chan WatchSubscription, cmdWatchChan ch
 		querySubscriptionMap:     make(map[string]map[uint32]struct{}),
 		tcpSubscriptionMap:       make(map[uint32]map[chan *cmd.DiceDBCmd]struct{}),
 		fingerprintCmdMap:        make(map[uint32]*cmd.DiceDBCmd),
+		backlogs:                 make(map[chan *cmd.DiceDBCmd][]uint32),
 		cmdWatchSubscriptionChan: cmdWatchSubscriptionChan,
 		cmdWatchChan:             cmdWatchChan,
 	}
@@ -66,13 +79,22 @@ func (m *Manager) Run(ctx context.Context) {
 }
 
 func (m *Manager) listenForEvents(ctx context.Context) {
+	flushTicker := time.NewTicker(backlogFlushInterval)
+	defer flushTicker.Stop()
+
 	for {
 		select {
 		case <-ctx.Done():
 			return
+		case <-flushTicker.C:
+			for clientChan := range m.backlogs {
+				m.flushBacklog(clientChan)
+			}
 		case sub := <-m.cmdWatchSubscriptionChan:
 			if sub.Subscribe {
 				m.handleSubscription(sub)
+			} else if sub.All {
+				m.handleClientGone(sub.AdhocReqChan)
 			} else {
 				m.handleUnsubscription(sub)
 			}
@@ -100,15 +122,32 @@ func (m *Manager) handleSubscription(sub WatchSubscription) {
 	if _, exists := m.tcpSubscriptionMap[fingerprint]; !exists {
 		m.tcpSubscriptionMap[fingerprint] = make(map[chan *cmd.DiceDBCmd]struct{})
 	}
+	if _, exists := m.tcpSubscriptionMap[fingerprint][sub.AdhocReqChan]; !exists {
+		m.subscriptions.Add(1)
+	}
 	m.tcpSubscriptionMap[fingerprint][sub.AdhocReqChan] = struct{}{}
+	m.fingerprints.Store(int64(len(m.fingerprintCmdMap)))
 }
 
 // handleUnsubscription processes an unsubscription request
 func (m *Manager) handleUnsubscription(sub WatchSubscription) {
 	fingerprint := sub.Fingerprint
 
+	// Drop the notifications of this fingerprint still held for the client
+	if backlog, ok := m.backlogs[sub.AdhocReqChan]; ok {
+		backlog = slices.DeleteFunc(backlog, func(fp uint32) bool { return fp == fingerprint })
+		if len(backlog) == 0 {
+			delete(m.backlogs, sub.AdhocReqChan)
+		} else {
+			m.backlogs[sub.AdhocReqChan] = backlog
+		}
+	}
+
 	// Remove clientID from tcpSubscriptionMap
 	if clients, ok := m.tcpSubscriptionMap[fingerprint]; ok {
+		if _, subscribed := clients[sub.AdhocReqChan]; subscribed {
+			m.subscriptions.Add(-1)
+		}
 		delete(clients, sub.AdhocReqChan)
 		// If there are no more clients listening to this fingerprint, remove it from the map
 		if len(clients) == 0 {
@@ -133,9 +172,25 @@ func (m *Manager) handleUnsubscription(sub WatchSubscription) {
 		}
 		// Also remove the fingerprint from fingerprintCmdMap
 		delete(m.fingerprintCmdMap, fingerprint)
+		m.fingerprints.Store(int64(len(m.fingerprintCmdMap)))
 	}
 }
 
+// handleClientGone unsubscribes the channel of a client that is gone from all its fingerprints, and
+// drops the notifications still held for it.
+func (m *Manager) handleClientGone(clientChan chan *cmd.DiceDBCmd) {
+	var fingerprints []uint32
+	for fingerprint, clients := range m.tcpSubscriptionMap {
+		if _, subscribed := clients[clientChan]; subscribed {
+			fingerprints = append(fingerprints, fingerprint)
+		}
+	}
+	for _, fingerprint := range fingerprints {
+		m.handleUnsubscription(WatchSubscription{AdhocReqChan: clientChan, Fingerprint: fingerprint})
+	}
+	delete(m.backlogs, clientChan)
+}
+
 func (m *Manager) handleWatchEvent(event dstore.CmdWatchEvent) {
 	// Check if any watch commands are listening to updates on this key.
 	fingerprints, exists := m.querySubscriptionMap[event.AffectedKey]
@@ -173,6 +228,68 @@ func (m *Manager) notifyClients(fingerprint uint32, diceDBCmd *cmd.DiceDBCmd) {
 	}
 
 	for clientChan := range clients {
-		clientChan <- diceDBCmd
+		m.notifyClient(clientChan, fingerprint, diceDBCmd)
+	}
+}
+
+// notifyClient sends cmd to a client without ever blocking, as that would stall notifications for
+// everyone else. Once the channel of the client is full, the fingerprint is held in the backlog of
+// the client until it catches up. A fingerprint is held only once however many events it gets, as the
+// client executes the command again anyway. A client that does not catch up is disconnected by the
+// output buffer limits of its class, which bound the results it has yet to read.
+func (m *Manager) notifyClient(clientChan chan *cmd.DiceDBCmd, fingerprint uint32, diceDBCmd *cmd.DiceDBCmd) {
+	if backlog, lagging := m.backlogs[clientChan]; lagging {
+		if !slices.Contains(backlog, fingerprint) {
+			m.backlogs[clientChan] = append(backlog, fingerprint)
+		}
+		m.flushBacklog(clientChan)
+		return
+	}
+
+	select {
+	case clientChan <- diceDBCmd:
+	default:
+		slog.Debug("Watch subscriber fell behind, holding its notifications",
+			slog.Uint64("fingerprint", uint64(fingerprint)),
+			slog.Int("pending", len(clientChan)))
+		m.slowConsumers.Add(1)
+		m.backlogs[clientChan] = []uint32{fingerprint}
+	}
+}
+
+// flushBacklog sends the notifications held for a client, as many as its channel takes.
+func (m *Manager) flushBacklog(clientChan chan *cmd.DiceDBCmd) {
+	backlog := m.backlogs[clientChan]
+	sent := 0
+flush:
+	for sent < len(backlog) {
+		select {
+		case clientChan <- m.fingerprintCmdMap[backlog[sent]]:
+			sent++
+		default:
+			break flush
+		}
 	}
+
+	if sent == len(backlog) {
+		delete(m.backlogs, clientChan)
+	} else {
+		m.backlogs[clientChan] = backlog[sent:]
+	}
+}
+
+// SlowConsumers returns the number of times a client fell behind and had its notifications held in a
+// backlog.
+func (m *Manager) SlowConsumers() uint64 {
+	return m.slowConsumers.Load()
+}
+
+// Subscriptions returns the number of subscriptions of the clients, one per client and fingerprint.
+func (m *Manager) Subscriptions() int64 {
+	return m.subscriptions.Load()
+}
+
+// Fingerprints returns the number of fingerprints watched by at least one client.
+func (m *Manager) Fingerprints() int64 {
+	return m.fingerprints.Load()
 }
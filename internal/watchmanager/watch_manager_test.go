@@ -0,0 +1,78 @@
+// Copyright (c) 2022-present, DiceDB contributors
+// All rights reserved. Licensed under the BSD 3-Clause License. See LICENSE file in the project root for full license information.
+
+package watchmanager
+
+import (
+	"testing"
+
+	"github.com/dicedb/dice/internal/cmd"
+	dstore "github.com/dicedb/dice/internal/store"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSlowSubscriberIsHeldBack(t *testing.T) {
+	m := NewManager(nil, nil)
+	watchCmd := &cmd.DiceDBCmd{Cmd: dstore.Get, Args: []string{"k"}}
+	otherCmd := &cmd.DiceDBCmd{Cmd: dstore.Get, Args: []string{"other"}}
+
+	slow := make(chan *cmd.DiceDBCmd, 1)
+	fast := make(chan *cmd.DiceDBCmd, 10)
+	m.handleSubscription(WatchSubscription{Subscribe: true, WatchCmd: watchCmd, AdhocReqChan: slow})
+	m.handleSubscription(WatchSubscription{Subscribe: true, WatchCmd: otherCmd, AdhocReqChan: slow})
+	m.handleSubscription(WatchSubscription{Subscribe: true, WatchCmd: watchCmd, AdhocReqChan: fast})
+	assert.Equal(t, int64(3), m.Subscriptions())
+	assert.Equal(t, int64(2), m.Fingerprints())
+
+	event := dstore.CmdWatchEvent{Cmd: dstore.Set, AffectedKey: "k"}
+	m.handleWatchEvent(event)
+	m.handleWatchEvent(event)
+	m.handleWatchEvent(event)
+	m.handleWatchEvent(dstore.CmdWatchEvent{Cmd: dstore.Set, AffectedKey: "other"})
+
+	// the slow subscriber could only take the first notification, the others are held once per
+	// fingerprint without holding back the other subscriber nor unsubscribing the slow one
+	assert.Len(t, slow, 1)
+	assert.Len(t, fast, 3)
+	assert.Equal(t, uint64(1), m.SlowConsumers())
+	assert.Equal(t, []uint32{watchCmd.GetFingerprint(), otherCmd.GetFingerprint()}, m.backlogs[slow])
+	assert.Contains(t, m.tcpSubscriptionMap[watchCmd.GetFingerprint()], slow)
+	assert.Equal(t, int64(3), m.Subscriptions())
+
+	// the held notifications are sent in order as the subscriber catches up
+	assert.Equal(t, watchCmd, <-slow)
+	m.flushBacklog(slow)
+	assert.Equal(t, watchCmd, <-slow)
+	m.flushBacklog(slow)
+	assert.Equal(t, otherCmd, <-slow)
+	assert.NotContains(t, m.backlogs, slow)
+
+	// unsubscribing drops the notifications held for the fingerprint
+	m.handleWatchEvent(event)
+	m.handleWatchEvent(dstore.CmdWatchEvent{Cmd: dstore.Set, AffectedKey: "other"})
+	assert.Equal(t, []uint32{otherCmd.GetFingerprint()}, m.backlogs[slow])
+	m.handleUnsubscription(WatchSubscription{AdhocReqChan: slow, Fingerprint: otherCmd.GetFingerprint()})
+	assert.NotContains(t, m.backlogs, slow)
+	assert.Equal(t, int64(2), m.Subscriptions())
+}
+
+func TestSubscriberGoneIsUnsubscribed(t *testing.T) {
+	m := NewManager(nil, nil)
+	watchCmd := &cmd.DiceDBCmd{Cmd: dstore.Get, Args: []string{"k"}}
+	otherCmd := &cmd.DiceDBCmd{Cmd: dstore.Get, Args: []string{"other"}}
+
+	gone := make(chan *cmd.DiceDBCmd, 1)
+	m.handleSubscription(WatchSubscription{Subscribe: true, WatchCmd: watchCmd, AdhocReqChan: gone})
+	m.handleSubscription(WatchSubscription{Subscribe: true, WatchCmd: otherCmd, AdhocReqChan: gone})
+	m.handleWatchEvent(dstore.CmdWatchEvent{Cmd: dstore.Set, AffectedKey: "k"})
+	m.handleWatchEvent(dstore.CmdWatchEvent{Cmd: dstore.Set, AffectedKey: "other"})
+	assert.Contains(t, m.backlogs, gone)
+
+	// the client that is gone leaves every fingerprint along with its held notifications
+	m.handleClientGone(gone)
+	assert.Empty(t, m.tcpSubscriptionMap)
+	assert.Empty(t, m.backlogs)
+	assert.Empty(t, m.querySubscriptionMap)
+	assert.Zero(t, m.Subscriptions())
+	assert.Zero(t, m.Fingerprints())
+}
@@ -19,7 +19,9 @@ import (
 	"time"
 
 	"github.com/dicedb/dice/internal/server/httpws"
+	"github.com/dicedb/dice/internal/server/memcached"
 
+	"github.com/dicedb/dice/internal/auth"
 	"github.com/dicedb/dice/internal/cli"
 	"github.com/dicedb/dice/internal/commandhandler"
 	"github.com/dicedb/dice/internal/logger"
@@ -47,6 +49,11 @@ func main() {
 	cli.Execute()
 	go observability.Ping()
 
+	if err := auth.InitDefaultUser(); err != nil {
+		slog.Error("could not initialize the default user", slog.Any("error", err))
+		return
+	}
+
 	ctx, cancel := context.WithCancel(context.Background())
 
 	// Handle SIGTERM and SIGINT
@@ -141,17 +148,23 @@ func main() {
 	go runServer(ctx, &serverWg, respServer, serverErrCh)
 
 	if config.DiceConfig.HTTP.Enabled {
-		httpServer := httpws.NewHTTPServer(shardManager, wl)
+		httpServer := httpws.NewHTTPServer(shardManager, cmdWatchSubscriptionChan, serverErrCh, wl)
 		serverWg.Add(1)
 		go runServer(ctx, &serverWg, httpServer, serverErrCh)
 	}
 
 	if config.DiceConfig.WebSocket.Enabled {
-		websocketServer := httpws.NewWebSocketServer(shardManager, config.DiceConfig.WebSocket.Port, wl)
+		websocketServer := httpws.NewWebSocketServer(shardManager, cmdWatchSubscriptionChan, config.DiceConfig.WebSocket.Port, serverErrCh, wl)
 		serverWg.Add(1)
 		go runServer(ctx, &serverWg, websocketServer, serverErrCh)
 	}
 
+	if config.DiceConfig.Memcached.Enabled {
+		memcachedServer := memcached.NewServer(shardManager, config.DiceConfig.Memcached.Port)
+		serverWg.Add(1)
+		go runServer(ctx, &serverWg, memcachedServer, serverErrCh)
+	}
+
 	wg.Add(1)
 	go func() {
 		defer wg.Done()